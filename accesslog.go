@@ -0,0 +1,208 @@
+package sugarzero
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AccessLogFormat selects the line format written by AccessLogMiddleware.
+type AccessLogFormat int
+
+const (
+	// AccessLogCommon is the NCSA Common Log Format:
+	//	127.0.0.1 - frank [10/Oct/2000:13:55:36 -0700] "GET /a.gif HTTP/1.0" 200 2326
+	AccessLogCommon AccessLogFormat = iota
+	// AccessLogCombined is the Apache Combined Log Format, which appends the
+	// quoted Referer and User-Agent to the Common Log Format.
+	AccessLogCombined
+	// AccessLogW3C is the W3C Extended Log File Format with the fields listed
+	// in w3cFields. The #Version and #Fields directives are written before the
+	// first entry.
+	AccessLogW3C
+)
+
+const (
+	clfTimeLayout = "02/Jan/2006:15:04:05 -0700"
+	w3cFields     = "date time c-ip cs-username cs-method cs-uri-stem cs-uri-query sc-status sc-bytes time-taken cs(User-Agent) cs(Referer)"
+)
+
+// AccessLogMiddleware returns HTTP middleware that writes one access log line
+// per request to w in the given format. It is meant for a dedicated writer,
+// since these formats are plain text rather than JSON.
+func AccessLogMiddleware(w io.Writer, format AccessLogFormat) func(http.Handler) http.Handler {
+	logger := &accessLogger{w: w, format: format}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &responseRecorder{ResponseWriter: rw}
+			next.ServeHTTP(recorder, r)
+			logger.log(r, recorder, start, time.Since(start))
+		})
+	}
+}
+
+type accessLogger struct {
+	mu            sync.Mutex
+	w             io.Writer
+	format        AccessLogFormat
+	headerWritten bool
+}
+
+func (l *accessLogger) log(r *http.Request, rec *responseRecorder, start time.Time, elapsed time.Duration) {
+	var line []byte
+	switch l.format {
+	case AccessLogW3C:
+		line = appendW3CLine(nil, r, rec, start, elapsed)
+	case AccessLogCombined:
+		line = appendCommonLine(nil, r, rec, start)
+		line = fmt.Appendf(line, " %s %s", quoteCLF(r.Referer()), quoteCLF(r.UserAgent()))
+	default:
+		line = appendCommonLine(nil, r, rec, start)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.format == AccessLogW3C && !l.headerWritten {
+		l.headerWritten = true
+		_, _ = fmt.Fprintf(l.w, "#Version: 1.0\n#Fields: %s\n", w3cFields)
+	}
+	if _, err := l.w.Write(line); err != nil {
+		reportWriteError(fmt.Errorf("sugarzero: write access log: %w", err))
+	}
+}
+
+func appendCommonLine(dst []byte, r *http.Request, rec *responseRecorder, start time.Time) []byte {
+	request := r.Method + " " + r.RequestURI + " " + r.Proto
+	size := "-"
+	if rec.bytes > 0 {
+		size = strconv.FormatInt(rec.bytes, 10)
+	}
+	user := "-"
+	if name := username(r); name != "" {
+		user = quoteCLF(name)
+	}
+	return fmt.Appendf(dst, "%s - %s [%s] %s %d %s",
+		remoteHost(r), user, start.Format(clfTimeLayout), quoteCLF(request), rec.statusCode(), size)
+}
+
+func appendW3CLine(dst []byte, r *http.Request, rec *responseRecorder, start time.Time, elapsed time.Duration) []byte {
+	utc := start.UTC()
+	return fmt.Appendf(dst, "%s %s %s %s %s %s %s %d %d %.3f %s %s",
+		utc.Format("2006-01-02"), utc.Format("15:04:05"),
+		remoteHost(r), w3cValue(username(r)), r.Method,
+		w3cValue(r.URL.Path), w3cValue(r.URL.RawQuery),
+		rec.statusCode(), rec.bytes, elapsed.Seconds(),
+		w3cValue(r.UserAgent()), w3cValue(r.Referer()))
+}
+
+func remoteHost(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	// Middleware may set RemoteAddr from client headers such as X-Forwarded-For
+	return orDash(strings.ReplaceAll(escapeControl(host), " ", "+"))
+}
+
+func username(r *http.Request) string {
+	if r.URL != nil && r.URL.User != nil {
+		return r.URL.User.Username()
+	}
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	return ""
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// quoteCLF quotes s for CLF, escaping embedded quotes, backslashes, and
+// control characters.
+func quoteCLF(s string) string {
+	if s == "" {
+		return `"-"`
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(escapeControl(s), `"`, `\"`) + `"`
+}
+
+// w3cValue renders s as a W3C field, where spaces are encoded as "+" and
+// control characters are escaped.
+func w3cValue(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return strings.ReplaceAll(escapeControl(s), " ", "+")
+}
+
+// escapeControl writes control bytes in s, such as CR and LF, as \xhh the way
+// Apache does, so client values can never start a forged line. Backslashes
+// are left to the caller.
+func escapeControl(s string) string {
+	i := strings.IndexFunc(s, isControlByte)
+	if i < 0 {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	b.WriteString(s[:i])
+	for ; i < len(s); i++ {
+		if c := s[i]; c < 0x20 || c == 0x7f {
+			fmt.Fprintf(&b, `\x%02x`, c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+func isControlByte(r rune) bool {
+	return r < 0x20 || r == 0x7f
+}
+
+// responseRecorder captures the status code and body size of a response.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *responseRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *responseRecorder) Write(p []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (r *responseRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *responseRecorder) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
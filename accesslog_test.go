@@ -0,0 +1,83 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func serveAccessLogged(format sugarzero.AccessLogFormat) string {
+	var out bytes.Buffer
+	handler := sugarzero.AccessLogMiddleware(&out, format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders?id=7", nil)
+	req.RemoteAddr = "10.0.0.1:51234"
+	req.SetBasicAuth("frank", "secret")
+	req.Header.Set("User-Agent", `curl/8.0 "test"`)
+	req.Header.Set("Referer", "https://shop.example/cart")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	return out.String()
+}
+
+func TestAccessLogCommonAndCombined(t *testing.T) {
+	common := regexp.MustCompile(`^10\.0\.0\.1 - "frank" \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "POST /orders\?id=7 HTTP/1\.1" 201 7\n$`)
+	if line := serveAccessLogged(sugarzero.AccessLogCommon); !common.MatchString(line) {
+		t.Fatalf("unexpected common log line %q", line)
+	}
+
+	line := serveAccessLogged(sugarzero.AccessLogCombined)
+	if !strings.HasSuffix(line, `201 7 "https://shop.example/cart" "curl/8.0 \"test\""`+"\n") {
+		t.Fatalf("unexpected combined log line %q", line)
+	}
+}
+
+func TestAccessLogW3C(t *testing.T) {
+	lines := strings.Split(strings.TrimSpace(serveAccessLogged(sugarzero.AccessLogW3C)), "\n")
+	if len(lines) != 3 || lines[0] != "#Version: 1.0" || !strings.HasPrefix(lines[1], "#Fields: date time c-ip") {
+		t.Fatalf("expected directives before the entry, got %q", lines)
+	}
+	fields := strings.Fields(lines[2])
+	if len(fields) != 12 {
+		t.Fatalf("expected 12 fields, got %q", fields)
+	}
+	if fields[2] != "10.0.0.1" || fields[3] != "frank" || fields[4] != "POST" || fields[5] != "/orders" || fields[6] != "id=7" || fields[7] != "201" || fields[8] != "7" {
+		t.Fatalf("unexpected W3C fields %q", fields)
+	}
+	if fields[10] != `curl/8.0+"test"` {
+		t.Fatalf("expected spaces in user agent to be encoded, got %q", fields[10])
+	}
+}
+
+func TestAccessLogEscapesClientValues(t *testing.T) {
+	for _, format := range []sugarzero.AccessLogFormat{sugarzero.AccessLogCombined, sugarzero.AccessLogW3C} {
+		var out bytes.Buffer
+		handler := sugarzero.AccessLogMiddleware(&out, format)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RequestURI = "/a\r\n127.0.0.1 - admin"
+		req.RemoteAddr = "10.0.0.1\n"
+		req.SetBasicAuth("eve\n10.0.0.2 - admin", "secret")
+		req.Header.Set("User-Agent", "curl\r\nforged")
+		req.Header.Set("Referer", "https://x.example/\x00")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		line := strings.TrimPrefix(out.String(), "#Version: 1.0\n#Fields: ")
+		if n := strings.Count(line, "\n"); format == sugarzero.AccessLogW3C && n != 2 || format != sugarzero.AccessLogW3C && n != 1 {
+			t.Fatalf("expected client values not to break lines, got %q", out.String())
+		}
+		if !strings.Contains(line, `eve\x0a10.0.0.2`) || !strings.Contains(line, `curl\x0d\x0aforged`) || !strings.Contains(line, `x.example/\x00`) {
+			t.Fatalf("expected control characters to be escaped, got %q", line)
+		}
+		if format == sugarzero.AccessLogCombined && !strings.Contains(line, ` - "eve\x0a10.0.0.2 - admin" [`) {
+			t.Fatalf("expected a quoted user field, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,486 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultAckMaxInFlight is the number of entries an AckWriter publishes
+	// before waiting for an acknowledgment.
+	DefaultAckMaxInFlight = 100
+
+	ackPublishTimeout = 30 * time.Second
+)
+
+// Publisher delivers entries to a backend such as Kafka or Loki. Publish must
+// return nil only once the backend has acknowledged every entry, e.g. after a
+// Kafka produce with acks=all or a successful Loki push. Errors are retried
+// unless they wrap a PermanentError, e.g. for a batch the backend rejects as
+// malformed, which retrying cannot fix.
+type Publisher interface {
+	Publish(ctx context.Context, entries [][]byte) error
+}
+
+// PermanentError marks a publish error that retrying cannot fix, such as a
+// 4xx response. AckWriter drops the batch instead of retrying it.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps err in a PermanentError; a nil err stays nil.
+// Example: return sugarzero.Permanent(fmt.Errorf("invalid batch: %w", err))
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// PublisherFunc adapts a function to the Publisher interface.
+type PublisherFunc func(ctx context.Context, entries [][]byte) error
+
+// Publish calls f(ctx, entries).
+func (f PublisherFunc) Publish(ctx context.Context, entries [][]byte) error {
+	return f(ctx, entries)
+}
+
+// AckWriter delivers entries through a Publisher and removes them from its
+// queue only after they are acknowledged; failed batches are retried with
+// exponential backoff. Unlike NetworkWriter it never drops entries because of
+// load: Write blocks while the queue is full. Use it for audit streams where
+// fire-and-forget delivery is not acceptable.
+//
+// Batches rejected with a PermanentError would block the queue forever, so
+// they are removed, counted in AckWriterStats.Dropped, reported like writer
+// errors, and written to the dead-letter writer set with SetDeadLetter.
+//
+// Entries are published in order, in batches of at most maxInFlight.
+type AckWriter struct {
+	publisher   Publisher
+	maxInFlight int
+	queue       ackQueue
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	closed bool
+	// stopped is set once the sender has exited.
+	stopped bool
+
+	closing chan struct{}
+	done    chan struct{}
+
+	// deadLetter receives permanently rejected entries; guarded by mu.
+	deadLetter io.Writer
+
+	acked    atomic.Uint64
+	failures atomic.Uint64
+	dropped  atomic.Uint64
+	lastErr  atomic.Value // errorValue
+}
+
+// AckWriterStats reports the delivery counters of an AckWriter.
+type AckWriterStats struct {
+	// Pending is the number of entries not yet acknowledged.
+	Pending int
+	Acked   uint64
+	// Failures counts rejected or failed publish attempts.
+	Failures uint64
+	// Dropped counts entries removed after a PermanentError.
+	Dropped   uint64
+	LastError error
+}
+
+// ackQueue stores entries until they are acknowledged.
+type ackQueue interface {
+	push(entry []byte) error
+	peekN(n int) ([][]byte, error)
+	discardN(n int) error
+	len() int
+	close() error
+}
+
+// NewAckWriter returns an AckWriter that keeps up to queueSize unacknowledged
+// entries in memory. A maxInFlight <= 0 uses DefaultAckMaxInFlight and a
+// queueSize <= 0 uses DefaultNetworkQueueSize.
+func NewAckWriter(publisher Publisher, maxInFlight, queueSize int) *AckWriter {
+	if queueSize <= 0 {
+		queueSize = DefaultNetworkQueueSize
+	}
+	return newAckWriter(publisher, maxInFlight, &memoryQueue{capacity: queueSize})
+}
+
+// NewSpooledAckWriter is NewAckWriter with the queue kept in a write-ahead
+// log in dir, as for NewSpooledNetworkWriter, so unacknowledged entries
+// survive restarts. A maxBytes <= 0 uses DefaultSpoolBytes.
+func NewSpooledAckWriter(publisher Publisher, maxInFlight int, dir string, maxBytes int64) (*AckWriter, error) {
+	if maxBytes <= 0 {
+		maxBytes = DefaultSpoolBytes
+	}
+	spool, err := openDiskSpool(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	return newAckWriter(publisher, maxInFlight, spool), nil
+}
+
+func newAckWriter(publisher Publisher, maxInFlight int, queue ackQueue) *AckWriter {
+	if maxInFlight <= 0 {
+		maxInFlight = DefaultAckMaxInFlight
+	}
+	w := &AckWriter{
+		publisher:   publisher,
+		maxInFlight: maxInFlight,
+		queue:       queue,
+		closing:     make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	w.cond = sync.NewCond(&w.mu)
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p, blocking while the queue is full.
+func (w *AckWriter) Write(p []byte) (int, error) {
+	entry := append([]byte(nil), p...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for !w.closed {
+		err := w.queue.push(entry)
+		if err == nil {
+			w.cond.Broadcast()
+			return len(p), nil
+		}
+		if !errors.Is(err, errQueueFull) {
+			return 0, err
+		}
+		w.cond.Wait()
+	}
+	return 0, ErrWriterClosed
+}
+
+// SetDeadLetter sets the writer receiving entries the publisher rejects
+// with a PermanentError, one entry per Write, e.g. a local file to inspect
+// and replay them later. A nil w only drops them.
+func (w *AckWriter) SetDeadLetter(deadLetter io.Writer) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.deadLetter = deadLetter
+}
+
+// Flush blocks until every queued entry is acknowledged, or the writer has
+// stopped, and returns the last delivery error if entries remain.
+func (w *AckWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.queue.len() > 0 && !w.stopped {
+		w.cond.Wait()
+	}
+	if w.queue.len() > 0 {
+		return fmt.Errorf("sugarzero: %d entries not acknowledged: %w", w.queue.len(), w.lastError())
+	}
+	return nil
+}
+
+// Stats returns a snapshot of the writer's delivery counters.
+func (w *AckWriter) Stats() AckWriterStats {
+	w.mu.Lock()
+	pending := w.queue.len()
+	w.mu.Unlock()
+	return AckWriterStats{
+		Pending:   pending,
+		Acked:     w.acked.Load(),
+		Failures:  w.failures.Load(),
+		Dropped:   w.dropped.Load(),
+		LastError: w.lastError(),
+	}
+}
+
+// Health implements HealthReporter. The writer is unhealthy while the last
+// publish attempt failed.
+func (w *AckWriter) Health() SinkStatus {
+	stats := w.Stats()
+	name := fmt.Sprintf("%T", w.publisher)
+	if s, ok := w.publisher.(fmt.Stringer); ok {
+		name = s.String()
+	}
+	return SinkStatus{
+		Name:      name,
+		Healthy:   stats.LastError == nil,
+		Queued:    stats.Pending,
+		LastError: errorString(stats.LastError),
+	}
+}
+
+// Close stops accepting entries and makes one attempt to publish the queued
+// ones. It returns an error if entries remain unacknowledged; a spooled
+// writer keeps them on disk for the next writer opened on its directory.
+func (w *AckWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.closing)
+	w.cond.Broadcast()
+	w.mu.Unlock()
+
+	<-w.done
+	err := w.Flush()
+	if closeErr := w.queue.close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func (w *AckWriter) run() {
+	defer func() {
+		w.mu.Lock()
+		w.stopped = true
+		w.cond.Broadcast()
+		w.mu.Unlock()
+		close(w.done)
+	}()
+
+	backoff := networkMinBackoff
+	for {
+		w.mu.Lock()
+		for w.queue.len() == 0 && !w.closed {
+			w.cond.Wait()
+		}
+		if w.queue.len() == 0 {
+			w.mu.Unlock()
+			return
+		}
+		batch, err := w.queue.peekN(w.maxInFlight)
+		w.mu.Unlock()
+
+		if err == nil {
+			err = w.publish(batch)
+		}
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			w.failures.Add(1)
+			w.lastErr.Store(errorValue{err: err})
+			w.drop(batch, err)
+			backoff = networkMinBackoff
+			continue
+		}
+		if err != nil {
+			w.failures.Add(1)
+			w.lastErr.Store(errorValue{err: err})
+			if !waitOrClose(w.closing, backoff) {
+				return
+			}
+			backoff = min(backoff*2, networkMaxBackoff)
+			continue
+		}
+
+		backoff = networkMinBackoff
+		w.lastErr.Store(errorValue{})
+		w.acked.Add(uint64(len(batch)))
+		w.mu.Lock()
+		err = w.queue.discardN(len(batch))
+		w.cond.Broadcast()
+		w.mu.Unlock()
+		if err != nil {
+			reportWriteError(err)
+		}
+	}
+}
+
+// drop removes batch, which the publisher rejected permanently with err, and
+// hands it to the dead-letter writer.
+func (w *AckWriter) drop(batch [][]byte, err error) {
+	w.dropped.Add(uint64(len(batch)))
+	reportWriteError(fmt.Errorf("sugarzero: dropped %d entries rejected by %T: %w", len(batch), w.publisher, err))
+
+	w.mu.Lock()
+	deadLetter := w.deadLetter
+	w.mu.Unlock()
+	if deadLetter != nil {
+		for _, entry := range batch {
+			if _, err := deadLetter.Write(entry); err != nil {
+				reportWriteError(fmt.Errorf("sugarzero: failed to dead-letter entry: %w", err))
+				break
+			}
+		}
+	}
+
+	w.mu.Lock()
+	err = w.queue.discardN(len(batch))
+	w.cond.Broadcast()
+	w.mu.Unlock()
+	if err != nil {
+		reportWriteError(err)
+	}
+}
+
+func (w *AckWriter) publish(batch [][]byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), ackPublishTimeout)
+	defer cancel()
+	return w.publisher.Publish(ctx, batch)
+}
+
+func (w *AckWriter) lastError() error {
+	if v, ok := w.lastErr.Load().(errorValue); ok {
+		return v.err
+	}
+	return nil
+}
+
+// waitOrClose sleeps for d and reports whether closing is still open. Once
+// closing is closed, failed deliveries are not retried.
+func waitOrClose(closing <-chan struct{}, d time.Duration) bool {
+	select {
+	case <-closing:
+		return false
+	default:
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-closing:
+		return false
+	}
+}
+
+// entryTime returns the timestamp of an encoded entry, or the current time.
+func entryTime(entry []byte) time.Time {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(entry, &fields) == nil {
+		var value string
+		if json.Unmarshal(fields[zerolog.TimestampFieldName], &value) == nil {
+			if t, err := time.Parse(zerolog.TimeFieldFormat, value); err == nil {
+				return t
+			}
+		}
+	}
+	return time.Now()
+}
+
+// memoryQueue is a bounded in-memory ackQueue; AckWriter guards it with its
+// mutex.
+type memoryQueue struct {
+	entries  [][]byte
+	capacity int
+}
+
+func (q *memoryQueue) push(entry []byte) error {
+	if len(q.entries) >= q.capacity {
+		return errQueueFull
+	}
+	q.entries = append(q.entries, entry)
+	return nil
+}
+
+func (q *memoryQueue) peekN(n int) ([][]byte, error) {
+	return q.entries[:min(n, len(q.entries)):min(n, len(q.entries))], nil
+}
+
+func (q *memoryQueue) discardN(n int) error {
+	n = min(n, len(q.entries))
+	clear(q.entries[:n])
+	q.entries = q.entries[n:]
+	return nil
+}
+
+func (q *memoryQueue) len() int {
+	return len(q.entries)
+}
+
+func (q *memoryQueue) close() error {
+	return nil
+}
+
+// LokiPublisher pushes entries to Grafana Loki's HTTP push API. Loki
+// acknowledges a push with a 2xx response once the entries are ingested.
+// Other 4xx responses than 408 and 429, e.g. for entries too old or too
+// large, are returned as a PermanentError.
+type LokiPublisher struct {
+	// URL is the push endpoint, e.g. "http://loki:3100/loki/api/v1/push".
+	URL string
+	// Labels identify the stream, e.g. {"app": "billing", "stream": "audit"}.
+	Labels map[string]string
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+}
+
+// String returns the push URL.
+func (p *LokiPublisher) String() string {
+	return p.URL
+}
+
+// Publish sends entries as one stream, timestamped with their "time" field
+// when it parses with zerolog.TimeFieldFormat, and with the current time
+// otherwise.
+func (p *LokiPublisher) Publish(ctx context.Context, entries [][]byte) error {
+	type lokiStream struct {
+		Stream map[string]string `json:"stream"`
+		Values [][2]string       `json:"values"`
+	}
+	stream := lokiStream{Stream: p.Labels, Values: make([][2]string, 0, len(entries))}
+	if stream.Stream == nil {
+		stream.Stream = map[string]string{}
+	}
+	for _, entry := range entries {
+		line := bytes.TrimRight(entry, "\n")
+		stream.Values = append(stream.Values, [2]string{
+			strconv.FormatInt(entryTime(line).UnixNano(), 10),
+			string(line),
+		})
+	}
+	body, err := json.Marshal(map[string][]lokiStream{"streams": {stream}})
+	if err != nil {
+		return fmt.Errorf("sugarzero: failed to encode loki push: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("sugarzero: invalid loki push request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := p.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sugarzero: loki push failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("sugarzero: loki push rejected: %s: %s", resp.Status, bytes.TrimSpace(msg))
+		if resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests {
+			return Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
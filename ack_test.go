@@ -0,0 +1,205 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestAckWriterRetriesUntilAcknowledged(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		attempts  int
+		delivered []string
+		batchSize []int
+	)
+	publisher := sugarzero.PublisherFunc(func(_ context.Context, entries [][]byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		attempts++
+		if attempts == 1 {
+			return errors.New("broker unavailable")
+		}
+		batchSize = append(batchSize, len(entries))
+		for _, entry := range entries {
+			delivered = append(delivered, string(entry))
+		}
+		return nil
+	})
+
+	writer := sugarzero.NewAckWriter(publisher, 2, 0)
+	for i := range 5 {
+		if _, err := writer.Write([]byte(fmt.Sprintf("entry-%d", i))); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 5 {
+		t.Fatalf("expected every entry to be delivered once, got %v", delivered)
+	}
+	for i, entry := range delivered {
+		if entry != fmt.Sprintf("entry-%d", i) {
+			t.Fatalf("expected entries in order, got %v", delivered)
+		}
+	}
+	for _, size := range batchSize {
+		if size > 2 {
+			t.Fatalf("expected at most 2 entries in flight, got batches %v", batchSize)
+		}
+	}
+	if stats := writer.Stats(); stats.Acked != 5 || stats.Failures != 1 || stats.Pending != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
+
+func TestAckWriterCloseReportsUnacknowledgedEntries(t *testing.T) {
+	publisher := sugarzero.PublisherFunc(func(context.Context, [][]byte) error {
+		return errors.New("broker unavailable")
+	})
+
+	dir := t.TempDir()
+	writer, err := sugarzero.NewSpooledAckWriter(publisher, 0, dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _ = writer.Write([]byte("audit-1"))
+	if err := writer.Close(); err == nil {
+		t.Fatal("expected Close to report the unacknowledged entry")
+	}
+
+	var replayed []string
+	writer, err = sugarzero.NewSpooledAckWriter(sugarzero.PublisherFunc(func(_ context.Context, entries [][]byte) error {
+		for _, entry := range entries {
+			replayed = append(replayed, string(entry))
+		}
+		return nil
+	}), 0, dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if len(replayed) != 1 || replayed[0] != "audit-1" {
+		t.Fatalf("expected the spooled entry to be replayed, got %v", replayed)
+	}
+}
+
+func TestLokiPublisherPushesStream(t *testing.T) {
+	var push struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/missing":
+			http.NotFound(w, r)
+			return
+		case "/busy":
+			http.Error(w, "slow down", http.StatusTooManyRequests)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &push); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	publisher := &sugarzero.LokiPublisher{URL: server.URL, Labels: map[string]string{"stream": "audit"}}
+	entry := []byte(`{"time":"2024-03-01T12:30:00Z","message":"granted"}` + "\n")
+	if err := publisher.Publish(context.Background(), [][]byte{entry}); err != nil {
+		t.Fatalf("unexpected publish error: %v", err)
+	}
+
+	if len(push.Streams) != 1 || push.Streams[0].Stream["stream"] != "audit" {
+		t.Fatalf("unexpected push %+v", push)
+	}
+	value := push.Streams[0].Values[0]
+	if value[0] != "1709296200000000000" || value[1] != `{"time":"2024-03-01T12:30:00Z","message":"granted"}` {
+		t.Fatalf("unexpected value %v", value)
+	}
+
+	failing := &sugarzero.LokiPublisher{URL: server.URL + "/missing"}
+	var permanent *sugarzero.PermanentError
+	if err := failing.Publish(context.Background(), [][]byte{entry}); !errors.As(err, &permanent) {
+		t.Fatalf("expected a rejected push to fail permanently, got %v", err)
+	}
+	busy := &sugarzero.LokiPublisher{URL: server.URL + "/busy"}
+	if err := busy.Publish(context.Background(), [][]byte{entry}); err == nil || errors.As(err, &permanent) {
+		t.Fatalf("expected a throttled push to fail and be retried, got %v", err)
+	}
+}
+
+func TestAckWriterDropsPermanentlyRejectedEntries(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		delivered []string
+	)
+	publisher := sugarzero.PublisherFunc(func(_ context.Context, entries [][]byte) error {
+		if string(entries[0]) == "malformed" {
+			return sugarzero.Permanent(errors.New("invalid entry"))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		for _, entry := range entries {
+			delivered = append(delivered, string(entry))
+		}
+		return nil
+	})
+
+	var reported []error
+	previous := zerolog.ErrorHandler
+	zerolog.ErrorHandler = func(err error) {
+		reported = append(reported, err)
+	}
+	t.Cleanup(func() {
+		zerolog.ErrorHandler = previous
+	})
+
+	writer := sugarzero.NewAckWriter(publisher, 1, 0)
+	var deadLetter bytes.Buffer
+	writer.SetDeadLetter(&deadLetter)
+	for _, entry := range []string{"valid-1", "malformed", "valid-2"} {
+		if _, err := writer.Write([]byte(entry)); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("expected the rejected entry not to block the queue, got %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(delivered) != 2 || delivered[0] != "valid-1" || delivered[1] != "valid-2" {
+		t.Fatalf("expected the valid entries to be delivered, got %v", delivered)
+	}
+	if stats := writer.Stats(); stats.Dropped != 1 || stats.Acked != 2 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+	if deadLetter.String() != "malformed" || len(reported) != 1 {
+		t.Fatalf("expected the entry to be dead-lettered and reported, got %q and %v", deadLetter.String(), reported)
+	}
+}
@@ -0,0 +1,188 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultAlertThrottle is the minimum interval between alerts with the
+	// same dedup key.
+	DefaultAlertThrottle = 5 * time.Minute
+	// DefaultAlertName is the alertname label of alerts raised from entries.
+	DefaultAlertName = "LogEntry"
+
+	alertPostTimeout = 5 * time.Second
+	// alertPruneSize is the number of throttled keys above which expired keys
+	// are dropped.
+	alertPruneSize = 1024
+)
+
+// Alert is an alert in the Alertmanager v2 API.
+type Alert struct {
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations,omitempty"`
+	StartsAt     time.Time         `json:"startsAt"`
+	GeneratorURL string            `json:"generatorURL,omitempty"`
+}
+
+// AlertmanagerSink is a Sink that turns critical entries into Prometheus
+// Alertmanager alerts, for log-triggered paging without a log backend.
+//
+// Each alert is labelled with alertname, level, and dedup_key, which is the
+// entry's dedup_key field (see WithDedupKey) or else its message, so
+// Alertmanager groups repeats of the same event. Alerts with the same dedup
+// key are sent at most once per Throttle. They carry no end time, so
+// Alertmanager resolves them after its resolve_timeout once they stop
+// repeating.
+//
+// Alerts are posted synchronously, so a fatal entry is delivered before the
+// process exits; wrap the sink with NewAsyncWriter where that matters less
+// than latency.
+// Example: WithSinks(&AlertmanagerSink{URL: "http://alertmanager:9093/api/v2/alerts"})
+type AlertmanagerSink struct {
+	// URL is the alerts endpoint, e.g. "http://alertmanager:9093/api/v2/alerts".
+	URL string
+	// Filter selects the entries that raise alerts; nil selects error level
+	// and above, including custom levels such as critical.
+	Filter *Filter
+	// AlertName is the alertname label; "" uses DefaultAlertName.
+	AlertName string
+	// Labels are added to every alert, e.g. {"service": "billing"}.
+	Labels map[string]string
+	// GeneratorURL links alerts back to their source, e.g. a log search.
+	GeneratorURL string
+	// Throttle is the minimum interval between alerts with the same dedup
+	// key; zero uses DefaultAlertThrottle.
+	Throttle time.Duration
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+}
+
+// String returns the alerts URL.
+func (s *AlertmanagerSink) String() string {
+	return s.URL
+}
+
+// WriteEntry posts an alert for entry if it matches the filter and its dedup
+// key is not throttled.
+func (s *AlertmanagerSink) WriteEntry(level zerolog.Level, entry []byte) error {
+	if s.Filter != nil {
+		if !s.Filter.Match(level, entry) {
+			return nil
+		}
+	} else if level < zerolog.ErrorLevel || level > zerolog.PanicLevel {
+		return nil
+	}
+
+	parsed, err := ParseEntry(entry)
+	if err != nil {
+		return err
+	}
+	key, _ := parsed.Fields[DedupKeyFieldName].(string)
+	if key == "" {
+		key = parsed.Message
+	}
+	if !s.allow(key, time.Now()) {
+		return nil
+	}
+
+	alert := s.alert(level, key, parsed, entry)
+	ctx, cancel := context.WithTimeout(context.Background(), alertPostTimeout)
+	defer cancel()
+	return s.post(ctx, []Alert{alert})
+}
+
+// allow reports whether an alert for key may be sent at now, and records it.
+func (s *AlertmanagerSink) allow(key string, now time.Time) bool {
+	throttle := s.Throttle
+	if throttle <= 0 {
+		throttle = DefaultAlertThrottle
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.sent[key]; ok && now.Sub(last) < throttle {
+		return false
+	}
+	if s.sent == nil {
+		s.sent = make(map[string]time.Time)
+	}
+	if len(s.sent) >= alertPruneSize {
+		maps.DeleteFunc(s.sent, func(_ string, last time.Time) bool {
+			return now.Sub(last) >= throttle
+		})
+	}
+	s.sent[key] = now
+	return true
+}
+
+func (s *AlertmanagerSink) alert(level zerolog.Level, key string, parsed Entry, raw []byte) Alert {
+	name := s.AlertName
+	if name == "" {
+		name = DefaultAlertName
+	}
+	levelName := parsed.Level
+	if levelName == "" {
+		levelName = level.String()
+	}
+
+	labels := make(map[string]string, len(s.Labels)+3)
+	maps.Copy(labels, s.Labels)
+	labels["alertname"] = name
+	labels[zerolog.LevelFieldName] = levelName
+	labels[DedupKeyFieldName] = key
+
+	startsAt := parsed.Time
+	if startsAt.IsZero() {
+		startsAt = time.Now()
+	}
+	return Alert{
+		Labels: labels,
+		Annotations: map[string]string{
+			"summary":     parsed.Message,
+			"description": string(bytes.TrimSpace(raw)),
+		},
+		StartsAt:     startsAt,
+		GeneratorURL: s.GeneratorURL,
+	}
+}
+
+func (s *AlertmanagerSink) post(ctx context.Context, alerts []Alert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return fmt.Errorf("sugarzero: failed to encode alerts: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("sugarzero: invalid alertmanager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sugarzero: alertmanager post failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sugarzero: alertmanager rejected alerts: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package sugarzero_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestAlertmanagerSink(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		alerts []sugarzero.Alert
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []sugarzero.Alert
+		if err := json.NewDecoder(r.Body).Decode(&batch); err != nil {
+			t.Errorf("invalid alerts: %v", err)
+		}
+		mu.Lock()
+		alerts = append(alerts, batch...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithSinks(&sugarzero.AlertmanagerSink{
+			URL:    server.URL,
+			Labels: map[string]string{"service": "billing"},
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Warn(ctx, "slow query")
+	sugarzero.Error(sugarzero.WithDedupKey(ctx, "db-down"), "database unreachable")
+	sugarzero.Error(sugarzero.WithDedupKey(ctx, "db-down"), "database unreachable")
+	sugarzero.Error(ctx, "payment provider down")
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(alerts) != 2 {
+		t.Fatalf("expected 2 throttled alerts, got %+v", alerts)
+	}
+	first := alerts[0]
+	if first.Labels["alertname"] != sugarzero.DefaultAlertName || first.Labels["dedup_key"] != "db-down" ||
+		first.Labels["service"] != "billing" || first.Labels["level"] != "error" {
+		t.Fatalf("unexpected labels: %v", first.Labels)
+	}
+	if first.Annotations["summary"] != "database unreachable" || first.StartsAt.IsZero() {
+		t.Fatalf("unexpected alert: %+v", first)
+	}
+	if alerts[1].Labels["dedup_key"] != "payment provider down" {
+		t.Fatalf("expected the message as dedup key, got %v", alerts[1].Labels)
+	}
+}
+
+func TestAlertmanagerSinkFilterAndErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad alert", http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := &sugarzero.AlertmanagerSink{URL: server.URL, Filter: sugarzero.MustParseFilter("page == true")}
+	if err := sink.WriteEntry(zerolog.ErrorLevel, []byte(`{"level":"error","message":"quiet"}`)); err != nil {
+		t.Fatalf("expected unmatched entry to be skipped, got %v", err)
+	}
+	if err := sink.WriteEntry(zerolog.InfoLevel, []byte(`{"level":"info","message":"loud","page":true}`)); err == nil {
+		t.Fatal("expected the rejected post to fail")
+	}
+}
@@ -0,0 +1,270 @@
+package sugarzero
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultAsyncQueueSize is the number of entries buffered by an AsyncWriter
+// when no queue size is provided.
+const DefaultAsyncQueueSize = 4096
+
+// DegradationPolicy decides which entries an AsyncWriter sheds as its queue
+// fills up. Thresholds are fractions of the queue capacity in [0, 1]; a
+// threshold of 0 disables shedding for that level. Warn and above are never
+// dropped unless NeverBlock is set: when the queue is full, writing them
+// blocks until there is room.
+type DegradationPolicy struct {
+	// DropDebugAt is the fill ratio from which trace and debug entries are dropped.
+	DropDebugAt float64
+	// DropInfoAt is the fill ratio from which info and unleveled entries are dropped.
+	DropInfoAt float64
+	// NeverBlock drops warn and above too when the queue is full, so a stuck
+	// writer can never stall its callers.
+	NeverBlock bool
+}
+
+// DefaultDegradationPolicy drops debug entries once the queue is half full
+// and info entries once it is 80% full.
+func DefaultDegradationPolicy() DegradationPolicy {
+	return DegradationPolicy{DropDebugAt: 0.5, DropInfoAt: 0.8}
+}
+
+// AsyncWriter decouples logging from a slow writer with a bounded queue
+// drained by a background goroutine. Under backpressure it degrades
+// gracefully according to its DegradationPolicy instead of blocking every
+// caller or dropping indiscriminately.
+type AsyncWriter struct {
+	next   io.Writer
+	policy DegradationPolicy
+
+	mu     sync.RWMutex
+	queue  chan asyncEntry
+	closed bool
+	done   chan struct{}
+
+	// Entries are numbered as they are queued; finished is the highest
+	// sequence up to which every entry has been written or dropped, and
+	// outOfOrder holds the sequences finished above it, for Flush
+	seqMu      sync.Mutex
+	enqueued   uint64
+	finished   uint64
+	outOfOrder map[uint64]struct{}
+	progress   *sync.Cond
+
+	written      atomic.Uint64
+	droppedDebug atomic.Uint64
+	droppedInfo  atomic.Uint64
+	droppedWarn  atomic.Uint64
+	lastErr      atomic.Value // errorValue
+	drops        dropNotice
+}
+
+type asyncEntry struct {
+	seq   uint64
+	level zerolog.Level
+	data  []byte
+}
+
+// AsyncWriterStats is a snapshot of an AsyncWriter's queue and counters.
+type AsyncWriterStats struct {
+	Queued       int
+	Capacity     int
+	Written      uint64
+	DroppedDebug uint64
+	DroppedInfo  uint64
+	// DroppedWarn counts warn and above entries dropped under NeverBlock.
+	DroppedWarn uint64
+	LastError   error
+}
+
+// Dropped returns the total number of shed entries.
+func (s AsyncWriterStats) Dropped() uint64 {
+	return s.DroppedDebug + s.DroppedInfo + s.DroppedWarn
+}
+
+// WithAsync buffers entries in an AsyncWriter in front of the configured
+// writers. A queueSize <= 0 uses DefaultAsyncQueueSize. Sync and critical
+// entries (WithSync) drain the queue before syncing the writers.
+func WithAsync(queueSize int, policy DegradationPolicy) Option {
+	return func(o *options) {
+		o.wrapWriter(func(next io.Writer) (io.Writer, error) {
+			w := NewAsyncWriter(next, queueSize, policy)
+			o.buffered = append(o.buffered, w)
+			return w, nil
+		})
+	}
+}
+
+// NewAsyncWriter starts an AsyncWriter in front of next. A queueSize <= 0
+// uses DefaultAsyncQueueSize.
+func NewAsyncWriter(next io.Writer, queueSize int, policy DegradationPolicy) *AsyncWriter {
+	if queueSize <= 0 {
+		queueSize = DefaultAsyncQueueSize
+	}
+	w := &AsyncWriter{
+		next:   next,
+		policy: policy,
+		queue:  make(chan asyncEntry, queueSize),
+		done:   make(chan struct{}),
+
+		outOfOrder: map[uint64]struct{}{},
+	}
+	w.progress = sync.NewCond(&w.seqMu)
+	go w.run()
+	return w
+}
+
+// Write queues an unleveled entry, which is treated like info.
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel queues a copy of p, shedding it if the queue is too full for
+// its level.
+func (w *AsyncWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if w.shed(level) {
+		w.drops.report("async", w.Stats().Dropped())
+		return len(p), nil
+	}
+
+	entry := asyncEntry{level: level, data: make([]byte, len(p))}
+	copy(entry.data, p)
+
+	w.seqMu.Lock()
+	w.enqueued++
+	entry.seq = w.enqueued
+	w.seqMu.Unlock()
+
+	if level >= zerolog.WarnLevel && level != zerolog.NoLevel && !w.policy.NeverBlock {
+		w.queue <- entry
+		return len(p), nil
+	}
+	select {
+	case w.queue <- entry:
+	default:
+		w.finish(entry.seq)
+		w.countDrop(level)
+		w.drops.report("async", w.Stats().Dropped())
+	}
+	return len(p), nil
+}
+
+// shed reports whether an entry at level must be dropped at the current
+// queue depth, counting the drop.
+func (w *AsyncWriter) shed(level zerolog.Level) bool {
+	fill := float64(len(w.queue)) / float64(cap(w.queue))
+	switch {
+	case level <= zerolog.DebugLevel:
+		if w.policy.DropDebugAt > 0 && fill >= w.policy.DropDebugAt {
+			w.droppedDebug.Add(1)
+			return true
+		}
+	case level == zerolog.InfoLevel || level == zerolog.NoLevel:
+		if w.policy.DropInfoAt > 0 && fill >= w.policy.DropInfoAt {
+			w.droppedInfo.Add(1)
+			return true
+		}
+	}
+	return false
+}
+
+func (w *AsyncWriter) countDrop(level zerolog.Level) {
+	switch {
+	case level <= zerolog.DebugLevel:
+		w.droppedDebug.Add(1)
+	case level >= zerolog.WarnLevel && level != zerolog.NoLevel:
+		w.droppedWarn.Add(1)
+	default:
+		w.droppedInfo.Add(1)
+	}
+}
+
+// Stats returns the current queue depth and delivery counters.
+func (w *AsyncWriter) Stats() AsyncWriterStats {
+	stats := AsyncWriterStats{
+		Queued:       len(w.queue),
+		Capacity:     cap(w.queue),
+		Written:      w.written.Load(),
+		DroppedDebug: w.droppedDebug.Load(),
+		DroppedInfo:  w.droppedInfo.Load(),
+		DroppedWarn:  w.droppedWarn.Load(),
+	}
+	if v, ok := w.lastErr.Load().(errorValue); ok {
+		stats.LastError = v.err
+	}
+	return stats
+}
+
+// Flush blocks until every entry queued before the call has been written.
+// Entries queued while it waits are not waited for, so steady logging can
+// never stall it.
+func (w *AsyncWriter) Flush() error {
+	w.seqMu.Lock()
+	defer w.seqMu.Unlock()
+	target := w.enqueued
+	for w.finished < target {
+		w.progress.Wait()
+	}
+	return nil
+}
+
+// Close stops accepting entries, writes the queued ones, and closes the
+// underlying writer if it implements io.Closer.
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	if closer, ok := w.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+	for entry := range w.queue {
+		if _, err := writeLevel(w.next, entry.level, entry.data); err != nil {
+			w.lastErr.Store(errorValue{err: err})
+		} else {
+			w.written.Add(1)
+		}
+		w.finish(entry.seq)
+	}
+}
+
+// finish marks the entry numbered seq as written or dropped, advancing
+// finished past every entry finished in sequence.
+func (w *AsyncWriter) finish(seq uint64) {
+	w.seqMu.Lock()
+	defer w.seqMu.Unlock()
+	if seq != w.finished+1 {
+		w.outOfOrder[seq] = struct{}{}
+		return
+	}
+	w.finished = seq
+	for {
+		if _, ok := w.outOfOrder[w.finished+1]; !ok {
+			break
+		}
+		delete(w.outOfOrder, w.finished+1)
+		w.finished++
+	}
+	w.progress.Broadcast()
+}
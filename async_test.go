@@ -0,0 +1,158 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// gatedWriter blocks every write until release is closed.
+type gatedWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	buf     bytes.Buffer
+}
+
+func (g *gatedWriter) Write(p []byte) (int, error) {
+	<-g.release
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.Write(p)
+}
+
+func (g *gatedWriter) String() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.buf.String()
+}
+
+func TestAsyncWriterShedsLowLevelsUnderBackpressure(t *testing.T) {
+	sink := &gatedWriter{release: make(chan struct{})}
+	w := sugarzero.NewAsyncWriter(sink, 10, sugarzero.DefaultDegradationPolicy())
+
+	// The first entry is picked up by the worker and blocks it
+	write := func(level zerolog.Level, msg string) {
+		if _, err := w.WriteLevel(level, []byte(msg+"\n")); err != nil {
+			t.Fatalf("WriteLevel failed: %v", err)
+		}
+	}
+	write(zerolog.WarnLevel, "blocker")
+	for i := 0; i < 9; i++ {
+		write(zerolog.InfoLevel, "info")
+	}
+	write(zerolog.DebugLevel, "debug-dropped")
+	write(zerolog.InfoLevel, "info-dropped")
+	write(zerolog.ErrorLevel, "error-kept")
+
+	stats := w.Stats()
+	if stats.Capacity != 10 {
+		t.Fatalf("expected capacity 10, got %d", stats.Capacity)
+	}
+	if stats.DroppedDebug != 1 || stats.DroppedInfo == 0 {
+		t.Fatalf("expected debug and info drops, got %+v", stats)
+	}
+
+	close(sink.release)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	out := sink.String()
+	if strings.Contains(out, "debug-dropped") || strings.Contains(out, "info-dropped") {
+		t.Fatalf("expected shed entries to be missing, got %s", out)
+	}
+	if !strings.Contains(out, "error-kept") {
+		t.Fatalf("expected error entry to be kept, got %s", out)
+	}
+	if stats := w.Stats(); stats.Written+stats.Dropped() != 13 {
+		t.Fatalf("expected every entry to be accounted for, got %+v", stats)
+	}
+	if _, err := w.Write([]byte("late\n")); err != sugarzero.ErrWriterClosed {
+		t.Fatalf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+// slowWriter takes a moment over every write.
+type slowWriter struct{}
+
+func (slowWriter) Write(p []byte) (int, error) {
+	time.Sleep(50 * time.Microsecond)
+	return len(p), nil
+}
+
+func TestAsyncWriterFlushUnderSteadyLogging(t *testing.T) {
+	w := sugarzero.NewAsyncWriter(slowWriter{}, 64, sugarzero.DegradationPolicy{})
+	defer w.Close()
+
+	// Keep the queue busy for the whole test, so it never drains
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					w.WriteLevel(zerolog.WarnLevel, []byte("steady\n"))
+				}
+			}
+		}()
+	}
+	defer func() {
+		close(stop)
+		wg.Wait()
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	flushed := make(chan struct{})
+	go func() {
+		w.Flush()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Flush to return while other goroutines keep logging")
+	}
+}
+
+func TestWithAsyncDrainsOnSync(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	sink := &countingSyncer{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(sink),
+		sugarzero.WithAsync(16, sugarzero.DefaultDegradationPolicy()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "queued")
+	sugarzero.ErrorCritical(ctx, "durable")
+
+	sink.mu.Lock()
+	out := sink.data.String()
+	sink.mu.Unlock()
+	if !strings.Contains(out, "queued") || !strings.Contains(out, "durable") {
+		t.Fatalf("expected queue to be drained before sync returned, got %s", out)
+	}
+	if sink.Syncs() != 1 {
+		t.Fatalf("expected one sync, got %d", sink.Syncs())
+	}
+}
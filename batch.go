@@ -0,0 +1,227 @@
+package sugarzero
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultBatchBytes is the batch size used by BatchWriter when none is given.
+const DefaultBatchBytes = 64 << 10
+
+// ErrBatchWriterClosed is returned by writes to a closed BatchWriter.
+var ErrBatchWriterClosed = errors.New("sugarzero: batch writer is closed")
+
+// BatchWriter collects newline-delimited entries and writes them to the
+// underlying writer in one call once a size or count limit is reached, or
+// after an interval, trading a little latency for far fewer syscalls when
+// writing to files or pipes at high rates. The underlying writer must accept
+// several entries per write.
+//
+// Entries are never split across writes, and keep their level: when the
+// underlying writer is a zerolog.LevelWriter, each run of consecutive entries
+// of the same level is written with WriteLevel. Write errors are reported
+// through zerolog.ErrorHandler, since the entry that triggered them was
+// written earlier.
+type BatchWriter struct {
+	next       io.Writer
+	maxBytes   int
+	maxEntries int
+	interval   time.Duration
+
+	mu      sync.Mutex
+	buf     []byte
+	entries int
+	runs    []batchRun
+	timer   *time.Timer
+	closed  bool
+	lastErr error
+}
+
+// batchRun is a run of consecutive buffered entries of the same level,
+// ending at end in the buffer.
+type batchRun struct {
+	level zerolog.Level
+	end   int
+}
+
+// WithBatching buffers entries in a BatchWriter in front of every configured
+// byte-stream writer: files, including os.Stdout and os.Stderr, buffers, and
+// GzipWriters, also when they are the destinations of an Output or a
+// LevelSplitWriter. Writers that handle one entry per write, such as sinks,
+// network writers, and TenantRouter, are left unbatched. Sync and critical
+// entries (WithSync) flush the batches first.
+// Example: NewWithOptions(ctx, "info", WithWriters(file), WithBatching(0, 0, 100*time.Millisecond))
+func WithBatching(maxBytes, maxEntries int, interval time.Duration) Option {
+	return func(o *options) {
+		o.batching = &batchConfig{maxBytes: maxBytes, maxEntries: maxEntries, interval: interval}
+	}
+}
+
+type batchConfig struct {
+	maxBytes   int
+	maxEntries int
+	interval   time.Duration
+}
+
+// batchStreams returns w with its byte-stream destinations batched, adding
+// the BatchWriters to o.buffered.
+func (o *options) batchStreams(w io.Writer) io.Writer {
+	switch w := w.(type) {
+	case *os.File, *bytes.Buffer, *bufio.Writer, *GzipWriter:
+		batched := NewBatchWriter(w, o.batching.maxBytes, o.batching.maxEntries, o.batching.interval)
+		o.buffered = append(o.buffered, batched)
+		return batched
+	case *outputWriter:
+		w.next = o.batchStreams(w.next)
+		return w
+	case *LevelSplitWriter:
+		return &LevelSplitWriter{low: o.batchStreams(w.low), high: o.batchStreams(w.high), threshold: w.threshold}
+	default:
+		return w
+	}
+}
+
+// NewBatchWriter returns a BatchWriter in front of next that flushes once
+// maxBytes bytes or maxEntries entries are buffered, or interval after the
+// first entry of a batch. A maxBytes <= 0 uses DefaultBatchBytes; maxEntries
+// and interval <= 0 disable the respective limit.
+func NewBatchWriter(next io.Writer, maxBytes, maxEntries int, interval time.Duration) *BatchWriter {
+	if maxBytes <= 0 {
+		maxBytes = DefaultBatchBytes
+	}
+	return &BatchWriter{
+		next:       next,
+		maxBytes:   maxBytes,
+		maxEntries: maxEntries,
+		interval:   interval,
+		buf:        make([]byte, 0, maxBytes),
+	}
+}
+
+// Write buffers one unleveled entry, adding a trailing newline if it lacks
+// one.
+func (w *BatchWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel buffers one entry at level, adding a trailing newline if it
+// lacks one.
+func (w *BatchWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, ErrBatchWriterClosed
+	}
+
+	size := len(p)
+	if size > 0 && p[size-1] != '\n' {
+		size++
+	}
+	if len(w.buf) > 0 && len(w.buf)+size > w.maxBytes {
+		w.flushLocked()
+	}
+
+	w.buf = append(w.buf, p...)
+	if size > len(p) {
+		w.buf = append(w.buf, '\n')
+	}
+	w.entries++
+	if n := len(w.runs); n > 0 && w.runs[n-1].level == level {
+		w.runs[n-1].end = len(w.buf)
+	} else {
+		w.runs = append(w.runs, batchRun{level: level, end: len(w.buf)})
+	}
+
+	if len(w.buf) >= w.maxBytes || (w.maxEntries > 0 && w.entries >= w.maxEntries) {
+		w.flushLocked()
+	} else if w.interval > 0 && w.timer == nil {
+		w.timer = time.AfterFunc(w.interval, w.flushOnTimer)
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered entries and returns the last write error.
+func (w *BatchWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.flushLocked()
+	return w.takeErrLocked()
+}
+
+// Sync flushes the batch and syncs the underlying writer.
+func (w *BatchWriter) Sync() error {
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return syncWriter(w.next)
+}
+
+// Close flushes the batch and closes the underlying writer if it implements
+// io.Closer. Later writes fail with ErrBatchWriterClosed.
+func (w *BatchWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.flushLocked()
+	err := w.takeErrLocked()
+	w.mu.Unlock()
+
+	if closer, ok := w.next.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (w *BatchWriter) flushOnTimer() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.timer = nil
+	w.flushLocked()
+}
+
+func (w *BatchWriter) flushLocked() {
+	if w.timer != nil {
+		w.timer.Stop()
+		w.timer = nil
+	}
+	if len(w.buf) == 0 {
+		return
+	}
+	if _, ok := w.next.(zerolog.LevelWriter); ok {
+		start := 0
+		for _, run := range w.runs {
+			w.writeLocked(run.level, w.buf[start:run.end])
+			start = run.end
+		}
+	} else {
+		w.writeLocked(zerolog.NoLevel, w.buf)
+	}
+	w.buf = w.buf[:0]
+	w.entries = 0
+	w.runs = w.runs[:0]
+}
+
+func (w *BatchWriter) writeLocked(level zerolog.Level, p []byte) {
+	if _, err := writeLevel(w.next, level, p); err != nil {
+		w.lastErr = err
+		reportWriteError(err)
+	}
+}
+
+func (w *BatchWriter) takeErrLocked() error {
+	err := w.lastErr
+	w.lastErr = nil
+	return err
+}
@@ -0,0 +1,191 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// countingWriter records every Write call.
+type countingWriter struct {
+	mu     sync.Mutex
+	writes []string
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.writes = append(w.writes, string(p))
+	return len(p), nil
+}
+
+func (w *countingWriter) snapshot() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.writes...)
+}
+
+func TestBatchWriterFlushesByCountAndSize(t *testing.T) {
+	next := &countingWriter{}
+	w := sugarzero.NewBatchWriter(next, 16, 3, 0)
+
+	for _, entry := range []string{"a\n", "b", "c\n"} {
+		if _, err := w.Write([]byte(entry)); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if writes := next.snapshot(); len(writes) != 1 || writes[0] != "a\nb\nc\n" {
+		t.Fatalf("expected one newline-delimited batch, got %q", writes)
+	}
+
+	// The second entry does not fit, so the first is flushed on its own
+	_, _ = w.Write([]byte(strings.Repeat("x", 10)))
+	_, _ = w.Write([]byte(strings.Repeat("y", 10)))
+	if writes := next.snapshot(); len(writes) != 2 || writes[1] != strings.Repeat("x", 10)+"\n" {
+		t.Fatalf("expected size-triggered flush, got %q", writes)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("close failed: %v", err)
+	}
+	if writes := next.snapshot(); len(writes) != 3 {
+		t.Fatalf("expected close to flush the remainder, got %q", writes)
+	}
+	if _, err := w.Write([]byte("late")); !errors.Is(err, sugarzero.ErrBatchWriterClosed) {
+		t.Fatalf("expected ErrBatchWriterClosed, got %v", err)
+	}
+}
+
+func TestBatchWriterFlushesAfterInterval(t *testing.T) {
+	next := &countingWriter{}
+	w := sugarzero.NewBatchWriter(next, 0, 0, 10*time.Millisecond)
+	t.Cleanup(func() {
+		_ = w.Close()
+	})
+
+	_, _ = w.Write([]byte("tick\n"))
+
+	deadline := time.Now().Add(time.Second)
+	for len(next.snapshot()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the interval to flush the batch")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestWithBatchingFlushesOnSync(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBatching(0, 0, 0),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "batched")
+	if buf.Len() != 0 {
+		t.Fatalf("expected entry to be buffered, got %q", buf.String())
+	}
+
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	entry := readLogEntry(t, &buf)
+	if entry["message"] != "batched" {
+		t.Fatalf("expected batched entry after Sync, got %v", entry)
+	}
+}
+
+func TestWithBatchingKeepsLevelRouting(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var stdout, stderr bytes.Buffer
+	split, err := sugarzero.NewLevelSplitWriter(&stdout, &stderr, "warn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(split),
+		sugarzero.WithBatching(0, 0, 0),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "info 1")
+	sugarzero.Info(ctx, "info 2")
+	sugarzero.Error(ctx, "error entry")
+	sugarzero.Info(ctx, "info 3")
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	if out := stdout.String(); strings.Count(out, "\n") != 3 || strings.Contains(out, "error entry") {
+		t.Fatalf("expected the info entries on stdout, got %s", out)
+	}
+	if out := stderr.String(); strings.Count(out, "\n") != 1 || !strings.Contains(out, "error entry") {
+		t.Fatalf("expected the error entry on stderr, got %s", out)
+	}
+	if got := readLogEntry(t, &stdout, 2)["message"]; got != "info 3" {
+		t.Fatalf("expected entries to keep their order, got %v", got)
+	}
+}
+
+func TestWithBatchingKeepsTenantRoutingPerEntry(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var shared bytes.Buffer
+	tenants := map[string]*bytes.Buffer{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&shared),
+		sugarzero.WithBatching(0, 10, 0),
+		sugarzero.WithTenantRouting("tenant_id", func(tenant string) (io.Writer, error) {
+			tenants[tenant] = &bytes.Buffer{}
+			return tenants[tenant], nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	for _, tenant := range []string{"acme", "globex", "acme", "initech"} {
+		sugarzero.Info(sugarzero.WithField(ctx, "tenant_id", tenant), "entry for "+tenant)
+	}
+	sugarzero.Info(ctx, "shared entry")
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	for tenant, want := range map[string]int{"acme": 2, "globex": 1, "initech": 1} {
+		buf, ok := tenants[tenant]
+		if !ok {
+			t.Fatalf("expected a writer for %s, got %v", tenant, tenants)
+		}
+		if got := strings.Count(buf.String(), "entry for "+tenant); got != want {
+			t.Fatalf("expected %d entries for %s, got %q", want, tenant, buf.String())
+		}
+	}
+	if !strings.Contains(shared.String(), "shared entry") {
+		t.Fatalf("expected the untenanted entry in the shared writer, got %q", shared.String())
+	}
+}
@@ -0,0 +1,258 @@
+// Package bench measures the cost of logging with a given sugarzero
+// configuration and compares the results against a stored baseline, so
+// applications can gate CI on logging performance regressions.
+//
+// From a test acting as a regression gate:
+//
+//	results, err := bench.Measure(opts...)
+//	regressions := bench.Compare(baseline, results, 0.15)
+//
+// From a benchmark, in a _test.go file:
+//
+//	func BenchmarkLogging(b *testing.B) {
+//		ctx, unregister, err := bench.NewContext(sugarzero.WithCoercion(sugarzero.DefaultCoercion()))
+//		if err != nil {
+//			b.Fatal(err)
+//		}
+//		defer unregister()
+//		for _, scenario := range bench.Scenarios {
+//			b.Run(scenario.Name, func(b *testing.B) {
+//				ctx, cleanup := scenario.Prepare(ctx)
+//				defer cleanup()
+//				b.ReportAllocs()
+//				for b.Loop() {
+//					scenario.Log(ctx)
+//				}
+//			})
+//		}
+//	}
+package bench
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// loggerName is the registry name of the logger under test, kept
+	// separate from the application's global logger.
+	loggerName = "sugarzero-bench"
+	// measureTime is how long Measure runs each scenario, like the default
+	// -benchtime of go test.
+	measureTime = time.Second
+)
+
+// Scenario is a realistic logging call pattern.
+type Scenario struct {
+	Name string
+	// Setup prepares the context passed to Log.
+	Setup func(ctx context.Context) (context.Context, func())
+	// Log performs a single log call.
+	Log func(ctx context.Context)
+}
+
+// Scenarios are the patterns measured by Run and Measure.
+var Scenarios = []Scenario{
+	{
+		Name: "NoFields",
+		Log: func(ctx context.Context) {
+			sugarzero.Info(ctx, "request handled")
+		},
+	},
+	{
+		Name: "TenContextFields",
+		Setup: func(ctx context.Context) (context.Context, func()) {
+			return sugarzero.WithFields(ctx,
+				"request_id", "req-123", "user_id", 789, "tenant", "acme",
+				"method", "GET", "path", "/orders", "status", 200,
+				"region", "eu-west-1", "version", "1.4.2", "attempt", 1, "cached", true,
+			), nil
+		},
+		Log: func(ctx context.Context) {
+			sugarzero.Info(ctx, "request handled")
+		},
+	},
+	{
+		Name: "Formatted",
+		Log: func(ctx context.Context) {
+			sugarzero.Infof(ctx, "handled %s %s in %dms", "GET", "/orders", 42)
+		},
+	},
+	{
+		Name: "WithTrace",
+		Setup: func(ctx context.Context) (context.Context, func()) {
+			provider := sdktrace.NewTracerProvider()
+			ctx, span := provider.Tracer("bench").Start(ctx, "request")
+			return ctx, func() {
+				span.End()
+				_ = provider.Shutdown(context.Background())
+			}
+		},
+		Log: func(ctx context.Context) {
+			sugarzero.Info(ctx, "request handled")
+		},
+	},
+}
+
+// Result is the measured cost of one scenario.
+type Result struct {
+	Name        string  `json:"name"`
+	NsPerOp     float64 `json:"ns_per_op"`
+	AllocsPerOp int64   `json:"allocs_per_op"`
+	BytesPerOp  int64   `json:"bytes_per_op"`
+}
+
+// NewContext registers the logger under test, built from opts with entries
+// discarded unless opts configure writers, and returns a context carrying it
+// and a function unregistering it.
+func NewContext(opts ...sugarzero.Option) (context.Context, func(), error) {
+	logger, err := sugarzero.Register(loggerName, "info", append([]sugarzero.Option{sugarzero.WithWriters(io.Discard)}, opts...)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("bench: create logger: %w", err)
+	}
+	return sugarzero.WithLogger(context.Background(), logger), func() {
+		sugarzero.Unregister(loggerName)
+	}, nil
+}
+
+// Prepare runs the scenario's Setup, if any, and returns the context to pass
+// to Log and a function releasing what Setup created.
+func (s Scenario) Prepare(ctx context.Context) (context.Context, func()) {
+	if s.Setup == nil {
+		return ctx, func() {}
+	}
+	ctx, cleanup := s.Setup(ctx)
+	if cleanup == nil {
+		cleanup = func() {}
+	}
+	return ctx, cleanup
+}
+
+// Measure runs every scenario against one logger built from opts, each for
+// about a second, and returns the results. It needs neither go test nor
+// -bench, so it can be called from a test comparing against a baseline.
+func Measure(opts ...sugarzero.Option) ([]Result, error) {
+	ctx, unregister, err := NewContext(opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer unregister()
+
+	results := make([]Result, 0, len(Scenarios))
+	for _, scenario := range Scenarios {
+		results = append(results, measure(ctx, scenario))
+	}
+	return results, nil
+}
+
+// measure runs scenario with a growing number of iterations until a run
+// takes measureTime, as testing.Benchmark does.
+func measure(ctx context.Context, scenario Scenario) Result {
+	ctx, cleanup := scenario.Prepare(ctx)
+	defer cleanup()
+
+	n := 1
+	for {
+		elapsed, allocs, bytes := run(ctx, scenario, n)
+		if elapsed >= measureTime || n >= 1e9 {
+			return Result{
+				Name:        scenario.Name,
+				NsPerOp:     float64(elapsed.Nanoseconds()) / float64(n),
+				AllocsPerOp: int64(allocs) / int64(n),
+				BytesPerOp:  int64(bytes) / int64(n),
+			}
+		}
+		// Aim 20% past measureTime, growing at most 100x per round
+		next := n * 100
+		if perOp := elapsed.Nanoseconds() / int64(n); perOp > 0 {
+			next = min(next, int(int64(measureTime)*6/5/perOp))
+		}
+		n = max(next, n+1)
+	}
+}
+
+// run calls scenario.Log n times and returns the time taken and the number
+// and bytes of allocations made.
+func run(ctx context.Context, scenario Scenario, n int) (time.Duration, uint64, uint64) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for range n {
+		scenario.Log(ctx)
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+	return elapsed, after.Mallocs - before.Mallocs, after.TotalAlloc - before.TotalAlloc
+}
+
+// Regression reports a metric that got worse than the baseline allows.
+type Regression struct {
+	Name     string
+	Metric   string
+	Baseline float64
+	Current  float64
+}
+
+func (r Regression) String() string {
+	return fmt.Sprintf("%s %s: %.1f -> %.1f (%+.0f%%)", r.Name, r.Metric, r.Baseline, r.Current, 100*(r.Current/r.Baseline-1))
+}
+
+// Compare reports every scenario whose time or allocations per operation
+// exceed the baseline by more than tolerance, a fraction such as 0.1 for 10%.
+// Any increase in allocations from a baseline of zero is a regression.
+// Scenarios missing from the baseline are ignored.
+func Compare(baseline, current []Result, tolerance float64) []Regression {
+	previous := make(map[string]Result, len(baseline))
+	for _, result := range baseline {
+		previous[result.Name] = result
+	}
+
+	var regressions []Regression
+	for _, result := range current {
+		base, ok := previous[result.Name]
+		if !ok {
+			continue
+		}
+		if exceeds(base.NsPerOp, result.NsPerOp, tolerance) {
+			regressions = append(regressions, Regression{Name: result.Name, Metric: "ns/op", Baseline: base.NsPerOp, Current: result.NsPerOp})
+		}
+		if exceeds(float64(base.AllocsPerOp), float64(result.AllocsPerOp), tolerance) {
+			regressions = append(regressions, Regression{Name: result.Name, Metric: "allocs/op", Baseline: float64(base.AllocsPerOp), Current: float64(result.AllocsPerOp)})
+		}
+	}
+	return regressions
+}
+
+func exceeds(baseline, current, tolerance float64) bool {
+	if baseline == 0 {
+		return current > 0
+	}
+	return current > baseline*(1+tolerance)
+}
+
+// ReadBaseline decodes results written by WriteBaseline.
+func ReadBaseline(r io.Reader) ([]Result, error) {
+	var results []Result
+	if err := json.NewDecoder(r).Decode(&results); err != nil {
+		return nil, fmt.Errorf("bench: read baseline: %w", err)
+	}
+	return results, nil
+}
+
+// WriteBaseline encodes results as indented JSON.
+func WriteBaseline(w io.Writer, results []Result) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(results); err != nil {
+		return fmt.Errorf("bench: write baseline: %w", err)
+	}
+	return nil
+}
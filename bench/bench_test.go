@@ -0,0 +1,80 @@
+package bench_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/bench"
+)
+
+func TestCompareFlagsRegressions(t *testing.T) {
+	baseline := []bench.Result{
+		{Name: "NoFields", NsPerOp: 100, AllocsPerOp: 0},
+		{Name: "Formatted", NsPerOp: 200, AllocsPerOp: 2},
+	}
+	current := []bench.Result{
+		{Name: "NoFields", NsPerOp: 105, AllocsPerOp: 1},
+		{Name: "Formatted", NsPerOp: 300, AllocsPerOp: 2},
+		{Name: "New", NsPerOp: 1000, AllocsPerOp: 10},
+	}
+
+	regressions := bench.Compare(baseline, current, 0.1)
+	if len(regressions) != 2 {
+		t.Fatalf("expected 2 regressions, got %v", regressions)
+	}
+	if regressions[0].Name != "NoFields" || regressions[0].Metric != "allocs/op" {
+		t.Fatalf("expected NoFields allocation regression, got %v", regressions[0])
+	}
+	if regressions[1].Name != "Formatted" || regressions[1].Metric != "ns/op" {
+		t.Fatalf("expected Formatted time regression, got %v", regressions[1])
+	}
+	if got := regressions[1].String(); got != "Formatted ns/op: 200.0 -> 300.0 (+50%)" {
+		t.Fatalf("unexpected regression text %q", got)
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	results := []bench.Result{{Name: "NoFields", NsPerOp: 98.5, AllocsPerOp: 0, BytesPerOp: 0}}
+
+	var buf bytes.Buffer
+	if err := bench.WriteBaseline(&buf, results); err != nil {
+		t.Fatalf("WriteBaseline failed: %v", err)
+	}
+	loaded, err := bench.ReadBaseline(&buf)
+	if err != nil {
+		t.Fatalf("ReadBaseline failed: %v", err)
+	}
+	if len(loaded) != 1 || loaded[0] != results[0] {
+		t.Fatalf("expected %v, got %v", results, loaded)
+	}
+}
+
+func TestMeasureReportsInvalidOptions(t *testing.T) {
+	_, err := bench.Measure(sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{}))
+	if err == nil {
+		t.Fatal("expected an error for an invalid sampling budget")
+	}
+	if names := sugarzero.NamedLoggers(); len(names) != 0 {
+		t.Fatalf("expected no logger left registered, got %v", names)
+	}
+}
+
+func BenchmarkScenarios(b *testing.B) {
+	ctx, unregister, err := bench.NewContext()
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer unregister()
+
+	for _, scenario := range bench.Scenarios {
+		b.Run(scenario.Name, func(b *testing.B) {
+			ctx, cleanup := scenario.Prepare(ctx)
+			defer cleanup()
+			b.ReportAllocs()
+			for b.Loop() {
+				scenario.Log(ctx)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+package sugarzero
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultBlobPutTimeout bounds a single BlobStore.Put call.
+	DefaultBlobPutTimeout = 10 * time.Second
+	// DefaultBlobQueueSize is the number of background uploads that may be
+	// pending; larger values are logged truncated while the queue is full.
+	DefaultBlobQueueSize = 64
+	// maxBlobRefs bounds the blobs remembered as already stored.
+	maxBlobRefs = 1024
+)
+
+// BlobStore stores field values that are too large to log inline. Put
+// returns a reference, typically a URL, that is logged in place of the data.
+// DirBlobStore, S3BlobStore, and GCSBlobStore are provided; other stores can
+// be plugged in by implementing Put with their client libraries.
+type BlobStore interface {
+	Put(ctx context.Context, key string, data []byte) (string, error)
+}
+
+// BlobLocator is implemented by stores that know the reference Put returns
+// for key before uploading. Their uploads run in the background, off the
+// logging goroutine; other stores are called synchronously.
+type BlobLocator interface {
+	Locate(key string) string
+}
+
+// BlobRef replaces an offloaded field value in the log entry.
+type BlobRef struct {
+	Ref    string `json:"blob_ref"`
+	SHA256 string `json:"sha256"`
+	Size   int    `json:"size"`
+}
+
+// WithBlobOffload stores string, []byte, and JSON field values larger than
+// threshold bytes in store and logs a BlobRef instead. Blobs are keyed by the
+// hex SHA-256 of their content, so repeated payloads are stored once. Values
+// are only offloaded for entries that are written. Each Put is bounded by
+// DefaultBlobPutTimeout; when it fails, the value is logged truncated to
+// threshold bytes, or, for background uploads, the error is reported like a
+// write error. Sync waits for pending background uploads.
+//
+// Payloads are redacted like the entry they are moved out of: the nested keys
+// of JSON payloads under WithKeyRedaction, and PII under WithPIIDetection.
+//
+// Values from JSON are truncated at DefaultJSONLimit before offloading; use
+// JSONWithLimit(key, v, 0) to offload complete payloads.
+// Example: NewWithOptions(ctx, "info", WithBlobOffload(NewDirBlobStore("/var/log/blobs"), 64<<10))
+func WithBlobOffload(store BlobStore, threshold int) Option {
+	return func(o *options) {
+		if store == nil || threshold <= 0 {
+			o.blobs = nil
+			return
+		}
+		o.blobs = &blobOffload{store: store, threshold: threshold}
+	}
+}
+
+type blobOffload struct {
+	store     BlobStore
+	threshold int
+	redaction *keyRedaction
+	detectors []PIIDetector
+
+	mu sync.Mutex
+	// refs maps the keys of stored or queued blobs to their reference.
+	refs    map[string]string
+	queue   []blobUpload
+	running bool
+	idle    *sync.Cond
+}
+
+type blobUpload struct {
+	key  string
+	data []byte
+}
+
+// withRedaction returns a copy of b redacting payloads with redaction and
+// detectors, or nil if b is nil.
+func (b *blobOffload) withRedaction(redaction *keyRedaction, detectors []PIIDetector) *blobOffload {
+	if b == nil {
+		return nil
+	}
+	offload := &blobOffload{store: b.store, threshold: b.threshold, redaction: redaction, detectors: detectors}
+	offload.idle = sync.NewCond(&offload.mu)
+	return offload
+}
+
+// offloadFields returns flat with oversized values replaced by blob
+// references. flat itself is never modified.
+func (l *ZeroLogger) offloadFields(flat []any) []any {
+	if l.blobs == nil {
+		return flat
+	}
+	var offloaded []any
+	for i := 0; i+1 < len(flat); i += 2 {
+		data, ok := blobData(flat[i+1])
+		if !ok || len(data) <= l.blobs.threshold {
+			continue
+		}
+		if offloaded == nil {
+			offloaded = append([]any(nil), flat...)
+		}
+		offloaded[i+1] = l.blobs.offload(data)
+	}
+	if offloaded == nil {
+		return flat
+	}
+	return offloaded
+}
+
+func (b *blobOffload) offload(data []byte) any {
+	data = b.redact(data)
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	b.mu.Lock()
+	ref, stored := b.refs[key]
+	if !stored {
+		if locator, ok := b.store.(BlobLocator); ok {
+			if len(b.queue) >= DefaultBlobQueueSize {
+				b.mu.Unlock()
+				return b.truncated(data, "offload queue full")
+			}
+			ref = locator.Locate(key)
+			b.remember(key, ref)
+			b.queue = append(b.queue, blobUpload{key: key, data: data})
+			if !b.running {
+				b.running = true
+				go b.upload()
+			}
+			stored = true
+		}
+	}
+	b.mu.Unlock()
+
+	if !stored {
+		var err error
+		if ref, err = b.put(key, data); err != nil {
+			return b.truncated(data, err.Error())
+		}
+		b.mu.Lock()
+		b.remember(key, ref)
+		b.mu.Unlock()
+	}
+	return BlobRef{Ref: ref, SHA256: key, Size: len(data)}
+}
+
+// remember records that the blob key is stored at ref. b.mu must be held.
+func (b *blobOffload) remember(key, ref string) {
+	if b.refs == nil || len(b.refs) >= maxBlobRefs {
+		b.refs = make(map[string]string)
+	}
+	b.refs[key] = ref
+}
+
+func (b *blobOffload) truncated(data []byte, reason string) string {
+	return fmt.Sprintf("%s...(truncated, %d bytes, offload failed: %s)", truncateUTF8(data, b.threshold), len(data), reason)
+}
+
+func (b *blobOffload) put(key string, data []byte) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultBlobPutTimeout)
+	defer cancel()
+	return b.store.Put(ctx, key, data)
+}
+
+// upload stores the queued blobs until the queue is empty.
+func (b *blobOffload) upload() {
+	for {
+		b.mu.Lock()
+		if len(b.queue) == 0 {
+			b.running = false
+			b.idle.Broadcast()
+			b.mu.Unlock()
+			return
+		}
+		next := b.queue[0]
+		b.queue = b.queue[1:]
+		b.mu.Unlock()
+
+		if _, err := b.put(next.key, next.data); err != nil {
+			b.mu.Lock()
+			delete(b.refs, next.key)
+			b.mu.Unlock()
+			reportWriteError(fmt.Errorf("sugarzero: failed to offload blob %s: %w", next.key, err))
+		}
+	}
+}
+
+// wait blocks until the queued uploads are done.
+func (b *blobOffload) wait() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.running {
+		b.idle.Wait()
+	}
+}
+
+// redact returns data with the values of redacted keys masked if it is JSON,
+// and detected PII masked. data itself is never modified.
+func (b *blobOffload) redact(data []byte) []byte {
+	if b.redaction != nil && json.Valid(data) {
+		data = b.redaction.redactJSON(data)
+	}
+	if masked := maskPII(b.detectors, data); masked != nil {
+		data = masked
+	}
+	return data
+}
+
+func blobData(v any) ([]byte, bool) {
+	switch value := v.(type) {
+	case string:
+		return []byte(value), true
+	case []byte:
+		return value, true
+	case json.RawMessage:
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+// DirBlobStore is a BlobStore that writes blobs as files in a local
+// directory, e.g. a volume collected alongside the logs. Blobs and the
+// directory are only accessible by the owner, as payloads may be sensitive.
+type DirBlobStore struct {
+	dir string
+}
+
+// NewDirBlobStore returns a DirBlobStore rooted at dir. The directory is
+// created on first use.
+func NewDirBlobStore(dir string) *DirBlobStore {
+	return &DirBlobStore{dir: dir}
+}
+
+// Put writes data to dir/key and returns its file:// URL.
+func (s *DirBlobStore) Put(_ context.Context, key string, data []byte) (string, error) {
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return "", fmt.Errorf("sugarzero: failed to create blob directory: %w", err)
+	}
+	if err := os.WriteFile(s.path(key), data, 0o600); err != nil {
+		return "", fmt.Errorf("sugarzero: failed to write blob: %w", err)
+	}
+	return s.Locate(key), nil
+}
+
+// Locate returns the file:// URL Put returns for key.
+func (s *DirBlobStore) Locate(key string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(s.path(key))}).String()
+}
+
+func (s *DirBlobStore) path(key string) string {
+	path := filepath.Join(s.dir, filepath.Base(key))
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
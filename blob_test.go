@@ -0,0 +1,264 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithBlobOffloadStoresLargeValues(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	dir := t.TempDir()
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(sugarzero.NewDirBlobStore(dir), 16),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	payload := strings.Repeat("x", 64)
+	ctx = sugarzero.WithFields(ctx,
+		"body", payload,
+		"small", "inline",
+		sugarzero.JSONWithLimit("doc", map[string]string{"payload": payload}, 0),
+	)
+	sugarzero.Info(ctx, "offloaded")
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	entry := readLogEntry(t, &buf)
+	if entry["small"] != "inline" {
+		t.Fatalf("expected small value inline, got %v", entry["small"])
+	}
+	for _, key := range []string{"body", "doc"} {
+		ref, ok := entry[key].(map[string]any)
+		if !ok {
+			t.Fatalf("expected %s to be a blob reference, got %v", key, entry[key])
+		}
+		location, err := url.Parse(ref["blob_ref"].(string))
+		if err != nil || location.Scheme != "file" {
+			t.Fatalf("expected file URL for %s, got %v", key, ref["blob_ref"])
+		}
+		stored, err := os.ReadFile(location.Path)
+		if err != nil {
+			t.Fatalf("failed to read blob for %s: %v", key, err)
+		}
+		if int(ref["size"].(float64)) != len(stored) {
+			t.Fatalf("expected size %v, got %d stored bytes", ref["size"], len(stored))
+		}
+	}
+}
+
+type failingBlobStore struct{}
+
+func (failingBlobStore) Put(context.Context, string, []byte) (string, error) {
+	return "", errors.New("bucket unavailable")
+}
+
+func TestWithBlobOffloadTruncatesWhenStoreFails(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(failingBlobStore{}, 4),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "body", "abcdefgh"), "offload failed")
+
+	entry := readLogEntry(t, &buf)
+	body, _ := entry["body"].(string)
+	if !strings.HasPrefix(body, "abcd...(truncated, 8 bytes") || !strings.Contains(body, "bucket unavailable") {
+		t.Fatalf("expected truncated value with error, got %q", body)
+	}
+}
+
+// memoryBlobStore keeps the last stored blob and counts Put calls.
+type memoryBlobStore struct {
+	data []byte
+	puts int
+}
+
+func (s *memoryBlobStore) Put(_ context.Context, key string, data []byte) (string, error) {
+	s.data = append([]byte(nil), data...)
+	s.puts++
+	return "mem://" + key, nil
+}
+
+func TestWithBlobOffloadStoresWrittenPayloadsOnce(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	store := &memoryBlobStore{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(store, 16),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithField(ctx, "body", strings.Repeat("x", 64))
+	sugarzero.Debug(ctx, "filtered out")
+	if store.puts != 0 {
+		t.Fatalf("expected no upload for a disabled entry, got %d", store.puts)
+	}
+
+	sugarzero.Info(ctx, "first")
+	sugarzero.Info(sugarzero.WithField(ctx, "attempt", 2), "derived")
+	sugarzero.Info(sugarzero.WithField(ctx, "attempt", 3), "derived again")
+	if store.puts != 1 {
+		t.Fatalf("expected the payload to be stored once, got %d puts", store.puts)
+	}
+	for i := 0; i < 3; i++ {
+		if ref, _ := readLogEntry(t, &buf, i)["body"].(map[string]any); ref["blob_ref"] == nil {
+			t.Fatalf("expected entry %d to reference the blob, got %v", i, readLogEntry(t, &buf, i))
+		}
+	}
+}
+
+// blockingBlobStore is a BlobLocator whose uploads wait for release.
+type blockingBlobStore struct {
+	release chan struct{}
+	stored  chan string
+}
+
+func (s *blockingBlobStore) Put(_ context.Context, key string, _ []byte) (string, error) {
+	<-s.release
+	s.stored <- key
+	return s.Locate(key), nil
+}
+
+func (s *blockingBlobStore) Locate(key string) string {
+	return "mem://" + key
+}
+
+func TestWithBlobOffloadUploadsLocatableStoresInBackground(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	store := &blockingBlobStore{release: make(chan struct{}), stored: make(chan string, 1)}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(store, 16),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// Returns while the upload is still blocked
+	sugarzero.Info(sugarzero.WithField(ctx, "body", strings.Repeat("x", 64)), "queued")
+	ref, _ := readLogEntry(t, &buf)["body"].(map[string]any)
+	if !strings.HasPrefix(fmt.Sprint(ref["blob_ref"]), "mem://") {
+		t.Fatalf("expected the located reference, got %v", ref)
+	}
+
+	close(store.release)
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	select {
+	case key := <-store.stored:
+		if ref["sha256"] != key {
+			t.Fatalf("expected %v to be uploaded, got %s", ref["sha256"], key)
+		}
+	default:
+		t.Fatal("expected Sync to wait for the upload")
+	}
+}
+
+func TestWithBlobOffloadRedactsPayloads(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	store := &memoryBlobStore{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(store, 16),
+		sugarzero.WithKeyRedaction([]string{"password"}, nil),
+		sugarzero.WithPIIDetection(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "body", `{"user":"alice@example.com","password":"hunter2"}`), "signup")
+
+	stored := string(store.data)
+	if strings.Contains(stored, "hunter2") || strings.Contains(stored, "alice@example.com") {
+		t.Fatalf("expected the payload to be redacted before storing, got %s", stored)
+	}
+	if stored != `{"user":"[REDACTED]","password":"[REDACTED]"}` {
+		t.Fatalf("unexpected stored payload %s", stored)
+	}
+}
+
+func TestDirBlobStoreIsPrivate(t *testing.T) {
+	dir := t.TempDir() + "/blobs"
+	ref, err := sugarzero.NewDirBlobStore(dir).Put(context.Background(), "k", []byte("secret"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	location, _ := url.Parse(ref)
+	for path, want := range map[string]os.FileMode{location.Path: 0o600, dir: 0o700 | os.ModeDir} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Stat failed: %v", err)
+		}
+		if info.Mode() != want {
+			t.Fatalf("expected %s to have mode %v, got %v", path, want, info.Mode())
+		}
+	}
+}
+
+func TestWithBlobOffloadTruncatesOnCharacterBoundary(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBlobOffload(failingBlobStore{}, 4),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "body", "日本語"), "offload failed")
+
+	body, _ := readLogEntry(t, &buf)["body"].(string)
+	if !strings.HasPrefix(body, "日...(truncated, 9 bytes") {
+		t.Fatalf("expected truncation before the split character, got %q", body)
+	}
+}
@@ -0,0 +1,267 @@
+package sugarzero
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultBreakerThreshold is the number of consecutive failures after
+	// which a CircuitBreaker opens.
+	DefaultBreakerThreshold = 5
+	// DefaultBreakerProbeInterval is how long a CircuitBreaker stays open
+	// before letting a probe write through.
+	DefaultBreakerProbeInterval = 30 * time.Second
+)
+
+// ErrWriteTimeout is returned by a CircuitBreaker when a write takes longer
+// than its timeout.
+var ErrWriteTimeout = errors.New("sugarzero: write timed out")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed passes every write through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen drops every write until the probe interval has passed.
+	BreakerOpen
+	// BreakerHalfOpen lets one probe write through; its outcome closes or
+	// reopens the breaker.
+	BreakerHalfOpen
+)
+
+// String returns "closed", "open", or "half-open".
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig configures a CircuitBreaker.
+type BreakerConfig struct {
+	// Timeout bounds each write; a write that takes longer counts as a
+	// failure and is abandoned. Writes are then made one at a time, so at
+	// most one abandoned write is ever left running, and writes made while
+	// it runs fail at once. Zero disables the timeout.
+	Timeout time.Duration
+	// Threshold is the number of consecutive failures that opens the
+	// breaker. Defaults to DefaultBreakerThreshold.
+	Threshold int
+	// ProbeInterval is how long the breaker stays open before a probe.
+	// Defaults to DefaultBreakerProbeInterval.
+	ProbeInterval time.Duration
+}
+
+// CircuitBreaker guards a writer or sink that may hang or fail, such as a
+// remote log endpoint. After Threshold consecutive failed or timed-out writes
+// it opens and drops entries without calling the writer, so a dead endpoint
+// cannot back-pressure the application; every ProbeInterval one entry is let
+// through to check whether the writer has recovered. Its state is reported by
+// Health.
+// Example: WithWriters(os.Stdout, NewCircuitBreaker(SinkWriter(loki), BreakerConfig{Timeout: 2 * time.Second}))
+type CircuitBreaker struct {
+	next io.Writer
+	cfg  BreakerConfig
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+
+	dropped atomic.Uint64
+	lastErr atomic.Value // errorValue
+	drops   dropNotice
+
+	// slot is held by the write in progress when there is a timeout; stuck
+	// is set while that write has timed out.
+	slot  chan struct{}
+	stuck atomic.Bool
+}
+
+// WithCircuitBreakers guards each configured writer and sink with its own
+// CircuitBreaker, so one dead endpoint is cut off without affecting the
+// others. Breaker states are reported by Health.
+// Example: WithSinks(loki), WithCircuitBreakers(BreakerConfig{Timeout: 2 * time.Second, Threshold: 3})
+func WithCircuitBreakers(cfg BreakerConfig) Option {
+	return func(o *options) {
+		o.breaker = &cfg
+	}
+}
+
+// NewCircuitBreaker returns a closed breaker in front of next.
+func NewCircuitBreaker(next io.Writer, cfg BreakerConfig) *CircuitBreaker {
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultBreakerThreshold
+	}
+	if cfg.ProbeInterval <= 0 {
+		cfg.ProbeInterval = DefaultBreakerProbeInterval
+	}
+	return &CircuitBreaker{next: next, cfg: cfg, slot: make(chan struct{}, 1)}
+}
+
+// Write writes an unleveled entry.
+func (b *CircuitBreaker) Write(p []byte) (int, error) {
+	return b.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel writes p unless the breaker is open, in which case the entry is
+// dropped without an error.
+func (b *CircuitBreaker) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !b.allow(time.Now()) {
+		b.drops.report(b.name(), b.dropped.Add(1))
+		return len(p), nil
+	}
+	n, err := b.write(level, p)
+	b.record(err)
+	return n, err
+}
+
+// allow reports whether a write may go through at now, moving an open
+// breaker to half-open once the probe interval has passed.
+func (b *CircuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if now.Sub(b.openedAt) < b.cfg.ProbeInterval {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		return true
+	case BreakerHalfOpen:
+		// A probe is in flight
+		return false
+	}
+	return true
+}
+
+func (b *CircuitBreaker) write(level zerolog.Level, p []byte) (int, error) {
+	if b.cfg.Timeout <= 0 {
+		return writeLevel(b.next, level, p)
+	}
+	if b.stuck.Load() {
+		return 0, fmt.Errorf("%w: an earlier write is still running", ErrWriteTimeout)
+	}
+	timer := time.NewTimer(b.cfg.Timeout)
+	defer timer.Stop()
+	select {
+	case b.slot <- struct{}{}:
+	case <-timer.C:
+		return 0, fmt.Errorf("%w after %s", ErrWriteTimeout, b.cfg.Timeout)
+	}
+
+	// The write may outlive the call, so it cannot use the caller's buffer
+	data := append([]byte(nil), p...)
+	result := make(chan error, 1)
+	go func() {
+		_, err := writeLevel(b.next, level, data)
+		result <- err
+		b.stuck.Store(false)
+		<-b.slot
+	}()
+	select {
+	case err := <-result:
+		if err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	case <-timer.C:
+		b.stuck.Store(true)
+		// The write may have finished, and cleared stuck, in the meantime
+		select {
+		case <-result:
+			b.stuck.Store(false)
+		default:
+		}
+		return 0, fmt.Errorf("%w after %s", ErrWriteTimeout, b.cfg.Timeout)
+	}
+}
+
+// record updates the state with the outcome of a write.
+func (b *CircuitBreaker) record(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		if b.state != BreakerClosed {
+			diagnose(DiagnosticCircuitBreaker, zerolog.InfoLevel, "circuit breaker closed", "writer", b.name())
+		}
+		b.state = BreakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.lastErr.Store(errorValue{err: err})
+	b.failures++
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.cfg.Threshold) {
+		if b.state == BreakerClosed {
+			diagnose(DiagnosticCircuitBreaker, zerolog.WarnLevel, "circuit breaker opened",
+				"writer", b.name(), "failures", b.failures, "error", err.Error())
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+// State returns the current state.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Sync syncs the writer unless the breaker is open.
+func (b *CircuitBreaker) Sync() error {
+	if b.State() != BreakerClosed {
+		return nil
+	}
+	return syncWriter(b.next)
+}
+
+// Close closes the writer if it implements io.Closer.
+func (b *CircuitBreaker) Close() error {
+	if closer, ok := b.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Health implements HealthReporter. The breaker is unhealthy unless closed.
+func (b *CircuitBreaker) Health() SinkStatus {
+	state := b.State()
+	status := SinkStatus{Name: b.name(), Healthy: true}
+	if reporter, ok := healthReporter(b.next); ok {
+		status = reporter.Health()
+	}
+	status.Healthy = status.Healthy && state == BreakerClosed
+	status.Breaker = state.String()
+	status.Dropped += b.dropped.Load()
+	if v, ok := b.lastErr.Load().(errorValue); ok && status.LastError == "" {
+		status.LastError = errorString(v.err)
+	}
+	return status
+}
+
+func (b *CircuitBreaker) name() string {
+	next := any(b.next)
+	if sw, ok := b.next.(*sinkWriter); ok {
+		next = sw.sink
+	}
+	if s, ok := next.(fmt.Stringer); ok {
+		return fmt.Sprintf("breaker(%s)", s)
+	}
+	return fmt.Sprintf("breaker(%T)", next)
+}
+
+var _ HealthReporter = (*CircuitBreaker)(nil)
@@ -0,0 +1,197 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// flakyWriter fails while fail is set and counts the writes it receives.
+type flakyWriter struct {
+	fail  atomic.Bool
+	calls atomic.Int32
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.calls.Add(1)
+	if w.fail.Load() {
+		return 0, errors.New("connection refused")
+	}
+	return len(p), nil
+}
+
+func TestCircuitBreakerOpensAndProbes(t *testing.T) {
+	sugarzero.SetDiagnosticsWriter(nil)
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	next := &flakyWriter{}
+	next.fail.Store(true)
+	b := sugarzero.NewCircuitBreaker(next, sugarzero.BreakerConfig{Threshold: 2, ProbeInterval: 50 * time.Millisecond})
+
+	for i := 0; i < 2; i++ {
+		if _, err := b.Write([]byte("entry\n")); err == nil {
+			t.Fatal("expected the write to fail while the breaker is closed")
+		}
+	}
+	if b.State() != sugarzero.BreakerOpen {
+		t.Fatalf("expected open breaker, got %s", b.State())
+	}
+	if _, err := b.Write([]byte("entry\n")); err != nil {
+		t.Fatalf("expected open breaker to drop silently, got %v", err)
+	}
+	if next.calls.Load() != 2 {
+		t.Fatalf("expected the open breaker not to call the writer, got %d calls", next.calls.Load())
+	}
+	health := b.Health()
+	if health.Healthy || health.Breaker != "open" || health.Dropped != 1 || health.LastError != "connection refused" {
+		t.Fatalf("unexpected health %+v", health)
+	}
+
+	// A failed probe reopens the breaker
+	time.Sleep(60 * time.Millisecond)
+	if _, err := b.Write([]byte("probe\n")); err == nil {
+		t.Fatal("expected the probe to fail")
+	}
+	if b.State() != sugarzero.BreakerOpen {
+		t.Fatalf("expected failed probe to reopen the breaker, got %s", b.State())
+	}
+
+	next.fail.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := b.Write([]byte("probe\n")); err != nil {
+		t.Fatalf("probe failed: %v", err)
+	}
+	if b.State() != sugarzero.BreakerClosed || !b.Health().Healthy {
+		t.Fatalf("expected successful probe to close the breaker, got %+v", b.Health())
+	}
+}
+
+func TestCircuitBreakerTimesOutSlowWrites(t *testing.T) {
+	sugarzero.SetDiagnosticsWriter(nil)
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	stuck := &gatedWriter{release: make(chan struct{})}
+	t.Cleanup(func() {
+		close(stuck.release)
+	})
+	b := sugarzero.NewCircuitBreaker(stuck, sugarzero.BreakerConfig{Timeout: 20 * time.Millisecond, Threshold: 1})
+
+	start := time.Now()
+	_, err := b.WriteLevel(zerolog.ErrorLevel, []byte("entry\n"))
+	if !errors.Is(err, sugarzero.ErrWriteTimeout) {
+		t.Fatalf("expected ErrWriteTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("write took %s", elapsed)
+	}
+	if b.State() != sugarzero.BreakerOpen {
+		t.Fatalf("expected timeout to open the breaker, got %s", b.State())
+	}
+}
+
+// writerFunc adapts a function to io.Writer.
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func TestCircuitBreakerLeavesOneTimedOutWriteRunning(t *testing.T) {
+	sugarzero.SetDiagnosticsWriter(nil)
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	stuck := &gatedWriter{release: make(chan struct{})}
+	var calls atomic.Int32
+	counting := writerFunc(func(p []byte) (int, error) {
+		calls.Add(1)
+		return stuck.Write(p)
+	})
+	b := sugarzero.NewCircuitBreaker(counting, sugarzero.BreakerConfig{Timeout: 20 * time.Millisecond, Threshold: 100})
+	t.Cleanup(func() {
+		_ = b.Close()
+	})
+
+	for range 3 {
+		if _, err := b.Write([]byte("entry\n")); !errors.Is(err, sugarzero.ErrWriteTimeout) {
+			t.Fatalf("expected ErrWriteTimeout, got %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected writes to be skipped while the timed-out one runs, got %d calls", got)
+	}
+
+	close(stuck.release)
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, err := b.Write([]byte("recovered\n"))
+		if err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected writes to resume once the stuck write finished, got %v", err)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := stuck.String(); got != "entry\nrecovered\n" {
+		t.Fatalf("unexpected output %q", got)
+	}
+}
+
+func TestWithCircuitBreakersReportsHealth(t *testing.T) {
+	sugarzero.Reset()
+	sugarzero.SetDiagnosticsWriter(nil)
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	dead := &flakyWriter{}
+	dead.fail.Store(true)
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf, dead),
+		sugarzero.WithCircuitBreakers(sugarzero.BreakerConfig{Threshold: 1, ProbeInterval: time.Hour}))
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		sugarzero.Info(ctx, "order placed")
+	}
+	if got := bytes.Count(buf.Bytes(), []byte("order placed")); got != 3 {
+		t.Fatalf("expected 3 entries in the healthy writer, got %d", got)
+	}
+	if dead.calls.Load() != 1 {
+		t.Fatalf("expected the dead writer to be cut off after one failure, got %d calls", dead.calls.Load())
+	}
+
+	rec := httptest.NewRecorder()
+	sugarzero.HealthHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	var health sugarzero.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &health); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if health.Healthy || len(health.Sinks) != 2 {
+		t.Fatalf("unexpected health %+v", health)
+	}
+	if health.Sinks[0].Breaker != "closed" || health.Sinks[1].Breaker != "open" {
+		t.Fatalf("unexpected breaker states %+v", health.Sinks)
+	}
+}
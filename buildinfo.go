@@ -0,0 +1,38 @@
+package sugarzero
+
+import "runtime/debug"
+
+// buildInfoSettings maps the build settings written by WithBuildInfo to
+// their field names.
+var buildInfoSettings = []string{"vcs.revision", "vcs.time", "vcs.modified"}
+
+// WithBuildInfo writes the main module version and the VCS revision, commit
+// time, and modified flag recorded by the Go toolchain on every entry, as the
+// module.version, vcs.revision, vcs.time, and vcs.modified fields. Values the
+// binary does not record, e.g. VCS data in builds outside a repository or
+// with -buildvcs=false, are omitted.
+// Example: NewWithOptions(ctx, "info", WithBuildInfo())
+func WithBuildInfo() Option {
+	return func(o *options) {
+		info, ok := debug.ReadBuildInfo()
+		if !ok {
+			return
+		}
+		o.fields = append(o.fields, buildInfoFields(info)...)
+	}
+}
+
+func buildInfoFields(info *debug.BuildInfo) []any {
+	var fields []any
+	if info.Main.Version != "" {
+		fields = append(fields, "module.version", info.Main.Version)
+	}
+	for _, key := range buildInfoSettings {
+		for _, setting := range info.Settings {
+			if setting.Key == key && setting.Value != "" {
+				fields = append(fields, key, setting.Value)
+			}
+		}
+	}
+	return fields
+}
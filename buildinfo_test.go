@@ -0,0 +1,51 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"runtime/debug"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithBuildInfo(t *testing.T) {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		t.Skip("binary has no build info")
+	}
+	want := map[string]string{"module.version": info.Main.Version}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision", "vcs.time", "vcs.modified":
+			want[setting.Key] = setting.Value
+		}
+	}
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithBuildInfo(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "started")
+
+	entry := readLogEntry(t, &buf)
+	for key, value := range want {
+		got, present := entry[key]
+		if value == "" && present {
+			t.Fatalf("expected empty %s to be omitted, got %v", key, got)
+		}
+		if value != "" && got != value {
+			t.Fatalf("expected %s=%q, got %v", key, value, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MessageSpec describes a log message registered in the catalog.
+type MessageSpec struct {
+	// ID is the stable message ID written to the msg_id field.
+	ID string `json:"id"`
+	// Level is the level the message is logged at; empty means info.
+	Level   string `json:"level"`
+	Message string `json:"message"`
+	// Fields lists the keys logged with the message.
+	Fields      []string `json:"fields,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+var catalog = struct {
+	sync.RWMutex
+	messages map[string]MessageSpec
+}{}
+
+// RegisterMessage adds spec to the catalog returned by Catalog, so tooling
+// can generate alert rules and documentation from the messages a service may
+// log. Registering the same spec again is a no-op; registering a different
+// spec under an existing ID is an error.
+func RegisterMessage(spec MessageSpec) (MessageSpec, error) {
+	if spec.ID == "" {
+		return spec, errors.New("sugarzero: message ID must not be empty")
+	}
+	if spec.Level == "" {
+		spec.Level = "info"
+	}
+	if !IsValidLevel(spec.Level) {
+		return spec, fmt.Errorf("sugarzero: message %q: invalid log level %q", spec.ID, spec.Level)
+	}
+	spec.Level = strings.ToLower(spec.Level)
+	spec.Fields = slices.Clone(spec.Fields)
+
+	catalog.Lock()
+	defer catalog.Unlock()
+	if existing, ok := catalog.messages[spec.ID]; ok {
+		if !existing.equal(spec) {
+			return spec, fmt.Errorf("sugarzero: message %q already registered with a different spec", spec.ID)
+		}
+		return existing, nil
+	}
+	if catalog.messages == nil {
+		catalog.messages = make(map[string]MessageSpec)
+	}
+	catalog.messages[spec.ID] = spec
+	return spec, nil
+}
+
+// MustRegisterMessage is RegisterMessage for package-level variables; it
+// panics on error.
+// Example:
+//
+//	var orderReceived = sugarzero.MustRegisterMessage(sugarzero.MessageSpec{
+//		ID: "ORD-1001", Message: "order received", Fields: []string{"order_id"},
+//	})
+//
+//	orderReceived.Log(ctx, "order_id", id)
+func MustRegisterMessage(spec MessageSpec) MessageSpec {
+	spec, err := RegisterMessage(spec)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// Catalog returns the registered messages sorted by ID.
+func Catalog() []MessageSpec {
+	catalog.RLock()
+	defer catalog.RUnlock()
+	specs := make([]MessageSpec, 0, len(catalog.messages))
+	for _, spec := range catalog.messages {
+		spec.Fields = slices.Clone(spec.Fields)
+		specs = append(specs, spec)
+	}
+	slices.SortFunc(specs, func(a, b MessageSpec) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return specs
+}
+
+// Log logs the message at its level with its ID, as InfoID and the other ID
+// functions do. Logging at fatal level does not exit.
+func (s MessageSpec) Log(ctx context.Context, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		resolved = withMessageID(resolved, s.ID, keyvals)
+		if zl, ok := logger.(*ZeroLogger); ok {
+			zl.Log(resolved, s.Level, s.Message)
+			return
+		}
+		logAt(resolved, standardLevel(s.Level), s.Message)
+	})
+}
+
+func (s MessageSpec) equal(other MessageSpec) bool {
+	return s.ID == other.ID && s.Level == other.Level && s.Message == other.Message &&
+		s.Description == other.Description && slices.Equal(s.Fields, other.Fields)
+}
@@ -0,0 +1,68 @@
+package sugarzero_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+var paymentDeclined = sugarzero.MustRegisterMessage(sugarzero.MessageSpec{
+	ID:          "PAY-2001",
+	Level:       "WARN",
+	Message:     "payment declined",
+	Fields:      []string{"order_id", "reason"},
+	Description: "The card issuer declined the charge.",
+})
+
+func TestMessageCatalog(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	paymentDeclined.Log(ctx, "order_id", "o-1", "reason", "insufficient_funds")
+
+	entry := readLogEntry(t, buf)
+	if entry["msg_id"] != "PAY-2001" || entry["level"] != "WARN" || entry["message"] != "payment declined" || entry["reason"] != "insufficient_funds" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "catalog_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+
+	var found bool
+	for _, spec := range sugarzero.Catalog() {
+		if spec.ID == "PAY-2001" {
+			found = true
+			if spec.Level != "warn" || len(spec.Fields) != 2 {
+				t.Fatalf("unexpected spec: %+v", spec)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("expected PAY-2001 in catalog")
+	}
+}
+
+func TestRegisterMessageValidation(t *testing.T) {
+	if _, err := sugarzero.RegisterMessage(sugarzero.MessageSpec{
+		ID: "PAY-2001", Level: "warn", Message: "payment declined",
+		Fields: []string{"order_id", "reason"}, Description: "The card issuer declined the charge.",
+	}); err != nil {
+		t.Fatalf("re-registering the same spec failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		spec sugarzero.MessageSpec
+	}{
+		{name: "conflicting spec", spec: sugarzero.MessageSpec{ID: "PAY-2001", Message: "payment failed"}},
+		{name: "empty id", spec: sugarzero.MessageSpec{Message: "no id"}},
+		{name: "invalid level", spec: sugarzero.MessageSpec{ID: "PAY-2002", Level: "loud"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := sugarzero.RegisterMessage(tt.spec); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
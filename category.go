@@ -0,0 +1,123 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// CategoryFieldName is the key under which WithCategory writes the category.
+const CategoryFieldName = "category"
+
+// Well-known event categories.
+const (
+	CategorySecurity  = "security"
+	CategoryBusiness  = "business"
+	CategoryTechnical = "technical"
+)
+
+// WithCategory tags entries logged with the returned context with category.
+// The category is written as a field and selects the levels and writers
+// configured with WithCategoryLevel and WithCategoryWriter.
+// Example: sugarzero.Warn(sugarzero.WithCategory(ctx, sugarzero.CategorySecurity), "login failed")
+func WithCategory(ctx context.Context, category string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if category == "" {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, categoryKey, category)
+	return withUnscopedFields(ctx, CategoryFieldName, category)
+}
+
+// CategoryFromContext returns the category set by WithCategory, or "".
+func CategoryFromContext(ctx context.Context) string {
+	return categoryFromContext(ctx)
+}
+
+// WithCategoryLevel sets the minimum level for entries of category,
+// independently of the logger level and of later SetLogLevel calls. Use it to
+// keep e.g. security events flowing while the application logs at error level.
+func WithCategoryLevel(category, level string) Option {
+	return func(o *options) {
+		if o.categoryLevels == nil {
+			o.categoryLevels = make(map[string]string)
+		}
+		o.categoryLevels[category] = level
+	}
+}
+
+// WithCategoryWriter copies every entry of category to w in addition to the
+// configured writers, e.g. to ship security events to a SIEM. The copy is
+// written synchronously before the regular output.
+func WithCategoryWriter(category string, w io.Writer) Option {
+	return func(o *options) {
+		o.wrapWriter(func(next io.Writer) (io.Writer, error) {
+			if category == "" {
+				return nil, errors.New("sugarzero: category must not be empty")
+			}
+			if w == nil {
+				return nil, fmt.Errorf("sugarzero: writer for category %q must not be nil", category)
+			}
+			return &categoryWriter{next: next, category: category, target: w}, nil
+		})
+	}
+}
+
+func (o *options) parseCategoryLevels() (map[string]zerolog.Level, error) {
+	if len(o.categoryLevels) == 0 {
+		return nil, nil
+	}
+	levels := make(map[string]zerolog.Level, len(o.categoryLevels))
+	for category, level := range o.categoryLevels {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: category %q: %w", category, err)
+		}
+		levels[category] = lvl
+	}
+	return levels, nil
+}
+
+type categoryWriter struct {
+	next     io.Writer
+	category string
+	target   io.Writer
+}
+
+func (w *categoryWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *categoryWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var targetErr error
+	if w.matches(p) {
+		_, targetErr = writeLevel(w.target, level, p)
+	}
+	n, err := writeLevel(w.next, level, p)
+	if err == nil && targetErr != nil {
+		return n, fmt.Errorf("sugarzero: category %q writer: %w", w.category, targetErr)
+	}
+	return n, err
+}
+
+func (w *categoryWriter) matches(p []byte) bool {
+	// Skip decoding entries that cannot carry the category
+	if !bytes.Contains(p, []byte(w.category)) {
+		return false
+	}
+	return extractStringField(p, CategoryFieldName) == w.category
+}
+
+func categoryFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	category, _ := ctx.Value(categoryKey).(string)
+	return category
+}
@@ -0,0 +1,73 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestCategoryLevelAndWriter(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var app, siem bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "error",
+		sugarzero.WithWriters(&app),
+		sugarzero.WithCategoryLevel(sugarzero.CategorySecurity, "info"),
+		sugarzero.WithCategoryWriter(sugarzero.CategorySecurity, &siem),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	security := sugarzero.WithCategory(ctx, sugarzero.CategorySecurity)
+	sugarzero.Warn(security, "login failed")
+	sugarzero.Warn(sugarzero.WithCategory(ctx, sugarzero.CategoryBusiness), "cart abandoned")
+	sugarzero.Debug(security, "token parsed")
+
+	entry := readLogEntry(t, &siem)
+	if entry["message"] != "login failed" || entry["category"] != "security" {
+		t.Fatalf("unexpected SIEM entry %v", entry)
+	}
+	if strings.Contains(app.String(), "cart abandoned") {
+		t.Fatal("expected uncategorized level to still apply to business entries")
+	}
+	if !strings.Contains(app.String(), "login failed") {
+		t.Fatal("expected security entry in the regular output as well")
+	}
+	if strings.Contains(siem.String(), "token parsed") {
+		t.Fatal("expected entries below the category level to be dropped")
+	}
+
+	if err := sugarzero.SetLogLevel(ctx, "fatal"); err != nil {
+		t.Fatalf("SetLogLevel failed: %v", err)
+	}
+	siem.Reset()
+	sugarzero.Info(security, "password changed")
+	if !strings.Contains(siem.String(), "password changed") {
+		t.Fatal("expected category level to survive SetLogLevel")
+	}
+	if got := sugarzero.CategoryFromContext(security); got != sugarzero.CategorySecurity {
+		t.Fatalf("expected security category, got %q", got)
+	}
+}
+
+func TestCategoryLevelRejectsInvalidLevel(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithCategoryLevel(sugarzero.CategorySecurity, "loud"),
+	)
+	if err == nil {
+		t.Fatal("expected error for invalid category level")
+	}
+}
@@ -0,0 +1,249 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCloudMetadataTimeout bounds cloud metadata detection in
+// WithCloudMetadata.
+const DefaultCloudMetadataTimeout = 2 * time.Second
+
+// Default instance metadata endpoints.
+const (
+	AWSMetadataEndpoint   = "http://169.254.169.254"
+	GCPMetadataEndpoint   = "http://metadata.google.internal"
+	AzureMetadataEndpoint = "http://169.254.169.254"
+)
+
+// metadataClient bypasses proxies, which cannot reach link-local metadata
+// services.
+var metadataClient = &http.Client{Transport: &http.Transport{Proxy: nil}}
+
+// cloudMetadataFields caches the fields detected by WithCloudMetadata, since
+// the instance a process runs on does not change.
+var cloudMetadataFields struct {
+	mu       sync.Mutex
+	detected bool
+	fields   []any
+}
+
+// CloudMetadata describes the cloud instance the process runs on.
+type CloudMetadata struct {
+	// Provider is "aws", "gcp", or "azure".
+	Provider   string
+	Region     string
+	Zone       string
+	Account    string
+	InstanceID string
+}
+
+// Fields returns the non-empty metadata as key-value pairs with the
+// OpenTelemetry names cloud.provider, cloud.region, cloud.availability_zone,
+// cloud.account.id, and host.id.
+func (m CloudMetadata) Fields() []any {
+	var fields []any
+	for _, field := range [][2]string{
+		{"cloud.provider", m.Provider},
+		{"cloud.region", m.Region},
+		{"cloud.availability_zone", m.Zone},
+		{"cloud.account.id", m.Account},
+		{"host.id", m.InstanceID},
+	} {
+		if field[1] != "" {
+			fields = append(fields, field[0], field[1])
+		}
+	}
+	return fields
+}
+
+// CloudMetadataDetector queries the AWS, GCP, and Azure instance metadata
+// services. Empty endpoints use the defaults and a nil Client uses a client
+// without proxies, since metadata services are link-local.
+type CloudMetadataDetector struct {
+	AWSEndpoint   string
+	GCPEndpoint   string
+	AzureEndpoint string
+	Client        *http.Client
+}
+
+// Detect queries every provider concurrently and returns the metadata of the
+// first one that answers, or an error once all have failed or ctx is done.
+func (d CloudMetadataDetector) Detect(ctx context.Context) (CloudMetadata, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		metadata CloudMetadata
+		err      error
+	}
+	probes := []func(context.Context) (CloudMetadata, error){d.detectAWS, d.detectGCP, d.detectAzure}
+	results := make(chan result, len(probes))
+	for _, probe := range probes {
+		go func() {
+			metadata, err := probe(ctx)
+			results <- result{metadata: metadata, err: err}
+		}()
+	}
+
+	var errs []error
+	for range probes {
+		r := <-results
+		if r.err == nil {
+			return r.metadata, nil
+		}
+		errs = append(errs, r.err)
+	}
+	return CloudMetadata{}, fmt.Errorf("sugarzero: no cloud metadata service found: %w", errors.Join(errs...))
+}
+
+// WithCloudMetadata detects the cloud instance, waiting at most timeout
+// (DefaultCloudMetadataTimeout if timeout <= 0), and writes its
+// CloudMetadata fields on every entry. Outside a cloud nothing is added.
+// Detection runs once per process; later constructions, such as reloads,
+// reuse its result without waiting.
+// Example: NewWithOptions(ctx, "info", WithCloudMetadata(0))
+func WithCloudMetadata(timeout time.Duration) Option {
+	return func(o *options) {
+		o.fields = append(o.fields, detectCloudMetadataOnce(timeout)...)
+	}
+}
+
+// detectCloudMetadataOnce returns the cached cloud metadata fields,
+// detecting them on the first call.
+func detectCloudMetadataOnce(timeout time.Duration) []any {
+	cloudMetadataFields.mu.Lock()
+	defer cloudMetadataFields.mu.Unlock()
+	if !cloudMetadataFields.detected {
+		if timeout <= 0 {
+			timeout = DefaultCloudMetadataTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if metadata, err := (CloudMetadataDetector{}).Detect(ctx); err == nil {
+			cloudMetadataFields.fields = metadata.Fields()
+		}
+		cloudMetadataFields.detected = true
+	}
+	return cloudMetadataFields.fields
+}
+
+func (d CloudMetadataDetector) detectAWS(ctx context.Context) (CloudMetadata, error) {
+	endpoint := endpointOrDefault(d.AWSEndpoint, AWSMetadataEndpoint)
+	token, err := d.get(ctx, http.MethodPut, endpoint+"/latest/api/token",
+		"X-aws-ec2-metadata-token-ttl-seconds", "60")
+	if err != nil {
+		return CloudMetadata{}, fmt.Errorf("aws: %w", err)
+	}
+	body, err := d.get(ctx, http.MethodGet, endpoint+"/latest/dynamic/instance-identity/document",
+		"X-aws-ec2-metadata-token", string(token))
+	if err != nil {
+		return CloudMetadata{}, fmt.Errorf("aws: %w", err)
+	}
+	var document struct {
+		InstanceID       string `json:"instanceId"`
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		AccountID        string `json:"accountId"`
+	}
+	if err := json.Unmarshal(body, &document); err != nil {
+		return CloudMetadata{}, fmt.Errorf("aws: invalid identity document: %w", err)
+	}
+	return CloudMetadata{
+		Provider:   "aws",
+		Region:     document.Region,
+		Zone:       document.AvailabilityZone,
+		Account:    document.AccountID,
+		InstanceID: document.InstanceID,
+	}, nil
+}
+
+func (d CloudMetadataDetector) detectGCP(ctx context.Context) (CloudMetadata, error) {
+	endpoint := endpointOrDefault(d.GCPEndpoint, GCPMetadataEndpoint) + "/computeMetadata/v1/"
+	values := make(map[string]string, 3)
+	for _, path := range []string{"instance/id", "instance/zone", "project/project-id"} {
+		body, err := d.get(ctx, http.MethodGet, endpoint+path, "Metadata-Flavor", "Google")
+		if err != nil {
+			return CloudMetadata{}, fmt.Errorf("gcp: %w", err)
+		}
+		values[path] = string(body)
+	}
+	// The zone is returned as projects/<number>/zones/<zone>.
+	zone := values["instance/zone"][strings.LastIndex(values["instance/zone"], "/")+1:]
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+	return CloudMetadata{
+		Provider:   "gcp",
+		Region:     region,
+		Zone:       zone,
+		Account:    values["project/project-id"],
+		InstanceID: values["instance/id"],
+	}, nil
+}
+
+func (d CloudMetadataDetector) detectAzure(ctx context.Context) (CloudMetadata, error) {
+	endpoint := endpointOrDefault(d.AzureEndpoint, AzureMetadataEndpoint)
+	body, err := d.get(ctx, http.MethodGet, endpoint+"/metadata/instance?api-version=2021-02-01",
+		"Metadata", "true")
+	if err != nil {
+		return CloudMetadata{}, fmt.Errorf("azure: %w", err)
+	}
+	var instance struct {
+		Compute struct {
+			VMID           string `json:"vmId"`
+			Location       string `json:"location"`
+			Zone           string `json:"zone"`
+			SubscriptionID string `json:"subscriptionId"`
+		} `json:"compute"`
+	}
+	if err := json.Unmarshal(body, &instance); err != nil {
+		return CloudMetadata{}, fmt.Errorf("azure: invalid instance metadata: %w", err)
+	}
+	return CloudMetadata{
+		Provider:   "azure",
+		Region:     instance.Compute.Location,
+		Zone:       instance.Compute.Zone,
+		Account:    instance.Compute.SubscriptionID,
+		InstanceID: instance.Compute.VMID,
+	}, nil
+}
+
+// get sends a request with one header and returns the body of a 200
+// response.
+func (d CloudMetadataDetector) get(ctx context.Context, method, url, header, value string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(header, value)
+
+	client := d.Client
+	if client == nil {
+		client = metadataClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s %s: %s", method, url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+}
+
+func endpointOrDefault(endpoint, fallback string) string {
+	if endpoint == "" {
+		return fallback
+	}
+	return strings.TrimRight(endpoint, "/")
+}
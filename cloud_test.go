@@ -0,0 +1,129 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestCloudMetadataDetector(t *testing.T) {
+	notFound := httptest.NewServer(http.NotFoundHandler())
+	defer notFound.Close()
+
+	aws := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("token-1"))
+		case r.URL.Path == "/latest/dynamic/instance-identity/document" && r.Header.Get("X-aws-ec2-metadata-token") == "token-1":
+			_, _ = w.Write([]byte(`{"instanceId":"i-0abc","region":"eu-west-1","availabilityZone":"eu-west-1b","accountId":"123456789012"}`))
+		default:
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}
+	}))
+	defer aws.Close()
+
+	gcp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		values := map[string]string{
+			"/computeMetadata/v1/instance/id":        "4520031799277581759",
+			"/computeMetadata/v1/instance/zone":      "projects/801/zones/us-central1-a",
+			"/computeMetadata/v1/project/project-id": "shop-prod",
+		}
+		_, _ = w.Write([]byte(values[r.URL.Path]))
+	}))
+	defer gcp.Close()
+
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Path != "/metadata/instance" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"compute":{"vmId":"02aab8a4","location":"westeurope","zone":"2","subscriptionId":"8d10da13"}}`))
+	}))
+	defer azure.Close()
+
+	tests := []struct {
+		name     string
+		detector sugarzero.CloudMetadataDetector
+		want     sugarzero.CloudMetadata
+	}{
+		{
+			name:     "aws",
+			detector: sugarzero.CloudMetadataDetector{AWSEndpoint: aws.URL, GCPEndpoint: notFound.URL, AzureEndpoint: notFound.URL},
+			want:     sugarzero.CloudMetadata{Provider: "aws", Region: "eu-west-1", Zone: "eu-west-1b", Account: "123456789012", InstanceID: "i-0abc"},
+		},
+		{
+			name:     "gcp",
+			detector: sugarzero.CloudMetadataDetector{AWSEndpoint: notFound.URL, GCPEndpoint: gcp.URL, AzureEndpoint: notFound.URL},
+			want:     sugarzero.CloudMetadata{Provider: "gcp", Region: "us-central1", Zone: "us-central1-a", Account: "shop-prod", InstanceID: "4520031799277581759"},
+		},
+		{
+			name:     "azure",
+			detector: sugarzero.CloudMetadataDetector{AWSEndpoint: notFound.URL, GCPEndpoint: notFound.URL, AzureEndpoint: azure.URL},
+			want:     sugarzero.CloudMetadata{Provider: "azure", Region: "westeurope", Zone: "2", Account: "8d10da13", InstanceID: "02aab8a4"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.detector.Detect(context.Background())
+			if err != nil {
+				t.Fatalf("Detect failed: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+
+	detector := sugarzero.CloudMetadataDetector{AWSEndpoint: notFound.URL, GCPEndpoint: notFound.URL, AzureEndpoint: notFound.URL}
+	if _, err := detector.Detect(context.Background()); err == nil {
+		t.Fatal("expected error without metadata service")
+	}
+}
+
+func TestWithCloudMetadataOutsideCloud(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	start := time.Now()
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithCloudMetadata(50*time.Millisecond),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("detection ignored its timeout, took %v", elapsed)
+	}
+
+	sugarzero.Info(ctx, "started")
+	if entry := readLogEntry(t, &buf); entry["cloud.provider"] != nil {
+		t.Skipf("running in %v, cannot check the outside-cloud behaviour", entry["cloud.provider"])
+	}
+
+	// The result is cached, so a new logger does not wait for detection again
+	sugarzero.Reset()
+	start = time.Now()
+	_, err = sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithCloudMetadata(time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the cached detection to be reused, took %v", elapsed)
+	}
+}
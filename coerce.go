@@ -0,0 +1,110 @@
+package sugarzero
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// BytesEncoding selects how []byte field values are rendered.
+type BytesEncoding int
+
+const (
+	// BytesAsIs keeps zerolog's default of writing bytes as a raw string.
+	BytesAsIs BytesEncoding = iota
+	// BytesBase64 renders bytes as standard base64.
+	BytesBase64
+	// BytesHex renders bytes as lowercase hex.
+	BytesHex
+)
+
+// Coercion defines how field values are canonicalized before encoding, so the
+// same Go type always produces the same JSON type regardless of how zerolog
+// would reflect on it. Downstream typed indices depend on this stability.
+type Coercion struct {
+	// ErrorsAsStrings renders error values as their Error() text.
+	ErrorsAsStrings bool
+	// TimeFormat formats time.Time values with the given layout, e.g. time.RFC3339.
+	// An empty layout keeps zerolog's default.
+	TimeFormat string
+	// Bytes selects the encoding of []byte values.
+	Bytes BytesEncoding
+	// UseStringer renders values implementing fmt.Stringer via String().
+	UseStringer bool
+}
+
+// DefaultCoercion returns rules that render errors and Stringers as strings,
+// times as RFC3339, and bytes as base64.
+func DefaultCoercion() Coercion {
+	return Coercion{
+		ErrorsAsStrings: true,
+		TimeFormat:      time.RFC3339,
+		Bytes:           BytesBase64,
+		UseStringer:     true,
+	}
+}
+
+// WithCoercion applies rules to every field attached with WithFields.
+// Example: NewWithOptions(ctx, "info", WithCoercion(DefaultCoercion()))
+func WithCoercion(rules Coercion) Option {
+	return func(o *options) {
+		o.coercion = &rules
+	}
+}
+
+// Coerce returns v canonicalized according to the rules.
+func (c Coercion) Coerce(v any) any {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case error:
+		if c.ErrorsAsStrings {
+			if isNilPointer(value) {
+				return nil
+			}
+			return value.Error()
+		}
+	case time.Time:
+		if c.TimeFormat != "" {
+			return value.Format(c.TimeFormat)
+		}
+	case []byte:
+		switch c.Bytes {
+		case BytesBase64:
+			return base64.StdEncoding.EncodeToString(value)
+		case BytesHex:
+			return hex.EncodeToString(value)
+		}
+	case fmt.Stringer:
+		if c.UseStringer {
+			if isNilPointer(value) {
+				return nil
+			}
+			return value.String()
+		}
+	}
+	return v
+}
+
+// isNilPointer reports whether v holds a nil pointer, such as a nil *url.URL,
+// whose String or Error method would panic.
+func isNilPointer(v any) bool {
+	value := reflect.ValueOf(v)
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// coerceFields returns a copy of the flattened key-value pairs with every value
+// coerced, or flat itself when no rules are configured.
+func (l *ZeroLogger) coerceFields(flat []any) []any {
+	if l.coercion == nil {
+		return flat
+	}
+	coerced := make([]any, len(flat))
+	for i := 0; i+1 < len(flat); i += 2 {
+		coerced[i] = flat[i]
+		coerced[i+1] = l.coercion.Coerce(flat[i+1])
+	}
+	return coerced
+}
@@ -0,0 +1,101 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithCoercionCanonicalizesFieldValues(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithCoercion(sugarzero.DefaultCoercion()),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx,
+		"cause", errors.New("disk full"),
+		"at", time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+		"payload", []byte{0x01, 0x02},
+		"client", net.IPv4(10, 0, 0, 1),
+		"timeout", 1500*time.Millisecond,
+	)
+	sugarzero.Info(ctx, "coerced")
+
+	entry := readLogEntry(t, &buf)
+
+	expected := map[string]string{
+		"cause":   "disk full",
+		"at":      "2024-03-01T12:30:00Z",
+		"payload": "AQI=",
+		"client":  "10.0.0.1",
+		"timeout": "1.5s",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%q, got %v", key, want, entry[key])
+		}
+	}
+}
+
+func TestCoercionHexBytes(t *testing.T) {
+	rules := sugarzero.Coercion{Bytes: sugarzero.BytesHex}
+	if got := rules.Coerce([]byte{0xca, 0xfe}); got != "cafe" {
+		t.Fatalf("expected cafe, got %v", got)
+	}
+	if got := rules.Coerce(42); got != 42 {
+		t.Fatalf("expected untouched value, got %v", got)
+	}
+}
+
+type nilPointerError struct{}
+
+func (*nilPointerError) Error() string { return "unreachable" }
+
+func TestCoercionNilPointers(t *testing.T) {
+	rules := sugarzero.DefaultCoercion()
+	var u *url.URL
+	var err *nilPointerError
+	if got := rules.Coerce(u); got != nil {
+		t.Fatalf("expected a nil Stringer to coerce to nil, got %v", got)
+	}
+	if got := rules.Coerce(error(err)); got != nil {
+		t.Fatalf("expected a typed nil error to coerce to nil, got %v", got)
+	}
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, logErr := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithCoercion(rules),
+		sugarzero.WithLogInjectionProtection(),
+	)
+	if logErr != nil {
+		t.Fatalf("Failed to create logger: %v", logErr)
+	}
+	sugarzero.Info(sugarzero.WithFields(ctx, "url", u, "cause", error(err)), "nil values")
+	entry := readLogEntry(t, &buf)
+	if v, ok := entry["url"]; !ok || v != nil {
+		t.Fatalf("expected url to be null, got %v", entry)
+	}
+	if v, ok := entry["cause"]; !ok || v != nil {
+		t.Fatalf("expected cause to be null, got %v", entry)
+	}
+}
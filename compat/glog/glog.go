@@ -0,0 +1,285 @@
+// Package glog is a drop-in facade for the github.com/golang/glog and
+// k8s.io/klog API that writes through sugarzero, honoring the -v and
+// -vmodule verbosity flags, so kubernetes-style components can be pointed
+// wholesale at sugarzero by changing imports:
+//
+//	import log "github.com/bigboss2063/sugarzero/compat/glog"
+//
+//	log.InitFlags(nil)
+//	flag.Parse()
+//	log.V(2).Infof("syncing %s", key)
+//
+// Verbose entries are logged at info level with their verbosity in the
+// VerbosityFieldName field. Old binaries using the standard library's log
+// package are redirected with RedirectStdLog.
+package glog
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// VerbosityFieldName is the key under which V entries write their level.
+const VerbosityFieldName = "v"
+
+// callerSkip skips write, logDepth, and the public function that called
+// them, so entries report the caller of the facade.
+const callerSkip = 3
+
+// Level is a verbosity level, as set by -v.
+type Level int32
+
+var verbosity atomic.Int32
+
+// SetVerbosity sets the -v level at runtime.
+func SetVerbosity(level Level) {
+	verbosity.Store(int32(level))
+}
+
+// Verbosity returns the -v level.
+func Verbosity() Level {
+	return Level(verbosity.Load())
+}
+
+// String implements flag.Value.
+func (l *Level) String() string {
+	if l == nil {
+		return "0"
+	}
+	return strconv.Itoa(int(*l))
+}
+
+// Set implements flag.Value, updating the verbosity used by V.
+func (l *Level) Set(value string) error {
+	v, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("sugarzero: invalid verbosity %q: %w", value, err)
+	}
+	*l = Level(v)
+	SetVerbosity(*l)
+	return nil
+}
+
+// modulePattern is one pattern=N entry of -vmodule.
+type modulePattern struct {
+	pattern string
+	level   Level
+}
+
+var vmodule = struct {
+	mu       sync.RWMutex
+	spec     string
+	patterns []modulePattern
+	// active skips the caller lookup in V while no -vmodule is set.
+	active atomic.Bool
+}{}
+
+type moduleSpec struct{}
+
+func (moduleSpec) String() string {
+	vmodule.mu.RLock()
+	defer vmodule.mu.RUnlock()
+	return vmodule.spec
+}
+
+func (moduleSpec) Set(value string) error {
+	return SetVModule(value)
+}
+
+// SetVModule sets per-file verbosity from a comma-separated list of
+// pattern=N, where pattern is matched against the source file name without
+// its .go extension, extended with as many parent directories as the pattern
+// has slashes.
+// Example: SetVModule("controller=4,cache/*=2")
+func SetVModule(spec string) error {
+	var patterns []modulePattern
+	for _, entry := range strings.Split(spec, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		pattern, value, ok := strings.Cut(entry, "=")
+		if !ok || pattern == "" {
+			return fmt.Errorf("sugarzero: invalid vmodule entry %q: expected pattern=N", entry)
+		}
+		level, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("sugarzero: invalid vmodule level in %q: %w", entry, err)
+		}
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return fmt.Errorf("sugarzero: invalid vmodule pattern %q: %w", pattern, err)
+		}
+		patterns = append(patterns, modulePattern{pattern: pattern, level: Level(level)})
+	}
+	vmodule.mu.Lock()
+	defer vmodule.mu.Unlock()
+	vmodule.spec = spec
+	vmodule.patterns = patterns
+	vmodule.active.Store(len(patterns) > 0)
+	return nil
+}
+
+// InitFlags registers -v and -vmodule on fs, or on flag.CommandLine when fs
+// is nil. The glog output flags (-logtostderr, -alsologtostderr,
+// -stderrthreshold, -log_dir) are accepted so existing command lines keep
+// parsing, but ignored: output is configured on the sugarzero logger.
+func InitFlags(fs *flag.FlagSet) {
+	if fs == nil {
+		fs = flag.CommandLine
+	}
+	level := Verbosity()
+	fs.Var(&level, "v", "number for the log level verbosity")
+	fs.Var(moduleSpec{}, "vmodule", "comma-separated list of pattern=N settings for file-filtered logging")
+	fs.Bool("logtostderr", true, "ignored: output is configured on the sugarzero logger")
+	fs.Bool("alsologtostderr", false, "ignored: output is configured on the sugarzero logger")
+	fs.String("stderrthreshold", "", "ignored: output is configured on the sugarzero logger")
+	fs.String("log_dir", "", "ignored: output is configured on the sugarzero logger")
+}
+
+// Verbose is returned by V; its methods log only when the verbosity is
+// enabled.
+type Verbose struct {
+	enabled bool
+	level   Level
+}
+
+// V reports whether verbosity level is enabled, by -vmodule for the calling
+// file if it matches, by -v otherwise.
+// Example: if v := glog.V(4); v.Enabled() { v.Info(expensiveDump()) }
+func V(level Level) Verbose {
+	return Verbose{enabled: level <= fileVerbosity(2), level: level}
+}
+
+func fileVerbosity(skip int) Level {
+	if !vmodule.active.Load() {
+		return Verbosity()
+	}
+	_, file, _, ok := runtime.Caller(skip)
+	if !ok {
+		return Verbosity()
+	}
+	elems := strings.Split(strings.TrimSuffix(filepath.ToSlash(file), ".go"), "/")
+	vmodule.mu.RLock()
+	defer vmodule.mu.RUnlock()
+	for _, p := range vmodule.patterns {
+		name := elems[len(elems)-1]
+		if n := strings.Count(p.pattern, "/"); n > 0 && n < len(elems) {
+			name = strings.Join(elems[len(elems)-1-n:], "/")
+		}
+		if matched, _ := filepath.Match(p.pattern, name); matched {
+			return p.level
+		}
+	}
+	return Verbosity()
+}
+
+// Enabled reports whether v logs.
+func (v Verbose) Enabled() bool {
+	return v.enabled
+}
+
+func (v Verbose) Info(args ...any) {
+	if v.enabled {
+		logDepth(zerolog.InfoLevel, v.level, fmt.Sprint(args...))
+	}
+}
+
+func (v Verbose) Infof(format string, args ...any) {
+	if v.enabled {
+		logDepth(zerolog.InfoLevel, v.level, fmt.Sprintf(format, args...))
+	}
+}
+
+func (v Verbose) Infoln(args ...any) {
+	if v.enabled {
+		logDepth(zerolog.InfoLevel, v.level, sprintln(args...))
+	}
+}
+
+func Info(args ...any)                 { logDepth(zerolog.InfoLevel, -1, fmt.Sprint(args...)) }
+func Infof(format string, args ...any) { logDepth(zerolog.InfoLevel, -1, fmt.Sprintf(format, args...)) }
+func Infoln(args ...any)               { logDepth(zerolog.InfoLevel, -1, sprintln(args...)) }
+func Warning(args ...any)              { logDepth(zerolog.WarnLevel, -1, fmt.Sprint(args...)) }
+func Warningf(format string, args ...any) {
+	logDepth(zerolog.WarnLevel, -1, fmt.Sprintf(format, args...))
+}
+func Warningln(args ...any) { logDepth(zerolog.WarnLevel, -1, sprintln(args...)) }
+func Error(args ...any)     { logDepth(zerolog.ErrorLevel, -1, fmt.Sprint(args...)) }
+func Errorf(format string, args ...any) {
+	logDepth(zerolog.ErrorLevel, -1, fmt.Sprintf(format, args...))
+}
+func Errorln(args ...any) { logDepth(zerolog.ErrorLevel, -1, sprintln(args...)) }
+func Fatal(args ...any)   { logDepth(zerolog.FatalLevel, -1, fmt.Sprint(args...)) }
+func Fatalf(format string, args ...any) {
+	logDepth(zerolog.FatalLevel, -1, fmt.Sprintf(format, args...))
+}
+func Fatalln(args ...any) { logDepth(zerolog.FatalLevel, -1, sprintln(args...)) }
+
+// Flush flushes the writers of the global sugarzero logger.
+func Flush() {
+	_ = sugarzero.Sync(resolve(context.Background()))
+}
+
+// logDepth writes msg at level, with verbosity unless it is negative; every
+// public function calls it directly so the caller position is known. Fatal
+// entries exit with status 255, as glog does.
+func logDepth(level zerolog.Level, v Level, msg string) {
+	ctx := resolve(context.Background())
+	if v >= 0 {
+		ctx = sugarzero.WithField(ctx, VerbosityFieldName, int(v))
+	}
+	write(ctx, level, callerSkip, msg)
+	if level == zerolog.FatalLevel {
+		_ = sugarzero.Sync(ctx)
+		os.Exit(255)
+	}
+}
+
+// resolve binds ctx to the sugarzero logger it writes to, so the global one
+// is used without the missing-logger warning; the facade targets it on
+// purpose.
+func resolve(ctx context.Context) context.Context {
+	if logger := sugarzero.FromContext(ctx); logger != nil {
+		return sugarzero.WithLogger(ctx, logger)
+	}
+	return ctx
+}
+
+// write logs msg through the logger in ctx, skipping skip frames above
+// write for the caller position.
+func write(ctx context.Context, level zerolog.Level, skip int, msg string) {
+	switch logger := sugarzero.FromContext(ctx).(type) {
+	case nil:
+	case *sugarzero.ZeroLogger:
+		logger.LogDepth(ctx, level, skip, msg)
+	default:
+		// Loggers other than sugarzero's ZeroLogger, such as mocks
+		switch level {
+		case zerolog.TraceLevel, zerolog.DebugLevel:
+			logger.Debug(ctx, msg)
+		case zerolog.InfoLevel:
+			logger.Info(ctx, msg)
+		case zerolog.WarnLevel:
+			logger.Warn(ctx, msg)
+		default:
+			logger.Error(ctx, msg)
+		}
+	}
+}
+
+// sprintln formats args like glog's *ln functions: always space-separated,
+// without the trailing newline.
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
@@ -0,0 +1,159 @@
+package glog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/compat/glog"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+		glog.SetVerbosity(0)
+		_ = glog.SetVModule("")
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+func TestVerbosityFlags(t *testing.T) {
+	_, buf := setup(t, "info")
+
+	fs := flag.NewFlagSet("component", flag.ContinueOnError)
+	glog.InitFlags(fs)
+	if err := fs.Parse([]string{"-v=2", "-logtostderr=true", "-alsologtostderr"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	glog.V(2).Infof("syncing %s", "pods")
+	glog.V(3).Info("too verbose")
+	glog.Warningln("lagging", 3)
+
+	got := entries(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(got), buf)
+	}
+	if got[0]["message"] != "syncing pods" || got[0][glog.VerbosityFieldName] != float64(2) || got[0]["level"] != "INFO" {
+		t.Fatalf("unexpected verbose entry %v", got[0])
+	}
+	if got[1]["message"] != "lagging 3" || got[1]["level"] != "WARN" {
+		t.Fatalf("unexpected warning entry %v", got[1])
+	}
+	if _, ok := got[1][glog.VerbosityFieldName]; ok {
+		t.Fatalf("expected no verbosity on plain entries, got %v", got[1])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "glog_test.go") {
+			t.Fatalf("expected the call site as position, got %v", entry["position"])
+		}
+	}
+}
+
+func TestVModuleOverridesVerbosity(t *testing.T) {
+	setup(t, "info")
+
+	if err := glog.SetVModule("glog_test=4"); err != nil {
+		t.Fatalf("SetVModule failed: %v", err)
+	}
+	if !glog.V(4).Enabled() || glog.V(5).Enabled() {
+		t.Fatal("expected -vmodule to set this file's verbosity to 4")
+	}
+	if err := glog.SetVModule("compat/glog/*=1,other=9"); err != nil {
+		t.Fatalf("SetVModule failed: %v", err)
+	}
+	if !glog.V(1).Enabled() || glog.V(2).Enabled() {
+		t.Fatal("expected a path pattern to match this file")
+	}
+	if err := glog.SetVModule("glog_test"); err == nil {
+		t.Fatal("expected an entry without a level to be rejected")
+	}
+}
+
+func TestRedirectStdLog(t *testing.T) {
+	ctx, buf := setup(t, "info")
+
+	flags := log.Flags()
+	restore, err := glog.RedirectStdLog(sugarzero.WithField(ctx, "component", "legacy"), "warn")
+	if err != nil {
+		t.Fatalf("RedirectStdLog failed: %v", err)
+	}
+	log.Printf("disk %d%% full", 91)
+	restore()
+	if log.Flags() != flags {
+		t.Fatal("expected restore to reset the log flags")
+	}
+
+	entry := entries(t, buf)[0]
+	if entry["message"] != "disk 91% full" || entry["level"] != "WARN" || entry["component"] != "legacy" {
+		t.Fatalf("unexpected redirected entry %v", entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "glog_test.go") {
+		t.Fatalf("expected the log.Printf call site as position, got %v", entry["position"])
+	}
+
+	logger, err := glog.NewStdLogger(ctx, "error")
+	if err != nil {
+		t.Fatalf("NewStdLogger failed: %v", err)
+	}
+	logger.Println("http: TLS handshake error")
+	if entry := entries(t, buf)[1]; entry["level"] != "ERROR" || entry["message"] != "http: TLS handshake error" {
+		t.Fatalf("unexpected std logger entry %v", entry)
+	}
+
+	if _, err := glog.NewStdLogger(ctx, "loud"); err == nil {
+		t.Fatal("expected an invalid level to be rejected")
+	}
+	sugarzero.Reset()
+	if _, err := glog.RedirectStdLog(context.Background(), "info"); !errors.Is(err, glog.ErrNoLogger) {
+		t.Fatalf("expected ErrNoLogger, got %v", err)
+	}
+}
+
+func TestShimAppliesLogInjectionProtection(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+	var buf bytes.Buffer
+	if _, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithLogInjectionProtection(),
+	); err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	glog.Info("login failed\nforged")
+
+	got := entries(t, &buf)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(got), buf.String())
+	}
+	if got[0]["message"] != `login failed\nforged` || got[0][sugarzero.LogInjectionFieldName] != true {
+		t.Fatalf("expected the shim message to be escaped and flagged, got %v", got[0])
+	}
+}
@@ -0,0 +1,81 @@
+package glog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// stdLogSkip skips stdLogWriter.Write and the log package's output and
+// print functions, so entries report the caller of log.Printf and friends.
+const stdLogSkip = 4
+
+// ErrNoLogger is returned when no sugarzero logger exists to redirect to.
+var ErrNoLogger = errors.New("sugarzero: no logger to redirect the standard log package to")
+
+// RedirectStdLog points the standard library's log package at the sugarzero
+// logger in ctx, or the global one, logging every line at level with the
+// context's fields. The log package's flags and prefix are cleared, as
+// sugarzero adds the time and position itself. The returned function
+// restores the previous output, flags, and prefix.
+// Example:
+//
+//	restore, err := glog.RedirectStdLog(ctx, "info")
+//	defer restore()
+func RedirectStdLog(ctx context.Context, level string) (restore func(), err error) {
+	w, err := newStdLogWriter(ctx, level)
+	if err != nil {
+		return func() {}, err
+	}
+	out, flags, prefix := log.Writer(), log.Flags(), log.Prefix()
+	log.SetOutput(w)
+	log.SetFlags(0)
+	log.SetPrefix("")
+	return func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+		log.SetPrefix(prefix)
+	}, nil
+}
+
+// NewStdLogger returns a *log.Logger writing to the sugarzero logger in ctx
+// at level, for APIs that take one, such as http.Server.ErrorLog.
+// Example: server.ErrorLog, err = glog.NewStdLogger(ctx, "error")
+func NewStdLogger(ctx context.Context, level string) (*log.Logger, error) {
+	w, err := newStdLogWriter(ctx, level)
+	if err != nil {
+		return nil, err
+	}
+	return log.New(w, "", 0), nil
+}
+
+type stdLogWriter struct {
+	ctx   context.Context
+	level zerolog.Level
+}
+
+func newStdLogWriter(ctx context.Context, level string) (io.Writer, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || lvl == zerolog.NoLevel {
+		return nil, fmt.Errorf("sugarzero: invalid standard log level %q", level)
+	}
+	if sugarzero.FromContext(ctx) == nil {
+		return nil, ErrNoLogger
+	}
+	return &stdLogWriter{ctx: resolve(ctx), level: lvl}, nil
+}
+
+// Write logs one line written by the log package.
+func (w *stdLogWriter) Write(p []byte) (int, error) {
+	write(w.ctx, w.level, stdLogSkip, strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
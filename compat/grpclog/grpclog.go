@@ -0,0 +1,222 @@
+// Package grpclog captures grpc-go's internal logs and net/http's HTTP/2
+// debug logs as structured sugarzero entries instead of unformatted stderr
+// lines.
+//
+// Logger implements grpclog.LoggerV2 and grpclog.DepthLoggerV2, so it is
+// installed with grpc's own setter, before any gRPC activity:
+//
+//	import sugargrpc "github.com/bigboss2063/sugarzero/compat/grpclog"
+//
+//	grpclog.SetLoggerV2(sugargrpc.NewLogger(ctx, sugargrpc.Config{InfoLevel: "debug"}))
+//
+// SetAsGRPCLogger in the grpcsetup module does the same, and checks at
+// compile time that Logger implements grpc's interfaces.
+//
+// HTTP/2 debug logs, enabled with GODEBUG=http2debug=1 or 2, are captured
+// with CaptureHTTP2Debug.
+package grpclog
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// ComponentFieldName is the key under which captured entries write their
+// source, "grpc" or "http2".
+const ComponentFieldName = "component"
+
+// GRPCComponentFieldName is the key under which the grpc-go subsystem that
+// logged, e.g. "core" or "transport", is written.
+const GRPCComponentFieldName = "grpc_component"
+
+// callerSkip skips write, Logger.log, the Logger method, and the grpclog
+// function calling it, so entries report the caller of grpc-go's grpclog
+// package, plus the depth grpc-go passes to the Depth methods.
+const callerSkip = 4
+
+// Config sets the sugarzero levels grpc-go's severities are logged at.
+// grpc-go is chatty at info, so InfoLevel is commonly lowered to "debug".
+type Config struct {
+	// InfoLevel defaults to "info".
+	InfoLevel string
+	// WarningLevel defaults to "warn".
+	WarningLevel string
+	// ErrorLevel defaults to "error".
+	ErrorLevel string
+	// Verbosity is the level up to which grpc's V(l) checks pass, as set by
+	// GRPC_GO_LOG_VERBOSITY_LEVEL.
+	Verbosity int
+}
+
+// Logger writes grpc-go's logs to the sugarzero logger of its context.
+type Logger struct {
+	ctx                       context.Context
+	info, warning, errorLevel zerolog.Level
+	verbosity                 int
+}
+
+// NewLogger returns a Logger writing to the sugarzero logger in ctx, or the
+// global one, at the levels in cfg. Invalid levels fall back to the defaults.
+func NewLogger(ctx context.Context, cfg Config) *Logger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Logger{
+		ctx:        sugarzero.WithField(resolve(ctx), ComponentFieldName, "grpc"),
+		info:       parseLevel(cfg.InfoLevel, zerolog.InfoLevel),
+		warning:    parseLevel(cfg.WarningLevel, zerolog.WarnLevel),
+		errorLevel: parseLevel(cfg.ErrorLevel, zerolog.ErrorLevel),
+		verbosity:  cfg.Verbosity,
+	}
+}
+
+func parseLevel(level string, fallback zerolog.Level) zerolog.Level {
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if level == "" || err != nil || lvl == zerolog.NoLevel {
+		return fallback
+	}
+	return lvl
+}
+
+func (l *Logger) Info(args ...any) {
+	l.log(l.info, 0, fmt.Sprint(args...))
+}
+
+func (l *Logger) Infoln(args ...any) {
+	l.log(l.info, 0, sprintln(args...))
+}
+
+func (l *Logger) Infof(format string, args ...any) {
+	l.log(l.info, 0, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Warning(args ...any) {
+	l.log(l.warning, 0, fmt.Sprint(args...))
+}
+
+func (l *Logger) Warningln(args ...any) {
+	l.log(l.warning, 0, sprintln(args...))
+}
+
+func (l *Logger) Warningf(format string, args ...any) {
+	l.log(l.warning, 0, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Error(args ...any) {
+	l.log(l.errorLevel, 0, fmt.Sprint(args...))
+}
+
+func (l *Logger) Errorln(args ...any) {
+	l.log(l.errorLevel, 0, sprintln(args...))
+}
+
+func (l *Logger) Errorf(format string, args ...any) {
+	l.log(l.errorLevel, 0, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Fatal(args ...any) {
+	l.log(zerolog.FatalLevel, 0, fmt.Sprint(args...))
+}
+
+func (l *Logger) Fatalln(args ...any) {
+	l.log(zerolog.FatalLevel, 0, sprintln(args...))
+}
+
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.log(zerolog.FatalLevel, 0, fmt.Sprintf(format, args...))
+}
+
+// The Depth methods implement grpclog.DepthLoggerV2; grpc-go passes the
+// number of frames to skip above grpclog.InfoDepth and the like.
+func (l *Logger) InfoDepth(depth int, args ...any) {
+	l.log(l.info, depth, fmt.Sprint(args...))
+}
+
+func (l *Logger) WarningDepth(depth int, args ...any) {
+	l.log(l.warning, depth, fmt.Sprint(args...))
+}
+
+func (l *Logger) ErrorDepth(depth int, args ...any) {
+	l.log(l.errorLevel, depth, fmt.Sprint(args...))
+}
+
+func (l *Logger) FatalDepth(depth int, args ...any) {
+	l.log(zerolog.FatalLevel, depth, fmt.Sprint(args...))
+}
+
+// V reports whether verbosity level is enabled.
+func (l *Logger) V(level int) bool {
+	return level <= l.verbosity
+}
+
+// log writes msg at level, moving grpc-go's "[component] " prefix to the
+// GRPCComponentFieldName field. Fatal entries exit with status 1, as
+// grpclog's default logger does.
+func (l *Logger) log(level zerolog.Level, depth int, msg string) {
+	ctx := l.ctx
+	if component, rest, ok := splitComponent(msg); ok {
+		ctx = sugarzero.WithField(ctx, GRPCComponentFieldName, component)
+		msg = rest
+	}
+	write(ctx, level, callerSkip+depth, msg)
+	if level == zerolog.FatalLevel {
+		_ = sugarzero.Sync(ctx)
+		os.Exit(1)
+	}
+}
+
+// splitComponent splits "[core] msg" into "core" and "msg".
+func splitComponent(msg string) (component, rest string, ok bool) {
+	if !strings.HasPrefix(msg, "[") {
+		return "", msg, false
+	}
+	end := strings.IndexByte(msg, ']')
+	if end < 2 || strings.ContainsAny(msg[1:end], " \t") {
+		return "", msg, false
+	}
+	return msg[1:end], strings.TrimPrefix(msg[end+1:], " "), true
+}
+
+// resolve binds ctx to the sugarzero logger it writes to, so the global one
+// is used without the missing-logger warning; the package targets it on
+// purpose.
+func resolve(ctx context.Context) context.Context {
+	if logger := sugarzero.FromContext(ctx); logger != nil {
+		return sugarzero.WithLogger(ctx, logger)
+	}
+	return ctx
+}
+
+// write logs msg through the logger in ctx, skipping skip frames above
+// write for the caller position.
+func write(ctx context.Context, level zerolog.Level, skip int, msg string) {
+	switch logger := sugarzero.FromContext(ctx).(type) {
+	case nil:
+	case *sugarzero.ZeroLogger:
+		logger.LogDepth(ctx, level, skip, msg)
+	default:
+		// Loggers other than sugarzero's ZeroLogger, such as mocks
+		switch level {
+		case zerolog.TraceLevel, zerolog.DebugLevel:
+			logger.Debug(ctx, msg)
+		case zerolog.InfoLevel:
+			logger.Info(ctx, msg)
+		case zerolog.WarnLevel:
+			logger.Warn(ctx, msg)
+		default:
+			logger.Error(ctx, msg)
+		}
+	}
+}
+
+// sprintln formats args like grpclog's *ln methods: always space-separated,
+// without the trailing newline.
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
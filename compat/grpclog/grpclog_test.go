@@ -0,0 +1,133 @@
+package grpclog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/compat/grpclog"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+// The helpers below call the Logger like grpc-go's grpclog package functions,
+// one frame above the caller.
+func infof(logger *grpclog.Logger, format string, args ...any) {
+	logger.Infof(format, args...)
+}
+
+func warningf(logger *grpclog.Logger, format string, args ...any) {
+	logger.Warningf(format, args...)
+}
+
+func errorln(logger *grpclog.Logger, args ...any) {
+	logger.Errorln(args...)
+}
+
+// forward logs like grpc-go's component loggers, one more frame above.
+func forward(logger *grpclog.Logger, args ...any) {
+	warningDepth(logger, 1, args...)
+}
+
+func warningDepth(logger *grpclog.Logger, depth int, args ...any) {
+	logger.WarningDepth(depth, args...)
+}
+
+func TestLoggerMapsSeverities(t *testing.T) {
+	ctx, buf := setup(t, "info")
+
+	logger := grpclog.NewLogger(ctx, grpclog.Config{InfoLevel: "debug", Verbosity: 2})
+	infof(logger, "[core] Channel #%d created", 1)
+	warningf(logger, "[transport] closing: %v", "EOF")
+	errorln(logger, "[xds]", "resource", "missing")
+	forward(logger, "deprecated option")
+
+	got := entries(t, buf)
+	if len(got) != 3 {
+		t.Fatalf("expected the info entry to be logged at debug and dropped, got %d: %s", len(got), buf)
+	}
+	warn := got[0]
+	if warn["level"] != "WARN" || warn["message"] != "closing: EOF" || warn[grpclog.GRPCComponentFieldName] != "transport" || warn[grpclog.ComponentFieldName] != "grpc" {
+		t.Fatalf("unexpected warning entry %v", warn)
+	}
+	if got[1]["level"] != "ERROR" || got[1]["message"] != "resource missing" || got[1][grpclog.GRPCComponentFieldName] != "xds" {
+		t.Fatalf("unexpected error entry %v", got[1])
+	}
+	if _, ok := got[2][grpclog.GRPCComponentFieldName]; ok || got[2]["message"] != "deprecated option" {
+		t.Fatalf("unexpected depth entry %v", got[2])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "grpclog_test.go") {
+			t.Fatalf("expected the caller as position, got %v", entry["position"])
+		}
+	}
+
+	if !logger.V(2) || logger.V(3) {
+		t.Fatal("expected V to follow the configured verbosity")
+	}
+}
+
+func TestCaptureHTTP2Debug(t *testing.T) {
+	ctx, buf := setup(t, "debug")
+
+	var other bytes.Buffer
+	out, flags := log.Writer(), log.Flags()
+	log.SetOutput(&other)
+	t.Cleanup(func() {
+		log.SetOutput(out)
+		log.SetFlags(flags)
+	})
+
+	restore, err := grpclog.CaptureHTTP2Debug(ctx, "debug")
+	if err != nil {
+		t.Fatalf("CaptureHTTP2Debug failed: %v", err)
+	}
+	log.Printf("http2: Framer %p: wrote SETTINGS len=18", buf)
+	log.Print("unrelated line")
+	restore()
+	log.Print("http2: after restore")
+
+	got := entries(t, buf)
+	if len(got) != 1 {
+		t.Fatalf("expected one captured entry, got %d: %s", len(got), buf)
+	}
+	if got[0]["level"] != "DEBUG" || got[0][grpclog.ComponentFieldName] != "http2" || !strings.HasPrefix(got[0]["message"].(string), "Framer ") {
+		t.Fatalf("unexpected captured entry %v", got[0])
+	}
+	if !strings.Contains(other.String(), "unrelated line") || !strings.Contains(other.String(), "after restore") {
+		t.Fatalf("expected other lines to reach the previous output, got %q", other.String())
+	}
+
+	if _, err := grpclog.CaptureHTTP2Debug(ctx, "loud"); err == nil {
+		t.Fatal("expected an invalid level to be rejected")
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/bigboss2063/sugarzero/compat/grpclog/grpcsetup
+
+go 1.24.5
+
+require (
+	github.com/bigboss2063/sugarzero v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.78.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-colorable v0.1.13 // indirect
+	github.com/mattn/go-isatty v0.0.19 // indirect
+	github.com/rs/zerolog v1.33.0 // indirect
+	go.opentelemetry.io/auto/sdk v1.2.1 // indirect
+	go.opentelemetry.io/otel v1.38.0 // indirect
+	go.opentelemetry.io/otel/metric v1.38.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.38.0 // indirect
+	go.opentelemetry.io/otel/trace v1.38.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+)
+
+replace github.com/bigboss2063/sugarzero => ../../..
@@ -0,0 +1,30 @@
+// Package grpcsetup installs the grpclog adapter of compat/grpclog as
+// grpc-go's logger. It is a module of its own so that only programs using it
+// depend on grpc:
+//
+//	import "github.com/bigboss2063/sugarzero/compat/grpclog/grpcsetup"
+//
+//	grpcsetup.SetAsGRPCLogger(ctx, sugargrpc.Config{InfoLevel: "debug"})
+package grpcsetup
+
+import (
+	"context"
+
+	sugargrpc "github.com/bigboss2063/sugarzero/compat/grpclog"
+	"google.golang.org/grpc/grpclog"
+)
+
+var (
+	_ grpclog.LoggerV2      = (*sugargrpc.Logger)(nil)
+	_ grpclog.DepthLoggerV2 = (*sugargrpc.Logger)(nil)
+)
+
+// SetAsGRPCLogger installs a Logger writing to the sugarzero logger in ctx as
+// grpc-go's logger, and returns it. Like grpclog.SetLoggerV2, it must be
+// called before any gRPC activity.
+// Example: grpcsetup.SetAsGRPCLogger(ctx, sugargrpc.Config{InfoLevel: "debug"})
+func SetAsGRPCLogger(ctx context.Context, cfg sugargrpc.Config) *sugargrpc.Logger {
+	logger := sugargrpc.NewLogger(ctx, cfg)
+	grpclog.SetLoggerV2(logger)
+	return logger
+}
@@ -0,0 +1,54 @@
+package grpcsetup_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	sugargrpc "github.com/bigboss2063/sugarzero/compat/grpclog"
+	"github.com/bigboss2063/sugarzero/compat/grpclog/grpcsetup"
+	"google.golang.org/grpc/grpclog"
+)
+
+func TestSetAsGRPCLoggerCapturesGRPCLogs(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), "info", &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	grpcsetup.SetAsGRPCLogger(ctx, sugargrpc.Config{Verbosity: 2})
+	grpclog.Component("transport").Warningf("closing: %v", "EOF")
+	grpclog.Infof("[core] Channel #%d created", 1)
+
+	var got []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		got = append(got, entry)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(got), buf.String())
+	}
+	if got[0]["level"] != "WARN" || got[0][sugargrpc.GRPCComponentFieldName] != "transport" || got[0]["message"] != "closing: EOF" {
+		t.Fatalf("unexpected component entry %v", got[0])
+	}
+	if got[1]["level"] != "INFO" || got[1][sugargrpc.GRPCComponentFieldName] != "core" || got[1][sugargrpc.ComponentFieldName] != "grpc" {
+		t.Fatalf("unexpected info entry %v", got[1])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "setlogger_test.go") {
+			t.Fatalf("expected the call into grpclog as position, got %v", entry["position"])
+		}
+	}
+	if !grpclog.V(2) || grpclog.V(3) {
+		t.Fatal("expected grpclog.V to follow the configured verbosity")
+	}
+}
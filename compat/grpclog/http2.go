@@ -0,0 +1,84 @@
+package grpclog
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// http2Prefix starts every line net/http's HTTP/2 code logs.
+const http2Prefix = "http2: "
+
+// http2Skip skips write, http2Writer.Write, and the log package's output and
+// Printf, so entries report the net/http code that logged.
+const http2Skip = 4
+
+// HTTP2DebugEnabled reports whether GODEBUG enables net/http's HTTP/2 debug
+// logs. GODEBUG is read at program start, so it cannot be switched on here.
+func HTTP2DebugEnabled() bool {
+	godebug := os.Getenv("GODEBUG")
+	return strings.Contains(godebug, "http2debug=1") || strings.Contains(godebug, "http2debug=2")
+}
+
+// CaptureHTTP2Debug logs the lines net/http's HTTP/2 code writes to the
+// standard log package, "http2: ..." lines, to the sugarzero logger in ctx
+// at level, with ComponentFieldName set to "http2". Other lines still go to
+// the previous log output unchanged. The returned function restores it.
+// Example:
+//
+//	restore, err := sugargrpc.CaptureHTTP2Debug(ctx, "debug")
+//	defer restore()
+func CaptureHTTP2Debug(ctx context.Context, level string) (restore func(), err error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+	if err != nil || lvl == zerolog.NoLevel {
+		return func() {}, fmt.Errorf("sugarzero: invalid HTTP/2 debug log level %q", level)
+	}
+	if sugarzero.FromContext(ctx) == nil {
+		return func() {}, fmt.Errorf("sugarzero: no logger to capture HTTP/2 debug logs")
+	}
+	w := &http2Writer{
+		ctx:   sugarzero.WithField(resolve(ctx), ComponentFieldName, "http2"),
+		level: lvl,
+		next:  log.Writer(),
+	}
+	log.SetOutput(w)
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			// Leave a later redirection in place
+			if log.Writer() == io.Writer(w) {
+				log.SetOutput(w.next)
+			}
+		})
+	}, nil
+}
+
+type http2Writer struct {
+	ctx   context.Context
+	level zerolog.Level
+	next  io.Writer
+}
+
+// Write logs a line written by the log package if it comes from net/http's
+// HTTP/2 code, whatever log flags and prefix precede it, and passes any
+// other line on.
+func (w *http2Writer) Write(p []byte) (int, error) {
+	i := bytes.Index(p, []byte(http2Prefix))
+	if i < 0 {
+		return w.next.Write(p)
+	}
+	msg := strings.TrimSuffix(string(p[i+len(http2Prefix):]), "\n")
+	write(w.ctx, w.level, http2Skip, msg)
+	return len(p), nil
+}
@@ -0,0 +1,281 @@
+// Package logrus is a drop-in facade for the most used parts of the
+// github.com/sirupsen/logrus API that writes through sugarzero, so a codebase
+// can move off logrus by changing imports instead of every call site:
+//
+//	import log "github.com/bigboss2063/sugarzero/compat/logrus"
+//
+//	log.WithFields(log.Fields{"order_id": id}).Warnf("retrying %s", step)
+//
+// Fields, errors, and levels map onto sugarzero's, and entries keep the
+// position of the original call site. Loggers write to the sugarzero logger
+// of their context, the global one for New and the package-level functions.
+//
+// Code that keeps real logrus loggers can forward their entries with the hook
+// in the logrushook module, which requires the logrus module.
+package logrus
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// callerSkip skips Entry.log and the public method that called it, so
+// entries report the caller of the facade.
+const callerSkip = 2
+
+// Fields is a set of fields, as in logrus.
+type Fields map[string]any
+
+// Level is a logrus level; lower values are more severe.
+type Level uint32
+
+// Levels, in the order used by logrus.
+const (
+	PanicLevel Level = iota
+	FatalLevel
+	ErrorLevel
+	WarnLevel
+	InfoLevel
+	DebugLevel
+	TraceLevel
+)
+
+// AllLevels lists every level, most severe first.
+var AllLevels = []Level{PanicLevel, FatalLevel, ErrorLevel, WarnLevel, InfoLevel, DebugLevel, TraceLevel}
+
+var levelNames = map[Level]string{
+	PanicLevel: "panic",
+	FatalLevel: "fatal",
+	ErrorLevel: "error",
+	WarnLevel:  "warning",
+	InfoLevel:  "info",
+	DebugLevel: "debug",
+	TraceLevel: "trace",
+}
+
+// String returns the logrus name of the level, e.g. "warning".
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseLevel parses a logrus level name, accepting "warn" for "warning".
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "warn", "warning":
+		return WarnLevel, nil
+	}
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return 0, fmt.Errorf("not a valid logrus Level: %q", name)
+}
+
+func (l Level) zerolog() zerolog.Level {
+	switch l {
+	case PanicLevel:
+		return zerolog.PanicLevel
+	case FatalLevel:
+		return zerolog.FatalLevel
+	case ErrorLevel:
+		return zerolog.ErrorLevel
+	case WarnLevel:
+		return zerolog.WarnLevel
+	case DebugLevel:
+		return zerolog.DebugLevel
+	case TraceLevel:
+		return zerolog.TraceLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+func fromZerolog(level zerolog.Level) Level {
+	for _, l := range AllLevels {
+		if l.zerolog() == level {
+			return l
+		}
+	}
+	return InfoLevel
+}
+
+// Logger is the facade for *logrus.Logger. Its level is the sugarzero
+// logger's level.
+type Logger struct {
+	ctx context.Context
+	// ExitFunc is called with 1 after a fatal entry; nil uses os.Exit.
+	ExitFunc func(code int)
+}
+
+// New returns a Logger writing to the global sugarzero logger.
+func New() *Logger {
+	return &Logger{ctx: context.Background()}
+}
+
+// NewWithContext returns a Logger writing to the sugarzero logger in ctx,
+// with the context's fields on every entry.
+func NewWithContext(ctx context.Context) *Logger {
+	return &Logger{ctx: ctx}
+}
+
+var std = New()
+
+// StandardLogger returns the Logger behind the package-level functions.
+func StandardLogger() *Logger {
+	return std
+}
+
+// SetLevel sets the level of the underlying sugarzero logger.
+func (l *Logger) SetLevel(level Level) {
+	_ = sugarzero.SetLogLevel(l.ctx, level.zerolog().String())
+}
+
+// GetLevel returns the level of the underlying sugarzero logger.
+func (l *Logger) GetLevel() Level {
+	level, err := zerolog.ParseLevel(strings.ToLower(sugarzero.GetLogLevel(l.ctx)))
+	if err != nil {
+		return InfoLevel
+	}
+	return fromZerolog(level)
+}
+
+// IsLevelEnabled reports whether entries at level are written.
+func (l *Logger) IsLevelEnabled(level Level) bool {
+	return l.GetLevel() >= level
+}
+
+func (l *Logger) entry() *Entry {
+	return &Entry{Logger: l, Data: Fields{}, Context: l.ctx}
+}
+
+// Entry is the facade for *logrus.Entry: fields waiting to be logged.
+type Entry struct {
+	Logger  *Logger
+	Data    Fields
+	Context context.Context
+	// Message is set on the entry passed to panic by the Panic methods.
+	Message string
+	err     error
+}
+
+// NewEntry returns an empty Entry of logger.
+func NewEntry(logger *Logger) *Entry {
+	return logger.entry()
+}
+
+// WithField returns a copy of e with key set to value.
+func (e *Entry) WithField(key string, value any) *Entry {
+	return e.WithFields(Fields{key: value})
+}
+
+// WithFields returns a copy of e with fields added.
+func (e *Entry) WithFields(fields Fields) *Entry {
+	data := make(Fields, len(e.Data)+len(fields))
+	maps.Copy(data, e.Data)
+	maps.Copy(data, fields)
+	return &Entry{Logger: e.Logger, Data: data, Context: e.Context, err: e.err}
+}
+
+// WithError returns a copy of e carrying err, logged like sugarzero.WithError.
+func (e *Entry) WithError(err error) *Entry {
+	entry := e.WithFields(nil)
+	entry.err = err
+	return entry
+}
+
+// WithContext returns a copy of e logging through ctx, e.g. for its trace.
+func (e *Entry) WithContext(ctx context.Context) *Entry {
+	entry := e.WithFields(nil)
+	entry.Context = ctx
+	return entry
+}
+
+// log writes msg at level; every public method calls it directly so the
+// caller position is callerSkip frames up.
+func (e *Entry) log(level Level, msg string) {
+	ctx := e.write(level, msg, callerSkip+1)
+
+	switch level {
+	case FatalLevel:
+		_ = sugarzero.Sync(ctx)
+		exit := os.Exit
+		if e.Logger != nil && e.Logger.ExitFunc != nil {
+			exit = e.Logger.ExitFunc
+		}
+		exit(1)
+	case PanicLevel:
+		entry := e.WithFields(nil)
+		entry.Message = msg
+		panic(entry)
+	}
+}
+
+// LogDepth writes msg at level with the entry's fields and error, positioned
+// depth frames above the caller of LogDepth, for adapters such as logrushook.
+// Unlike the level methods it neither exits nor panics.
+func (e *Entry) LogDepth(level Level, depth int, msg string) {
+	// The first frame above write is LogDepth, the second its caller
+	e.write(level, msg, depth+2)
+}
+
+// write writes msg at level with the entry's fields and error, positioned
+// skip frames above write, and returns the context it logged with.
+func (e *Entry) write(level Level, msg string, skip int) context.Context {
+	ctx := e.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	// Bind the resolved logger so the global one is used without the
+	// missing-logger warning; the facade targets it on purpose.
+	logger := sugarzero.FromContext(ctx)
+	if logger != nil {
+		ctx = sugarzero.WithLogger(ctx, logger)
+	}
+	if len(e.Data) > 0 {
+		keyvals := make([]any, 0, 2*len(e.Data))
+		for _, key := range slices.Sorted(maps.Keys(e.Data)) {
+			keyvals = append(keyvals, key, e.Data[key])
+		}
+		ctx = sugarzero.WithFields(ctx, keyvals...)
+	}
+	if e.err != nil {
+		ctx = sugarzero.WithError(ctx, e.err)
+	}
+
+	switch zl := logger.(type) {
+	case nil:
+	case *sugarzero.ZeroLogger:
+		zl.LogDepth(ctx, level.zerolog(), skip, msg)
+	default:
+		// Loggers other than sugarzero's ZeroLogger, such as mocks
+		switch level {
+		case TraceLevel, DebugLevel:
+			zl.Debug(ctx, msg)
+		case InfoLevel:
+			zl.Info(ctx, msg)
+		case WarnLevel:
+			zl.Warn(ctx, msg)
+		default:
+			zl.Error(ctx, msg)
+		}
+	}
+	return ctx
+}
+
+// sprintln formats args like logrus' *ln methods: always space-separated,
+// without the trailing newline.
+func sprintln(args ...any) string {
+	msg := fmt.Sprintln(args...)
+	return msg[:len(msg)-1]
+}
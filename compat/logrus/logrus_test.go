@@ -0,0 +1,142 @@
+package logrus_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	log "github.com/bigboss2063/sugarzero/compat/logrus"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+func TestFacadeForwardsFieldsAndLevels(t *testing.T) {
+	_, buf := setup(t, "debug")
+
+	log.WithFields(log.Fields{"order_id": 42, "step": "charge"}).Warnf("retrying %s", "payment")
+	log.WithError(errors.New("card declined")).WithField("user", "u1").Error("payment failed")
+	log.Debugln("cache", "miss")
+	log.Trace("dropped below debug")
+	log.StandardLogger().Print("printed")
+
+	got := entries(t, buf)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 entries, got %d: %s", len(got), buf)
+	}
+	if got[0]["level"] != "WARN" || got[0]["message"] != "retrying payment" || got[0]["order_id"] != float64(42) || got[0]["step"] != "charge" {
+		t.Fatalf("unexpected warn entry %v", got[0])
+	}
+	if got[1]["level"] != "ERROR" || got[1]["error"] != "card declined" || got[1]["user"] != "u1" {
+		t.Fatalf("unexpected error entry %v", got[1])
+	}
+	if got[2]["level"] != "DEBUG" || got[2]["message"] != "cache miss" {
+		t.Fatalf("unexpected debug entry %v", got[2])
+	}
+	if got[3]["level"] != "INFO" || got[3]["message"] != "printed" {
+		t.Fatalf("unexpected print entry %v", got[3])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "logrus_test.go") {
+			t.Fatalf("expected the call site as position, got %v", entry["position"])
+		}
+	}
+}
+
+func TestFacadeUsesContextLogger(t *testing.T) {
+	ctx, buf := setup(t, "info")
+
+	logger := log.NewWithContext(sugarzero.WithField(ctx, "request_id", "r1"))
+	logger.Infof("handled %d", 3)
+	if got := entries(t, buf)[0]; got["request_id"] != "r1" || got["message"] != "handled 3" {
+		t.Fatalf("expected context fields, got %v", got)
+	}
+}
+
+func TestFacadeLevels(t *testing.T) {
+	setup(t, "info")
+
+	if log.GetLevel() != log.InfoLevel || log.IsLevelEnabled(log.DebugLevel) {
+		t.Fatalf("expected info level, got %s", log.GetLevel())
+	}
+	log.SetLevel(log.WarnLevel)
+	if log.GetLevel() != log.WarnLevel || sugarzero.GetLogLevel(context.Background()) != "warn" {
+		t.Fatalf("expected warn level, got %s", log.GetLevel())
+	}
+	if level, err := log.ParseLevel("WARN"); err != nil || level != log.WarnLevel || level.String() != "warning" {
+		t.Fatalf("ParseLevel returned %v, %v", level, err)
+	}
+	if _, err := log.ParseLevel("loud"); err == nil {
+		t.Fatal("expected an invalid level to be rejected")
+	}
+}
+
+func TestFacadeFatalAndPanic(t *testing.T) {
+	_, buf := setup(t, "info")
+
+	var code int
+	logger := log.New()
+	logger.ExitFunc = func(c int) { code = c }
+	logger.Fatal("shutting down")
+	if code != 1 || entries(t, buf)[0]["level"] != "FATAL" {
+		t.Fatalf("expected a fatal entry and exit code 1, got %d: %s", code, buf)
+	}
+
+	defer func() {
+		entry, ok := recover().(*log.Entry)
+		if !ok || entry.Message != "invariant broken" {
+			t.Fatalf("expected to panic with the entry, got %v", entry)
+		}
+	}()
+	logger.WithField("k", "v").Panic("invariant broken")
+}
+
+func TestFacadeEscapesLineBreaksInStrictMode(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+	var buf bytes.Buffer
+	if _, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithMessageSafety(sugarzero.MessagesStrict),
+	); err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	log.Warnf("login failed for %s", "bob\r\n"+`{"level":"INFO","message":"login ok for admin"}`)
+
+	got := entries(t, &buf)
+	if len(got) != 1 {
+		t.Fatalf("expected the forged line to stay in 1 entry, got %d: %s", len(got), buf.String())
+	}
+	if want := `login failed for bob\r\n{"level":"INFO","message":"login ok for admin"}`; got[0]["message"] != want {
+		t.Fatalf("expected %q, got %q", want, got[0]["message"])
+	}
+}
@@ -0,0 +1,89 @@
+// Package logrushook forwards the entries of github.com/sirupsen/logrus
+// loggers to sugarzero, for code that keeps its logrus loggers, such as
+// dependencies. It is a module of its own so that only programs using it
+// depend on logrus:
+//
+//	import "github.com/bigboss2063/sugarzero/compat/logrus/logrushook"
+//
+//	logrus.SetOutput(io.Discard)
+//	logrus.StandardLogger().AddHook(logrushook.NewHook(ctx))
+//
+// Code moving off logrus can use the facade in compat/logrus instead.
+package logrushook
+
+import (
+	"context"
+	"runtime"
+	"strings"
+
+	log "github.com/bigboss2063/sugarzero/compat/logrus"
+	"github.com/sirupsen/logrus"
+)
+
+// Hook is a logrus hook writing every entry fired by a logrus logger through
+// sugarzero. Entries logged WithContext use that context's sugarzero logger
+// and fields. Silence the logrus output, e.g. with SetOutput(io.Discard), so
+// entries are not written twice.
+// Example: logrus.StandardLogger().AddHook(logrushook.NewHook(ctx))
+type Hook struct {
+	ctx    context.Context
+	levels []logrus.Level
+}
+
+var _ logrus.Hook = (*Hook)(nil)
+
+// NewHook returns a Hook writing to the sugarzero logger in ctx, fired for
+// levels, or every level if none are given.
+func NewHook(ctx context.Context, levels ...logrus.Level) *Hook {
+	if len(levels) == 0 {
+		levels = logrus.AllLevels
+	}
+	return &Hook{ctx: ctx, levels: levels}
+}
+
+// Levels returns the levels the hook is fired for.
+func (h *Hook) Levels() []logrus.Level {
+	return h.levels
+}
+
+// Fire writes entry through sugarzero at its level, positioned at the call
+// into logrus. Fatal and panic entries are only written: logrus itself exits
+// or panics after firing its hooks.
+func (h *Hook) Fire(entry *logrus.Entry) error {
+	e := &log.Entry{Data: make(log.Fields, len(entry.Data)), Context: entry.Context}
+	if e.Context == nil {
+		e.Context = h.ctx
+	}
+	var err error
+	for key, value := range entry.Data {
+		if valueErr, ok := value.(error); ok && key == logrus.ErrorKey {
+			err = valueErr
+			continue
+		}
+		e.Data[key] = value
+	}
+	if err != nil {
+		e = e.WithError(err)
+	}
+	// Level values are the same as logrus'
+	e.LogDepth(log.Level(entry.Level), 1+logrusDepth(), entry.Message)
+	return nil
+}
+
+// logrusDepth returns the number of logrus frames calling Fire.
+func logrusDepth() int {
+	var pcs [32]uintptr
+	// Skips runtime.Callers, logrusDepth, and Fire
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/sirupsen/logrus.") {
+			return depth
+		}
+		depth++
+		if !more {
+			return depth
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package logrushook_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/compat/logrus/logrushook"
+	"github.com/sirupsen/logrus"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+func newLogger(ctx context.Context, levels ...logrus.Level) *logrus.Logger {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.SetLevel(logrus.TraceLevel)
+	logger.AddHook(logrushook.NewHook(ctx, levels...))
+	return logger
+}
+
+func TestHookForwardsLogrusEntries(t *testing.T) {
+	ctx, buf := setup(t, "debug")
+	logger := newLogger(ctx)
+
+	logger.WithError(errors.New("card declined")).WithField("order_id", 42).Warn("payment failed")
+	logger.Infof("charged %s", "u1")
+	logger.Trace("dropped below debug")
+
+	got := entries(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(got), buf)
+	}
+	if got[0]["level"] != "WARN" || got[0]["message"] != "payment failed" || got[0]["error"] != "card declined" || got[0]["order_id"] != float64(42) {
+		t.Fatalf("unexpected warn entry %v", got[0])
+	}
+	if got[1]["level"] != "INFO" || got[1]["message"] != "charged u1" {
+		t.Fatalf("unexpected info entry %v", got[1])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "hook_test.go") {
+			t.Fatalf("expected the call into logrus as position, got %v", entry["position"])
+		}
+	}
+}
+
+func TestHookUsesEntryContext(t *testing.T) {
+	ctx, buf := setup(t, "info")
+	logger := newLogger(context.Background())
+
+	logger.WithContext(sugarzero.WithField(ctx, "request_id", "r1")).Info("handled")
+
+	got := entries(t, buf)
+	if len(got) != 1 || got[0]["request_id"] != "r1" {
+		t.Fatalf("expected the entry context's fields, got %s", buf)
+	}
+}
+
+func TestHookFiresForGivenLevels(t *testing.T) {
+	ctx, buf := setup(t, "debug")
+	logger := newLogger(ctx, logrus.ErrorLevel)
+
+	logger.Info("skipped")
+	logger.Error("failed")
+
+	got := entries(t, buf)
+	if len(got) != 1 || got[0]["message"] != "failed" {
+		t.Fatalf("expected only the error entry, got %s", buf)
+	}
+}
+
+func TestHookEscapesLineBreaksInStrictMode(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithMessageSafety(sugarzero.MessagesStrict),
+	)
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	newLogger(ctx).Warn("login failed for bob\r\nforged")
+
+	got := entries(t, &buf)
+	if len(got) != 1 || got[0]["message"] != `login failed for bob\r\nforged` {
+		t.Fatalf("expected the message escaped in 1 entry, got %s", buf.String())
+	}
+}
@@ -0,0 +1,418 @@
+package logrus
+
+import (
+	"context"
+	"fmt"
+)
+
+// The logging methods of Entry, Logger, and the package mirror logrus. Each
+// one calls Entry.log directly, which keeps caller positions correct.
+
+// Log logs args at level.
+func (e *Entry) Log(level Level, args ...any) {
+	e.log(level, fmt.Sprint(args...))
+}
+
+// Logf logs a formatted message at level.
+func (e *Entry) Logf(level Level, format string, args ...any) {
+	e.log(level, fmt.Sprintf(format, args...))
+}
+
+// Logln logs args, separated by spaces, at level.
+func (e *Entry) Logln(level Level, args ...any) {
+	e.log(level, sprintln(args...))
+}
+
+func (e *Entry) Trace(args ...any) {
+	e.log(TraceLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Tracef(format string, args ...any) {
+	e.log(TraceLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Traceln(args ...any) {
+	e.log(TraceLevel, sprintln(args...))
+}
+
+func (e *Entry) Debug(args ...any) {
+	e.log(DebugLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Debugf(format string, args ...any) {
+	e.log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Debugln(args ...any) {
+	e.log(DebugLevel, sprintln(args...))
+}
+
+func (e *Entry) Info(args ...any) {
+	e.log(InfoLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Infof(format string, args ...any) {
+	e.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Infoln(args ...any) {
+	e.log(InfoLevel, sprintln(args...))
+}
+
+func (e *Entry) Print(args ...any) {
+	e.log(InfoLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Printf(format string, args ...any) {
+	e.log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Println(args ...any) {
+	e.log(InfoLevel, sprintln(args...))
+}
+
+func (e *Entry) Warn(args ...any) {
+	e.log(WarnLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Warnf(format string, args ...any) {
+	e.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Warnln(args ...any) {
+	e.log(WarnLevel, sprintln(args...))
+}
+
+func (e *Entry) Warning(args ...any) {
+	e.log(WarnLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Warningf(format string, args ...any) {
+	e.log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Warningln(args ...any) {
+	e.log(WarnLevel, sprintln(args...))
+}
+
+func (e *Entry) Error(args ...any) {
+	e.log(ErrorLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Errorf(format string, args ...any) {
+	e.log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Errorln(args ...any) {
+	e.log(ErrorLevel, sprintln(args...))
+}
+
+func (e *Entry) Fatal(args ...any) {
+	e.log(FatalLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Fatalf(format string, args ...any) {
+	e.log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Fatalln(args ...any) {
+	e.log(FatalLevel, sprintln(args...))
+}
+
+func (e *Entry) Panic(args ...any) {
+	e.log(PanicLevel, fmt.Sprint(args...))
+}
+
+func (e *Entry) Panicf(format string, args ...any) {
+	e.log(PanicLevel, fmt.Sprintf(format, args...))
+}
+
+func (e *Entry) Panicln(args ...any) {
+	e.log(PanicLevel, sprintln(args...))
+}
+
+// WithField returns an Entry with key set to value.
+func (l *Logger) WithField(key string, value any) *Entry {
+	return l.entry().WithField(key, value)
+}
+
+// WithFields returns an Entry with fields.
+func (l *Logger) WithFields(fields Fields) *Entry {
+	return l.entry().WithFields(fields)
+}
+
+// WithError returns an Entry carrying err.
+func (l *Logger) WithError(err error) *Entry {
+	return l.entry().WithError(err)
+}
+
+// WithContext returns an Entry logging through ctx.
+func (l *Logger) WithContext(ctx context.Context) *Entry {
+	return l.entry().WithContext(ctx)
+}
+
+// Log logs args at level.
+func (l *Logger) Log(level Level, args ...any) {
+	l.entry().log(level, fmt.Sprint(args...))
+}
+
+// Logf logs a formatted message at level.
+func (l *Logger) Logf(level Level, format string, args ...any) {
+	l.entry().log(level, fmt.Sprintf(format, args...))
+}
+
+// Logln logs args, separated by spaces, at level.
+func (l *Logger) Logln(level Level, args ...any) {
+	l.entry().log(level, sprintln(args...))
+}
+
+func (l *Logger) Trace(args ...any) {
+	l.entry().log(TraceLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Tracef(format string, args ...any) {
+	l.entry().log(TraceLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Traceln(args ...any) {
+	l.entry().log(TraceLevel, sprintln(args...))
+}
+
+func (l *Logger) Debug(args ...any) {
+	l.entry().log(DebugLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Debugf(format string, args ...any) {
+	l.entry().log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Debugln(args ...any) {
+	l.entry().log(DebugLevel, sprintln(args...))
+}
+
+func (l *Logger) Info(args ...any) {
+	l.entry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Infof(format string, args ...any) {
+	l.entry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Infoln(args ...any) {
+	l.entry().log(InfoLevel, sprintln(args...))
+}
+
+func (l *Logger) Print(args ...any) {
+	l.entry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Printf(format string, args ...any) {
+	l.entry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Println(args ...any) {
+	l.entry().log(InfoLevel, sprintln(args...))
+}
+
+func (l *Logger) Warn(args ...any) {
+	l.entry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Warnf(format string, args ...any) {
+	l.entry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Warnln(args ...any) {
+	l.entry().log(WarnLevel, sprintln(args...))
+}
+
+func (l *Logger) Warning(args ...any) {
+	l.entry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Warningf(format string, args ...any) {
+	l.entry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Warningln(args ...any) {
+	l.entry().log(WarnLevel, sprintln(args...))
+}
+
+func (l *Logger) Error(args ...any) {
+	l.entry().log(ErrorLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Errorf(format string, args ...any) {
+	l.entry().log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Errorln(args ...any) {
+	l.entry().log(ErrorLevel, sprintln(args...))
+}
+
+func (l *Logger) Fatal(args ...any) {
+	l.entry().log(FatalLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Fatalf(format string, args ...any) {
+	l.entry().log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Fatalln(args ...any) {
+	l.entry().log(FatalLevel, sprintln(args...))
+}
+
+func (l *Logger) Panic(args ...any) {
+	l.entry().log(PanicLevel, fmt.Sprint(args...))
+}
+
+func (l *Logger) Panicf(format string, args ...any) {
+	l.entry().log(PanicLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *Logger) Panicln(args ...any) {
+	l.entry().log(PanicLevel, sprintln(args...))
+}
+
+// WithField returns an Entry of the standard logger with key set to value.
+func WithField(key string, value any) *Entry {
+	return std.WithField(key, value)
+}
+
+// WithFields returns an Entry of the standard logger with fields.
+func WithFields(fields Fields) *Entry {
+	return std.WithFields(fields)
+}
+
+// WithError returns an Entry of the standard logger carrying err.
+func WithError(err error) *Entry {
+	return std.WithError(err)
+}
+
+// WithContext returns an Entry of the standard logger logging through ctx.
+func WithContext(ctx context.Context) *Entry {
+	return std.WithContext(ctx)
+}
+
+// SetLevel sets the level of the global sugarzero logger.
+func SetLevel(level Level) {
+	std.SetLevel(level)
+}
+
+// GetLevel returns the level of the global sugarzero logger.
+func GetLevel() Level {
+	return std.GetLevel()
+}
+
+// IsLevelEnabled reports whether the standard logger writes entries at level.
+func IsLevelEnabled(level Level) bool {
+	return std.IsLevelEnabled(level)
+}
+
+func Trace(args ...any) {
+	std.entry().log(TraceLevel, fmt.Sprint(args...))
+}
+
+func Tracef(format string, args ...any) {
+	std.entry().log(TraceLevel, fmt.Sprintf(format, args...))
+}
+
+func Traceln(args ...any) {
+	std.entry().log(TraceLevel, sprintln(args...))
+}
+
+func Debug(args ...any) {
+	std.entry().log(DebugLevel, fmt.Sprint(args...))
+}
+
+func Debugf(format string, args ...any) {
+	std.entry().log(DebugLevel, fmt.Sprintf(format, args...))
+}
+
+func Debugln(args ...any) {
+	std.entry().log(DebugLevel, sprintln(args...))
+}
+
+func Info(args ...any) {
+	std.entry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+func Infof(format string, args ...any) {
+	std.entry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func Infoln(args ...any) {
+	std.entry().log(InfoLevel, sprintln(args...))
+}
+
+func Print(args ...any) {
+	std.entry().log(InfoLevel, fmt.Sprint(args...))
+}
+
+func Printf(format string, args ...any) {
+	std.entry().log(InfoLevel, fmt.Sprintf(format, args...))
+}
+
+func Println(args ...any) {
+	std.entry().log(InfoLevel, sprintln(args...))
+}
+
+func Warn(args ...any) {
+	std.entry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+func Warnf(format string, args ...any) {
+	std.entry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func Warnln(args ...any) {
+	std.entry().log(WarnLevel, sprintln(args...))
+}
+
+func Warning(args ...any) {
+	std.entry().log(WarnLevel, fmt.Sprint(args...))
+}
+
+func Warningf(format string, args ...any) {
+	std.entry().log(WarnLevel, fmt.Sprintf(format, args...))
+}
+
+func Warningln(args ...any) {
+	std.entry().log(WarnLevel, sprintln(args...))
+}
+
+func Error(args ...any) {
+	std.entry().log(ErrorLevel, fmt.Sprint(args...))
+}
+
+func Errorf(format string, args ...any) {
+	std.entry().log(ErrorLevel, fmt.Sprintf(format, args...))
+}
+
+func Errorln(args ...any) {
+	std.entry().log(ErrorLevel, sprintln(args...))
+}
+
+func Fatal(args ...any) {
+	std.entry().log(FatalLevel, fmt.Sprint(args...))
+}
+
+func Fatalf(format string, args ...any) {
+	std.entry().log(FatalLevel, fmt.Sprintf(format, args...))
+}
+
+func Fatalln(args ...any) {
+	std.entry().log(FatalLevel, sprintln(args...))
+}
+
+func Panic(args ...any) {
+	std.entry().log(PanicLevel, fmt.Sprint(args...))
+}
+
+func Panicf(format string, args ...any) {
+	std.entry().log(PanicLevel, fmt.Sprintf(format, args...))
+}
+
+func Panicln(args ...any) {
+	std.entry().log(PanicLevel, sprintln(args...))
+}
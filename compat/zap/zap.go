@@ -0,0 +1,396 @@
+// Package zap is a drop-in facade for the most used parts of the
+// go.uber.org/zap API that writes through sugarzero, so a codebase can move
+// off zap by changing imports instead of every call site:
+//
+//	import "github.com/bigboss2063/sugarzero/compat/zap"
+//
+//	zap.L().With(zap.String("order_id", id)).Warn("retrying", zap.Int("attempt", n))
+//	zap.S().Infow("charged", "amount", amount)
+//
+// Fields, errors, logger names, and levels map onto sugarzero's, and entries
+// go through the sugarzero pipeline (routing, redaction, sinks) with the
+// position of the original call site.
+//
+// Code that keeps real zap loggers can write through sugarzero with the
+// zapcore.Core in the zapsink module, which requires the zap module.
+package zap
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// callerSkip skips Logger.log and the public method that called it, so
+// entries report the caller of the facade.
+const callerSkip = 2
+
+// Level is a zap level; higher values are more severe.
+type Level int8
+
+// Levels, with the values used by zapcore.
+const (
+	DebugLevel Level = iota - 1
+	InfoLevel
+	WarnLevel
+	ErrorLevel
+	// DPanicLevel entries are logged at error level; unlike zap's development
+	// mode they never panic.
+	DPanicLevel
+	PanicLevel
+	FatalLevel
+)
+
+func (l Level) zerolog() zerolog.Level {
+	switch l {
+	case DebugLevel:
+		return zerolog.DebugLevel
+	case WarnLevel:
+		return zerolog.WarnLevel
+	case ErrorLevel, DPanicLevel:
+		return zerolog.ErrorLevel
+	case PanicLevel:
+		return zerolog.PanicLevel
+	case FatalLevel:
+		return zerolog.FatalLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// Field is a key-value pair, as built by zap's field constructors.
+type Field struct {
+	Key   string
+	Value any
+	// err marks fields built by Error, logged like sugarzero.WithError.
+	// Fields without a key are skipped.
+	err error
+}
+
+// String constructs a string field.
+func String(key, value string) Field { return Field{Key: key, Value: value} }
+
+// Strings constructs a field holding a slice of strings.
+func Strings(key string, value []string) Field { return Field{Key: key, Value: value} }
+
+// Int constructs an int field.
+func Int(key string, value int) Field { return Field{Key: key, Value: value} }
+
+// Int64 constructs an int64 field.
+func Int64(key string, value int64) Field { return Field{Key: key, Value: value} }
+
+// Uint64 constructs a uint64 field.
+func Uint64(key string, value uint64) Field { return Field{Key: key, Value: value} }
+
+// Float64 constructs a float64 field.
+func Float64(key string, value float64) Field { return Field{Key: key, Value: value} }
+
+// Bool constructs a bool field.
+func Bool(key string, value bool) Field { return Field{Key: key, Value: value} }
+
+// Duration constructs a time.Duration field.
+func Duration(key string, value time.Duration) Field { return Field{Key: key, Value: value} }
+
+// Time constructs a time.Time field.
+func Time(key string, value time.Time) Field { return Field{Key: key, Value: value} }
+
+// Stringer constructs a field with the value's String result.
+func Stringer(key string, value fmt.Stringer) Field { return Field{Key: key, Value: value.String()} }
+
+// Any constructs a field with an arbitrary value.
+func Any(key string, value any) Field { return Field{Key: key, Value: value} }
+
+// Error attaches err to the entry the way sugarzero.WithError does. A nil
+// error is skipped.
+func Error(err error) Field {
+	if err == nil {
+		return Field{}
+	}
+	return Field{Key: "error", err: err}
+}
+
+// NamedError constructs a field with the error message under key.
+func NamedError(key string, err error) Field {
+	if err == nil {
+		return Field{Key: key}
+	}
+	return Field{Key: key, Value: err.Error()}
+}
+
+// Logger is the facade for *zap.Logger. Its level is the sugarzero logger's
+// level.
+type Logger struct {
+	ctx    context.Context
+	fields []Field
+}
+
+// New returns a Logger writing to the sugarzero logger in ctx, or to the
+// global one, with the context's fields on every entry.
+func New(ctx context.Context) *Logger {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Logger{ctx: ctx}
+}
+
+var global = New(context.Background())
+
+// L returns the Logger writing to the global sugarzero logger.
+func L() *Logger {
+	return global
+}
+
+// S returns the SugaredLogger writing to the global sugarzero logger.
+func S() *SugaredLogger {
+	return global.Sugar()
+}
+
+// With returns a child Logger adding fields to every entry.
+func (l *Logger) With(fields ...Field) *Logger {
+	if len(fields) == 0 {
+		return l
+	}
+	return &Logger{ctx: l.ctx, fields: append(l.fields[:len(l.fields):len(l.fields)], fields...)}
+}
+
+// Named returns a child Logger whose entries carry name in the
+// sugarzero.LoggerNameFieldName field, dot-joined to the parent's name as zap
+// does.
+func (l *Logger) Named(name string) *Logger {
+	for i := len(l.fields) - 1; i >= 0; i-- {
+		if l.fields[i].Key == sugarzero.LoggerNameFieldName {
+			if parent, ok := l.fields[i].Value.(string); ok && parent != "" {
+				name = parent + "." + name
+			}
+			break
+		}
+	}
+	return l.With(String(sugarzero.LoggerNameFieldName, name))
+}
+
+// WithContext returns a copy of l logging through ctx, e.g. for its trace.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	return &Logger{ctx: ctx, fields: l.fields}
+}
+
+// Sugar returns the SugaredLogger sharing l's context and fields.
+func (l *Logger) Sugar() *SugaredLogger {
+	return &SugaredLogger{base: l}
+}
+
+// Sync flushes the writers of the underlying sugarzero logger.
+func (l *Logger) Sync() error {
+	ctx, _ := l.resolve()
+	return sugarzero.Sync(ctx)
+}
+
+// resolve returns l's context bound to the sugarzero logger it writes to, so
+// the global one is used without the missing-logger warning; the facade
+// targets it on purpose.
+func (l *Logger) resolve() (context.Context, sugarzero.Logger) {
+	logger := sugarzero.FromContext(l.ctx)
+	if logger == nil {
+		return l.ctx, nil
+	}
+	return sugarzero.WithLogger(l.ctx, logger), logger
+}
+
+// Level returns the level of the underlying sugarzero logger.
+func (l *Logger) Level() Level {
+	level, err := zerolog.ParseLevel(sugarzero.GetLogLevel(l.ctx))
+	if err != nil {
+		return InfoLevel
+	}
+	switch {
+	case level <= zerolog.DebugLevel:
+		return DebugLevel
+	case level == zerolog.InfoLevel:
+		return InfoLevel
+	case level == zerolog.WarnLevel:
+		return WarnLevel
+	case level == zerolog.ErrorLevel:
+		return ErrorLevel
+	case level == zerolog.FatalLevel:
+		return FatalLevel
+	default:
+		return PanicLevel
+	}
+}
+
+func (l *Logger) Debug(msg string, fields ...Field)  { l.log(DebugLevel, msg, fields) }
+func (l *Logger) Info(msg string, fields ...Field)   { l.log(InfoLevel, msg, fields) }
+func (l *Logger) Warn(msg string, fields ...Field)   { l.log(WarnLevel, msg, fields) }
+func (l *Logger) Error(msg string, fields ...Field)  { l.log(ErrorLevel, msg, fields) }
+func (l *Logger) DPanic(msg string, fields ...Field) { l.log(DPanicLevel, msg, fields) }
+func (l *Logger) Panic(msg string, fields ...Field)  { l.log(PanicLevel, msg, fields) }
+func (l *Logger) Fatal(msg string, fields ...Field)  { l.log(FatalLevel, msg, fields) }
+
+// log writes msg at level; every public method calls it directly so the
+// caller position is callerSkip frames up.
+func (l *Logger) log(level Level, msg string, fields []Field) {
+	ctx := l.write(level, msg, fields, callerSkip+1)
+
+	switch level {
+	case FatalLevel:
+		_ = sugarzero.Sync(ctx)
+		os.Exit(1)
+	case PanicLevel:
+		panic(msg)
+	}
+}
+
+// LogDepth writes msg at level with l's fields and fields, positioned depth
+// frames above the caller of LogDepth, for adapters such as zapsink. Unlike
+// the level methods it neither exits nor panics.
+func (l *Logger) LogDepth(level Level, depth int, msg string, fields ...Field) {
+	// The first frame above write is LogDepth, the second its caller
+	l.write(level, msg, fields, depth+2)
+}
+
+// write writes msg at level with l's fields and fields, positioned skip
+// frames above write, and returns the context it logged with.
+func (l *Logger) write(level Level, msg string, fields []Field, skip int) context.Context {
+	ctx, logger := l.resolve()
+	if all := append(l.fields[:len(l.fields):len(l.fields)], fields...); len(all) > 0 {
+		keyvals := make([]any, 0, 2*len(all))
+		for _, field := range all {
+			switch {
+			case field.err != nil:
+				ctx = sugarzero.WithError(ctx, field.err)
+			case field.Key != "":
+				keyvals = append(keyvals, field.Key, field.Value)
+			}
+		}
+		if len(keyvals) > 0 {
+			ctx = sugarzero.WithFields(ctx, keyvals...)
+		}
+	}
+
+	switch zl := logger.(type) {
+	case nil:
+	case *sugarzero.ZeroLogger:
+		zl.LogDepth(ctx, level.zerolog(), skip, msg)
+	default:
+		// Loggers other than sugarzero's ZeroLogger, such as mocks
+		switch level {
+		case DebugLevel:
+			zl.Debug(ctx, msg)
+		case InfoLevel:
+			zl.Info(ctx, msg)
+		case WarnLevel:
+			zl.Warn(ctx, msg)
+		default:
+			zl.Error(ctx, msg)
+		}
+	}
+	return ctx
+}
+
+// SugaredLogger is the facade for *zap.SugaredLogger.
+type SugaredLogger struct {
+	base *Logger
+}
+
+// Desugar returns the Logger sharing s's context and fields.
+func (s *SugaredLogger) Desugar() *Logger {
+	return s.base
+}
+
+// With returns a child SugaredLogger adding loosely typed key-value pairs, or
+// Fields, to every entry.
+func (s *SugaredLogger) With(args ...any) *SugaredLogger {
+	return &SugaredLogger{base: s.base.With(sweeten(args)...)}
+}
+
+// Named returns a child SugaredLogger; see Logger.Named.
+func (s *SugaredLogger) Named(name string) *SugaredLogger {
+	return &SugaredLogger{base: s.base.Named(name)}
+}
+
+// Sync flushes the writers of the underlying sugarzero logger.
+func (s *SugaredLogger) Sync() error {
+	return s.base.Sync()
+}
+
+func (s *SugaredLogger) Debug(args ...any) { s.base.log(DebugLevel, fmt.Sprint(args...), nil) }
+func (s *SugaredLogger) Info(args ...any)  { s.base.log(InfoLevel, fmt.Sprint(args...), nil) }
+func (s *SugaredLogger) Warn(args ...any)  { s.base.log(WarnLevel, fmt.Sprint(args...), nil) }
+func (s *SugaredLogger) Error(args ...any) { s.base.log(ErrorLevel, fmt.Sprint(args...), nil) }
+func (s *SugaredLogger) Panic(args ...any) { s.base.log(PanicLevel, fmt.Sprint(args...), nil) }
+func (s *SugaredLogger) Fatal(args ...any) { s.base.log(FatalLevel, fmt.Sprint(args...), nil) }
+
+func (s *SugaredLogger) Debugf(format string, args ...any) {
+	s.base.log(DebugLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Infof(format string, args ...any) {
+	s.base.log(InfoLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Warnf(format string, args ...any) {
+	s.base.log(WarnLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Errorf(format string, args ...any) {
+	s.base.log(ErrorLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Panicf(format string, args ...any) {
+	s.base.log(PanicLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Fatalf(format string, args ...any) {
+	s.base.log(FatalLevel, fmt.Sprintf(format, args...), nil)
+}
+
+func (s *SugaredLogger) Debugw(msg string, keysAndValues ...any) {
+	s.base.log(DebugLevel, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Infow(msg string, keysAndValues ...any) {
+	s.base.log(InfoLevel, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Warnw(msg string, keysAndValues ...any) {
+	s.base.log(WarnLevel, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Errorw(msg string, keysAndValues ...any) {
+	s.base.log(ErrorLevel, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Panicw(msg string, keysAndValues ...any) {
+	s.base.log(PanicLevel, msg, sweeten(keysAndValues))
+}
+
+func (s *SugaredLogger) Fatalw(msg string, keysAndValues ...any) {
+	s.base.log(FatalLevel, msg, sweeten(keysAndValues))
+}
+
+// sweeten turns loosely typed key-value pairs into Fields. As in zap, Fields
+// may be mixed in, non-string keys are stringified, and a dangling key is
+// logged with a nil value.
+func sweeten(args []any) []Field {
+	fields := make([]Field, 0, len(args)/2+1)
+	for i := 0; i < len(args); i++ {
+		if field, ok := args[i].(Field); ok {
+			fields = append(fields, field)
+			continue
+		}
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprint(args[i])
+		}
+		var value any
+		if i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		fields = append(fields, Any(key, value))
+	}
+	return fields
+}
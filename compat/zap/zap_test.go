@@ -0,0 +1,109 @@
+package zap_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/compat/zap"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+func TestLoggerForwardsFields(t *testing.T) {
+	_, buf := setup(t, "info")
+
+	logger := zap.L().Named("billing").Named("charges").With(zap.String("order_id", "o1"))
+	logger.Warn("retrying", zap.Int("attempt", 2), zap.Error(errors.New("timeout")), zap.Error(nil))
+	logger.Debug("dropped below info")
+	logger.DPanic("inconsistent")
+
+	got := entries(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(got), buf)
+	}
+	warn := got[0]
+	if warn["level"] != "WARN" || warn["message"] != "retrying" || warn["order_id"] != "o1" || warn["attempt"] != float64(2) {
+		t.Fatalf("unexpected warn entry %v", warn)
+	}
+	if warn["error"] != "timeout" || warn[sugarzero.LoggerNameFieldName] != "billing.charges" {
+		t.Fatalf("expected error and logger name, got %v", warn)
+	}
+	if got[1]["level"] != "ERROR" {
+		t.Fatalf("expected DPanic to log at error level, got %v", got[1])
+	}
+	for _, entry := range got {
+		if position, _ := entry["position"].(string); !strings.Contains(position, "zap_test.go") {
+			t.Fatalf("expected the call site as position, got %v", entry["position"])
+		}
+	}
+}
+
+func TestSugaredLogger(t *testing.T) {
+	ctx, buf := setup(t, "debug")
+
+	sugar := zap.New(sugarzero.WithField(ctx, "request_id", "r1")).Sugar().With("tenant", "acme")
+	sugar.Infow("charged", "amount", 12.5, zap.Bool("retry", false), "dangling")
+	sugar.Debugf("cache %s", "miss")
+
+	got := entries(t, buf)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(got), buf)
+	}
+	info := got[0]
+	if info["request_id"] != "r1" || info["tenant"] != "acme" || info["amount"] != 12.5 || info["retry"] != false {
+		t.Fatalf("unexpected infow entry %v", info)
+	}
+	if value, ok := info["dangling"]; !ok || value != nil {
+		t.Fatalf("expected the dangling key with a nil value, got %v", info)
+	}
+	if got[1]["message"] != "cache miss" || got[1]["level"] != "DEBUG" {
+		t.Fatalf("unexpected debugf entry %v", got[1])
+	}
+	if position, _ := info["position"].(string); !strings.Contains(position, "zap_test.go") {
+		t.Fatalf("expected the call site as position, got %v", info["position"])
+	}
+}
+
+func TestLoggerLevelAndPanic(t *testing.T) {
+	setup(t, "warn")
+
+	if level := zap.L().Level(); level != zap.WarnLevel {
+		t.Fatalf("expected warn level, got %d", level)
+	}
+	defer func() {
+		if recovered := recover(); recovered != "invariant broken" {
+			t.Fatalf("expected to panic with the message, got %v", recovered)
+		}
+	}()
+	zap.S().Panicf("invariant %s", "broken")
+}
@@ -0,0 +1,119 @@
+// Package zapsink writes the entries of go.uber.org/zap loggers through
+// sugarzero, for code that keeps real zap loggers, such as dependencies. It
+// is a module of its own so that only programs using it depend on zap:
+//
+//	import "github.com/bigboss2063/sugarzero/compat/zap/zapsink"
+//
+//	logger := zap.New(zapsink.NewCore(ctx), zap.AddCaller())
+//
+// Code moving off zap can use the facade in compat/zap instead.
+package zapsink
+
+import (
+	"context"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/bigboss2063/sugarzero"
+	sugarzap "github.com/bigboss2063/sugarzero/compat/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// stackFieldName is the key of the stack trace zap captures for an entry.
+const stackFieldName = "stack"
+
+// Core is a zapcore.Core writing every entry through sugarzero. Its level is
+// the sugarzero logger's level; fatal and panic entries are only written, zap
+// itself exits or panics after writing them.
+// Example: logger := zap.New(zapsink.NewCore(ctx), zap.AddCaller())
+type Core struct {
+	logger *sugarzap.Logger
+}
+
+var _ zapcore.Core = (*Core)(nil)
+
+// NewCore returns a Core writing to the sugarzero logger in ctx, or to the
+// global one, with the context's fields on every entry.
+func NewCore(ctx context.Context) *Core {
+	return &Core{logger: sugarzap.New(ctx)}
+}
+
+// Enabled reports whether entries at level are written.
+func (c *Core) Enabled(level zapcore.Level) bool {
+	// Level values are the same as zapcore's
+	return sugarzap.Level(level) >= c.logger.Level()
+}
+
+// With returns a Core adding fields to every entry.
+func (c *Core) With(fields []zapcore.Field) zapcore.Core {
+	return &Core{logger: c.logger.With(fromZapcore(fields)...)}
+}
+
+// Check adds c to ce if entries at the level of ent are written.
+func (c *Core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write writes ent with fields through sugarzero, positioned at the call
+// into zap.
+func (c *Core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	converted := fromZapcore(fields)
+	if ent.LoggerName != "" {
+		converted = append(converted, sugarzap.String(sugarzero.LoggerNameFieldName, ent.LoggerName))
+	}
+	if ent.Stack != "" {
+		converted = append(converted, sugarzap.String(stackFieldName, ent.Stack))
+	}
+	c.logger.LogDepth(sugarzap.Level(ent.Level), 1+zapDepth(), ent.Message, converted...)
+	return nil
+}
+
+// Sync flushes the writers of the underlying sugarzero logger.
+func (c *Core) Sync() error {
+	return c.logger.Sync()
+}
+
+// fromZapcore converts zapcore fields, encoding their values with zap's
+// own encoders; keys are sorted as encoding collects them in a map. Errors
+// under the "error" key are attached like sugarzap.Error.
+func fromZapcore(fields []zapcore.Field) []sugarzap.Field {
+	if len(fields) == 0 {
+		return nil
+	}
+	converted := make([]sugarzap.Field, 0, len(fields))
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range fields {
+		if err, ok := field.Interface.(error); ok && field.Type == zapcore.ErrorType && field.Key == "error" {
+			converted = append(converted, sugarzap.Error(err))
+			continue
+		}
+		field.AddTo(enc)
+	}
+	for _, key := range slices.Sorted(maps.Keys(enc.Fields)) {
+		converted = append(converted, sugarzap.Any(key, enc.Fields[key]))
+	}
+	return converted
+}
+
+// zapDepth returns the number of zap frames calling Core.Write.
+func zapDepth() int {
+	var pcs [32]uintptr
+	// Skips runtime.Callers, zapDepth, and Write
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs[:])])
+	depth := 0
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "go.uber.org/zap.") && !strings.HasPrefix(frame.Function, "go.uber.org/zap/zapcore.") {
+			return depth
+		}
+		depth++
+		if !more {
+			return depth
+		}
+	}
+}
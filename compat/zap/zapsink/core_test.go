@@ -0,0 +1,93 @@
+package zapsink_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/compat/zap/zapsink"
+	"go.uber.org/zap"
+)
+
+func setup(t *testing.T, level string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), level, &buf)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	return ctx, &buf
+}
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var parsed []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		parsed = append(parsed, entry)
+	}
+	return parsed
+}
+
+func TestCoreWritesZapEntries(t *testing.T) {
+	ctx, buf := setup(t, "info")
+
+	logger := zap.New(zapsink.NewCore(ctx)).Named("payments")
+	logger.Warn("payment failed", zap.Int("attempt", 2), zap.Error(errors.New("card declined")))
+	logger.Debug("dropped below info")
+
+	got := entries(t, buf)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(got), buf)
+	}
+	entry := got[0]
+	if entry["level"] != "WARN" || entry["message"] != "payment failed" || entry["attempt"] != float64(2) || entry["error"] != "card declined" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+	if entry[sugarzero.LoggerNameFieldName] != "payments" {
+		t.Fatalf("expected the logger name, got %v", entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "core_test.go") {
+		t.Fatalf("expected the call into zap as position, got %v", entry["position"])
+	}
+}
+
+func TestCoreWithAddsFields(t *testing.T) {
+	ctx, buf := setup(t, "info")
+
+	logger := zap.New(zapsink.NewCore(ctx)).With(zap.String("order_id", "o1"))
+	logger.Sugar().Infow("charged", "amount", 12.5)
+
+	got := entries(t, buf)
+	if len(got) != 1 || got[0]["order_id"] != "o1" || got[0]["amount"] != 12.5 {
+		t.Fatalf("expected the With and call fields, got %s", buf)
+	}
+}
+
+func TestCoreFollowsSugarzeroLevel(t *testing.T) {
+	ctx, buf := setup(t, "warn")
+
+	core := zapsink.NewCore(ctx)
+	if core.Enabled(zap.InfoLevel) || !core.Enabled(zap.ErrorLevel) {
+		t.Fatal("expected the core to follow the sugarzero level")
+	}
+	if err := sugarzero.SetLogLevel(ctx, "debug"); err != nil {
+		t.Fatalf("SetLogLevel failed: %v", err)
+	}
+	zap.New(core).Debug("now enabled")
+
+	if got := entries(t, buf); len(got) != 1 || got[0]["message"] != "now enabled" {
+		t.Fatalf("expected the debug entry after lowering the level, got %s", buf)
+	}
+}
@@ -0,0 +1,125 @@
+package sugarzero
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+	"time"
+)
+
+// DefaultCompressFlushInterval is how often a GzipWriter flushes buffered
+// entries when no interval is provided.
+const DefaultCompressFlushInterval = time.Second
+
+// GzipWriter compresses log entries with gzip before writing them to the
+// underlying writer. Buffered data is flushed periodically with a deflate sync
+// marker, so after a crash everything written before the last flush can still
+// be decompressed from the truncated file. Idle writers do not flush, so
+// quiet periods add nothing to the file.
+//
+// Only gzip is provided, as the standard library has no zstd encoder; wrap a
+// zstd encoder from a third-party module as the writer of an Output to
+// compress with zstd.
+type GzipWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	gz     *gzip.Writer
+	done   chan struct{}
+	closed bool
+	// dirty reports whether data was written since the last flush.
+	dirty bool
+	wg    sync.WaitGroup
+}
+
+// NewGzipWriter wraps w with gzip compression at the given level (for example
+// gzip.BestSpeed or gzip.DefaultCompression) and flushes every interval.
+// An interval <= 0 uses DefaultCompressFlushInterval.
+func NewGzipWriter(w io.Writer, level int, interval time.Duration) (*GzipWriter, error) {
+	gz, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultCompressFlushInterval
+	}
+
+	g := &GzipWriter{
+		w:    w,
+		gz:   gz,
+		done: make(chan struct{}),
+	}
+	g.wg.Add(1)
+	go g.flushLoop(interval)
+	return g, nil
+}
+
+// Write compresses p into the stream.
+func (g *GzipWriter) Write(p []byte) (int, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return 0, io.ErrClosedPipe
+	}
+	g.dirty = true
+	return g.gz.Write(p)
+}
+
+// Flush writes any buffered data followed by a sync marker. It does nothing
+// if nothing was written since the last flush.
+func (g *GzipWriter) Flush() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed || !g.dirty {
+		return nil
+	}
+	g.dirty = false
+	return g.gz.Flush()
+}
+
+// Sync flushes buffered data and, if the underlying writer supports it,
+// commits it to stable storage.
+func (g *GzipWriter) Sync() error {
+	if err := g.Flush(); err != nil {
+		return err
+	}
+	return syncWriter(g.w)
+}
+
+// Close stops the flush loop, writes the gzip footer, and closes the
+// underlying writer if it implements io.Closer.
+func (g *GzipWriter) Close() error {
+	g.mu.Lock()
+	if g.closed {
+		g.mu.Unlock()
+		return nil
+	}
+	g.closed = true
+	close(g.done)
+	err := g.gz.Close()
+	g.mu.Unlock()
+
+	g.wg.Wait()
+
+	if closer, ok := g.w.(io.Closer); ok {
+		if closeErr := closer.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+func (g *GzipWriter) flushLoop(interval time.Duration) {
+	defer g.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = g.Flush()
+		case <-g.done:
+			return
+		}
+	}
+}
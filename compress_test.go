@@ -0,0 +1,123 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use by background flushers.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) Bytes() []byte {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.buf.Bytes()...)
+}
+
+func TestGzipWriterRoundTrip(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var sink syncBuffer
+	writer, err := sugarzero.NewGzipWriter(&sink, gzip.BestSpeed, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, err := sugarzero.New(context.Background(), "info", writer)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "compressed entry")
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(sink.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected gzip error: %v", err)
+	}
+	plain, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+
+	entry := readLogEntry(t, bytes.NewBuffer(plain))
+	if entry["message"] != "compressed entry" {
+		t.Fatalf("unexpected message: %v", entry["message"])
+	}
+}
+
+func TestGzipWriterFlushesPeriodically(t *testing.T) {
+	var sink syncBuffer
+	writer, err := sugarzero.NewGzipWriter(&sink, gzip.DefaultCompression, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = writer.Close()
+	})
+
+	if _, err := writer.Write([]byte("{\"message\":\"before crash\"}\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		// Simulate a crash by reading the stream without the gzip footer.
+		reader, err := gzip.NewReader(bytes.NewReader(sink.Bytes()))
+		if err == nil {
+			plain, _ := io.ReadAll(reader)
+			if bytes.Contains(plain, []byte("before crash")) {
+				return
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected periodic flush to make the entry recoverable")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestGzipWriterSkipsIdleFlushes(t *testing.T) {
+	var sink countingWriter
+	writer, err := sugarzero.NewGzipWriter(&sink, gzip.DefaultCompression, 5*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = writer.Close()
+	})
+
+	if _, err := writer.Write([]byte("{\"message\":\"once\"}\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("unexpected flush error: %v", err)
+	}
+	flushed := len(sink.snapshot())
+	time.Sleep(50 * time.Millisecond)
+
+	if got := len(sink.snapshot()); got != flushed {
+		t.Fatalf("expected no writes while idle, got %d more", got-flushed)
+	}
+}
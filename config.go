@@ -0,0 +1,281 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Output formats accepted by Config.Format.
+const (
+	FormatJSON    = "json"
+	FormatConsole = "console"
+)
+
+// Config describes a logger as plain data, so applications can unmarshal it
+// from their own configuration format and build the logger with
+// NewFromConfig. The zero value logs info and above as JSON to stdout.
+type Config struct {
+	// Level is the minimum level; "" is info.
+	Level string `json:"level" yaml:"level"`
+	// Format is FormatJSON (the default) or FormatConsole for human-readable
+	// output during development.
+	Format string `json:"format" yaml:"format"`
+	// Outputs are the destinations; none writes to stdout.
+	Outputs []OutputConfig `json:"outputs" yaml:"outputs"`
+	// Sampling enables adaptive sampling; see WithAdaptiveSampling.
+	Sampling *SamplingConfig `json:"sampling" yaml:"sampling"`
+	// Redaction redacts fields by key; see WithKeyRedaction.
+	Redaction *RedactionConfig `json:"redaction" yaml:"redaction"`
+	// Caller adds the caller position to every entry; nil means true.
+	Caller *bool `json:"caller" yaml:"caller"`
+	// CategoryLevels overrides the level per category; see WithCategoryLevel.
+	CategoryLevels map[string]string `json:"category_levels" yaml:"category_levels"`
+	// Fields are written on every entry, e.g. {"service": "billing"}.
+	Fields map[string]any `json:"fields" yaml:"fields"`
+	// TailBuffer keeps the most recent entries; see WithTailBuffer.
+	TailBuffer int `json:"tail_buffer" yaml:"tail_buffer"`
+	// RecentErrors keeps the most recent warn and error entries; see
+	// WithRecentErrors.
+	RecentErrors int `json:"recent_errors" yaml:"recent_errors"`
+}
+
+// OutputConfig describes one destination of a Config.
+type OutputConfig struct {
+	// Target is "stdout", "stderr", "file:///path/to.log" (opened for
+	// appending), or a NetworkWriter target such as "tcp://host:port".
+	Target string `json:"target" yaml:"target"`
+	// Level and SampleRates are applied to this output only; see Output.
+	Level       string             `json:"level" yaml:"level"`
+	SampleRates map[string]float64 `json:"sample_rates" yaml:"sample_rates"`
+}
+
+// RedactionConfig lists the keys whose values are redacted.
+type RedactionConfig struct {
+	Keys     []string `json:"keys" yaml:"keys"`
+	Prefixes []string `json:"prefixes" yaml:"prefixes"`
+}
+
+// Duration is a time.Duration written in configuration files as a string
+// accepted by time.ParseDuration, e.g. "500ms" or "1m". JSON numbers are read
+// as nanoseconds, like time.Duration.
+type Duration time.Duration
+
+// String returns d formatted like time.Duration.
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalText encodes d like String.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses a duration string such as "1s".
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("sugarzero: invalid duration: %w", err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// UnmarshalJSON accepts a duration string or a number of nanoseconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' {
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return d.UnmarshalText([]byte(text))
+	}
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err != nil {
+		return fmt.Errorf("sugarzero: invalid duration %s: %w", data, err)
+	}
+	*d = Duration(nanos)
+	return nil
+}
+
+// Validate reports every invalid setting in c without opening any output.
+func (c *Config) Validate() error {
+	var errs []error
+	if _, err := parseLevel(c.Level); err != nil {
+		errs = append(errs, fmt.Errorf("level: %w", err))
+	}
+	switch strings.ToLower(c.Format) {
+	case "", FormatJSON, FormatConsole:
+	default:
+		errs = append(errs, fmt.Errorf("format: unknown format %q: must be %s or %s", c.Format, FormatJSON, FormatConsole))
+	}
+	for i, out := range c.Outputs {
+		if _, _, err := parseOutputTarget(out.Target); err != nil {
+			errs = append(errs, fmt.Errorf("outputs[%d]: %w", i, err))
+		}
+		if _, err := newOutputWriter(Output{Writer: io.Discard, Level: out.Level, SampleRates: out.SampleRates}); err != nil {
+			errs = append(errs, fmt.Errorf("outputs[%d]: %w", i, err))
+		}
+	}
+	if c.Sampling != nil && c.Sampling.EventsPerSecond <= 0 {
+		errs = append(errs, errors.New("sampling: events per second must be positive"))
+	}
+	if c.Sampling != nil && c.Sampling.KeepLevel != "" && !IsValidLevel(c.Sampling.KeepLevel) {
+		errs = append(errs, fmt.Errorf("sampling: invalid keep level %q", c.Sampling.KeepLevel))
+	}
+	for category, level := range c.CategoryLevels {
+		if !IsValidLevel(level) {
+			errs = append(errs, fmt.Errorf("category_levels: invalid level %q for %s", level, category))
+		}
+	}
+	if c.TailBuffer < 0 || c.RecentErrors < 0 {
+		errs = append(errs, errors.New("tail_buffer and recent_errors must not be negative"))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("sugarzero: invalid config: %w", err)
+	}
+	return nil
+}
+
+// Options validates c and returns the equivalent options, opening the
+// outputs. Append further options to customize what Config does not cover.
+func (c *Config) Options() ([]Option, error) {
+	opts, _, err := c.options()
+	return opts, err
+}
+
+// options is Options, also returning the outputs it opened so callers can
+// close them if the logger cannot be built.
+func (c *Config) options() ([]Option, []Output, error) {
+	if err := c.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	var opts []Option
+	outputs := make([]Output, 0, len(c.Outputs))
+	for _, out := range c.Outputs {
+		w, err := openOutputTarget(out.Target)
+		if err != nil {
+			closeOutputs(outputs)
+			return nil, nil, err
+		}
+		outputs = append(outputs, Output{Writer: w, Level: out.Level, SampleRates: out.SampleRates})
+	}
+	if len(outputs) > 0 {
+		opts = append(opts, WithOutputs(outputs...))
+	}
+	if strings.EqualFold(c.Format, FormatConsole) {
+		opts = append(opts, WithConsoleFormat())
+	}
+	if c.Sampling != nil {
+		opts = append(opts, WithAdaptiveSampling(*c.Sampling))
+	}
+	if c.Redaction != nil {
+		opts = append(opts, WithKeyRedaction(c.Redaction.Keys, c.Redaction.Prefixes))
+	}
+	if c.Caller != nil && !*c.Caller {
+		opts = append(opts, WithoutCaller())
+	}
+	for category, level := range c.CategoryLevels {
+		opts = append(opts, WithCategoryLevel(category, level))
+	}
+	if len(c.Fields) > 0 {
+		fields := make([]any, 0, 2*len(c.Fields))
+		for _, key := range slices.Sorted(maps.Keys(c.Fields)) {
+			fields = append(fields, key, c.Fields[key])
+		}
+		opts = append(opts, WithStaticFields(fields...))
+	}
+	if c.TailBuffer > 0 {
+		opts = append(opts, WithTailBuffer(c.TailBuffer))
+	}
+	if c.RecentErrors > 0 {
+		opts = append(opts, WithRecentErrors(c.RecentErrors))
+	}
+	return opts, outputs, nil
+}
+
+// NewFromConfig validates cfg and initializes the global logger from it like
+// NewWithOptions, with opts applied after the ones derived from cfg. The
+// outputs it opened are closed again if the logger cannot be built.
+// Example:
+//
+//	var cfg sugarzero.Config
+//	if err := json.Unmarshal(data, &cfg); err != nil { ... }
+//	ctx, err := sugarzero.NewFromConfig(ctx, cfg)
+func NewFromConfig(ctx context.Context, cfg Config, opts ...Option) (context.Context, error) {
+	derived, outputs, err := cfg.options()
+	if err != nil {
+		return ctx, err
+	}
+	newCtx, err := NewWithOptions(ctx, cfg.Level, append(derived, opts...)...)
+	if err != nil {
+		closeOutputs(outputs)
+		return ctx, err
+	}
+	return newCtx, nil
+}
+
+// parseOutputTarget splits target into a scheme ("stdout", "stderr", "file",
+// or a network) and a location.
+func parseOutputTarget(target string) (string, string, error) {
+	switch target {
+	case "stdout", "stderr":
+		return target, "", nil
+	case "":
+		return "", "", errors.New("output target must not be empty")
+	}
+	if path, ok := strings.CutPrefix(target, "file://"); ok {
+		if path == "" {
+			return "", "", fmt.Errorf("output target %q has no path", target)
+		}
+		return "file", path, nil
+	}
+	network, address, err := parseNetworkTarget(target)
+	if err != nil {
+		return "", "", err
+	}
+	return network, address, nil
+}
+
+func openOutputTarget(target string) (io.Writer, error) {
+	scheme, path, err := parseOutputTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	switch scheme {
+	case "stdout":
+		return os.Stdout, nil
+	case "stderr":
+		return os.Stderr, nil
+	case "file":
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: failed to open log file: %w", err)
+		}
+		return f, nil
+	}
+	return NewNetworkWriter(target, 0)
+}
+
+func closeOutputs(outputs []Output) {
+	for _, out := range outputs {
+		closeOutput(out.Writer)
+	}
+}
+
+func closeOutput(w io.Writer) {
+	if w == os.Stdout || w == os.Stderr {
+		return
+	}
+	if closer, ok := w.(io.Closer); ok {
+		_ = closer.Close()
+	}
+}
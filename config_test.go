@@ -0,0 +1,159 @@
+package sugarzero_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestConfigValidateReportsEveryProblem(t *testing.T) {
+	cfg := sugarzero.Config{
+		Level:  "loud",
+		Format: "xml",
+		Outputs: []sugarzero.OutputConfig{
+			{Target: "ftp://example.com"},
+			{Target: "stdout", SampleRates: map[string]float64{"info": 1.5}},
+		},
+		Sampling:       &sugarzero.SamplingConfig{},
+		CategoryLevels: map[string]string{"security": "verbose"},
+	}
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected validation to fail")
+	}
+	for _, want := range []string{"level", "format", "outputs[0]", "outputs[1]", "sampling", "security"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected error to mention %q, got %v", want, err)
+		}
+	}
+
+	if err := (&sugarzero.Config{}).Validate(); err != nil {
+		t.Fatalf("expected zero config to be valid, got %v", err)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	data := `{
+		"level": "debug",
+		"outputs": [{"target": "file://` + path + `", "sample_rates": {"debug": 0}}],
+		"redaction": {"keys": ["password"]},
+		"caller": false,
+		"category_levels": {"security": "warn"},
+		"fields": {"service": "billing"}
+	}`
+	var cfg sugarzero.Config
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	ctx, err := sugarzero.NewFromConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+
+	sugarzero.Debug(ctx, "cache miss")
+	sugarzero.Info(sugarzero.WithField(ctx, "password", "hunter2"), "user logged in")
+	sugarzero.Info(sugarzero.WithCategory(ctx, sugarzero.CategorySecurity), "token refreshed")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected only the info entry, got %q", content)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if entry["message"] != "user logged in" || entry["service"] != "billing" || entry["password"] != "[REDACTED]" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+	if _, ok := entry["position"]; ok {
+		t.Fatalf("expected no caller position, got %v", entry)
+	}
+}
+
+func TestNewFromConfigConsoleFormat(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	path := filepath.Join(t.TempDir(), "app.log")
+	ctx, err := sugarzero.NewFromConfig(context.Background(), sugarzero.Config{
+		Format:  sugarzero.FormatConsole,
+		Outputs: []sugarzero.OutputConfig{{Target: "file://" + path}},
+	})
+	if err != nil {
+		t.Fatalf("NewFromConfig failed: %v", err)
+	}
+	sugarzero.Info(sugarzero.WithField(ctx, "order_id", 42), "order placed")
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if line := string(content); !strings.Contains(line, "INF") || !strings.Contains(line, "order placed") ||
+		!strings.Contains(line, "order_id=42") || strings.HasPrefix(line, "{") {
+		t.Fatalf("expected a console line, got %q", line)
+	}
+}
+
+func TestSamplingConfigUnmarshalsDurations(t *testing.T) {
+	var cfg sugarzero.Config
+	data := `{"sampling": {"events_per_second": 200, "window": "250ms", "keep_level": "error"}}`
+	if err := json.Unmarshal([]byte(data), &cfg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	want := sugarzero.SamplingConfig{EventsPerSecond: 200, Window: sugarzero.Duration(250 * time.Millisecond), KeepLevel: "error"}
+	if cfg.Sampling == nil || *cfg.Sampling != want {
+		t.Fatalf("expected %+v, got %+v", want, cfg.Sampling)
+	}
+
+	var nanos sugarzero.SamplingConfig
+	if err := json.Unmarshal([]byte(`{"window": 1000000000}`), &nanos); err != nil || nanos.Window != sugarzero.Duration(time.Second) {
+		t.Fatalf("expected numbers to be read as nanoseconds, got %v: %v", nanos.Window, err)
+	}
+	if err := json.Unmarshal([]byte(`{"window": "soon"}`), &nanos); err == nil {
+		t.Fatal("expected an invalid duration to be rejected")
+	}
+
+	encoded, err := json.Marshal(want)
+	if err != nil || !strings.Contains(string(encoded), `"window":"250ms"`) {
+		t.Fatalf("expected the window to be encoded as a string, got %s: %v", encoded, err)
+	}
+}
+
+func TestNewFromConfigClosesOutputsOnFailure(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	before, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		t.Skip("open files cannot be counted on this platform")
+	}
+
+	cfg := sugarzero.Config{Outputs: []sugarzero.OutputConfig{{Target: "file://" + filepath.Join(t.TempDir(), "app.log")}}}
+	if _, err := sugarzero.NewFromConfig(context.Background(), cfg, sugarzero.WithCategoryLevel("audit", "loud")); err == nil {
+		t.Fatal("expected NewFromConfig to fail")
+	}
+
+	after, _ := os.ReadDir("/proc/self/fd")
+	if len(after) > len(before) {
+		t.Fatalf("expected the opened output to be closed, open files went from %d to %d", len(before), len(after))
+	}
+}
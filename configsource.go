@@ -0,0 +1,85 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// DefaultConfigPrefix is the key the logging settings are read from when no
+// prefix is given.
+const DefaultConfigPrefix = "logging"
+
+// ConfigSource is the read method shared by configuration libraries such as
+// viper (*viper.Viper) and koanf (*koanf.Koanf), so either binds to Config
+// without sugarzero depending on it.
+type ConfigSource interface {
+	Get(key string) any
+}
+
+// ConfigFromSource reads the Config stored under prefix in src, e.g. the
+// "logging" section of a viper or koanf file, using the Config field names
+// (level, format, outputs, sample_rates, ...). A prefix of "" uses
+// DefaultConfigPrefix. A missing section yields the zero Config.
+// Example:
+//
+//	# config.yaml
+//	logging:
+//	  level: info
+//	  outputs:
+//	    - target: stdout
+//	    - target: tcp://collector:5170
+//	      sample_rates: {debug: 0, info: 0.01}
+func ConfigFromSource(src ConfigSource, prefix string) (Config, error) {
+	if prefix == "" {
+		prefix = DefaultConfigPrefix
+	}
+	var cfg Config
+	section := src.Get(prefix)
+	if section == nil {
+		return cfg, nil
+	}
+	// Both libraries return nested maps and slices, which JSON round-trips
+	// into the tagged Config fields.
+	data, err := json.Marshal(section)
+	if err != nil {
+		return cfg, fmt.Errorf("sugarzero: failed to read %q config: %w", prefix, err)
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("sugarzero: failed to decode %q config: %w", prefix, err)
+	}
+	return cfg, cfg.Validate()
+}
+
+// NewFromSource initializes the global logger from the Config under prefix
+// in src; see ConfigFromSource and NewFromConfig.
+// Example: ctx, err := sugarzero.NewFromSource(ctx, viper.GetViper(), "logging")
+func NewFromSource(ctx context.Context, src ConfigSource, prefix string, opts ...Option) (context.Context, error) {
+	cfg, err := ConfigFromSource(src, prefix)
+	if err != nil {
+		return ctx, err
+	}
+	return NewFromConfig(ctx, cfg, opts...)
+}
+
+// ReloadFromSource re-reads the Config under prefix in src and applies its
+// level to the logger in ctx. Call it from the library's change callback for
+// hot reload; settings other than the level take effect on restart. An
+// invalid config is rejected as a whole and the logger is left unchanged.
+// Example:
+//
+//	v.OnConfigChange(func(fsnotify.Event) {
+//		if err := sugarzero.ReloadFromSource(ctx, v, "logging"); err != nil {
+//			sugarzero.Error(ctx, err)
+//		}
+//	})
+//	v.WatchConfig()
+//
+// With koanf, call it from the provider's Watch callback after reloading k.
+func ReloadFromSource(ctx context.Context, src ConfigSource, prefix string) error {
+	cfg, err := ConfigFromSource(src, prefix)
+	if err != nil {
+		return err
+	}
+	return SetLogLevel(ctx, cfg.Level)
+}
@@ -0,0 +1,86 @@
+package sugarzero_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// mapSource resolves dotted keys in nested maps, like viper and koanf.
+type mapSource map[string]any
+
+func (m mapSource) Get(key string) any {
+	var value any = map[string]any(m)
+	for _, part := range strings.Split(key, ".") {
+		section, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = section[part]
+	}
+	return value
+}
+
+func TestConfigFromSource(t *testing.T) {
+	src := mapSource{"app": map[string]any{"logging": map[string]any{
+		"level":  "warn",
+		"format": "json",
+		"outputs": []any{
+			map[string]any{"target": "stdout"},
+			map[string]any{"target": "stderr", "level": "error", "sample_rates": map[string]any{"error": 0.5}},
+		},
+		"fields": map[string]any{"service": "billing"},
+	}}}
+
+	cfg, err := sugarzero.ConfigFromSource(src, "app.logging")
+	if err != nil {
+		t.Fatalf("ConfigFromSource failed: %v", err)
+	}
+	if cfg.Level != "warn" || len(cfg.Outputs) != 2 || cfg.Outputs[1].SampleRates["error"] != 0.5 || cfg.Fields["service"] != "billing" {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+
+	if cfg, err := sugarzero.ConfigFromSource(mapSource{}, ""); err != nil || cfg.Level != "" {
+		t.Fatalf("expected zero config for a missing section, got %+v, %v", cfg, err)
+	}
+	if _, err := sugarzero.ConfigFromSource(mapSource{"logging": map[string]any{"level": "loud"}}, ""); err == nil {
+		t.Fatal("expected invalid level to be rejected")
+	}
+	if _, err := sugarzero.ConfigFromSource(mapSource{"logging": map[string]any{"outputs": "stdout"}}, ""); err == nil {
+		t.Fatal("expected mistyped outputs to be rejected")
+	}
+}
+
+func TestReloadFromSourceUpdatesLevel(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	src := mapSource{"logging": map[string]any{"level": "info"}}
+	ctx, err := sugarzero.NewFromSource(context.Background(), src, "", sugarzero.WithWriters(&strings.Builder{}))
+	if err != nil {
+		t.Fatalf("NewFromSource failed: %v", err)
+	}
+	if level := sugarzero.GetLogLevel(ctx); level != "info" {
+		t.Fatalf("expected info, got %s", level)
+	}
+
+	src["logging"] = map[string]any{"level": "debug"}
+	if err := sugarzero.ReloadFromSource(ctx, src, ""); err != nil {
+		t.Fatalf("ReloadFromSource failed: %v", err)
+	}
+	if level := sugarzero.GetLogLevel(ctx); level != "debug" {
+		t.Fatalf("expected debug after reload, got %s", level)
+	}
+
+	src["logging"] = map[string]any{"level": "loud"}
+	if err := sugarzero.ReloadFromSource(ctx, src, ""); err == nil {
+		t.Fatal("expected invalid reload to fail")
+	}
+	if level := sugarzero.GetLogLevel(ctx); level != "debug" {
+		t.Fatalf("expected level to be kept after a failed reload, got %s", level)
+	}
+}
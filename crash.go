@@ -0,0 +1,109 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultCrashBundleEntries is the number of recent entries kept for
+	// crash bundles.
+	DefaultCrashBundleEntries = 100
+
+	crashBundleTimeout = 10 * time.Second
+)
+
+// CrashBundle is the report written by WithCrashBundles.
+type CrashBundle struct {
+	Time time.Time `json:"time"`
+	// Reason is "fatal" or "panic".
+	Reason string `json:"reason"`
+	// Entries are the most recent entries, oldest first, ending with the
+	// one that triggered the bundle.
+	Entries        []json.RawMessage `json:"entries"`
+	GoroutineCount int               `json:"goroutine_count"`
+	Goroutines     string            `json:"goroutines"`
+	BuildInfo      *debug.BuildInfo  `json:"build_info,omitempty"`
+	Config         Snapshot          `json:"config"`
+}
+
+// WithCrashBundles writes a CrashBundle to store whenever an entry is logged
+// at fatal or panic level, and when LogPanic or RecoverAndLog handle a panic,
+// so a post-mortem does not depend on the log backend having received the
+// last entries. The bundle holds the last entries entries (all levels,
+// DefaultCrashBundleEntries if entries <= 0), a goroutine dump, the build
+// info, and the logger Snapshot.
+//
+// Bundles are written synchronously before the process exits. Their location
+// is reported as a DiagnosticCrashBundle diagnostic.
+// Example: NewWithOptions(ctx, "info", WithCrashBundles(NewDirBlobStore("/var/crash/api"), 200))
+func WithCrashBundles(store BlobStore, entries int) Option {
+	return func(o *options) {
+		if entries <= 0 {
+			entries = DefaultCrashBundleEntries
+		}
+		o.crashStore = store
+		o.crashEntries = entries
+	}
+}
+
+// crashReporter keeps recent entries and writes crash bundles.
+type crashReporter struct {
+	store   BlobStore
+	entries *entryRing
+	// logger is set once the logger owning the reporter is built.
+	logger *ZeroLogger
+}
+
+// write stores a bundle for reason and reports where it went.
+func (c *crashReporter) write(reason string) {
+	bundle := CrashBundle{
+		Time:           time.Now(),
+		Reason:         reason,
+		Entries:        []json.RawMessage{},
+		GoroutineCount: runtime.NumGoroutine(),
+		Goroutines:     goroutineStacks(DefaultGoroutineDumpLimit),
+	}
+	for _, entry := range c.entries.snapshot() {
+		if json.Valid(entry.raw) {
+			bundle.Entries = append(bundle.Entries, entry.raw)
+		}
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		bundle.BuildInfo = info
+	}
+	if c.logger != nil {
+		bundle.Config = c.logger.Snapshot()
+	}
+
+	data, err := json.MarshalIndent(bundle, "", "  ")
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), crashBundleTimeout)
+		defer cancel()
+		key := fmt.Sprintf("crash-%s-%d.json", bundle.Time.UTC().Format("20060102T150405.000000000Z"), os.Getpid())
+		var ref string
+		if ref, err = c.store.Put(ctx, key, data); err == nil {
+			diagnose(DiagnosticCrashBundle, zerolog.ErrorLevel, "crash bundle written", "reason", reason, "ref", ref)
+			return
+		}
+	}
+	diagnose(DiagnosticCrashBundle, zerolog.ErrorLevel, "failed to write crash bundle", "reason", reason, "error", err.Error())
+}
+
+// crashReason returns the bundle reason for entries at level, if they
+// trigger one.
+func crashReason(level zerolog.Level) (string, bool) {
+	switch level {
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return level.String(), true
+	default:
+		return "", false
+	}
+}
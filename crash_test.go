@@ -0,0 +1,111 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func readCrashBundles(t *testing.T, dir string) []sugarzero.CrashBundle {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "crash-*.json"))
+	if err != nil {
+		t.Fatalf("glob failed: %v", err)
+	}
+	var bundles []sugarzero.CrashBundle
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("read failed: %v", err)
+		}
+		var bundle sugarzero.CrashBundle
+		if err := json.Unmarshal(data, &bundle); err != nil {
+			t.Fatalf("invalid bundle %s: %v", path, err)
+		}
+		bundles = append(bundles, bundle)
+	}
+	return bundles
+}
+
+func newCrashLogger(t *testing.T, dir string) (context.Context, *bytes.Buffer) {
+	t.Helper()
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithCrashBundles(sugarzero.NewDirBlobStore(dir), 2),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+	return ctx, &diagnostics
+}
+
+func TestCrashBundleOnFatal(t *testing.T) {
+	dir := t.TempDir()
+	ctx, diagnostics := newCrashLogger(t, dir)
+
+	sugarzero.Info(ctx, "evicted")
+	sugarzero.Warn(ctx, "connection pool exhausted")
+	if bundles := readCrashBundles(t, dir); len(bundles) != 0 {
+		t.Fatalf("expected no bundle before fatal, got %d", len(bundles))
+	}
+
+	sugarzero.Fatal(ctx, "cannot recover")
+
+	bundles := readCrashBundles(t, dir)
+	if len(bundles) != 1 {
+		t.Fatalf("expected one bundle, got %d", len(bundles))
+	}
+	bundle := bundles[0]
+	if bundle.Reason != "fatal" || len(bundle.Entries) != 2 {
+		t.Fatalf("unexpected bundle: reason %q, %d entries", bundle.Reason, len(bundle.Entries))
+	}
+	if !strings.Contains(string(bundle.Entries[0]), "connection pool exhausted") ||
+		!strings.Contains(string(bundle.Entries[1]), "cannot recover") {
+		t.Fatalf("unexpected entries: %s", bundle.Entries)
+	}
+	if bundle.GoroutineCount == 0 || !strings.Contains(bundle.Goroutines, "goroutine") {
+		t.Fatal("expected goroutine dump")
+	}
+	if bundle.BuildInfo == nil || bundle.Config.Level != "info" {
+		t.Fatalf("expected build info and config, got %+v, %+v", bundle.BuildInfo, bundle.Config)
+	}
+
+	diagnostic := readLogEntry(t, diagnostics)
+	if diagnostic["diagnostic"] != sugarzero.DiagnosticCrashBundle || !strings.HasPrefix(diagnostic["ref"].(string), "file://") {
+		t.Fatalf("unexpected diagnostic: %v", diagnostic)
+	}
+}
+
+func TestCrashBundleOnRecoveredPanic(t *testing.T) {
+	dir := t.TempDir()
+	ctx, _ := newCrashLogger(t, dir)
+
+	func() {
+		defer sugarzero.RecoverAndLog(ctx)
+		panic("nil map write")
+	}()
+
+	bundles := readCrashBundles(t, dir)
+	if len(bundles) != 1 || bundles[0].Reason != "panic" {
+		t.Fatalf("expected one panic bundle, got %+v", bundles)
+	}
+	if last := bundles[0].Entries[len(bundles[0].Entries)-1]; !strings.Contains(string(last), "nil map write") {
+		t.Fatalf("expected panic entry last, got %s", last)
+	}
+}
@@ -0,0 +1,207 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+var customLevelKey any = ctxKey{name: "custom_level"}
+
+// SeverityFieldName holds the numeric severity of entries logged at a custom
+// level.
+const SeverityFieldName = "severity"
+
+// CustomLevel defines a level name outside zerolog's set, such as syslog's
+// "notice" or "critical". Entries logged at it carry Name as their level and
+// Severity in the severity field, and are filtered and routed like entries at
+// Level.
+// Example: CustomLevel{Name: "notice", Severity: 5, Level: zerolog.InfoLevel}
+type CustomLevel struct {
+	Name     string
+	Severity int
+	Level    zerolog.Level
+}
+
+// SyslogLevels returns custom levels for the syslog severities that zerolog
+// lacks: notice, critical, alert, and emergency.
+func SyslogLevels() []CustomLevel {
+	return []CustomLevel{
+		{Name: "notice", Severity: 5, Level: zerolog.InfoLevel},
+		{Name: "critical", Severity: 2, Level: zerolog.ErrorLevel},
+		{Name: "alert", Severity: 1, Level: zerolog.ErrorLevel},
+		{Name: "emergency", Severity: 0, Level: zerolog.ErrorLevel},
+	}
+}
+
+// WithCustomLevels lets the logger accept the given level names in New,
+// SetLogLevel, and Log. Names are case-insensitive and must not shadow a
+// standard level.
+// Example: NewWithOptions(ctx, "notice", WithCustomLevels(SyslogLevels()...))
+func WithCustomLevels(levels ...CustomLevel) Option {
+	return func(o *options) {
+		o.customLevels = append(o.customLevels, levels...)
+	}
+}
+
+func (o *options) parseCustomLevels() (map[string]*CustomLevel, error) {
+	if len(o.customLevels) == 0 {
+		return nil, nil
+	}
+	levels := make(map[string]*CustomLevel, len(o.customLevels))
+	for _, level := range o.customLevels {
+		name := strings.ToLower(level.Name)
+		if name == "" {
+			return nil, fmt.Errorf("sugarzero: custom level name must not be empty")
+		}
+		if IsValidLevel(name) {
+			return nil, fmt.Errorf("sugarzero: custom level %q shadows a standard level", level.Name)
+		}
+		if level.Level < zerolog.TraceLevel || level.Level > zerolog.PanicLevel {
+			return nil, fmt.Errorf("sugarzero: custom level %q maps to invalid level %d", level.Name, level.Level)
+		}
+		if _, ok := levels[name]; ok {
+			return nil, fmt.Errorf("sugarzero: custom level %q defined twice", level.Name)
+		}
+		level.Name = name
+		levels[name] = &level
+	}
+	return levels, nil
+}
+
+// parseLevel is the package parseLevel extended with the logger's custom
+// levels, which resolve to the level they map to.
+func (l *ZeroLogger) parseLevel(level string) (zerolog.Level, error) {
+	if custom, ok := l.customLevels[strings.ToLower(level)]; ok {
+		return custom.Level, nil
+	}
+	lvl, err := parseLevel(level)
+	if err != nil && len(l.customLevels) > 0 {
+		return lvl, fmt.Errorf("invalid log level %q: must be one of %s", level, strings.Join(l.ValidLevels(), ", "))
+	}
+	return lvl, err
+}
+
+// ValidLevels returns the level names accepted by the logger: the standard
+// levels followed by its custom levels, ordered by severity.
+func (l *ZeroLogger) ValidLevels() []string {
+	names := ValidLevels()
+	custom := make([]*CustomLevel, 0, len(l.customLevels))
+	for _, level := range l.customLevels {
+		custom = append(custom, level)
+	}
+	slices.SortFunc(custom, func(a, b *CustomLevel) int {
+		if a.Severity != b.Severity {
+			return b.Severity - a.Severity
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	for _, level := range custom {
+		names = append(names, level.Name)
+	}
+	return names
+}
+
+// Log logs args at the named level, which may be a standard level or one of
+// the logger's custom levels. Unlike Fatal and Panic, logging at "fatal" or
+// "panic" here neither exits nor panics. Unknown names are logged at error
+// level.
+func (l *ZeroLogger) Log(ctx context.Context, level string, args ...any) {
+	lvl, ctx := l.resolveLevel(ctx, level)
+	l.writeArgs(ctx, lvl, callerSkipFramePublic, args...)
+}
+
+// Logf is Log with a format string.
+func (l *ZeroLogger) Logf(ctx context.Context, level string, format string, args ...any) {
+	lvl, ctx := l.resolveLevel(ctx, level)
+	l.writef(ctx, lvl, callerSkipFramePublic, format, args...)
+}
+
+// resolveLevel returns the zerolog level for name and, for custom levels, a
+// context that makes newEvent label the entry with the custom level.
+func (l *ZeroLogger) resolveLevel(ctx context.Context, name string) (zerolog.Level, context.Context) {
+	if custom, ok := l.customLevels[strings.ToLower(name)]; ok {
+		return custom.Level, context.WithValue(ctx, customLevelKey, custom)
+	}
+	return standardLevel(name), ctx
+}
+
+// Log logs args at the named level through the logger in ctx. Custom levels
+// are only known to loggers created with WithCustomLevels; other loggers log
+// unknown names at error level.
+func Log(ctx context.Context, level string, args ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			zl.Log(resolved, level, args...)
+			return
+		}
+		logAt(resolved, standardLevel(level), fmt.Sprint(args...))
+	})
+}
+
+// Logf is Log with a format string.
+func Logf(ctx context.Context, level string, format string, args ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			zl.Logf(resolved, level, format, args...)
+			return
+		}
+		logAt(resolved, standardLevel(level), fmt.Sprintf(format, args...))
+	})
+}
+
+// standardLevel parses a standard level name, treating unknown names as error.
+func standardLevel(name string) zerolog.Level {
+	if lvl, err := parseLevel(name); err == nil {
+		return lvl
+	}
+	return zerolog.ErrorLevel
+}
+
+// customLevelEvent creates an event for a custom level: it is filtered and
+// routed as custom.Level but labelled with the custom name and severity.
+func customLevelEvent(logger zerolog.Logger, events io.Writer, custom *CustomLevel, skipFrame int) *zerolog.Event {
+	if custom.Level < logger.GetLevel() || custom.Level < zerolog.GlobalLevel() {
+		return nil
+	}
+	if events != nil {
+		logger = logger.Output(fixedLevelWriter{next: events, level: custom.Level})
+	}
+	event := logger.WithLevel(zerolog.NoLevel).CallerSkipFrame(skipFrame)
+	if event == nil {
+		return nil
+	}
+	name := custom.Name
+	if zerolog.LevelFieldMarshalFunc(zerolog.InfoLevel) == "INFO" {
+		name = strings.ToUpper(name)
+	}
+	return event.Str(zerolog.LevelFieldName, name).Int(SeverityFieldName, custom.Severity)
+}
+
+// customLevel returns the custom level Log attached to ctx, if any.
+func (l *ZeroLogger) customLevel(ctx context.Context) *CustomLevel {
+	if len(l.customLevels) == 0 || ctx == nil {
+		return nil
+	}
+	custom, _ := ctx.Value(customLevelKey).(*CustomLevel)
+	return custom
+}
+
+// fixedLevelWriter passes entries to next as written at level, so level
+// routing treats custom-level entries like those at the level they map to.
+type fixedLevelWriter struct {
+	next  io.Writer
+	level zerolog.Level
+}
+
+func (w fixedLevelWriter) Write(p []byte) (int, error) {
+	return writeLevel(w.next, w.level, p)
+}
+
+func (w fixedLevelWriter) WriteLevel(_ zerolog.Level, p []byte) (int, error) {
+	return writeLevel(w.next, w.level, p)
+}
@@ -0,0 +1,93 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestCustomLevels(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf, errorsOnly bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "notice",
+		sugarzero.WithWriters(&buf, &zerolog.FilteredLevelWriter{
+			Writer: zerolog.LevelWriterAdapter{Writer: &errorsOnly},
+			Level:  zerolog.ErrorLevel,
+		}),
+		sugarzero.WithCustomLevels(sugarzero.SyslogLevels()...),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Log(ctx, "notice", "config reloaded")
+	entry := readLogEntry(t, &buf)
+	if entry["level"] != "NOTICE" || entry["severity"] != float64(5) || entry["message"] != "config reloaded" {
+		t.Fatalf("unexpected notice entry: %v", entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "customlevels_test.go") {
+		t.Fatalf("expected caller position in test file, got %q", position)
+	}
+	if errorsOnly.Len() != 0 {
+		t.Fatalf("notice must be routed as info, got %q", errorsOnly.String())
+	}
+
+	sugarzero.Logf(ctx, "CRITICAL", "disk %s full", "/var")
+	entry = readLogEntry(t, &errorsOnly)
+	if entry["level"] != "CRITICAL" || entry["severity"] != float64(2) || entry["message"] != "disk /var full" {
+		t.Fatalf("unexpected critical entry: %v", entry)
+	}
+
+	sugarzero.Log(ctx, "warn", "standard level")
+	if entry := readLogEntry(t, &buf); entry["level"] != "WARN" || entry["severity"] != nil {
+		t.Fatalf("unexpected warn entry: %v", entry)
+	}
+
+	if err := sugarzero.SetLogLevel(ctx, "critical"); err != nil {
+		t.Fatalf("SetLogLevel(critical) failed: %v", err)
+	}
+	buf.Reset()
+	sugarzero.Log(ctx, "notice", "suppressed")
+	if buf.Len() != 0 {
+		t.Fatalf("notice must be filtered at error level, got %q", buf.String())
+	}
+
+	if err := sugarzero.SetLogLevel(ctx, "verbose"); err == nil || !strings.Contains(err.Error(), "emergency") {
+		t.Fatalf("expected error listing custom levels, got %v", err)
+	}
+}
+
+func TestWithCustomLevelsValidation(t *testing.T) {
+	tests := []struct {
+		name  string
+		level sugarzero.CustomLevel
+	}{
+		{name: "empty name", level: sugarzero.CustomLevel{Level: zerolog.InfoLevel}},
+		{name: "shadows standard", level: sugarzero.CustomLevel{Name: "Info", Level: zerolog.InfoLevel}},
+		{name: "invalid mapping", level: sugarzero.CustomLevel{Name: "notice", Level: zerolog.NoLevel}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sugarzero.Reset()
+			t.Cleanup(func() {
+				sugarzero.Reset()
+			})
+
+			_, err := sugarzero.NewWithOptions(context.Background(), "info",
+				sugarzero.WithCustomLevels(tt.level),
+			)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
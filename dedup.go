@@ -0,0 +1,33 @@
+package sugarzero
+
+import "context"
+
+// DedupKeyFieldName is the key under which WithDedupKey writes the dedup key.
+const DedupKeyFieldName = "dedup_key"
+
+var dedupKey any = ctxKey{name: "dedup"}
+
+// WithDedupKey sets the key that identifies entries logged with the returned
+// context as repeats of the same event. It is written as a field so downstream
+// systems can group on it (for example as a Sentry fingerprint), and adaptive
+// sampling uses it in place of the message to group similar entries.
+// Example: sugarzero.Error(sugarzero.WithDedupKey(ctx, "db-timeout:"+shard), "query timed out")
+func WithDedupKey(ctx context.Context, key string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if key == "" {
+		return ctx
+	}
+	ctx = context.WithValue(ctx, dedupKey, key)
+	return withUnscopedFields(ctx, DedupKeyFieldName, key)
+}
+
+// DedupKeyFromContext returns the key set by WithDedupKey, or "".
+func DedupKeyFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	key, _ := ctx.Value(dedupKey).(string)
+	return key
+}
@@ -0,0 +1,60 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithDedupKeyWritesField(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	ctx = sugarzero.WithDedupKey(ctx, "db-timeout:shard-3")
+	sugarzero.Error(ctx, "query timed out")
+
+	entry := readLogEntry(t, testWriter)
+	if entry["dedup_key"] != "db-timeout:shard-3" {
+		t.Fatalf("expected dedup_key field, got %v", entry)
+	}
+	if got := sugarzero.DedupKeyFromContext(ctx); got != "db-timeout:shard-3" {
+		t.Fatalf("expected dedup key from context, got %q", got)
+	}
+}
+
+func TestDedupKeyGroupsSampledEntries(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	window := 50 * time.Millisecond
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{EventsPerSecond: 200, Window: sugarzero.Duration(window)}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	ctx = sugarzero.WithDedupKey(ctx, "upstream-retry")
+
+	for i := 0; i < 100; i++ {
+		sugarzero.Info(ctx, "retrying upstream")
+	}
+	time.Sleep(window)
+	for i := 0; i < 100; i++ {
+		// Distinct messages share the dedup key and are sampled together
+		sugarzero.Info(ctx, "retry", i)
+	}
+	time.Sleep(window)
+	buf.Reset()
+	sugarzero.Info(ctx, "done")
+
+	if !strings.Contains(buf.String(), `"sample_key":"upstream-retry"`) {
+		t.Fatalf("expected summary keyed by dedup key, got %s", buf.String())
+	}
+}
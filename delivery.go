@@ -0,0 +1,115 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"syscall"
+
+	"github.com/rs/zerolog"
+)
+
+// syncKey marks contexts whose entries must be durable before the log call returns.
+var syncKey any = ctxKey{name: "sync"}
+
+// WithSync marks entries logged with the returned context as critical: after
+// each entry is written, every configured writer is flushed and, for files,
+// fsynced before the log call returns. Critical entries are also exempt from
+// sampling. Use it for payment and security events that must survive a crash
+// right after logging; it is too slow for the hot path.
+//
+// Writers that deliver asynchronously, such as NetworkWriter, cannot be
+// synced; pair critical events with a file writer.
+func WithSync(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, syncKey, true)
+}
+
+// ErrorCritical logs at error level like Error and waits until the entry is
+// durable. It is shorthand for Error(WithSync(ctx), args...).
+func ErrorCritical(ctx context.Context, args ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Error(WithSync(resolved), args...)
+	})
+}
+
+// ErrorfCritical is the formatted variant of ErrorCritical.
+func ErrorfCritical(ctx context.Context, format string, args ...any) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Errorf(WithSync(resolved), format, args...)
+	})
+}
+
+// Sync flushes and fsyncs every configured writer that supports it. Writers
+// implementing Sync() error (such as *os.File) are synced; writers implementing
+// Flush() error are flushed. Pending blob uploads are waited for first.
+func (l *ZeroLogger) Sync() error {
+	if l.blobs != nil {
+		l.blobs.wait()
+	}
+	var errs []error
+	for _, w := range l.sinks {
+		if err := syncWriter(w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncIfRequested syncs the writers when ctx was marked with WithSync.
+func (l *ZeroLogger) syncIfRequested(ctx context.Context) {
+	if !isSync(ctx) {
+		return
+	}
+	if err := l.Sync(); err != nil {
+		reportWriteError(fmt.Errorf("sugarzero: sync critical entry: %w", err))
+	}
+}
+
+// sinks lists the configured writers, buffering writers first so Sync drains
+// them before syncing the writers behind them.
+func (o *options) sinks() []io.Writer {
+	targets := append([]io.Writer(nil), o.buffered...)
+	if len(o.writers) == 0 {
+		return append(targets, os.Stdout)
+	}
+	return append(targets, o.writers...)
+}
+
+func syncWriter(w io.Writer) error {
+	switch s := w.(type) {
+	case interface{ Sync() error }:
+		err := s.Sync()
+		// Terminals and pipes cannot be fsynced; there is nothing to commit
+		if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+			return nil
+		}
+		return err
+	case interface{ Flush() error }:
+		return s.Flush()
+	}
+	return nil
+}
+
+// reportWriteError surfaces errors that cannot be returned to the caller,
+// through zerolog.ErrorHandler if set and as a diagnostic otherwise.
+func reportWriteError(err error) {
+	if zerolog.ErrorHandler != nil {
+		zerolog.ErrorHandler(err)
+		return
+	}
+	diagnose(DiagnosticWriteError, zerolog.ErrorLevel, err.Error())
+}
+
+func isSync(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	critical, _ := ctx.Value(syncKey).(bool)
+	return critical
+}
@@ -0,0 +1,70 @@
+package sugarzero_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// countingSyncer records how often it was synced.
+type countingSyncer struct {
+	mu    sync.Mutex
+	data  strings.Builder
+	syncs int
+}
+
+func (c *countingSyncer) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data.Write(p)
+}
+
+func (c *countingSyncer) Sync() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.syncs++
+	return nil
+}
+
+func (c *countingSyncer) Syncs() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.syncs
+}
+
+func TestCriticalEntriesSyncWriters(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	sink := &countingSyncer{}
+	ctx, err := sugarzero.New(context.Background(), "info", sink)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "routine")
+	if sink.Syncs() != 0 {
+		t.Fatalf("expected routine entries not to sync, got %d syncs", sink.Syncs())
+	}
+
+	sugarzero.ErrorCritical(ctx, "payment captured")
+	sugarzero.Infof(sugarzero.WithSync(ctx), "login from %s", "10.0.0.1")
+	if sink.Syncs() != 2 {
+		t.Fatalf("expected 2 syncs, got %d", sink.Syncs())
+	}
+	if !strings.Contains(sink.data.String(), "payment captured") {
+		t.Fatal("expected critical entry to be written")
+	}
+
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if sink.Syncs() != 3 {
+		t.Fatalf("expected explicit Sync to sync writers, got %d syncs", sink.Syncs())
+	}
+}
@@ -0,0 +1,136 @@
+package sugarzero
+
+import (
+	"io"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DiagnosticFieldName marks entries about sugarzero itself.
+	DiagnosticFieldName = "sugarzero_internal"
+	// DiagnosticKindFieldName holds the kind of a diagnostic entry.
+	DiagnosticKindFieldName = "diagnostic"
+)
+
+// Kinds of diagnostics written by sugarzero.
+const (
+	// DiagnosticFallbackLogger reports logging through a context without a
+	// logger, which falls back to the global logger.
+	DiagnosticFallbackLogger = "fallback_logger"
+	// DiagnosticWriteError reports failed writes that cannot be returned to
+	// the caller, such as background deliveries.
+	DiagnosticWriteError = "write_error"
+	// DiagnosticDroppedEntries reports entries shed or dropped by a writer.
+	DiagnosticDroppedEntries = "dropped_entries"
+	// DiagnosticBadFormat reports format verbs that do not match their
+	// arguments; see WithFormatValidation.
+	DiagnosticBadFormat = "bad_format"
+	// DiagnosticCrashBundle reports where a crash bundle was written, or why
+	// writing it failed; see WithCrashBundles.
+	DiagnosticCrashBundle = "crash_bundle"
+	// DiagnosticTraceOrder reports requests that carry trace headers but
+	// reach sugarzero middleware without trace context; see
+	// TraceOrderCheckMiddleware.
+	DiagnosticTraceOrder = "trace_order"
+	// DiagnosticCircuitBreaker reports a CircuitBreaker opening or closing.
+	DiagnosticCircuitBreaker = "circuit_breaker"
+	// DiagnosticLoadShedding reports the governor starting or stopping to
+	// shed entries; see WithGovernor.
+	DiagnosticLoadShedding = "load_shedding"
+)
+
+// dropReportInterval throttles dropped-entry diagnostics per writer.
+const dropReportInterval = 10 * time.Second
+
+var diagnostics = newDiagnosticsState(os.Stderr)
+
+type diagnosticsState struct {
+	mu         sync.RWMutex
+	logger     zerolog.Logger
+	enabled    bool
+	suppressed []string
+}
+
+func newDiagnosticsState(w io.Writer) *diagnosticsState {
+	d := &diagnosticsState{}
+	d.setWriter(w)
+	return d
+}
+
+func (d *diagnosticsState) setWriter(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.enabled = w != nil
+	if w == nil {
+		w = io.Discard
+	}
+	d.logger = zerolog.New(w).With().Timestamp().Bool(DiagnosticFieldName, true).Logger()
+}
+
+// SetDiagnosticsWriter sends sugarzero's own warnings, such as fallback
+// logger use, background write errors, and dropped entries, to w instead of
+// os.Stderr. They are written as JSON entries with sugarzero_internal=true
+// and a "diagnostic" kind field, separate from application output. A nil w
+// disables diagnostics.
+//
+// Write errors still go to zerolog.ErrorHandler when it is set.
+func SetDiagnosticsWriter(w io.Writer) {
+	diagnostics.setWriter(w)
+}
+
+// SuppressDiagnostics disables the given kinds of diagnostics, e.g.
+// DiagnosticFallbackLogger in code that knowingly relies on the global logger.
+func SuppressDiagnostics(kinds ...string) {
+	diagnostics.mu.Lock()
+	defer diagnostics.mu.Unlock()
+	diagnostics.suppressed = append(diagnostics.suppressed, kinds...)
+}
+
+func resetDiagnostics() {
+	diagnostics.setWriter(os.Stderr)
+	diagnostics.mu.Lock()
+	diagnostics.suppressed = nil
+	diagnostics.mu.Unlock()
+	traceOrderReported.Store(false)
+}
+
+// diagnose writes a diagnostic entry of kind at level with the key-value
+// pairs in fields.
+func diagnose(kind string, level zerolog.Level, message string, fields ...any) {
+	diagnostics.mu.RLock()
+	logger := diagnostics.logger
+	skip := !diagnostics.enabled || slices.Contains(diagnostics.suppressed, kind)
+	diagnostics.mu.RUnlock()
+	if skip {
+		return
+	}
+
+	logger.WithLevel(level).
+		Str(DiagnosticKindFieldName, kind).
+		Fields(fields).
+		Msg(message)
+}
+
+// dropNotice reports dropped entries at most once per dropReportInterval.
+type dropNotice struct {
+	last atomic.Int64
+}
+
+func (n *dropNotice) report(writer string, total uint64) {
+	now := time.Now().UnixNano()
+	last := n.last.Load()
+	if last != 0 && now-last < int64(dropReportInterval) {
+		return
+	}
+	if !n.last.CompareAndSwap(last, now) {
+		return
+	}
+	diagnose(DiagnosticDroppedEntries, zerolog.WarnLevel, "writer is dropping entries",
+		"writer", writer, "dropped_total", total)
+}
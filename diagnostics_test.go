@@ -0,0 +1,51 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestDiagnosticsReportWriteErrors(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+
+	w := sugarzero.NewBatchWriter(failingWriter{}, 0, 0, 0)
+	_, _ = w.Write([]byte("entry\n"))
+	if err := w.Flush(); err == nil {
+		t.Fatal("expected Flush to return the write error")
+	}
+
+	entry := readLogEntry(t, &diagnostics)
+	if entry[sugarzero.DiagnosticKindFieldName] != sugarzero.DiagnosticWriteError || entry["message"] != "disk full" {
+		t.Fatalf("expected a write error diagnostic, got %v", entry)
+	}
+}
+
+func TestSuppressDiagnosticsSkipsKinds(t *testing.T) {
+	_, _ = setupTest(t, "info")
+
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+	sugarzero.SuppressDiagnostics(sugarzero.DiagnosticFallbackLogger)
+
+	sugarzero.Info(context.Background(), "relies on the global logger")
+
+	if diagnostics.Len() != 0 {
+		t.Fatalf("expected fallback diagnostics to be suppressed, got %s", diagnostics.String())
+	}
+}
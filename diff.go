@@ -0,0 +1,131 @@
+package sugarzero
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// DiffChange is a single field-level difference reported by Diff.
+type DiffChange struct {
+	// Path is the dotted path of the field, e.g. "limits.max_connections".
+	Path string `json:"path"`
+	// Op is "added", "removed", or "changed".
+	Op   string `json:"op"`
+	From any    `json:"from,omitempty"`
+	To   any    `json:"to,omitempty"`
+}
+
+// Diff returns a field that logs the field-level differences between before
+// and after, which may be structs, maps, or pointers to either. Both values
+// are compared through their JSON encoding, so json tags decide the paths.
+// Values of fields whose name or dotted path matches the logger's
+// WithKeyRedaction policy are replaced with "[REDACTED]"; the change itself
+// is still reported.
+// Example: Info(WithFields(ctx, Diff("config", old, updated)), "config updated")
+func Diff(key string, before, after any) Field {
+	from, err := decodeForDiff(before)
+	if err != nil {
+		return Field{Key: key, Value: fmt.Sprintf("!diff(%v)", err)}
+	}
+	to, err := decodeForDiff(after)
+	if err != nil {
+		return Field{Key: key, Value: fmt.Sprintf("!diff(%v)", err)}
+	}
+	return Field{Key: key, Value: diffValue{from: from, to: to}}
+}
+
+// diffValue holds the values compared by Diff, decoded when Diff is called.
+// The changes are computed when the fields of a context are prepared, so the
+// logger's key redaction applies to them.
+type diffValue struct {
+	from, to any
+}
+
+// render returns the changes as a JSON value, with the values of fields
+// matching redaction masked.
+func (v diffValue) render(redaction *keyRedaction) any {
+	changes := []DiffChange{}
+	diffInto(&changes, redaction, "", v.from, v.to, false)
+	value := safeJSON(changes, DefaultJSONLimit)
+	if encoded, ok := value.(jsonValue); ok {
+		return encoded.render(redaction)
+	}
+	return value
+}
+
+// MarshalJSON renders v without redaction, for loggers other than ZeroLogger.
+func (v diffValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.render(nil))
+}
+
+// decodeForDiff round-trips v through JSON, keeping numbers exact.
+func decodeForDiff(v any) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+func diffInto(changes *[]DiffChange, redaction *keyRedaction, path string, from, to any, redact bool) {
+	fromMap, fromIsMap := from.(map[string]any)
+	toMap, toIsMap := to.(map[string]any)
+	if fromIsMap && toIsMap && !redact {
+		keys := make([]string, 0, len(fromMap)+len(toMap))
+		for key := range fromMap {
+			keys = append(keys, key)
+		}
+		for key := range toMap {
+			if _, ok := fromMap[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child := joinPath(path, key)
+			sensitive := redaction != nil && (redaction.matches(key) || redaction.matches(child))
+			fromValue, inFrom := fromMap[key]
+			toValue, inTo := toMap[key]
+			switch {
+			case !inFrom:
+				*changes = append(*changes, DiffChange{Path: child, Op: "added", To: redactValue(toValue, sensitive)})
+			case !inTo:
+				*changes = append(*changes, DiffChange{Path: child, Op: "removed", From: redactValue(fromValue, sensitive)})
+			default:
+				diffInto(changes, redaction, child, fromValue, toValue, sensitive)
+			}
+		}
+		return
+	}
+
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	*changes = append(*changes, DiffChange{
+		Path: path,
+		Op:   "changed",
+		From: redactValue(from, redact),
+		To:   redactValue(to, redact),
+	})
+}
+
+func redactValue(v any, redact bool) any {
+	if redact {
+		return redactedValue
+	}
+	return v
+}
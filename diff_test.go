@@ -0,0 +1,96 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type dbConfig struct {
+	Host     string         `json:"host"`
+	Password string         `json:"password"`
+	Limits   map[string]int `json:"limits"`
+	Replicas []string       `json:"replicas,omitempty"`
+}
+
+func TestDiffLogsFieldLevelChanges(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	testWriter := &bytes.Buffer{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(testWriter),
+		sugarzero.WithKeyRedaction([]string{"password"}, nil),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	before := dbConfig{Host: "db-1", Password: "hunter2", Limits: map[string]int{"max_conns": 10, "idle": 2}}
+	after := &dbConfig{Host: "db-2", Password: "hunter3", Limits: map[string]int{"max_conns": 20}, Replicas: []string{"db-3"}}
+
+	sugarzero.Info(sugarzero.WithFields(ctx, sugarzero.Diff("config", before, after)), "config updated")
+
+	entry := readLogEntry(t, testWriter)
+	changes, ok := entry["config"].([]any)
+	if !ok {
+		t.Fatalf("expected config diff to be an array, got %T", entry["config"])
+	}
+
+	expected := []map[string]any{
+		{"path": "host", "op": "changed", "from": "db-1", "to": "db-2"},
+		{"path": "limits.idle", "op": "removed", "from": float64(2)},
+		{"path": "limits.max_conns", "op": "changed", "from": float64(10), "to": float64(20)},
+		{"path": "password", "op": "changed", "from": "[REDACTED]", "to": "[REDACTED]"},
+		{"path": "replicas", "op": "added", "to": []any{"db-3"}},
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %d changes, got %v", len(expected), changes)
+	}
+	for i, want := range expected {
+		if !reflect.DeepEqual(changes[i], want) {
+			t.Fatalf("change %d: expected %v, got %v", i, want, changes[i])
+		}
+	}
+}
+
+func TestDiffFollowsKeyRedactionPolicy(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKeyRedaction([]string{"limits.max_conns"}, []string{"pass"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	before := dbConfig{Host: "db-1", Password: "hunter2", Limits: map[string]int{"max_conns": 10}}
+	after := dbConfig{Host: "db-1", Password: "hunter3", Limits: map[string]int{"max_conns": 20}}
+	sugarzero.Info(sugarzero.WithFields(ctx, sugarzero.Diff("config", before, after)), "config updated")
+
+	if out := buf.String(); strings.Contains(out, "hunter") || strings.Contains(out, `"from":10`) {
+		t.Fatalf("expected redacted names and paths to be masked, got %s", out)
+	}
+	changes, _ := readLogEntry(t, &buf)["config"].([]any)
+	if len(changes) != 2 {
+		t.Fatalf("expected the redacted changes to be reported, got %v", changes)
+	}
+}
+
+func TestDiffReportsMarshalErrors(t *testing.T) {
+	field := sugarzero.Diff("config", map[string]any{"ch": make(chan int)}, nil)
+
+	value, _ := field.Value.(string)
+	if !strings.HasPrefix(value, "!diff(") {
+		t.Fatalf("expected diff error marker, got %v", field.Value)
+	}
+}
@@ -0,0 +1,387 @@
+package sugarzero
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// DefaultDigestInterval is how often a DigestSink sends its report.
+	DefaultDigestInterval = 24 * time.Hour
+	// DefaultDigestGroups is the number of message keys listed in a report.
+	DefaultDigestGroups = 50
+	// DefaultDigestTopValues is the number of values listed per field.
+	DefaultDigestTopValues = 3
+
+	// digestMaxValues bounds the distinct values counted per field and key.
+	digestMaxValues = 100
+	// digestMaxKeys bounds the groups tracked per report; entries with
+	// other keys are counted in the digestOtherKey group.
+	digestMaxKeys  = 1000
+	digestOtherKey = "(other)"
+)
+
+// Mailer sends a plain-text email.
+type Mailer interface {
+	Send(subject, body string) error
+}
+
+// MailerFunc adapts a function to the Mailer interface.
+type MailerFunc func(subject, body string) error
+
+// Send calls f(subject, body).
+func (f MailerFunc) Send(subject, body string) error {
+	return f(subject, body)
+}
+
+// SMTPMailer sends email through an SMTP server, upgrading to TLS with
+// STARTTLS when the server supports it.
+type SMTPMailer struct {
+	// Addr is the server address, e.g. "smtp.example.com:587".
+	Addr string
+	// Auth authenticates to the server; nil sends unauthenticated, e.g.
+	// through a local relay.
+	Auth smtp.Auth
+	From string
+	To   []string
+}
+
+// Send sends one message to every recipient.
+func (m *SMTPMailer) Send(subject, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(m.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.NewReplacer("\r", " ", "\n", " ").Replace(subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(m.Addr, m.Auth, m.From, m.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("sugarzero: failed to send digest email: %w", err)
+	}
+	return nil
+}
+
+// DigestConfig configures a DigestSink.
+type DigestConfig struct {
+	// Interval is how often the report is sent. Defaults to
+	// DefaultDigestInterval.
+	Interval time.Duration
+	// Level is the minimum level of summarized entries. Defaults to "warn".
+	Level string
+	// Subject prefixes the email subject, e.g. the service name. Defaults to
+	// "Log digest".
+	Subject string
+	// Fields lists the fields whose most common values are reported per
+	// message key, e.g. "tenant_id" or "dependency".
+	Fields []string
+	// TopValues is the number of values listed per field. Defaults to
+	// DefaultDigestTopValues.
+	TopValues int
+	// MaxGroups is the number of message keys listed, most frequent first.
+	// Defaults to DefaultDigestGroups.
+	MaxGroups int
+}
+
+// DigestSink is a Sink that summarizes warn and error entries and emails a
+// periodic report, for low-traffic internal services no one watches in real
+// time. Entries are grouped by message key: the dedup_key field (see
+// WithDedupKey), else the msg_id field (see InfoID), else the message with
+// the words containing digits replaced by "*", so "retry 3 of order 8812"
+// and "retry 4 of order 9001" share a group. Each group lists its count, its
+// first and last occurrence, an example message, and the most common values
+// of the configured fields. Beyond the first 1000 keys or MaxGroups of a
+// period, whichever is larger, entries are counted in an "(other)" group.
+// No email is sent for a period without entries, and the entries of a report
+// that fails to send are kept for the next one.
+// Example:
+//
+//	digest, err := sugarzero.NewDigestSink(&sugarzero.SMTPMailer{Addr: "localhost:25", From: from, To: team},
+//		sugarzero.DigestConfig{Subject: "billing-api", Fields: []string{"tenant_id"}})
+//	ctx, err = sugarzero.NewWithOptions(ctx, "info", sugarzero.WithSinks(digest))
+type DigestSink struct {
+	mailer Mailer
+	cfg    DigestConfig
+	level  zerolog.Level
+
+	mu     sync.Mutex
+	since  time.Time
+	groups map[string]*digestGroup
+	levels map[zerolog.Level]int
+	closed bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+type digestGroup struct {
+	key         string
+	example     string        // message of the group's first entry
+	level       zerolog.Level // highest level of the group's entries
+	count       int
+	first, last time.Time
+	values      map[string]map[string]int
+}
+
+// NewDigestSink validates cfg and starts the report timer. Close sends the
+// final report.
+func NewDigestSink(mailer Mailer, cfg DigestConfig) (*DigestSink, error) {
+	if mailer == nil {
+		return nil, errors.New("sugarzero: digest mailer must not be nil")
+	}
+	if cfg.Level == "" {
+		cfg.Level = "warn"
+	}
+	level, err := parseLevel(cfg.Level)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: digest level: %w", err)
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultDigestInterval
+	}
+	if cfg.Subject == "" {
+		cfg.Subject = "Log digest"
+	}
+	if cfg.TopValues <= 0 {
+		cfg.TopValues = DefaultDigestTopValues
+	}
+	if cfg.MaxGroups <= 0 {
+		cfg.MaxGroups = DefaultDigestGroups
+	}
+
+	s := &DigestSink{
+		mailer: mailer,
+		cfg:    cfg,
+		level:  level,
+		since:  time.Now(),
+		groups: make(map[string]*digestGroup),
+		levels: make(map[zerolog.Level]int),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go s.run()
+	return s, nil
+}
+
+// WriteEntry adds entry to the current report.
+func (s *DigestSink) WriteEntry(level zerolog.Level, entry []byte) error {
+	if level < s.level || level == zerolog.NoLevel {
+		return nil
+	}
+	parsed, err := ParseEntry(entry)
+	if err != nil {
+		return err
+	}
+	key := digestKey(parsed)
+	at := parsed.Time
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	group := s.group(s.groups, key, parsed.Message, level, at)
+	s.levels[level]++
+	group.count++
+	group.level = max(group.level, level)
+	group.last = at
+	for _, field := range s.cfg.Fields {
+		value, ok := parsed.Fields[field]
+		if !ok {
+			continue
+		}
+		group.addValues(field, map[string]int{fmt.Sprint(value): 1})
+	}
+	return nil
+}
+
+// digestKey returns the message key of entry.
+func digestKey(entry Entry) string {
+	for _, field := range []string{DedupKeyFieldName, MessageIDFieldName} {
+		if key, _ := entry.Fields[field].(string); key != "" {
+			return key
+		}
+	}
+	words := strings.Fields(entry.Message)
+	for i, word := range words {
+		if strings.ContainsAny(word, "0123456789") {
+			words[i] = "*"
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// group returns the group of key in groups, adding it, or when groups is
+// full the digestOtherKey group, if needed. s.mu must be held.
+func (s *DigestSink) group(groups map[string]*digestGroup, key, example string, level zerolog.Level, at time.Time) *digestGroup {
+	if group, ok := groups[key]; ok {
+		return group
+	}
+	if len(groups) >= max(digestMaxKeys, s.cfg.MaxGroups) {
+		key, example = digestOtherKey, ""
+		if group, ok := groups[key]; ok {
+			return group
+		}
+	}
+	group := &digestGroup{key: key, example: example, level: level, first: at, values: make(map[string]map[string]int)}
+	groups[key] = group
+	return group
+}
+
+// addValues adds counts to the counts of field, keeping at most
+// digestMaxValues distinct values.
+func (g *digestGroup) addValues(field string, counts map[string]int) {
+	current := g.values[field]
+	if current == nil {
+		current = make(map[string]int)
+		g.values[field] = current
+	}
+	for value, n := range counts {
+		if _, seen := current[value]; seen || len(current) < digestMaxValues {
+			current[value] += n
+		}
+	}
+}
+
+// Send emails the report for the entries since the last report now, and
+// starts a new period.
+func (s *DigestSink) Send() error {
+	until := time.Now()
+	s.mu.Lock()
+	since, groups, levels := s.since, s.groups, s.levels
+	s.since, s.groups, s.levels = until, make(map[string]*digestGroup), make(map[zerolog.Level]int)
+	s.mu.Unlock()
+
+	if len(groups) == 0 {
+		return nil
+	}
+	subject, body := s.report(since, until, groups, levels)
+	if err := s.mailer.Send(subject, body); err != nil {
+		s.restore(since, groups, levels)
+		return err
+	}
+	return nil
+}
+
+// restore merges the groups of a report that failed to send back into the
+// current period, so the next report includes them.
+func (s *DigestSink) restore(since time.Time, groups map[string]*digestGroup, levels map[zerolog.Level]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.since = since
+	for level, n := range levels {
+		s.levels[level] += n
+	}
+	// Merged into the failed report's groups, which come first
+	for key, group := range s.groups {
+		merged := s.group(groups, key, group.example, group.level, group.first)
+		merged.count += group.count
+		merged.level = max(merged.level, group.level)
+		if group.first.Before(merged.first) {
+			merged.first = group.first
+		}
+		if group.last.After(merged.last) {
+			merged.last = group.last
+		}
+		for field, counts := range group.values {
+			merged.addValues(field, counts)
+		}
+	}
+	s.groups = groups
+}
+
+// Close stops the timer and sends the final report.
+func (s *DigestSink) Close() error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closed = true
+	s.mu.Unlock()
+
+	close(s.stop)
+	<-s.done
+	return s.Send()
+}
+
+func (s *DigestSink) run() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Send(); err != nil {
+				reportWriteError(err)
+			}
+		}
+	}
+}
+
+func (s *DigestSink) report(since, until time.Time, groups map[string]*digestGroup, levels map[zerolog.Level]int) (string, string) {
+	sorted := make([]*digestGroup, 0, len(groups))
+	var total int
+	for _, group := range groups {
+		sorted = append(sorted, group)
+		total += group.count
+	}
+	slices.SortFunc(sorted, func(a, b *digestGroup) int {
+		return cmp.Or(cmp.Compare(b.count, a.count), cmp.Compare(a.key, b.key))
+	})
+
+	subject := fmt.Sprintf("%s: %d entries in %d groups", s.cfg.Subject, total, len(sorted))
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "%d entries from %s to %s\n", total, since.Format(time.RFC3339), until.Format(time.RFC3339))
+	for level := zerolog.PanicLevel; level >= s.level; level-- {
+		if levels[level] > 0 {
+			fmt.Fprintf(&body, "  %s: %d\n", level, levels[level])
+		}
+	}
+	body.WriteString("\n")
+
+	for i, group := range sorted {
+		if i == s.cfg.MaxGroups {
+			fmt.Fprintf(&body, "... and %d more groups\n", len(sorted)-i)
+			break
+		}
+		fmt.Fprintf(&body, "%6d  %-5s  %s\n", group.count, group.level, group.key)
+		if group.example != "" && group.example != group.key {
+			fmt.Fprintf(&body, "        e.g. %s\n", group.example)
+		}
+		fmt.Fprintf(&body, "        first %s, last %s\n", group.first.Format(time.RFC3339), group.last.Format(time.RFC3339))
+		for _, field := range s.cfg.Fields {
+			if top := topValues(group.values[field], s.cfg.TopValues); top != "" {
+				fmt.Fprintf(&body, "        %s: %s\n", field, top)
+			}
+		}
+	}
+	return subject, body.String()
+}
+
+// topValues lists the n most common values in counts as "value (count)".
+func topValues(counts map[string]int, n int) string {
+	values := make([]string, 0, len(counts))
+	for value := range counts {
+		values = append(values, value)
+	}
+	slices.SortFunc(values, func(a, b string) int {
+		return cmp.Or(cmp.Compare(counts[b], counts[a]), cmp.Compare(a, b))
+	})
+	parts := make([]string, 0, n)
+	for _, value := range values[:min(n, len(values))] {
+		parts = append(parts, fmt.Sprintf("%s (%d)", value, counts[value]))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,196 @@
+package sugarzero_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestDigestSinkReport(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var subjects, bodies []string
+	digest, err := sugarzero.NewDigestSink(sugarzero.MailerFunc(func(subject, body string) error {
+		subjects = append(subjects, subject)
+		bodies = append(bodies, body)
+		return nil
+	}), sugarzero.DigestConfig{Subject: "billing-api", Fields: []string{"tenant_id"}})
+	if err != nil {
+		t.Fatalf("NewDigestSink failed: %v", err)
+	}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithSinks(digest),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "request served")
+	for _, tenant := range []string{"acme", "acme", "globex"} {
+		sugarzero.Warn(sugarzero.WithField(ctx, "tenant_id", tenant), "slow query")
+	}
+	sugarzero.Error(sugarzero.WithDedupKey(ctx, "payments-down"), "payment provider unreachable")
+
+	if err := digest.Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if err := digest.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(subjects) != 1 {
+		t.Fatalf("expected one report and no empty report on Close, got %q", subjects)
+	}
+	if subjects[0] != "billing-api: 4 entries in 2 groups" {
+		t.Fatalf("unexpected subject %q", subjects[0])
+	}
+	for _, want := range []string{"warn: 3", "error: 1", "     3  warn   slow query", "tenant_id: acme (2), globex (1)", "     1  error  payments-down"} {
+		if !strings.Contains(bodies[0], want) {
+			t.Fatalf("expected %q in report:\n%s", want, bodies[0])
+		}
+	}
+}
+
+func TestDigestSinkGroupsByMessageShape(t *testing.T) {
+	var bodies []string
+	digest, err := sugarzero.NewDigestSink(sugarzero.MailerFunc(func(_, body string) error {
+		bodies = append(bodies, body)
+		return nil
+	}), sugarzero.DigestConfig{})
+	if err != nil {
+		t.Fatalf("NewDigestSink failed: %v", err)
+	}
+	defer digest.Close()
+
+	for _, entry := range []string{
+		`{"level":"warn","message":"retry 3 of order 8812"}`,
+		`{"level":"warn","message":"retry 4 of order 9001"}`,
+		`{"level":"warn","message":"card declined","msg_id":"PAY-402"}`,
+	} {
+		if err := digest.WriteEntry(zerolog.WarnLevel, []byte(entry)); err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	}
+	for i := range 1000 {
+		entry := fmt.Sprintf(`{"level":"warn","message":"noise","dedup_key":"key-%d"}`, i)
+		if err := digest.WriteEntry(zerolog.WarnLevel, []byte(entry)); err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	}
+	if err := digest.Send(); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+
+	for _, want := range []string{"     2  warn   retry * of order *\n        e.g. retry 3 of order 8812", "     1  warn   PAY-402", "     2  warn   (other)"} {
+		if !strings.Contains(bodies[0], want) {
+			t.Fatalf("expected %q in report:\n%s", want, bodies[0])
+		}
+	}
+}
+
+func TestDigestSinkKeepsEntriesWhenSendFails(t *testing.T) {
+	var bodies []string
+	digest, err := sugarzero.NewDigestSink(sugarzero.MailerFunc(func(_, body string) error {
+		bodies = append(bodies, body)
+		if len(bodies) == 1 {
+			return errors.New("smtp unavailable")
+		}
+		return nil
+	}), sugarzero.DigestConfig{})
+	if err != nil {
+		t.Fatalf("NewDigestSink failed: %v", err)
+	}
+
+	write := func(message string) {
+		t.Helper()
+		if err := digest.WriteEntry(zerolog.ErrorLevel, []byte(`{"level":"error","message":"`+message+`"}`)); err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	}
+	write("disk full")
+	if err := digest.Send(); err == nil {
+		t.Fatal("expected the mailer error")
+	}
+	write("disk full")
+	write("queue stalled")
+	if err := digest.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	if len(bodies) != 2 {
+		t.Fatalf("expected a retried report, got %d", len(bodies))
+	}
+	for _, want := range []string{"3 entries", "     2  error  disk full", "     1  error  queue stalled"} {
+		if !strings.Contains(bodies[1], want) {
+			t.Fatalf("expected %q in the retried report:\n%s", want, bodies[1])
+		}
+	}
+}
+
+func TestSMTPMailer(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer listener.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+		reply("220 localhost")
+		var data strings.Builder
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					data.WriteString(line)
+				}
+				received <- data.String()
+				reply("250 ok")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	mailer := &sugarzero.SMTPMailer{Addr: listener.Addr().String(), From: "logs@example.com", To: []string{"team@example.com"}}
+	if err := mailer.Send("digest\r\nBcc: evil@example.com", "line one\nline two"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	msg := <-received
+	if !strings.Contains(msg, "Subject: digest  Bcc: evil@example.com\r\n") || !strings.Contains(msg, "line one\r\nline two") {
+		t.Fatalf("unexpected message:\n%s", msg)
+	}
+}
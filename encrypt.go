@@ -0,0 +1,110 @@
+package sugarzero
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// maxEncryptedRecord bounds the size of a single record accepted by Decrypt so
+// a corrupted length prefix cannot trigger a huge allocation.
+const maxEncryptedRecord = 64 << 20
+
+// EncryptingWriter encrypts each log entry with AES-GCM before writing it to
+// the underlying writer. Every entry becomes one self-contained record:
+//
+//	[4-byte big-endian length][12-byte nonce][ciphertext+tag]
+//
+// Use Decrypt to recover the plaintext stream.
+type EncryptingWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	aead cipher.AEAD
+}
+
+// NewEncryptingWriter wraps w so that entries are encrypted with key, which
+// must be 16, 24, or 32 bytes long to select AES-128, AES-192, or AES-256.
+func NewEncryptingWriter(w io.Writer, key []byte) (*EncryptingWriter, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return &EncryptingWriter{w: w, aead: aead}, nil
+}
+
+// Write encrypts p as a single record.
+func (e *EncryptingWriter) Write(p []byte) (int, error) {
+	nonceSize := e.aead.NonceSize()
+	record := make([]byte, 4+nonceSize, 4+nonceSize+len(p)+e.aead.Overhead())
+	if _, err := rand.Read(record[4 : 4+nonceSize]); err != nil {
+		return 0, fmt.Errorf("sugarzero: generate nonce: %w", err)
+	}
+	record = e.aead.Seal(record, record[4:4+nonceSize], p, nil)
+	binary.BigEndian.PutUint32(record[:4], uint32(len(record)-4))
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, err := e.w.Write(record); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the underlying writer if it implements io.Closer.
+func (e *EncryptingWriter) Close() error {
+	if closer, ok := e.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// Decrypt reads records produced by EncryptingWriter from src and writes the
+// decrypted entries to dst. It fails on the first truncated or tampered record.
+func Decrypt(dst io.Writer, src io.Reader, key []byte) error {
+	aead, err := newGCM(key)
+	if err != nil {
+		return err
+	}
+
+	var header [4]byte
+	for {
+		if _, err := io.ReadFull(src, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("sugarzero: read record header: %w", err)
+		}
+
+		size := binary.BigEndian.Uint32(header[:])
+		if size < uint32(aead.NonceSize()) || size > maxEncryptedRecord {
+			return fmt.Errorf("sugarzero: invalid record size %d", size)
+		}
+
+		record := make([]byte, size)
+		if _, err := io.ReadFull(src, record); err != nil {
+			return fmt.Errorf("sugarzero: read record: %w", err)
+		}
+
+		nonce, ciphertext := record[:aead.NonceSize()], record[aead.NonceSize():]
+		plaintext, err := aead.Open(ciphertext[:0], nonce, ciphertext, nil)
+		if err != nil {
+			return fmt.Errorf("sugarzero: decrypt record: %w", err)
+		}
+		if _, err := dst.Write(plaintext); err != nil {
+			return err
+		}
+	}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: invalid encryption key: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,77 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestEncryptingWriterRoundTrip(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	key := bytes.Repeat([]byte{0x42}, 32)
+
+	var sink bytes.Buffer
+	writer, err := sugarzero.NewEncryptingWriter(&sink, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, err := sugarzero.New(context.Background(), "info", writer)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "card number accepted")
+	sugarzero.Info(ctx, "second entry")
+
+	if strings.Contains(sink.String(), "card number") {
+		t.Fatal("plaintext leaked into encrypted sink")
+	}
+
+	var plain bytes.Buffer
+	if err := sugarzero.Decrypt(&plain, bytes.NewReader(sink.Bytes()), key); err != nil {
+		t.Fatalf("unexpected decrypt error: %v", err)
+	}
+
+	entry := readLogEntry(t, &plain, 0)
+	if entry["message"] != "card number accepted" {
+		t.Fatalf("unexpected message: %v", entry["message"])
+	}
+	entry = readLogEntry(t, &plain, 1)
+	if entry["message"] != "second entry" {
+		t.Fatalf("unexpected message: %v", entry["message"])
+	}
+}
+
+func TestDecryptDetectsTampering(t *testing.T) {
+	key := bytes.Repeat([]byte{0x01}, 16)
+
+	var sink bytes.Buffer
+	writer, err := sugarzero.NewEncryptingWriter(&sink, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := writer.Write([]byte("{\"message\":\"hello\"}\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	data := sink.Bytes()
+	data[len(data)-1] ^= 0xff
+
+	if err := sugarzero.Decrypt(&bytes.Buffer{}, bytes.NewReader(data), key); err == nil {
+		t.Fatal("expected tampered record to fail decryption")
+	}
+}
+
+func TestNewEncryptingWriterRejectsInvalidKey(t *testing.T) {
+	if _, err := sugarzero.NewEncryptingWriter(&bytes.Buffer{}, []byte("short")); err == nil {
+		t.Fatal("expected error for invalid key length")
+	}
+}
@@ -0,0 +1,280 @@
+package sugarzero
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// maxEntrySize is the longest line EntryReader accepts.
+const maxEntrySize = 64 << 20
+
+// Entry is a decoded log entry.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Message string    `json:"message"`
+	// Fields holds every key of the entry, including time, level, and
+	// message. Numbers are float64, as decoded from JSON.
+	Fields map[string]any `json:"fields"`
+}
+
+// clfLine matches Common and Combined Log Format lines written by
+// AccessLogMiddleware.
+var clfLine = regexp.MustCompile(`^(\S+) \S+ ("(?:[^"\\]|\\.)*"|\S+) \[([^\]]+)\] "((?:[^"\\]|\\.)*)" (\d{3}) (\S+)(?: "((?:[^"\\]|\\.)*)" "((?:[^"\\]|\\.)*)")?$`)
+
+// ParseEntry decodes one line of sugarzero output: a JSON entry, including
+// hash-chained entries and custom levels, or a Common or Combined Log Format
+// line written by AccessLogMiddleware. Access log lines are returned at info
+// level with the request line as message and remote_addr, user, method,
+// path, proto, status, bytes, referer, and user_agent fields.
+func ParseEntry(data []byte) (Entry, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return Entry{}, errors.New("sugarzero: empty entry")
+	}
+	if data[0] == '{' {
+		return parseJSONEntry(data)
+	}
+	if match := clfLine.FindSubmatch(data); match != nil {
+		return parseCLFEntry(match)
+	}
+	return Entry{}, fmt.Errorf("sugarzero: unrecognized entry format: %.40q", data)
+}
+
+func parseJSONEntry(data []byte) (Entry, error) {
+	var entry Entry
+	if err := json.Unmarshal(data, &entry.Fields); err != nil {
+		return Entry{}, fmt.Errorf("sugarzero: invalid JSON entry: %w", err)
+	}
+	entry.Level, _ = entry.Fields[zerolog.LevelFieldName].(string)
+	entry.Level = strings.ToLower(entry.Level)
+	entry.Message, _ = entry.Fields[zerolog.MessageFieldName].(string)
+	entry.Time = parseEntryTime(entry.Fields[zerolog.TimestampFieldName])
+	return entry, nil
+}
+
+// parseEntryTime decodes a timestamp written with zerolog.TimeFieldFormat,
+// including the Unix number formats, decoded as float64 or json.Number.
+func parseEntryTime(value any) time.Time {
+	switch v := value.(type) {
+	case string:
+		t, _ := time.Parse(zerolog.TimeFieldFormat, v)
+		return t
+	case float64:
+		return unixEntryTime(int64(v))
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return unixEntryTime(n)
+		}
+	}
+	return time.Time{}
+}
+
+// unixEntryTime converts a Unix timestamp in the unit of
+// zerolog.TimeFieldFormat.
+func unixEntryTime(v int64) time.Time {
+	switch zerolog.TimeFieldFormat {
+	case zerolog.TimeFormatUnixMs:
+		return time.UnixMilli(v)
+	case zerolog.TimeFormatUnixMicro:
+		return time.UnixMicro(v)
+	case zerolog.TimeFormatUnixNano:
+		return time.Unix(0, v)
+	default:
+		return time.Unix(v, 0)
+	}
+}
+
+func parseCLFEntry(match [][]byte) (Entry, error) {
+	at, err := time.Parse(clfTimeLayout, string(match[3]))
+	if err != nil {
+		return Entry{}, fmt.Errorf("sugarzero: invalid access log time: %w", err)
+	}
+	request := unquoteCLF(match[4])
+	status, _ := strconv.Atoi(string(match[5]))
+
+	fields := map[string]any{"remote_addr": string(match[1]), "status": float64(status)}
+	if user := match[2]; len(user) > 1 && user[0] == '"' {
+		fields["user"] = unquoteCLF(user[1 : len(user)-1])
+	} else if string(user) != "-" {
+		fields["user"] = string(user)
+	}
+	if method, rest, ok := strings.Cut(request, " "); ok {
+		fields["method"] = method
+		path, proto, _ := strings.Cut(rest, " ")
+		fields["path"] = path
+		if proto != "" {
+			fields["proto"] = proto
+		}
+	}
+	if size, err := strconv.ParseFloat(string(match[6]), 64); err == nil {
+		fields["bytes"] = size
+	}
+	if match[7] != nil {
+		if referer := unquoteCLF(match[7]); referer != "-" {
+			fields["referer"] = referer
+		}
+		if agent := unquoteCLF(match[8]); agent != "-" {
+			fields["user_agent"] = agent
+		}
+	}
+	return newAccessEntry(at, request, fields), nil
+}
+
+func unquoteCLF(quoted []byte) string {
+	s := string(quoted)
+	s = strings.ReplaceAll(s, `\"`, `"`)
+	return strings.ReplaceAll(s, `\\`, `\`)
+}
+
+func newAccessEntry(at time.Time, message string, fields map[string]any) Entry {
+	fields[zerolog.TimestampFieldName] = at.Format(time.RFC3339)
+	fields[zerolog.LevelFieldName] = "info"
+	fields[zerolog.MessageFieldName] = message
+	return Entry{Time: at, Level: "info", Message: message, Fields: fields}
+}
+
+// w3cFieldNames maps W3C Extended Log fields to Entry field names.
+var w3cFieldNames = map[string]string{
+	"c-ip":           "remote_addr",
+	"cs-username":    "user",
+	"cs-method":      "method",
+	"cs-uri-stem":    "path",
+	"cs-uri-query":   "query",
+	"sc-status":      "status",
+	"sc-bytes":       "bytes",
+	"time-taken":     "duration_s",
+	"cs(User-Agent)": "user_agent",
+	"cs(Referer)":    "referer",
+}
+
+// EntryReader reads entries from a stream of sugarzero output, such as a log
+// file, a gzip file written through GzipWriter, or an access log. W3C
+// Extended Log lines are parsed with the fields named by the stream's
+// #Fields directive.
+type EntryReader struct {
+	scanner *bufio.Scanner
+	line    int
+	parser  lineParser
+}
+
+// NewEntryReader returns a reader for r, decompressing it if it starts with
+// a gzip header.
+func NewEntryReader(r io.Reader) (*EntryReader, error) {
+	buffered := bufio.NewReader(r)
+	if magic, err := buffered.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: invalid gzip stream: %w", err)
+		}
+		r = gz
+	} else {
+		r = buffered
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEntrySize)
+	return &EntryReader{scanner: scanner}, nil
+}
+
+// Next returns the next entry, skipping blank lines and directives. It
+// returns io.EOF at the end of the stream. A line that cannot be parsed
+// yields an error naming its line number; reading may continue after it.
+func (r *EntryReader) Next() (Entry, error) {
+	for r.scanner.Scan() {
+		r.line++
+		entry, ok, err := r.parser.parse(r.scanner.Bytes())
+		if err != nil {
+			return Entry{}, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if ok {
+			return entry, nil
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Entry{}, fmt.Errorf("sugarzero: read entries: %w", err)
+	}
+	return Entry{}, io.EOF
+}
+
+// lineParser parses the lines of a stream, remembering the fields of W3C
+// Extended Log lines declared by a #Fields directive.
+type lineParser struct {
+	w3cFields []string
+}
+
+// parse decodes line. It reports false for blank lines and directives.
+func (p *lineParser) parse(line []byte) (Entry, bool, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return Entry{}, false, nil
+	}
+	if line[0] == '#' {
+		if fields, ok := bytes.CutPrefix(line, []byte("#Fields:")); ok {
+			p.w3cFields = strings.Fields(string(fields))
+		}
+		return Entry{}, false, nil
+	}
+
+	var (
+		entry Entry
+		err   error
+	)
+	if p.w3cFields != nil && line[0] != '{' {
+		entry, err = p.parseW3C(string(line))
+	} else {
+		entry, err = ParseEntry(line)
+	}
+	return entry, err == nil, err
+}
+
+func (p *lineParser) parseW3C(line string) (Entry, error) {
+	values := strings.Fields(line)
+	if len(values) != len(p.w3cFields) {
+		return Entry{}, fmt.Errorf("sugarzero: W3C line has %d fields, want %d", len(values), len(p.w3cFields))
+	}
+
+	fields := make(map[string]any, len(values))
+	var date, clock string
+	for i, name := range p.w3cFields {
+		value := values[i]
+		switch name {
+		case "date":
+			date = value
+			continue
+		case "time":
+			clock = value
+			continue
+		}
+		if value == "-" {
+			continue
+		}
+		key, ok := w3cFieldNames[name]
+		if !ok {
+			key = name
+		}
+		if number, err := strconv.ParseFloat(value, 64); err == nil && (key == "status" || key == "bytes" || key == "duration_s") {
+			fields[key] = number
+			continue
+		}
+		fields[key] = strings.ReplaceAll(value, "+", " ")
+	}
+
+	at, err := time.Parse("2006-01-02 15:04:05", date+" "+clock)
+	if err != nil {
+		return Entry{}, fmt.Errorf("sugarzero: invalid W3C time: %w", err)
+	}
+	method, _ := fields["method"].(string)
+	path, _ := fields["path"].(string)
+	return newAccessEntry(at, strings.TrimSpace(method+" "+path), fields), nil
+}
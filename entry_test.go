@@ -0,0 +1,96 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestParseEntryJSON(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+	sugarzero.Info(sugarzero.WithField(ctx, "order_id", "o-1"), "order placed")
+
+	entry, err := sugarzero.ParseEntry(buf.Bytes())
+	if err != nil {
+		t.Fatalf("ParseEntry failed: %v", err)
+	}
+	if entry.Level != "info" || entry.Message != "order placed" || entry.Fields["order_id"] != "o-1" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if entry.Time.IsZero() {
+		t.Fatal("expected time to be parsed")
+	}
+
+	for _, invalid := range []string{"", "{not json", "plain text"} {
+		if _, err := sugarzero.ParseEntry([]byte(invalid)); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestParseEntryAccessLog(t *testing.T) {
+	entry, err := sugarzero.ParseEntry([]byte(serveAccessLogged(sugarzero.AccessLogCombined)))
+	if err != nil {
+		t.Fatalf("ParseEntry failed: %v", err)
+	}
+	if entry.Level != "info" || entry.Message != "POST /orders?id=7 HTTP/1.1" || entry.Time.IsZero() {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	want := map[string]any{
+		"remote_addr": "10.0.0.1", "user": "frank", "method": "POST", "path": "/orders?id=7",
+		"status": float64(201), "bytes": float64(7), "user_agent": `curl/8.0 "test"`,
+	}
+	for key, value := range want {
+		if entry.Fields[key] != value {
+			t.Fatalf("expected %s=%v, got %v", key, value, entry.Fields[key])
+		}
+	}
+}
+
+func TestEntryReader(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+	sugarzero.Info(ctx, "first")
+	buf.WriteString("\n")
+	sugarzero.Error(ctx, "second")
+	buf.WriteString("garbage\n")
+	buf.WriteString(serveAccessLogged(sugarzero.AccessLogW3C))
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, _ = gz.Write(buf.Bytes())
+	_ = gz.Close()
+
+	for name, input := range map[string]io.Reader{"plain": bytes.NewReader(buf.Bytes()), "gzip": &compressed} {
+		t.Run(name, func(t *testing.T) {
+			reader, err := sugarzero.NewEntryReader(input)
+			if err != nil {
+				t.Fatalf("NewEntryReader failed: %v", err)
+			}
+
+			var messages []string
+			var parseErrors int
+			for {
+				entry, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if err != nil {
+					parseErrors++
+					continue
+				}
+				messages = append(messages, entry.Message)
+				if entry.Message == "POST /orders" && (entry.Fields["query"] != "id=7" || entry.Fields["user_agent"] != `curl/8.0 "test"`) {
+					t.Fatalf("unexpected W3C entry: %+v", entry)
+				}
+			}
+			if got := strings.Join(messages, ","); got != "first,second,POST /orders" || parseErrors != 1 {
+				t.Fatalf("unexpected messages %q with %d errors", got, parseErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// errorClassifier reports the level an error should be logged at.
+type errorClassifier func(err error) (zerolog.Level, bool)
+
+var (
+	errorLevelsMu sync.RWMutex
+	errorLevels   []errorClassifier
+)
+
+// WithError attaches err to the context so the next log call emits it under the
+// "error" key. If a registered classifier matches err, the entry is logged at
+// the classifier's level instead of the level of the call.
+func WithError(ctx context.Context, err error) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, errorKey, err)
+}
+
+// RegisterErrorLevel logs errors matching target (as reported by errors.Is) at
+// the given level. It is typically used to downgrade expected errors such as
+// context.Canceled or sql.ErrNoRows to debug.
+// Example: RegisterErrorLevel(context.Canceled, "debug")
+func RegisterErrorLevel(target error, level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	registerErrorClassifier(func(err error) (zerolog.Level, bool) {
+		return lvl, errors.Is(err, target)
+	})
+	return nil
+}
+
+// RegisterErrorType logs errors whose chain contains a T (as reported by
+// errors.As) at the given level.
+// Example: RegisterErrorType[*net.OpError]("warn")
+func RegisterErrorType[T error](level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	registerErrorClassifier(func(err error) (zerolog.Level, bool) {
+		var target T
+		return lvl, errors.As(err, &target)
+	})
+	return nil
+}
+
+// RegisterErrorClassifier registers a custom classifier. fn returns the level
+// name for err and whether it applies. Classifiers are consulted in
+// registration order and the first match wins.
+func RegisterErrorClassifier(fn func(err error) (level string, ok bool)) {
+	if fn == nil {
+		return
+	}
+	registerErrorClassifier(func(err error) (zerolog.Level, bool) {
+		level, ok := fn(err)
+		if !ok {
+			return zerolog.NoLevel, false
+		}
+		lvl, parseErr := parseLevel(level)
+		if parseErr != nil {
+			return zerolog.NoLevel, false
+		}
+		return lvl, true
+	})
+}
+
+func registerErrorClassifier(classifier errorClassifier) {
+	errorLevelsMu.Lock()
+	defer errorLevelsMu.Unlock()
+	errorLevels = append(errorLevels, classifier)
+}
+
+func classifyError(err error) (zerolog.Level, bool) {
+	errorLevelsMu.RLock()
+	defer errorLevelsMu.RUnlock()
+	for _, classifier := range errorLevels {
+		if lvl, ok := classifier(err); ok {
+			return lvl, true
+		}
+	}
+	return zerolog.NoLevel, false
+}
+
+func resetErrorLevels() {
+	errorLevelsMu.Lock()
+	defer errorLevelsMu.Unlock()
+	errorLevels = nil
+}
+
+func errorFromContext(ctx context.Context) error {
+	if ctx == nil {
+		return nil
+	}
+	if err, ok := ctx.Value(errorKey).(error); ok {
+		return err
+	}
+	return nil
+}
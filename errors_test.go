@@ -0,0 +1,75 @@
+package sugarzero_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithErrorEmitsErrorField(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	ctx = sugarzero.WithError(ctx, errors.New("boom"))
+	sugarzero.Error(ctx, "operation failed")
+
+	entry := readLogEntry(t, testWriter)
+
+	if entry["error"] != "boom" {
+		t.Fatalf("expected error=boom, got %v", entry["error"])
+	}
+
+	if strings.ToUpper(entry["level"].(string)) != "ERROR" {
+		t.Fatalf("expected ERROR level, got %s", entry["level"])
+	}
+}
+
+func TestRegisterErrorLevelDowngradesWrappedErrors(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	if err := sugarzero.RegisterErrorLevel(context.Canceled, "debug"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errCtx := sugarzero.WithError(ctx, fmt.Errorf("query: %w", context.Canceled))
+	sugarzero.Error(errCtx, "request aborted")
+	if strings.TrimSpace(testWriter.String()) != "" {
+		t.Fatalf("expected canceled error to be downgraded below info, got %s", testWriter.String())
+	}
+
+	sugarzero.SetLogLevel(ctx, "debug")
+	sugarzero.Error(errCtx, "request aborted")
+
+	entry := readLogEntry(t, testWriter)
+	if strings.ToUpper(entry["level"].(string)) != "DEBUG" {
+		t.Fatalf("expected DEBUG level, got %s", entry["level"])
+	}
+}
+
+func TestRegisterErrorTypeMatchesWithAs(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	if err := sugarzero.RegisterErrorType[*fs.PathError]("warn"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	pathErr := &fs.PathError{Op: "open", Path: "/missing", Err: fs.ErrNotExist}
+	sugarzero.Error(sugarzero.WithError(ctx, fmt.Errorf("load config: %w", pathErr)), "config missing")
+
+	entry := readLogEntry(t, testWriter)
+	if strings.ToUpper(entry["level"].(string)) != "WARN" {
+		t.Fatalf("expected WARN level, got %s", entry["level"])
+	}
+}
+
+func TestRegisterErrorLevelRejectsInvalidLevel(t *testing.T) {
+	setupTest(t, "debug")
+
+	if err := sugarzero.RegisterErrorLevel(context.Canceled, "loud"); err == nil {
+		t.Fatal("expected error for invalid log level")
+	}
+}
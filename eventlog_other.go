@@ -0,0 +1,33 @@
+//go:build !windows
+
+package sugarzero
+
+import (
+	"errors"
+
+	"github.com/rs/zerolog"
+)
+
+// EventLogWriter writes entries to the Windows Event Log. It is only
+// functional on Windows.
+type EventLogWriter struct{}
+
+// NewEventLogWriter always fails on platforms other than Windows.
+func NewEventLogWriter(source string, register bool) (*EventLogWriter, error) {
+	return nil, errors.New("sugarzero: the Windows Event Log is not available on this platform")
+}
+
+// Write is never called because NewEventLogWriter fails on this platform.
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// WriteLevel is never called because NewEventLogWriter fails on this platform.
+func (w *EventLogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	return 0, errors.ErrUnsupported
+}
+
+// Close is a no-op on this platform.
+func (w *EventLogWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,70 @@
+//go:build windows
+
+package sugarzero
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// eventLogEventID is the event identifier used for every entry.
+const eventLogEventID = 1
+
+// EventLogWriter writes entries to the Windows Event Log. Debug and info
+// entries become Information events, warn entries become Warning events, and
+// error and above become Error events. The full JSON entry is used as the
+// event message so fields remain searchable.
+type EventLogWriter struct {
+	log *eventlog.Log
+}
+
+// NewEventLogWriter opens the event source named source. When register is
+// true, the source is first registered under the Application log using the
+// EventCreate message file, which requires administrator privileges; an
+// already registered source is not an error.
+func NewEventLogWriter(source string, register bool) (*EventLogWriter, error) {
+	if register {
+		err := eventlog.InstallAsEventCreate(source, eventlog.Error|eventlog.Warning|eventlog.Info)
+		if err != nil && !strings.Contains(err.Error(), "already exists") {
+			return nil, fmt.Errorf("sugarzero: register event source %q: %w", source, err)
+		}
+	}
+
+	log, err := eventlog.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: open event source %q: %w", source, err)
+	}
+	return &EventLogWriter{log: log}, nil
+}
+
+// Write records p as an Information event.
+func (w *EventLogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.InfoLevel, p)
+}
+
+// WriteLevel records p with the event type matching level.
+func (w *EventLogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg := strings.TrimRight(string(p), "\n")
+
+	var err error
+	switch {
+	case level >= zerolog.ErrorLevel && level != zerolog.NoLevel:
+		err = w.log.Error(eventLogEventID, msg)
+	case level == zerolog.WarnLevel:
+		err = w.log.Warning(eventLogEventID, msg)
+	default:
+		err = w.log.Info(eventLogEventID, msg)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the event source handle.
+func (w *EventLogWriter) Close() error {
+	return w.log.Close()
+}
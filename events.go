@@ -0,0 +1,202 @@
+package sugarzero
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// LogEvent is a written entry as seen by OnEvent subscribers.
+type LogEvent struct {
+	Level   string
+	Message string
+	// Fields holds every key of the entry, including level and message.
+	Fields map[string]any
+	// Raw is the encoded entry. It must not be retained after the callback returns.
+	Raw []byte
+}
+
+// EventMatcher selects the entries a subscription is interested in.
+type EventMatcher func(LogEvent) bool
+
+// MatchMessage matches entries whose message contains substr.
+func MatchMessage(substr string) EventMatcher {
+	return func(e LogEvent) bool {
+		return strings.Contains(e.Message, substr)
+	}
+}
+
+// MatchField matches entries whose field key equals value. Numbers are
+// compared as float64, as decoded from JSON.
+func MatchField(key string, value any) EventMatcher {
+	return func(e LogEvent) bool {
+		got, ok := e.Fields[key]
+		return ok && got == value
+	}
+}
+
+// MatchAll matches entries accepted by every matcher.
+func MatchAll(matchers ...EventMatcher) EventMatcher {
+	return func(e LogEvent) bool {
+		for _, match := range matchers {
+			if match != nil && !match(e) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// OnEvent calls callback for every written entry at or above level accepted
+// by match; a nil match accepts every entry. Use it to react to specific
+// errors, for example to open a circuit breaker or page someone, without
+// writing a custom writer. The returned function cancels the subscription.
+//
+// Callbacks run synchronously in the logging goroutine and must be fast and
+// must not log through the same logger at a level they are subscribed to.
+// Example:
+//
+//	stop, err := logger.OnEvent("error", sugarzero.MatchField("dependency", "payments"), breaker.Trip)
+func (l *ZeroLogger) OnEvent(level string, match EventMatcher, callback func(LogEvent)) (func(), error) {
+	if l.events == nil {
+		return nil, errors.New("sugarzero: OnEvent is not supported by loggers created with NewFromZerolog")
+	}
+	if callback == nil {
+		return nil, errors.New("sugarzero: OnEvent callback must not be nil")
+	}
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return l.events.subscribe(&subscription{level: lvl, match: match, callback: callback}), nil
+}
+
+type subscription struct {
+	level    zerolog.Level
+	match    EventMatcher
+	callback func(LogEvent)
+}
+
+// eventBus forwards entries to next and dispatches them to subscribers. With no
+// subscribers it adds a single atomic load per entry.
+type eventBus struct {
+	next io.Writer
+	// reserved renames or drops duplicated reserved keys, such as those
+	// added to Raw events.
+	reserved reservedKeys
+	// pii masks personal data before the entry goes anywhere; nil unless
+	// WithPIIDetection is used.
+	pii *piiScrubber
+	// tail keeps recent entries for TailHandler; nil unless WithTailBuffer
+	// is used.
+	tail *entryRing
+	// recentErrors keeps recent warn and error entries; nil unless
+	// WithRecentErrors is used.
+	recentErrors *entryRing
+	// crash writes crash bundles; nil unless WithCrashBundles is used.
+	crash *crashReporter
+
+	mu            sync.Mutex
+	subscriptions atomic.Pointer[[]*subscription]
+}
+
+func (b *eventBus) Write(p []byte) (int, error) {
+	return b.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (b *eventBus) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	size := len(p)
+	p = b.reserved.filterEntry(p)
+	if b.pii != nil {
+		p = b.pii.scrub(p)
+	}
+	n, err := writeLevel(b.next, level, p)
+	if err == nil {
+		n = size
+	}
+	if b.tail != nil {
+		b.tail.add(level, p)
+	}
+	if b.recentErrors != nil && recordsRecentError(level) {
+		b.recentErrors.add(level, p)
+	}
+	if b.crash != nil {
+		b.crash.entries.add(level, p)
+		if reason, ok := crashReason(level); ok {
+			b.crash.write(reason)
+		}
+	}
+	if subs := b.subscriptions.Load(); subs != nil {
+		b.dispatch(*subs, level, p)
+	}
+	return n, err
+}
+
+func (b *eventBus) dispatch(subs []*subscription, level zerolog.Level, p []byte) {
+	var (
+		event   LogEvent
+		decoded bool
+	)
+	for _, sub := range subs {
+		if level < sub.level || level == zerolog.NoLevel {
+			continue
+		}
+		if !decoded {
+			decoded = true
+			if err := json.Unmarshal(p, &event.Fields); err != nil {
+				return
+			}
+			event.Level = level.String()
+			event.Message, _ = event.Fields[zerolog.MessageFieldName].(string)
+			event.Raw = p
+		}
+		if sub.match == nil || sub.match(event) {
+			sub.callback(event)
+		}
+	}
+}
+
+func (b *eventBus) subscribe(sub *subscription) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var subs []*subscription
+	if current := b.subscriptions.Load(); current != nil {
+		subs = append(subs, *current...)
+	}
+	subs = append(subs, sub)
+	b.subscriptions.Store(&subs)
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.unsubscribe(sub)
+		})
+	}
+}
+
+func (b *eventBus) unsubscribe(sub *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	current := b.subscriptions.Load()
+	if current == nil {
+		return
+	}
+	var subs []*subscription
+	for _, s := range *current {
+		if s != sub {
+			subs = append(subs, s)
+		}
+	}
+	if len(subs) == 0 {
+		b.subscriptions.Store(nil)
+		return
+	}
+	b.subscriptions.Store(&subs)
+}
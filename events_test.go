@@ -0,0 +1,58 @@
+package sugarzero_test
+
+import (
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestOnEventDispatchesMatchingEntries(t *testing.T) {
+	ctx, _ := setupTest(t, "debug")
+
+	var tripped []sugarzero.LogEvent
+	stop, err := sugarzero.OnEvent(ctx, "error",
+		sugarzero.MatchAll(
+			sugarzero.MatchField("dependency", "payments"),
+			sugarzero.MatchMessage("timeout"),
+		),
+		func(e sugarzero.LogEvent) {
+			tripped = append(tripped, e)
+		},
+	)
+	if err != nil {
+		t.Fatalf("OnEvent failed: %v", err)
+	}
+
+	payments := sugarzero.WithField(ctx, "dependency", "payments")
+	sugarzero.Error(payments, "upstream timeout")
+	sugarzero.Warn(payments, "slow timeout")
+	sugarzero.Error(sugarzero.WithField(ctx, "dependency", "search"), "upstream timeout")
+	sugarzero.Error(payments, "bad request")
+
+	if len(tripped) != 1 {
+		t.Fatalf("expected one matching event, got %+v", tripped)
+	}
+	if tripped[0].Level != "error" || tripped[0].Message != "upstream timeout" {
+		t.Fatalf("unexpected event %+v", tripped[0])
+	}
+
+	stop()
+	sugarzero.Error(payments, "upstream timeout")
+	if len(tripped) != 1 {
+		t.Fatalf("expected no events after unsubscribe, got %d", len(tripped))
+	}
+}
+
+func TestOnEventRejectsInvalidArguments(t *testing.T) {
+	ctx, _ := setupTest(t, "debug")
+
+	if _, err := sugarzero.OnEvent(ctx, "error", nil, nil); err == nil {
+		t.Fatal("expected error for nil callback")
+	}
+	if _, err := sugarzero.OnEvent(ctx, "loud", nil, func(sugarzero.LogEvent) {}); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+	if _, err := sugarzero.OnEvent(sugarzero.WithLogger(ctx, sugarzero.NewMock()), "error", nil, func(sugarzero.LogEvent) {}); err == nil {
+		t.Fatal("expected error for non-zerolog logger")
+	}
+}
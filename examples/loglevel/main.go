@@ -16,17 +16,6 @@ const (
 	defaultLevel = "info"
 )
 
-var (
-	allowedLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
-	allowedLookup = func() map[string]struct{} {
-		m := make(map[string]struct{}, len(allowedLevels))
-		for _, lvl := range allowedLevels {
-			m[lvl] = struct{}{}
-		}
-		return m
-	}()
-)
-
 type (
 	levelChangeRequest struct {
 		Level string `json:"level"`
@@ -53,6 +42,7 @@ func main() {
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/log-level", logLevelHandler(ctx))
+	mux.Handle("/log-health", sugarzero.HealthHandler(ctx))
 
 	sugarzero.Infof(ctx, "log level API listening on http://localhost%s/log-level", apiAddr)
 
@@ -84,8 +74,8 @@ func logLevelHandler(ctx context.Context) http.HandlerFunc {
 				return
 			}
 
-			if _, ok := allowedLookup[desired]; !ok {
-				writeJSON(w, http.StatusBadRequest, errorResponse{Error: "level must be one of " + strings.Join(allowedLevels, ", ")})
+			if !sugarzero.IsValidLevel(desired) {
+				writeJSON(w, http.StatusBadRequest, errorResponse{Error: "level must be one of " + strings.Join(sugarzero.ValidLevels(), ", ")})
 				return
 			}
 
@@ -124,6 +114,6 @@ func newLevelResponse(ctx context.Context) levelResponse {
 	return levelResponse{
 		CurrentLevel: sugarzero.GetLogLevel(ctx),
 		DefaultLevel: defaultLevel,
-		Allowed:      allowedLevels,
+		Allowed:      sugarzero.ValidLevels(),
 	}
 }
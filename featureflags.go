@@ -0,0 +1,91 @@
+package sugarzero
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// FeatureFlagsFieldName holds the flag variations recorded for a request.
+const FeatureFlagsFieldName = "feature_flags"
+
+var featureFlagsKey any = ctxKey{name: "feature_flags"}
+
+// featureFlagsInUse is set by the first WithFeatureFlags call, so loggers in
+// programs that never track flags skip the context lookup.
+var featureFlagsInUse atomic.Bool
+
+// featureFlags collects the variations evaluated during a request.
+type featureFlags struct {
+	mu       sync.Mutex
+	variants map[string]string
+}
+
+// WithFeatureFlags returns a context that collects feature flag evaluations
+// recorded with RecordFeatureFlag. Every entry logged through it, or a
+// context derived from it, carries the variations recorded so far in the
+// feature_flags field, e.g. {"new-checkout":"on"}. Call it once per request,
+// before flags are evaluated.
+func WithFeatureFlags(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	featureFlagsInUse.Store(true)
+	return context.WithValue(ctx, featureFlagsKey, &featureFlags{variants: make(map[string]string)})
+}
+
+// RecordFeatureFlag records that flag evaluated to variant for the request
+// in ctx. It does nothing if ctx does not come from WithFeatureFlags. Call it
+// from the flag SDK's evaluation hook, e.g. an OpenFeature hook:
+//
+//	func (flagLogHook) After(ctx context.Context, _ openfeature.HookContext,
+//		details openfeature.InterfaceEvaluationDetails, _ openfeature.HookHints) error {
+//		sugarzero.RecordFeatureFlag(ctx, details.FlagKey, details.Variant)
+//		return nil
+//	}
+func RecordFeatureFlag(ctx context.Context, flag, variant string) {
+	flags := featureFlagsFromContext(ctx)
+	if flags == nil {
+		return
+	}
+	flags.mu.Lock()
+	flags.variants[flag] = variant
+	flags.mu.Unlock()
+}
+
+// FeatureFlagsFromContext returns a copy of the variations recorded in ctx.
+func FeatureFlagsFromContext(ctx context.Context) map[string]string {
+	flags := featureFlagsFromContext(ctx)
+	if flags == nil {
+		return nil
+	}
+	flags.mu.Lock()
+	defer flags.mu.Unlock()
+	return maps.Clone(flags.variants)
+}
+
+func featureFlagsFromContext(ctx context.Context) *featureFlags {
+	if ctx == nil || !featureFlagsInUse.Load() {
+		return nil
+	}
+	flags, _ := ctx.Value(featureFlagsKey).(*featureFlags)
+	return flags
+}
+
+// appendTo adds the recorded variations to event, sorted by flag.
+func (f *featureFlags) appendTo(event *zerolog.Event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.variants) == 0 {
+		return
+	}
+	dict := zerolog.Dict()
+	for _, flag := range slices.Sorted(maps.Keys(f.variants)) {
+		dict.Str(flag, f.variants[flag])
+	}
+	event.Dict(FeatureFlagsFieldName, dict)
+}
@@ -0,0 +1,58 @@
+package sugarzero_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// evaluateFlag stands in for a flag SDK call whose hook records the result.
+func evaluateFlag(ctx context.Context, flag, variant string) bool {
+	sugarzero.RecordFeatureFlag(ctx, flag, variant)
+	return variant == "on"
+}
+
+func TestFeatureFlags(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	sugarzero.Info(ctx, "before tracking")
+	if entry := readLogEntry(t, buf); entry["feature_flags"] != nil {
+		t.Fatalf("unexpected flags: %v", entry)
+	}
+
+	ctx = sugarzero.WithFeatureFlags(ctx)
+	sugarzero.Info(ctx, "no flags yet")
+	if entry := readLogEntry(t, buf); entry["feature_flags"] != nil {
+		t.Fatalf("unexpected empty flags: %v", entry)
+	}
+
+	requestCtx := sugarzero.WithField(ctx, "request_id", "r-1")
+	evaluateFlag(requestCtx, "new-checkout", "on")
+	evaluateFlag(requestCtx, "search-ranking", "v2")
+
+	sugarzero.Info(ctx, "checkout started")
+	entry := readLogEntry(t, buf)
+	flags, ok := entry["feature_flags"].(map[string]any)
+	if !ok || flags["new-checkout"] != "on" || flags["search-ranking"] != "v2" {
+		t.Fatalf("expected recorded flags, got %v", entry)
+	}
+
+	if got := sugarzero.FeatureFlagsFromContext(requestCtx); len(got) != 2 || got["search-ranking"] != "v2" {
+		t.Fatalf("unexpected flags from context: %v", got)
+	}
+}
+
+func TestRecordFeatureFlagWithoutTracking(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	sugarzero.RecordFeatureFlag(ctx, "new-checkout", "on")
+	sugarzero.Info(ctx, "untracked")
+
+	if entry := readLogEntry(t, buf); entry["feature_flags"] != nil {
+		t.Fatalf("unexpected flags: %v", entry)
+	}
+	if flags := sugarzero.FeatureFlagsFromContext(ctx); flags != nil {
+		t.Fatalf("expected no flags, got %v", flags)
+	}
+}
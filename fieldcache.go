@@ -0,0 +1,53 @@
+package sugarzero
+
+import (
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// contextFields holds the fields attached to a context by WithFields together
+// with a lazily built child logger that carries them pre-encoded.
+//
+// zerolog encodes the fields of a child logger (Logger.With) once and copies
+// the encoded bytes into every event, so caching the child per context turns
+// per-event field serialization into a single memory copy. The cache is keyed
+// by the owning ZeroLogger and its generation, so SetLogLevel and other
+// changes to the base logger are picked up on the next log call. Values are
+// not re-encoded when they are mutated; WithFields documents this.
+type contextFields struct {
+	flat  []any
+	cache atomic.Pointer[fieldsCache]
+	// crlf caches whether flat contains CR or LF, for log injection
+	// protection; zero until scanned.
+	crlf atomic.Uint32
+}
+
+type fieldsCache struct {
+	owner      *ZeroLogger
+	generation uint64
+	logger     zerolog.Logger
+}
+
+// logger returns base extended with the encoded fields, reusing the cached
+// child logger when it was built from the same base.
+func (f *contextFields) logger(owner *ZeroLogger, generation uint64, base zerolog.Logger) zerolog.Logger {
+	if cached := f.cache.Load(); cached != nil && cached.owner == owner && cached.generation == generation {
+		return cached.logger
+	}
+
+	child := base.With().Fields(owner.prepareFields(f.flat)).Logger()
+	f.cache.Store(&fieldsCache{owner: owner, generation: generation, logger: child})
+	return child
+}
+
+// prepareFields applies the key sanitization, reserved-key policy, key
+// redaction, coercion rules, log injection escaping, and blob offloading of l
+// to the flattened key-value pairs. flat itself is never modified.
+func (l *ZeroLogger) prepareFields(flat []any) []any {
+	flat = l.coerceFields(l.redactFields(l.reserved.apply(sanitizeKeys(l.keySanitization, flat))))
+	if l.injectionProtection {
+		flat = escapeFieldValues(flat)
+	}
+	return l.offloadFields(flat)
+}
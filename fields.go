@@ -0,0 +1,89 @@
+package sugarzero
+
+import (
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+)
+
+// DefaultJSONLimit is the maximum encoded size, in bytes, of a value logged via JSON.
+const DefaultJSONLimit = 16 << 10
+
+// Field is a single key-value pair that can be passed to WithFields alongside
+// regular alternating key-value pairs.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// JSON returns a field that logs v as a nested JSON object rather than its Go
+// syntax representation. Values larger than DefaultJSONLimit are truncated.
+// Example: WithFields(ctx, JSON("body", payload))
+func JSON(key string, v any) Field {
+	return JSONWithLimit(key, v, DefaultJSONLimit)
+}
+
+// JSONWithLimit is like JSON but truncates values whose encoding exceeds limit
+// bytes. A limit <= 0 disables truncation. Nested keys matching WithKeyRedaction
+// are redacted before truncating, which never splits a character.
+//
+// Marshaling never panics: cyclic values, unsupported types, and panicking
+// MarshalJSON implementations are logged as a descriptive string instead.
+func JSONWithLimit(key string, v any, limit int) Field {
+	return Field{Key: key, Value: safeJSON(v, limit)}
+}
+
+func safeJSON(v any, limit int) (value any) {
+	defer func() {
+		if r := recover(); r != nil {
+			value = fmt.Sprintf("!json(panic: %v)", r)
+		}
+	}()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("!json(%v)", err)
+	}
+	return jsonValue{data: data, limit: limit}
+}
+
+// jsonValue is a value marshaled by JSONWithLimit. It is rendered when the
+// fields of a context are prepared, so the logger's key redaction applies to
+// its nested keys before it is truncated.
+type jsonValue struct {
+	data  json.RawMessage
+	limit int
+}
+
+// render returns the value with the values of keys matching redaction masked
+// at any depth, truncated at the limit on a character boundary.
+func (v jsonValue) render(redaction *keyRedaction) any {
+	data := v.data
+	if redaction != nil {
+		data = redaction.redactJSON(data)
+	}
+	if v.limit <= 0 || len(data) <= v.limit {
+		return data
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes)", truncateUTF8(data, v.limit), len(data))
+}
+
+// truncateUTF8 returns at most the first n bytes of data, cut before a
+// character that would be split.
+func truncateUTF8(data []byte, n int) []byte {
+	if len(data) <= n {
+		return data
+	}
+	for n > 0 && !utf8.RuneStart(data[n]) {
+		n--
+	}
+	return data[:n]
+}
+
+// MarshalJSON renders v without redaction, for loggers other than ZeroLogger.
+func (v jsonValue) MarshalJSON() ([]byte, error) {
+	if data, ok := v.render(nil).(json.RawMessage); ok {
+		return data, nil
+	}
+	return json.Marshal(v.render(nil))
+}
@@ -0,0 +1,123 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type node struct {
+	Name string `json:"name"`
+	Next *node  `json:"next,omitempty"`
+}
+
+func TestJSONFieldEmitsNestedObject(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	ctx = sugarzero.WithFields(ctx,
+		"request_id", "req-1",
+		sugarzero.JSON("body", map[string]any{"user": "alice", "items": []int{1, 2}}),
+	)
+	sugarzero.Info(ctx, "request body")
+
+	entry := readLogEntry(t, testWriter)
+
+	body, ok := entry["body"].(map[string]any)
+	if !ok {
+		t.Fatalf("expected body to be a JSON object, got %T", entry["body"])
+	}
+	if body["user"] != "alice" {
+		t.Fatalf("expected body.user=alice, got %v", body["user"])
+	}
+	if entry["request_id"] != "req-1" {
+		t.Fatalf("expected request_id=req-1, got %v", entry["request_id"])
+	}
+}
+
+func TestJSONFieldTruncatesLargeValues(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	ctx = sugarzero.WithFields(ctx, sugarzero.JSONWithLimit("body", strings.Repeat("x", 100), 10))
+	sugarzero.Info(ctx, "large body")
+
+	entry := readLogEntry(t, testWriter)
+
+	body, ok := entry["body"].(string)
+	if !ok || !strings.Contains(body, "truncated") {
+		t.Fatalf("expected truncated body, got %v", entry["body"])
+	}
+}
+
+func TestJSONFieldTruncatesOnCharacterBoundary(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	// The limit falls in the middle of the second character
+	ctx = sugarzero.WithFields(ctx, sugarzero.JSONWithLimit("body", "日本語", 5))
+	sugarzero.Info(ctx, "multibyte body")
+
+	body, _ := readLogEntry(t, testWriter)["body"].(string)
+	if !strings.HasPrefix(body, `"日...(truncated`) || !utf8.ValidString(body) {
+		t.Fatalf("expected truncation before the split character, got %q", body)
+	}
+}
+
+func TestJSONFieldRedactsNestedKeys(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKeyRedaction([]string{"password"}, []string{"secret_"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx,
+		sugarzero.JSON("body", map[string]any{
+			"user":  "alice",
+			"login": map[string]any{"password": "hunter2", "secret_token": []string{"a", "b"}},
+		}),
+		sugarzero.JSONWithLimit("short", struct {
+			Password string `json:"password"`
+			Padding  string `json:"padding"`
+		}{"hunter2", strings.Repeat("x", 40)}, 30),
+	)
+	sugarzero.Info(ctx, "signup")
+
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Fatalf("expected nested secrets to be redacted, got %s", buf.String())
+	}
+	entry := readLogEntry(t, &buf)
+	body := entry["body"].(map[string]any)
+	login := body["login"].(map[string]any)
+	if body["user"] != "alice" || login["password"] != "[REDACTED]" || login["secret_token"] != "[REDACTED]" {
+		t.Fatalf("expected nested keys to be redacted, got %v", body)
+	}
+	if short, _ := entry["short"].(string); !strings.Contains(short, `"password":"[REDACTED]"`) {
+		t.Fatalf("expected redaction before truncation, got %q", short)
+	}
+}
+
+func TestJSONFieldSurvivesCycles(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	cyclic := &node{Name: "a"}
+	cyclic.Next = cyclic
+
+	ctx = sugarzero.WithFields(ctx, sugarzero.JSON("graph", cyclic))
+	sugarzero.Info(ctx, "cyclic value")
+
+	entry := readLogEntry(t, testWriter)
+
+	graph, ok := entry["graph"].(string)
+	if !ok || !strings.HasPrefix(graph, "!json(") {
+		t.Fatalf("expected marshal error placeholder, got %v", entry["graph"])
+	}
+}
@@ -0,0 +1,539 @@
+package sugarzero
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/rs/zerolog"
+)
+
+// Filter is a compiled filter expression matching log entries. It is the
+// matcher behind FilterSink, MatchFilter, and the filter parameter of
+// TailHandler.
+//
+// An expression compares fields with ==, !=, <, <=, >, >=, or =~ (a regular
+// expression), and combines comparisons with &&, ||, !, and parentheses.
+// Identifiers name top-level keys such as message or tenant_id; the fields.
+// prefix is optional, and dots select nested keys. level compares by
+// severity when the other side names a standard level; ordering it against
+// anything else is an error. A bare identifier
+// matches entries where the field is present and not false, null, 0, or "".
+// Example: ParseFilter(`level>=warn && fields.tenant=='acme'`)
+type Filter struct {
+	expr   string
+	root   filterNode
+	fields bool
+}
+
+// ParseFilter compiles expr.
+func ParseFilter(expr string) (*Filter, error) {
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: invalid filter %q: %w", expr, err)
+	}
+	p := &filterParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err == nil && p.peek().kind != filterEOF {
+		err = fmt.Errorf("unexpected %q at offset %d", p.peek().text, p.peek().pos)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: invalid filter %q: %w", expr, err)
+	}
+	return &Filter{expr: expr, root: root, fields: p.fields}, nil
+}
+
+// MustParseFilter is like ParseFilter but panics on error, for package-level
+// filters.
+func MustParseFilter(expr string) *Filter {
+	f, err := ParseFilter(expr)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// String returns the source expression.
+func (f *Filter) String() string {
+	return f.expr
+}
+
+// Match reports whether the encoded entry at level matches. The entry is
+// only decoded when the expression references fields other than level, or
+// when level is NoLevel and is read from the entry instead.
+func (f *Filter) Match(level zerolog.Level, entry []byte) bool {
+	in := &filterInput{level: level}
+	if f.fields || level == zerolog.NoLevel {
+		if json.Unmarshal(entry, &in.fields) != nil {
+			return false
+		}
+		if level == zerolog.NoLevel {
+			in.level = fieldLevel(in.fields)
+		}
+	}
+	return f.root.eval(in)
+}
+
+// MatchEntry reports whether a decoded entry matches.
+func (f *Filter) MatchEntry(entry Entry) bool {
+	return f.root.eval(&filterInput{level: fieldLevel(entry.Fields), fields: entry.Fields})
+}
+
+// fieldLevel returns the standard level named by the level field, or NoLevel.
+func fieldLevel(fields map[string]any) zerolog.Level {
+	name, _ := fields[zerolog.LevelFieldName].(string)
+	level, err := parseLevel(name)
+	if err != nil || name == "" {
+		return zerolog.NoLevel
+	}
+	return level
+}
+
+// MatchFilter adapts filter to an EventMatcher for OnEvent subscriptions.
+func MatchFilter(filter *Filter) EventMatcher {
+	return func(e LogEvent) bool {
+		level, err := parseLevel(e.Level)
+		if err != nil || e.Level == "" {
+			level = zerolog.NoLevel
+		}
+		return filter.root.eval(&filterInput{level: level, fields: e.Fields})
+	}
+}
+
+// FilterSink returns a Sink that forwards entries matching filter to next
+// and discards the rest, for routing a subset of entries to a dedicated
+// destination.
+// Example: WithSinks(FilterSink(MustParseFilter(`fields.category=='audit'`), auditSink))
+func FilterSink(filter *Filter, next Sink) Sink {
+	return SinkFunc(func(level zerolog.Level, entry []byte) error {
+		if !filter.Match(level, entry) {
+			return nil
+		}
+		return next.WriteEntry(level, entry)
+	})
+}
+
+type filterInput struct {
+	level  zerolog.Level
+	fields map[string]any
+}
+
+func (in *filterInput) lookup(path []string) (any, bool) {
+	var value any = in.fields
+	for _, key := range path {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		if value, ok = m[key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+type filterNode interface {
+	eval(in *filterInput) bool
+}
+
+type filterAnd struct{ left, right filterNode }
+
+func (n filterAnd) eval(in *filterInput) bool { return n.left.eval(in) && n.right.eval(in) }
+
+type filterOr struct{ left, right filterNode }
+
+func (n filterOr) eval(in *filterInput) bool { return n.left.eval(in) || n.right.eval(in) }
+
+type filterNot struct{ node filterNode }
+
+func (n filterNot) eval(in *filterInput) bool { return !n.node.eval(in) }
+
+// filterLevel compares the entry level by severity, without decoding it.
+type filterLevel struct {
+	op    string
+	level zerolog.Level
+}
+
+func (n filterLevel) eval(in *filterInput) bool {
+	if in.level == zerolog.NoLevel {
+		return n.op == "!="
+	}
+	return compareOrdered(in.level, n.level, n.op)
+}
+
+// filterTruthy matches entries where path is set to a truthy value.
+type filterTruthy struct{ path []string }
+
+func (n filterTruthy) eval(in *filterInput) bool {
+	value, ok := in.lookup(n.path)
+	if !ok {
+		return false
+	}
+	switch v := value.(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case float64:
+		return v != 0
+	case string:
+		return v != ""
+	}
+	return true
+}
+
+type filterRegexp struct {
+	path []string
+	re   *regexp.Regexp
+}
+
+func (n filterRegexp) eval(in *filterInput) bool {
+	value, ok := in.lookup(n.path)
+	if !ok {
+		return false
+	}
+	s, ok := value.(string)
+	if !ok {
+		s = fmt.Sprint(value)
+	}
+	return n.re.MatchString(s)
+}
+
+// filterOperand is a field path or a literal.
+type filterOperand struct {
+	path    []string
+	literal any
+}
+
+func (o filterOperand) value(in *filterInput) any {
+	if o.path == nil {
+		return o.literal
+	}
+	value, _ := in.lookup(o.path)
+	return value
+}
+
+type filterCompare struct {
+	op          string
+	left, right filterOperand
+}
+
+func (n filterCompare) eval(in *filterInput) bool {
+	left, right := n.left.value(in), n.right.value(in)
+	switch n.op {
+	case "==":
+		return equalValues(left, right)
+	case "!=":
+		return !equalValues(left, right)
+	}
+	switch l := left.(type) {
+	case float64:
+		if r, ok := right.(float64); ok {
+			return compareOrdered(l, r, n.op)
+		}
+	case string:
+		if r, ok := right.(string); ok {
+			return compareOrdered(l, r, n.op)
+		}
+	}
+	return false
+}
+
+// equalValues compares decoded JSON scalars; objects and arrays never match.
+func equalValues(a, b any) bool {
+	switch a.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	switch b.(type) {
+	case map[string]any, []any:
+		return false
+	}
+	return a == b
+}
+
+func compareOrdered[T zerolog.Level | float64 | string](a, b T, op string) bool {
+	switch op {
+	case "==":
+		return a == b
+	case "!=":
+		return a != b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	}
+	return false
+}
+
+type filterTokenKind int
+
+const (
+	filterEOF filterTokenKind = iota
+	filterIdent
+	filterLiteral
+	filterOp
+)
+
+type filterToken struct {
+	kind  filterTokenKind
+	text  string
+	pos   int
+	value any
+}
+
+var filterOps = []string{"&&", "||", "==", "!=", "<=", ">=", "=~", "<", ">", "!", "(", ")"}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '\'' || c == '"':
+			var b strings.Builder
+			j := i + 1
+			for ; j < len(expr) && expr[j] != c; j++ {
+				if expr[j] == '\\' && j+1 < len(expr) {
+					j++
+				}
+				b.WriteByte(expr[j])
+			}
+			if j == len(expr) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			tokens = append(tokens, filterToken{kind: filterLiteral, text: expr[i : j+1], pos: i, value: b.String()})
+			i = j + 1
+		case c == '-' || c >= '0' && c <= '9':
+			j := i + 1
+			for j < len(expr) && (expr[j] == '.' || expr[j] >= '0' && expr[j] <= '9' || expr[j] == 'e' || expr[j] == 'E') {
+				j++
+			}
+			n, err := strconv.ParseFloat(expr[i:j], 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid number %q at offset %d", expr[i:j], i)
+			}
+			tokens = append(tokens, filterToken{kind: filterLiteral, text: expr[i:j], pos: i, value: n})
+			i = j
+		case c == '_' || c == '.' || unicode.IsLetter(rune(c)):
+			j := i
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '.' || expr[j] == '-' || unicode.IsLetter(rune(expr[j])) || unicode.IsDigit(rune(expr[j]))) {
+				j++
+			}
+			word := expr[i:j]
+			token := filterToken{kind: filterIdent, text: word, pos: i}
+			switch word {
+			case "true", "false":
+				token = filterToken{kind: filterLiteral, text: word, pos: i, value: word == "true"}
+			case "null":
+				token = filterToken{kind: filterLiteral, text: word, pos: i}
+			}
+			tokens = append(tokens, token)
+			i = j
+		default:
+			var op string
+			for _, candidate := range filterOps {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected %q at offset %d", c, i)
+			}
+			tokens = append(tokens, filterToken{kind: filterOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, filterToken{kind: filterEOF, text: "end of expression", pos: len(expr)}), nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	// fields is set once the expression references a field other than level.
+	fields bool
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	token := p.tokens[p.pos]
+	if token.kind != filterEOF {
+		p.pos++
+	}
+	return token
+}
+
+func (p *filterParser) acceptOp(op string) bool {
+	if token := p.peek(); token.kind == filterOp && token.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr() (filterNode, error) {
+	left, err := p.parseAnd()
+	for err == nil && p.acceptOp("||") {
+		var right filterNode
+		if right, err = p.parseAnd(); err == nil {
+			left = filterOr{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseAnd() (filterNode, error) {
+	left, err := p.parseUnary()
+	for err == nil && p.acceptOp("&&") {
+		var right filterNode
+		if right, err = p.parseUnary(); err == nil {
+			left = filterAnd{left: left, right: right}
+		}
+	}
+	return left, err
+}
+
+func (p *filterParser) parseUnary() (filterNode, error) {
+	if p.acceptOp("!") {
+		node, err := p.parseUnary()
+		return filterNot{node: node}, err
+	}
+	if p.acceptOp("(") {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptOp(")") {
+			return nil, fmt.Errorf("expected ) at offset %d", p.peek().pos)
+		}
+		return node, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	token := p.peek()
+	if token.kind != filterOp || !isComparisonOp(token.text) {
+		if left.path == nil {
+			return nil, fmt.Errorf("expected comparison at offset %d", token.pos)
+		}
+		p.fields = true
+		return filterTruthy{path: left.path}, nil
+	}
+	p.next()
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	if token.text == "=~" {
+		pattern, ok := right.literal.(string)
+		if left.path == nil || right.path != nil || !ok {
+			return nil, fmt.Errorf("=~ at offset %d needs a field and a string pattern", token.pos)
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, err
+		}
+		p.fields = true
+		return filterRegexp{path: left.path, re: re}, nil
+	}
+
+	node, ok, err := levelComparison(token.text, left, right)
+	if err != nil {
+		return nil, fmt.Errorf("%w at offset %d", err, token.pos)
+	}
+	if ok {
+		return node, nil
+	}
+	p.fields = true
+	return filterCompare{op: token.text, left: left, right: right}, nil
+}
+
+func (p *filterParser) parseOperand() (filterOperand, error) {
+	token := p.next()
+	switch token.kind {
+	case filterIdent:
+		path := strings.Split(strings.TrimPrefix(strings.TrimPrefix(token.text, "."), "fields."), ".")
+		for _, key := range path {
+			if key == "" {
+				return filterOperand{}, fmt.Errorf("invalid field %q at offset %d", token.text, token.pos)
+			}
+		}
+		return filterOperand{path: path}, nil
+	case filterLiteral:
+		return filterOperand{literal: token.value}, nil
+	}
+	return filterOperand{}, fmt.Errorf("unexpected %s at offset %d", token.text, token.pos)
+}
+
+func isComparisonOp(op string) bool {
+	switch op {
+	case "==", "!=", "<", "<=", ">", ">=", "=~":
+		return true
+	}
+	return false
+}
+
+// levelComparison compiles level compared with a standard level name, with
+// either operand first, into a severity comparison. The name may be quoted
+// or bare, as in level>=warn. Ordering level against anything else is an
+// error, as it would silently never match.
+func levelComparison(op string, left, right filterOperand) (filterNode, bool, error) {
+	isLevel := func(o filterOperand) bool {
+		return len(o.path) == 1 && o.path[0] == zerolog.LevelFieldName
+	}
+	other := right
+	if !isLevel(left) {
+		if !isLevel(right) {
+			return nil, false, nil
+		}
+		other, op = left, mirrorOp(op)
+	}
+	name, ok := other.literal.(string)
+	if len(other.path) == 1 {
+		name, ok = other.path[0], true
+	}
+	if !ok || !IsValidLevel(name) {
+		if op == "==" || op == "!=" {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("level %s needs a level name, one of %s", op, strings.Join(validLevels, ", "))
+	}
+	level, err := parseLevel(name)
+	if err != nil {
+		return nil, false, err
+	}
+	return filterLevel{op: op, level: level}, true, nil
+}
+
+// mirrorOp returns the operator for swapped operands.
+func mirrorOp(op string) string {
+	switch op {
+	case "<":
+		return ">"
+	case "<=":
+		return ">="
+	case ">":
+		return "<"
+	case ">=":
+		return "<="
+	}
+	return op
+}
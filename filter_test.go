@@ -0,0 +1,88 @@
+package sugarzero_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestFilterMatch(t *testing.T) {
+	entry := []byte(`{"level":"WARN","message":"payment declined","tenant":"acme","http":{"status":502},"retry":true,"attempts":0}`)
+
+	tests := []struct {
+		expr string
+		want bool
+	}{
+		{expr: "level>=warn && fields.tenant=='acme'", want: true},
+		{expr: "level>warn", want: false},
+		{expr: "'info' < level", want: true},
+		{expr: "level=='WARN'", want: true},
+		{expr: "http.status >= 500 && http.status < 600", want: true},
+		{expr: `message =~ "^payment"`, want: true},
+		{expr: "tenant == 'other' || !(retry)", want: false},
+		{expr: "retry && !attempts", want: true},
+		{expr: "missing != 'x'", want: true},
+		{expr: "missing == null", want: true},
+		{expr: "http == 'x'", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			filter, err := sugarzero.ParseFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseFilter failed: %v", err)
+			}
+			if got := filter.Match(zerolog.WarnLevel, entry); got != tt.want {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			if got := filter.Match(zerolog.NoLevel, entry); got != tt.want {
+				t.Fatalf("expected %v reading the level from the entry, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestFilterInvalid(t *testing.T) {
+	for _, expr := range []string{"", "level >=", "(level == 'warn'", "'a' == 'b' &&", "tenant =~ '('", "tenant =~ other", "a & b", "'unterminated"} {
+		if _, err := sugarzero.ParseFilter(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestFilterRejectsUnknownLevels(t *testing.T) {
+	for _, expr := range []string{"level>=bogus", "'bogus' < level", "level <= 'critical'", "level > 3"} {
+		if _, err := sugarzero.ParseFilter(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+	// Equality with other names compares the level field as a string
+	if _, err := sugarzero.ParseFilter("level == 'critical'"); err != nil {
+		t.Fatalf("ParseFilter failed: %v", err)
+	}
+}
+
+func TestFilterSink(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	audit := &recordingSink{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithSinks(sugarzero.FilterSink(sugarzero.MustParseFilter("category == 'audit'"), audit)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "request served")
+	sugarzero.Info(sugarzero.WithCategory(ctx, "audit"), "role granted")
+
+	if len(audit.entries) != 1 {
+		t.Fatalf("expected only the audit entry, got %q", audit.entries)
+	}
+}
@@ -0,0 +1,279 @@
+package sugarzero
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sort"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// NewFluentWriter creates a writer that ships entries to Fluent Bit or Fluentd
+// using the forward protocol (msgpack over TCP) with the given tag. target is
+// "tcp://host:port" or "unix:///path/to.sock". When requireAck is true, every
+// entry carries a chunk id and is only considered delivered once the server
+// acknowledges it (require_ack_response in Fluentd terms); unacknowledged
+// entries are retried after reconnecting. Events are timestamped with the
+// entry's time field, or with the send time when it has none.
+//
+// The returned writer shares the queueing, reconnect, and drop accounting of
+// NetworkWriter.
+func NewFluentWriter(target, tag string, requireAck bool, queueSize int) (*NetworkWriter, error) {
+	network, address, err := parseNetworkTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if network == "udp" || network == "udp4" || network == "udp6" || network == "unixgram" {
+		return nil, fmt.Errorf("sugarzero: the forward protocol requires a stream transport, got %q", network)
+	}
+
+	send := func(conn net.Conn, entry []byte) error {
+		return sendForward(conn, tag, entry, requireAck)
+	}
+	return newNetworkWriter(network, address, queueSize, send, nil), nil
+}
+
+func sendForward(conn net.Conn, tag string, entry []byte, requireAck bool) error {
+	decoder := json.NewDecoder(bytes.NewReader(entry))
+	decoder.UseNumber()
+	var record map[string]any
+	if err := decoder.Decode(&record); err != nil {
+		return fmt.Errorf("sugarzero: forward protocol expects a JSON entry: %w", err)
+	}
+	// Entries may be sent long after they were logged, e.g. after a reconnect
+	at := parseEntryTime(record[zerolog.TimestampFieldName])
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	var chunk string
+	if requireAck {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return err
+		}
+		chunk = base64.StdEncoding.EncodeToString(id)
+	}
+
+	// Message mode: [tag, time, record, option]
+	var msg []byte
+	if requireAck {
+		msg = appendMsgpackArrayHeader(msg, 4)
+	} else {
+		msg = appendMsgpackArrayHeader(msg, 3)
+	}
+	msg = appendMsgpackString(msg, tag)
+	msg = appendMsgpackEventTime(msg, at)
+	msg = appendMsgpackValue(msg, record)
+	if requireAck {
+		msg = appendMsgpackValue(msg, map[string]any{"chunk": chunk})
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+	if !requireAck {
+		return nil
+	}
+
+	ack, err := readForwardAck(bufio.NewReader(conn))
+	if err != nil {
+		return fmt.Errorf("sugarzero: read forward ack: %w", err)
+	}
+	if ack != chunk {
+		return fmt.Errorf("sugarzero: forward ack mismatch: got %q, want %q", ack, chunk)
+	}
+	return nil
+}
+
+func appendMsgpackValue(b []byte, v any) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0)
+	case bool:
+		if v {
+			return append(b, 0xc3)
+		}
+		return append(b, 0xc2)
+	case string:
+		return appendMsgpackString(b, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return appendMsgpackInt(b, i)
+		}
+		f, _ := v.Float64()
+		return appendMsgpackFloat(b, f)
+	case float64:
+		return appendMsgpackFloat(b, v)
+	case int:
+		return appendMsgpackInt(b, int64(v))
+	case int64:
+		return appendMsgpackInt(b, v)
+	case []any:
+		b = appendMsgpackArrayHeader(b, len(v))
+		for _, item := range v {
+			b = appendMsgpackValue(b, item)
+		}
+		return b
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		b = appendMsgpackMapHeader(b, len(v))
+		for _, key := range keys {
+			b = appendMsgpackString(b, key)
+			b = appendMsgpackValue(b, v[key])
+		}
+		return b
+	default:
+		return appendMsgpackString(b, fmt.Sprint(v))
+	}
+}
+
+func appendMsgpackInt(b []byte, i int64) []byte {
+	switch {
+	case i >= 0 && i < 128:
+		return append(b, byte(i))
+	case i < 0 && i >= -32:
+		return append(b, byte(int8(i)))
+	default:
+		b = append(b, 0xd3)
+		return binary.BigEndian.AppendUint64(b, uint64(i))
+	}
+}
+
+func appendMsgpackFloat(b []byte, f float64) []byte {
+	b = append(b, 0xcb)
+	return binary.BigEndian.AppendUint64(b, math.Float64bits(f))
+}
+
+func appendMsgpackString(b []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		b = append(b, 0xa0|byte(n))
+	case n < 1<<8:
+		b = append(b, 0xd9, byte(n))
+	case n < 1<<16:
+		b = append(b, 0xda)
+		b = binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdb)
+		b = binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+	return append(b, s...)
+}
+
+func appendMsgpackArrayHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x90|byte(n))
+	case n < 1<<16:
+		b = append(b, 0xdc)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdd)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+func appendMsgpackMapHeader(b []byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(b, 0x80|byte(n))
+	case n < 1<<16:
+		b = append(b, 0xde)
+		return binary.BigEndian.AppendUint16(b, uint16(n))
+	default:
+		b = append(b, 0xdf)
+		return binary.BigEndian.AppendUint32(b, uint32(n))
+	}
+}
+
+// appendMsgpackEventTime encodes t as the forward protocol EventTime extension.
+func appendMsgpackEventTime(b []byte, t time.Time) []byte {
+	b = append(b, 0xd7, 0x00)
+	b = binary.BigEndian.AppendUint32(b, uint32(t.Unix()))
+	return binary.BigEndian.AppendUint32(b, uint32(t.Nanosecond()))
+}
+
+// readForwardAck decodes the server response {"ack": chunk}.
+func readForwardAck(r *bufio.Reader) (string, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	var entries int
+	switch {
+	case header&0xf0 == 0x80:
+		entries = int(header & 0x0f)
+	case header == 0xde:
+		var n uint16
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return "", err
+		}
+		entries = int(n)
+	default:
+		return "", fmt.Errorf("unexpected msgpack type 0x%x", header)
+	}
+
+	var ack string
+	for i := 0; i < entries; i++ {
+		key, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		value, err := readMsgpackString(r)
+		if err != nil {
+			return "", err
+		}
+		if key == "ack" {
+			ack = value
+		}
+	}
+	return ack, nil
+}
+
+func readMsgpackString(r *bufio.Reader) (string, error) {
+	header, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+
+	var n int
+	switch {
+	case header&0xe0 == 0xa0:
+		n = int(header & 0x1f)
+	case header == 0xd9:
+		size, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		n = int(size)
+	case header == 0xda:
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return "", err
+		}
+		n = int(size)
+	default:
+		return "", fmt.Errorf("unexpected msgpack type 0x%x", header)
+	}
+
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return "", err
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,134 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestFluentWriterSendsForwardMessageAndWaitsForAck(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		msg := buf[:n]
+		received <- msg
+
+		// The chunk id is a 24 character base64 string at the end of the message.
+		chunk := msg[len(msg)-24:]
+		ack := append([]byte{0x81, 0xa3, 'a', 'c', 'k', 0xb8}, chunk...)
+		_, _ = conn.Write(ack)
+	}()
+
+	writer, err := sugarzero.NewFluentWriter("tcp://"+listener.Addr().String(), "app.orders", true, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := writer.Write([]byte(`{"level":"INFO","message":"order placed","amount":42}` + "\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	var msg []byte
+	select {
+	case msg = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for forward message")
+	}
+
+	if msg[0] != 0x94 {
+		t.Fatalf("expected a 4 element msgpack array, got 0x%x", msg[0])
+	}
+	if !bytes.Contains(msg, []byte("app.orders")) || !bytes.Contains(msg, []byte("order placed")) {
+		t.Fatalf("expected tag and record in message, got %q", msg)
+	}
+
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if stats := writer.Stats(); stats.Sent != 1 || stats.LastError != nil {
+		t.Fatalf("expected acknowledged delivery, got %+v", stats)
+	}
+}
+
+func TestFluentWriterUsesEntryTime(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+
+	received := make(chan []byte, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		buf := make([]byte, 4096)
+		n, err := conn.Read(buf)
+		if err != nil {
+			return
+		}
+		received <- buf[:n]
+	}()
+
+	writer, err := sugarzero.NewFluentWriter("tcp://"+listener.Addr().String(), "app", false, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = writer.Close()
+	})
+
+	logged := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	if _, err := writer.Write([]byte(`{"level":"INFO","time":"` + logged.Format(time.RFC3339) + `","message":"queued"}` + "\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	var msg []byte
+	select {
+	case msg = <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for forward message")
+	}
+
+	// EventTime extension: 0xd7 0x00, then seconds and nanoseconds
+	i := bytes.Index(msg, []byte{0xd7, 0x00})
+	if i < 0 || len(msg) < i+10 {
+		t.Fatalf("expected an EventTime in the message, got %q", msg)
+	}
+	if seconds := binary.BigEndian.Uint32(msg[i+2:]); int64(seconds) != logged.Unix() {
+		t.Fatalf("expected event time %d, got %d", logged.Unix(), seconds)
+	}
+}
+
+func TestNewFluentWriterRejectsDatagramTransport(t *testing.T) {
+	if _, err := sugarzero.NewFluentWriter("udp://127.0.0.1:24224", "app", false, 0); err == nil {
+		t.Fatal("expected error for udp transport")
+	}
+}
@@ -0,0 +1,47 @@
+package sugarzero
+
+import (
+	"fmt"
+	"regexp"
+	"runtime"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// badVerb matches the markers fmt writes for wrong verbs and argument counts,
+// such as "%!d(string=x)", "%!v(MISSING)", and "%!(EXTRA int=1)".
+var badVerb = regexp.MustCompile(`%!\w?\(`)
+
+// vetPrintf lets go vet check calls to the package-level *f functions. Vet
+// treats functions that forward format and args to fmt as printf wrappers,
+// which it cannot infer through the Logger interface they call.
+func vetPrintf(format string, args ...any) {
+	if false {
+		_ = fmt.Sprintf(format, args...)
+	}
+}
+
+// WithFormatValidation reports formatted messages whose verbs do not match
+// their arguments, e.g. Infof(ctx, "%d", "x"), as DiagnosticBadFormat
+// diagnostics naming the call site. The entry is still logged. It is meant
+// for development and tests, where it catches format bugs that go vet cannot
+// see, such as calls through the Logger interface or non-constant formats.
+func WithFormatValidation() Option {
+	return func(o *options) {
+		o.formatValidation = true
+	}
+}
+
+// checkFormat reports msg, formatted from format, if fmt flagged bad verbs in
+// it. skip counts the frames above checkFormat's caller to the call site.
+func checkFormat(format string, msg []byte, skip int) {
+	if len(badVerb.FindAllIndex(msg, -1)) <= len(badVerb.FindAllStringIndex(format, -1)) {
+		return
+	}
+	fields := []any{"format", format, "output", string(msg)}
+	if _, file, line, ok := runtime.Caller(skip + 1); ok {
+		fields = append(fields, zerolog.CallerFieldName, file+":"+strconv.Itoa(line))
+	}
+	diagnose(DiagnosticBadFormat, zerolog.WarnLevel, "log format does not match its arguments", fields...)
+}
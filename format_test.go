@@ -0,0 +1,59 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithFormatValidation(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf, diagnostics bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithFormatValidation(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+
+	sugarzero.Infof(ctx, "%d%% done, literal %%!d(", 50)
+	if diagnostics.Len() != 0 {
+		t.Fatalf("valid format reported: %s", diagnostics.String())
+	}
+
+	format := "processed %d items"
+	sugarzero.Infof(ctx, format, "many")
+
+	if entry := readLogEntry(t, &buf); entry["message"] != "processed %!d(string=many) items" {
+		t.Fatalf("entry must still be logged, got %v", entry)
+	}
+	diagnostic := readLogEntry(t, &diagnostics)
+	if diagnostic["diagnostic"] != sugarzero.DiagnosticBadFormat || diagnostic["format"] != format {
+		t.Fatalf("unexpected diagnostic: %v", diagnostic)
+	}
+	if position, _ := diagnostic["position"].(string); !strings.Contains(position, "format_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+}
+
+func TestFormatValidationDisabledByDefault(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+
+	format := "%s and %s"
+	sugarzero.Infof(ctx, format, "one")
+
+	if diagnostics.Len() != 0 {
+		t.Fatalf("unexpected diagnostic: %s", diagnostics.String())
+	}
+}
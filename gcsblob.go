@@ -0,0 +1,83 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// DefaultGCSEndpoint is the Cloud Storage JSON API endpoint used by
+// GCSBlobStore.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// GCSConfig locates the bucket a GCSBlobStore writes to and how it
+// authenticates.
+type GCSConfig struct {
+	Bucket string
+	// Prefix is prepended to blob keys, e.g. "logs/blobs/".
+	Prefix string
+	// Token returns an OAuth 2.0 access token allowed to create objects, e.g.
+	// from the metadata server or golang.org/x/oauth2.
+	Token func(ctx context.Context) (string, error)
+	// Endpoint replaces DefaultGCSEndpoint, e.g. for an emulator.
+	Endpoint string
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+}
+
+// GCSBlobStore is a BlobStore writing blobs as objects in a Google Cloud
+// Storage bucket through the JSON API, without depending on the Cloud SDK.
+// Put returns the gs:// URL of the object.
+// Example: store, err := NewGCSBlobStore(GCSConfig{Bucket: "logs", Token: tokenSource})
+type GCSBlobStore struct {
+	cfg GCSConfig
+}
+
+// NewGCSBlobStore returns a GCSBlobStore for cfg.
+func NewGCSBlobStore(cfg GCSConfig) (*GCSBlobStore, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("sugarzero: GCS bucket must not be empty")
+	}
+	if cfg.Token == nil {
+		return nil, errors.New("sugarzero: GCS token source must not be nil")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = DefaultGCSEndpoint
+	}
+	return &GCSBlobStore{cfg: cfg}, nil
+}
+
+// Put writes data to the object Prefix+key with a simple media upload.
+func (s *GCSBlobStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	object := s.cfg.Prefix + key
+	endpoint, err := url.Parse(s.cfg.Endpoint)
+	if err != nil {
+		return "", fmt.Errorf("sugarzero: invalid GCS endpoint: %w", err)
+	}
+	target := endpoint.JoinPath("upload/storage/v1/b", s.cfg.Bucket, "o")
+	target.RawQuery = url.Values{"uploadType": {"media"}, "name": {object}}.Encode()
+
+	token, err := s.cfg.Token(ctx)
+	if err != nil {
+		return "", fmt.Errorf("sugarzero: failed to get GCS token: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("sugarzero: failed to create GCS request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	if err := sendBlobRequest(s.cfg.Client, req); err != nil {
+		return "", fmt.Errorf("sugarzero: failed to put GCS object %q: %w", object, err)
+	}
+	return s.Locate(key), nil
+}
+
+// Locate returns the gs:// URL Put returns for key.
+func (s *GCSBlobStore) Locate(key string) string {
+	return "gs://" + s.cfg.Bucket + "/" + s.cfg.Prefix + key
+}
@@ -0,0 +1,66 @@
+package sugarzero_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestGCSBlobStoreUploadsObjects(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, `{"name":"blobs/abc"}`)
+	}))
+	defer server.Close()
+
+	store, err := sugarzero.NewGCSBlobStore(sugarzero.GCSConfig{
+		Bucket:   "logs",
+		Prefix:   "blobs/",
+		Token:    func(context.Context) (string, error) { return "token", nil },
+		Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewGCSBlobStore failed: %v", err)
+	}
+
+	ref, err := store.Put(context.Background(), "abc", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if ref != "gs://logs/blobs/abc" {
+		t.Fatalf("unexpected reference %q", ref)
+	}
+	if got.Method != http.MethodPost || got.URL.Path != "/upload/storage/v1/b/logs/o" || string(body) != "payload" {
+		t.Fatalf("unexpected request %s %s %q", got.Method, got.URL.Path, body)
+	}
+	if query := got.URL.Query(); query.Get("uploadType") != "media" || query.Get("name") != "blobs/abc" {
+		t.Fatalf("unexpected query %v", query)
+	}
+	if got.Header.Get("Authorization") != "Bearer token" {
+		t.Fatalf("unexpected Authorization %q", got.Header.Get("Authorization"))
+	}
+}
+
+func TestGCSBlobStoreReportsTokenErrors(t *testing.T) {
+	store, err := sugarzero.NewGCSBlobStore(sugarzero.GCSConfig{
+		Bucket: "logs",
+		Token:  func(context.Context) (string, error) { return "", errors.New("metadata server unavailable") },
+	})
+	if err != nil {
+		t.Fatalf("NewGCSBlobStore failed: %v", err)
+	}
+	if _, err := store.Put(context.Background(), "abc", []byte("payload")); err == nil {
+		t.Fatal("expected the token error")
+	}
+	if _, err := sugarzero.NewGCSBlobStore(sugarzero.GCSConfig{Bucket: "logs"}); err == nil {
+		t.Fatal("expected an error for a missing token source")
+	}
+}
@@ -0,0 +1,124 @@
+package sugarzero
+
+import (
+	"bytes"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// WithGoroutineFields adds the fields pushed with PushFields on the logging
+// goroutine to its entries, for legacy code paths that cannot thread a
+// context through. Context fields remain the preferred mechanism; see
+// PushFields for the tradeoffs.
+func WithGoroutineFields() Option {
+	return func(o *options) {
+		o.goroutineFields = true
+	}
+}
+
+// goroutineStash holds the fields pushed per goroutine id.
+var goroutineStash = struct {
+	mu     sync.Mutex
+	fields map[uint64][][]any
+	// active counts goroutines with pushed fields, so logging skips the
+	// goroutine id lookup while no stash is in use.
+	active atomic.Int64
+}{fields: make(map[uint64][][]any)}
+
+// PushFields adds key-value pairs to the current goroutine's stash until the
+// returned function, or PopFields, removes them. Loggers created with
+// WithGoroutineFields add the stashed fields to every entry logged on this
+// goroutine, whatever context is passed.
+//
+// Tradeoffs, compared to context fields:
+//   - Go has no goroutine-local storage; the goroutine is identified by
+//     parsing runtime.Stack, which costs about a microsecond per entry while
+//     any goroutine has fields pushed.
+//   - Fields do not follow work handed to other goroutines, so they are lost
+//     across `go` statements, worker pools, and callbacks.
+//   - Every push must be popped, normally with defer. Nothing clears a stash
+//     when its goroutine exits, so a leaked push stays on every later entry
+//     of a long-lived goroutine, such as a pool worker serving unrelated
+//     requests, and is never freed.
+//
+// Use it to migrate code that cannot yet thread a context, not in new code.
+// Example:
+//
+//	defer sugarzero.PushFields("job_id", job.ID)()
+func PushFields(keyvals ...any) (pop func()) {
+	id := goroutineID()
+	goroutineStash.mu.Lock()
+	stack := goroutineStash.fields[id]
+	if len(stack) == 0 {
+		goroutineStash.active.Add(1)
+	}
+	goroutineStash.fields[id] = append(stack, append([]any(nil), keyvals...))
+	goroutineStash.mu.Unlock()
+
+	var popped atomic.Bool
+	return func() {
+		if popped.CompareAndSwap(false, true) {
+			popFields(id)
+		}
+	}
+}
+
+// PopFields removes the fields pushed last on the current goroutine.
+func PopFields() {
+	popFields(goroutineID())
+}
+
+func popFields(id uint64) {
+	goroutineStash.mu.Lock()
+	defer goroutineStash.mu.Unlock()
+	stack := goroutineStash.fields[id]
+	if len(stack) == 0 {
+		return
+	}
+	if len(stack) == 1 {
+		delete(goroutineStash.fields, id)
+		goroutineStash.active.Add(-1)
+		return
+	}
+	goroutineStash.fields[id] = stack[:len(stack)-1]
+}
+
+// GoroutineFields returns the fields stashed on the current goroutine,
+// oldest push first.
+func GoroutineFields() []any {
+	if goroutineStash.active.Load() == 0 {
+		return nil
+	}
+	id := goroutineID()
+	goroutineStash.mu.Lock()
+	defer goroutineStash.mu.Unlock()
+	var flat []any
+	for _, fields := range goroutineStash.fields[id] {
+		flat = append(flat, fields...)
+	}
+	return flat
+}
+
+func resetGoroutineStash() {
+	goroutineStash.mu.Lock()
+	defer goroutineStash.mu.Unlock()
+	goroutineStash.fields = make(map[uint64][][]any)
+	goroutineStash.active.Store(0)
+}
+
+var goroutinePrefix = []byte("goroutine ")
+
+// goroutineID parses the current goroutine's id from its stack header,
+// "goroutine 123 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, goroutinePrefix)
+	if i := bytes.IndexByte(header, ' '); i > 0 {
+		header = header[:i]
+	}
+	id, _ := strconv.ParseUint(string(header), 10, 64)
+	return id
+}
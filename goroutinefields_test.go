@@ -0,0 +1,75 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestGoroutineFieldsAreAddedOnThePushingGoroutine(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithGoroutineFields(),
+		sugarzero.WithKeyRedaction([]string{"token"}, nil))
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	pop := sugarzero.PushFields("job_id", "job-7")
+	sugarzero.PushFields("step", "charge", "token", "secret")
+	legacyCharge := func() {
+		sugarzero.Info(context.Background(), "charging card")
+	}
+	legacyCharge()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sugarzero.Info(ctx, "other goroutine")
+	}()
+	wg.Wait()
+
+	sugarzero.PopFields()
+	sugarzero.Info(ctx, "after pop")
+	pop()
+	pop()
+	sugarzero.Info(ctx, "after both pops")
+
+	entry := readLogEntry(t, &buf, 0)
+	if entry["job_id"] != "job-7" || entry["step"] != "charge" || entry["token"] != "[REDACTED]" {
+		t.Fatalf("expected stashed fields, got %v", entry)
+	}
+	if _, ok := readLogEntry(t, &buf, 1)["job_id"]; ok {
+		t.Fatal("expected fields not to leak to other goroutines")
+	}
+	entry = readLogEntry(t, &buf, 2)
+	if entry["job_id"] != "job-7" || entry["step"] != nil {
+		t.Fatalf("expected only the first push after PopFields, got %v", entry)
+	}
+	if _, ok := readLogEntry(t, &buf, 3)["job_id"]; ok {
+		t.Fatal("expected no fields after both pops")
+	}
+	if fields := sugarzero.GoroutineFields(); len(fields) != 0 {
+		t.Fatalf("expected an empty stash, got %v", fields)
+	}
+}
+
+func TestGoroutineFieldsRequireOptIn(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	defer sugarzero.PushFields("job_id", "job-7")()
+	sugarzero.Info(ctx, "not stashed")
+	if _, ok := readLogEntry(t, buf)["job_id"]; ok {
+		t.Fatal("expected stashed fields to be ignored without WithGoroutineFields")
+	}
+}
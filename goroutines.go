@@ -0,0 +1,111 @@
+package sugarzero
+
+import (
+	"context"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultGoroutineDumpLimit caps the size of goroutine dumps written by
+// DumpGoroutines.
+const DefaultGoroutineDumpLimit = 256 << 10
+
+// GoroutineDumpInterval is the minimum time between two goroutine stack
+// captures. Dumps requested sooner are logged with their "goroutine_count"
+// but without stacks, so a storm of hung requests cannot stall the process.
+const GoroutineDumpInterval = time.Minute
+
+// lastGoroutineDump is the UnixNano time of the last stack capture.
+var lastGoroutineDump atomic.Int64
+
+// DumpGoroutines logs the stacks of all goroutines at level, in the
+// "goroutines" field, together with a "goroutine_count" field. Dumps larger
+// than DefaultGoroutineDumpLimit are truncated, stacks are captured at most
+// once per GoroutineDumpInterval, and nothing is captured when level is
+// disabled.
+func DumpGoroutines(ctx context.Context, level zerolog.Level) {
+	dumpGoroutines(ctx, level, 1, "goroutine dump", DefaultGoroutineDumpLimit)
+}
+
+// DumpGoroutinesWithLimit is DumpGoroutines with a custom size limit in bytes.
+func DumpGoroutinesWithLimit(ctx context.Context, level zerolog.Level, limit int) {
+	dumpGoroutines(ctx, level, 1, "goroutine dump", limit)
+}
+
+// WithWatchdog returns a context whose goroutines are dumped at warn level if
+// stop is not called within timeout, to capture where a hung request is stuck.
+// Example:
+//
+//	ctx, stop := sugarzero.WithWatchdog(ctx, 30*time.Second)
+//	defer stop()
+func WithWatchdog(ctx context.Context, timeout time.Duration) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	timer := time.AfterFunc(timeout, func() {
+		dumpGoroutines(withUnscopedFields(ctx, "watchdog_timeout", timeout.String()), zerolog.WarnLevel, 0,
+			"request exceeded watchdog timeout", DefaultGoroutineDumpLimit)
+	})
+	return ctx, func() {
+		timer.Stop()
+	}
+}
+
+// WatchdogMiddleware returns HTTP middleware that dumps goroutines when a
+// request takes longer than timeout. The request keeps running. Like every
+// dump, stacks are captured at most once per GoroutineDumpInterval.
+func WatchdogMiddleware(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, stop := WithWatchdog(r.Context(), timeout)
+			defer stop()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// dumpGoroutines implements DumpGoroutines, positioning the entry skip frames
+// above its caller.
+func dumpGoroutines(ctx context.Context, level zerolog.Level, skip int, message string, limit int) {
+	if limit <= 0 {
+		limit = DefaultGoroutineDumpLimit
+	}
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		if !levelEnabled(resolved, logger, level) {
+			return
+		}
+		fields := []any{"goroutine_count", runtime.NumGoroutine()}
+		if allowGoroutineDump(time.Now()) {
+			fields = append(fields, "goroutines", goroutineStacks(limit))
+		}
+		// Skips this function, withLogger, and dumpGoroutines
+		logAtDepth(withUnscopedFields(resolved, fields...), level, 3+skip, message)
+	})
+}
+
+// allowGoroutineDump reports whether stacks may be captured at now.
+func allowGoroutineDump(now time.Time) bool {
+	last := lastGoroutineDump.Load()
+	if last != 0 && now.UnixNano()-last < int64(GoroutineDumpInterval) {
+		return false
+	}
+	return lastGoroutineDump.CompareAndSwap(last, now.UnixNano())
+}
+
+func resetGoroutineDumps() {
+	lastGoroutineDump.Store(0)
+}
+
+// goroutineStacks returns the stacks of all goroutines, truncated to limit bytes.
+func goroutineStacks(limit int) string {
+	buf := make([]byte, limit+1)
+	n := runtime.Stack(buf, true)
+	if n > limit {
+		return string(buf[:limit]) + "...(truncated)"
+	}
+	return string(buf[:n])
+}
@@ -0,0 +1,104 @@
+package sugarzero_test
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestDumpGoroutinesLogsStacks(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	_, _, line, _ := runtime.Caller(0)
+	sugarzero.DumpGoroutinesWithLimit(ctx, zerolog.WarnLevel, 512)
+
+	entry := readLogEntry(t, testWriter)
+	if entry["level"] != "WARN" || entry["message"] != "goroutine dump" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+	if want := fmt.Sprintf("goroutines_test.go:%d", line+1); !strings.HasSuffix(entry["position"].(string), want) {
+		t.Fatalf("expected position %s, got %v", want, entry["position"])
+	}
+	stacks, _ := entry["goroutines"].(string)
+	if !strings.Contains(stacks, "goroutine ") {
+		t.Fatalf("expected goroutine stacks, got %q", stacks)
+	}
+	if len(stacks) > 512+len("...(truncated)") {
+		t.Fatalf("expected dump to be truncated, got %d bytes", len(stacks))
+	}
+	if count, _ := entry["goroutine_count"].(float64); count < 1 {
+		t.Fatalf("expected goroutine_count, got %v", entry["goroutine_count"])
+	}
+}
+
+func TestWatchdogDumpsOnlyHungRequests(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+	mock := sugarzero.NewMock()
+	ctx := sugarzero.WithLogger(context.Background(), mock)
+
+	_, stop := sugarzero.WithWatchdog(ctx, time.Hour)
+	stop()
+	if len(mock.Entries()) != 0 {
+		t.Fatalf("expected no dump for finished request, got %+v", mock.Entries())
+	}
+
+	_, stop = sugarzero.WithWatchdog(sugarzero.WithField(ctx, "request_id", "req-9"), 10*time.Millisecond)
+	defer stop()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mock.Entries()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	entries := mock.Entries()
+	if len(entries) != 1 {
+		t.Fatalf("expected one watchdog entry, got %+v", entries)
+	}
+	if entries[0].Level != "warn" || entries[0].Message != "request exceeded watchdog timeout" {
+		t.Fatalf("unexpected watchdog entry %+v", entries[0])
+	}
+	if entries[0].Fields["request_id"] != "req-9" || entries[0].Fields["goroutines"] == nil {
+		t.Fatalf("expected request fields and stacks, got %+v", entries[0].Fields)
+	}
+}
+
+func TestDumpGoroutinesCapturesStacksOncePerInterval(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	sugarzero.DumpGoroutines(ctx, zerolog.WarnLevel)
+	sugarzero.DumpGoroutines(ctx, zerolog.WarnLevel)
+
+	first := readLogEntry(t, testWriter, 0)
+	second := readLogEntry(t, testWriter, 1)
+	if first["goroutines"] == nil {
+		t.Fatalf("expected stacks in the first dump, got %v", first)
+	}
+	if _, ok := second["goroutines"]; ok {
+		t.Fatalf("expected the second dump to be rate limited, got %v", second)
+	}
+	if count, _ := second["goroutine_count"].(float64); count < 1 {
+		t.Fatalf("expected goroutine_count in the rate limited dump, got %v", second)
+	}
+}
+
+func TestDumpGoroutinesSkipsDisabledLevels(t *testing.T) {
+	ctx, testWriter := setupTest(t, "warn")
+
+	sugarzero.DumpGoroutines(ctx, zerolog.DebugLevel)
+	if testWriter.Len() != 0 {
+		t.Fatalf("expected no output, got %s", testWriter)
+	}
+
+	// The skipped dump must not use up the interval
+	sugarzero.DumpGoroutines(ctx, zerolog.WarnLevel)
+	if entry := readLogEntry(t, testWriter); entry["goroutines"] == nil {
+		t.Fatalf("expected stacks, got %v", entry)
+	}
+}
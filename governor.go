@@ -0,0 +1,152 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultGovernorWindow is the period over which the governor compares the
+// time spent logging with its budget when no window is configured.
+const DefaultGovernorWindow = time.Second
+
+// GovernorConfig configures the logging CPU governor.
+type GovernorConfig struct {
+	// Budget is the time per second that logging calls may take in total,
+	// summed over all goroutines, e.g. 50ms for 5% of one core.
+	Budget time.Duration
+	// ShedLevel is the level from which entries are never shed. Defaults to
+	// "warn".
+	ShedLevel string
+	// KeepOneIn keeps one in every KeepOneIn entries below ShedLevel while
+	// shedding instead of dropping them all; 0 drops them all.
+	KeepOneIn int
+	// Window is how often the load is measured and the shedding decision
+	// revisited. Defaults to DefaultGovernorWindow.
+	Window time.Duration
+}
+
+// WithGovernor measures the time spent inside logging calls and, once it
+// exceeds cfg.Budget per second over a window, sheds entries below
+// cfg.ShedLevel in the following windows until the load, including the shed
+// entries, fits the budget again. Shedding starts and stops with a DiagnosticLoadShedding
+// diagnostic, so log storms cannot eat into request latency unnoticed.
+// Critical entries (WithSync) are never shed.
+// Example: WithGovernor(GovernorConfig{Budget: 50 * time.Millisecond, KeepOneIn: 100})
+func WithGovernor(cfg GovernorConfig) Option {
+	return func(o *options) {
+		o.governor = &cfg
+	}
+}
+
+type governor struct {
+	budget    time.Duration
+	window    time.Duration
+	shedLevel zerolog.Level
+	keepOneIn int64
+
+	shedding atomic.Bool
+	// Counters of the current window
+	windowStart atomic.Int64
+	spent       atomic.Int64
+	measured    atomic.Int64
+	shed        atomic.Int64
+
+	mu        sync.Mutex
+	shedTotal int64
+	// cost is the average time of a measured call, kept for windows in
+	// which every call was shed.
+	cost time.Duration
+}
+
+func newGovernor(cfg *GovernorConfig) (*governor, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.Budget <= 0 {
+		return nil, errors.New("sugarzero: governor budget must be positive")
+	}
+	if cfg.KeepOneIn < 0 {
+		return nil, errors.New("sugarzero: governor KeepOneIn must not be negative")
+	}
+	shedLevel := zerolog.WarnLevel
+	if cfg.ShedLevel != "" {
+		level, err := parseLevel(cfg.ShedLevel)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: governor shed level: %w", err)
+		}
+		shedLevel = level
+	}
+	window := cfg.Window
+	if window <= 0 {
+		window = DefaultGovernorWindow
+	}
+	g := &governor{budget: cfg.Budget, window: window, shedLevel: shedLevel, keepOneIn: int64(cfg.KeepOneIn)}
+	g.windowStart.Store(time.Now().UnixNano())
+	return g, nil
+}
+
+// drop reports whether an entry at level, logged at now, must be shed.
+func (g *governor) drop(ctx context.Context, level zerolog.Level, now time.Time) bool {
+	g.roll(now)
+	if !g.shedding.Load() || (level >= g.shedLevel && level != zerolog.NoLevel) || isSync(ctx) {
+		return false
+	}
+	n := g.shed.Add(1)
+	return g.keepOneIn == 0 || n%g.keepOneIn != 1%g.keepOneIn
+}
+
+// track records a logging call that started at start.
+func (g *governor) track(start time.Time) {
+	g.spent.Add(int64(time.Since(start)))
+	g.measured.Add(1)
+}
+
+// roll closes the window once it has elapsed and decides whether the next
+// one sheds. Shed entries are counted at the average cost of measured calls,
+// so shedding only stops once the unshed load would fit the budget.
+func (g *governor) roll(now time.Time) {
+	if now.UnixNano()-g.windowStart.Load() < int64(g.window) {
+		return
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	start := g.windowStart.Load()
+	if now.UnixNano()-start < int64(g.window) {
+		return
+	}
+	g.windowStart.Store(now.UnixNano())
+	spent, measured, shed := g.spent.Swap(0), g.measured.Swap(0), g.shed.Swap(0)
+
+	// Scale to a second, as the window may be shorter or have run long for
+	// lack of calls
+	elapsed := time.Duration(now.UnixNano() - start)
+	if measured > 0 {
+		g.cost = time.Duration(spent / measured)
+	}
+	load := time.Duration(spent) + time.Duration(shed)*g.cost
+	load = time.Duration(float64(load) * float64(time.Second) / float64(elapsed))
+
+	was := g.shedding.Load()
+	g.shedTotal += shed
+	switch over := load > g.budget; {
+	case over && !was:
+		g.shedding.Store(true)
+		diagnose(DiagnosticLoadShedding, zerolog.WarnLevel, "logging exceeded its CPU budget, shedding entries",
+			"budget_ms", durationMillis(g.budget), "load_ms", durationMillis(load), "shed_below", g.shedLevel.String())
+	case !over && was:
+		g.shedding.Store(false)
+		diagnose(DiagnosticLoadShedding, zerolog.InfoLevel, "logging is within its CPU budget again",
+			"budget_ms", durationMillis(g.budget), "load_ms", durationMillis(load), "shed_total", g.shedTotal)
+		g.shedTotal = 0
+	}
+}
+
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,75 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestGovernorShedsLowLevelsOverBudget(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var diag syncBuffer
+	sugarzero.SetDiagnosticsWriter(&diag)
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithGovernor(sugarzero.GovernorConfig{Budget: 50 * time.Millisecond, Window: 20 * time.Millisecond}))
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	// A storm spends the whole window logging, far above 50ms per second
+	for start := time.Now(); time.Since(start) < 60*time.Millisecond; {
+		sugarzero.Info(ctx, "storm")
+	}
+	if !strings.Contains(string(diag.Bytes()), "shedding entries") {
+		t.Fatalf("expected a shedding diagnostic, got %q", diag.Bytes())
+	}
+	sugarzero.Info(ctx, "shed info")
+	sugarzero.Warn(ctx, "kept warn")
+	sugarzero.Info(sugarzero.WithSync(ctx), "kept critical")
+	if strings.Contains(buf.String(), "shed info") {
+		t.Fatal("expected info to be shed")
+	}
+	if !strings.Contains(buf.String(), "kept warn") || !strings.Contains(buf.String(), "kept critical") {
+		t.Fatalf("expected warn and critical entries to be kept, got %q", buf.String())
+	}
+
+	// The first call after the storm closes the window holding its tail, and
+	// a quiet window after that ends the shedding
+	time.Sleep(30 * time.Millisecond)
+	sugarzero.Info(ctx, "closing info")
+	time.Sleep(30 * time.Millisecond)
+	sugarzero.Info(ctx, "quiet info")
+	if !strings.Contains(buf.String(), "quiet info") {
+		t.Fatalf("expected shedding to stop after a quiet window, got %q", diag.Bytes())
+	}
+	if !strings.Contains(string(diag.Bytes()), "within its CPU budget again") {
+		t.Fatalf("expected a recovery diagnostic, got %q", diag.Bytes())
+	}
+}
+
+func TestGovernorRejectsInvalidConfig(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	for _, cfg := range []sugarzero.GovernorConfig{
+		{},
+		{Budget: time.Millisecond, ShedLevel: "loud"},
+		{Budget: time.Millisecond, KeepOneIn: -1},
+	} {
+		if _, err := sugarzero.NewWithOptions(context.Background(), "info", sugarzero.WithGovernor(cfg)); err == nil {
+			t.Errorf("expected %+v to be rejected", cfg)
+		}
+	}
+}
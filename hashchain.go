@@ -0,0 +1,157 @@
+package sugarzero
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// genesisHash is the prev_hash of the first entry in a chain.
+var genesisHash = hex.EncodeToString(make([]byte, sha256.Size))
+
+// ErrChainBroken is returned by Verify when entries were modified, removed, or
+// reordered.
+var ErrChainBroken = errors.New("sugarzero: hash chain broken")
+
+// HashChainWriter makes a log stream tamper-evident. Each entry is extended
+// with "prev_hash" (the hash of the previous entry) and "hash", computed as
+// SHA-256 over prev_hash and the original entry bytes. Modifying, removing, or
+// reordering entries breaks the chain, which Verify detects.
+type HashChainWriter struct {
+	mu   sync.Mutex
+	w    io.Writer
+	prev string
+}
+
+// NewHashChainWriter wraps w with hash chaining, starting a new chain.
+func NewHashChainWriter(w io.Writer) *HashChainWriter {
+	return &HashChainWriter{w: w, prev: genesisHash}
+}
+
+// ResumeHashChainWriter wraps w and continues an existing chain whose last hash
+// is head, for example after reopening an audit file.
+func ResumeHashChainWriter(w io.Writer, head string) *HashChainWriter {
+	return &HashChainWriter{w: w, prev: head}
+}
+
+// Write chains a single JSON entry.
+func (h *HashChainWriter) Write(p []byte) (int, error) {
+	entry := bytes.TrimRight(p, "\n")
+	if len(entry) < 2 || entry[len(entry)-1] != '}' {
+		return 0, fmt.Errorf("sugarzero: hash chain expects a JSON object entry")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	hash := chainHash(h.prev, entry)
+	line := make([]byte, 0, len(entry)+160)
+	line = append(line, entry[:len(entry)-1]...)
+	if len(entry) > 2 {
+		line = append(line, ',')
+	}
+	line = fmt.Appendf(line, `"prev_hash":%q,"hash":%q}`+"\n", h.prev, hash)
+
+	if _, err := h.w.Write(line); err != nil {
+		return 0, err
+	}
+	h.prev = hash
+	return len(p), nil
+}
+
+// Head returns the hash of the last written entry. Recording it out of band
+// allows Verify results to be checked for truncation at the end of the stream.
+func (h *HashChainWriter) Head() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.prev
+}
+
+// ChainState summarizes a verified hash chain.
+type ChainState struct {
+	// Entries is the number of verified entries.
+	Entries int
+	// Head is the hash of the last entry, or the genesis hash for an empty chain.
+	Head string
+}
+
+// Verify reads a stream written by HashChainWriter and checks every link of the
+// chain. It returns ErrChainBroken (wrapped with the offending line number) if
+// any entry was altered, removed, or reordered, or if the stream does not
+// start at the genesis entry. Compare ChainState.Head against a previously
+// recorded head to detect truncation at the end of the stream.
+func Verify(r io.Reader) (ChainState, error) {
+	state := ChainState{Head: genesisHash}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxEncryptedRecord)
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		state.Entries++
+
+		entry, prev, hash, ok := splitChainedEntry(line)
+		if !ok {
+			return state, fmt.Errorf("%w: line %d is missing chain fields", ErrChainBroken, state.Entries)
+		}
+		if prev != state.Head {
+			return state, fmt.Errorf("%w: line %d does not follow the previous entry", ErrChainBroken, state.Entries)
+		}
+		if chainHash(prev, entry) != hash {
+			return state, fmt.Errorf("%w: line %d was modified", ErrChainBroken, state.Entries)
+		}
+		state.Head = hash
+	}
+	return state, scanner.Err()
+}
+
+func chainHash(prev string, entry []byte) string {
+	sum := sha256.New()
+	sum.Write([]byte(prev))
+	sum.Write(entry)
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// splitChainedEntry recovers the original entry and chain fields from a line
+// produced by HashChainWriter.
+func splitChainedEntry(line []byte) (entry []byte, prev, hash string, ok bool) {
+	const (
+		hexLen     = sha256.Size * 2
+		prevPrefix = `"prev_hash":"`
+		hashPrefix = `","hash":"`
+	)
+	suffixLen := len(prevPrefix) + hexLen + len(hashPrefix) + hexLen + len(`"}`)
+	if len(line) < suffixLen+1 {
+		return nil, "", "", false
+	}
+
+	suffix := line[len(line)-suffixLen:]
+	if !bytes.HasPrefix(suffix, []byte(prevPrefix)) {
+		return nil, "", "", false
+	}
+	prev = string(suffix[len(prevPrefix) : len(prevPrefix)+hexLen])
+	rest := suffix[len(prevPrefix)+hexLen:]
+	if !bytes.HasPrefix(rest, []byte(hashPrefix)) || !bytes.HasSuffix(rest, []byte(`"}`)) {
+		return nil, "", "", false
+	}
+	hash = string(rest[len(hashPrefix) : len(hashPrefix)+hexLen])
+
+	body := line[:len(line)-suffixLen]
+	switch {
+	case bytes.HasSuffix(body, []byte(",")):
+		body = body[:len(body)-1]
+	case !bytes.Equal(body, []byte("{")):
+		return nil, "", "", false
+	}
+	entry = make([]byte, 0, len(body)+1)
+	entry = append(entry, body...)
+	entry = append(entry, '}')
+	return entry, prev, hash, true
+}
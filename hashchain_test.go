@@ -0,0 +1,73 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func writeAuditTrail(t *testing.T) (*bytes.Buffer, *sugarzero.HashChainWriter) {
+	t.Helper()
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var sink bytes.Buffer
+	chain := sugarzero.NewHashChainWriter(&sink)
+	ctx, err := sugarzero.New(context.Background(), "info", chain)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "actor", "alice"), "granted admin role")
+	sugarzero.Info(sugarzero.WithField(ctx, "actor", "bob"), "deleted bucket")
+	sugarzero.Info(sugarzero.WithField(ctx, "actor", "carol"), "rotated keys")
+
+	return &sink, chain
+}
+
+func TestVerifyAcceptsIntactChain(t *testing.T) {
+	sink, chain := writeAuditTrail(t)
+
+	entry := readLogEntry(t, sink, 1)
+	if entry["prev_hash"] == "" || entry["hash"] == "" {
+		t.Fatalf("expected chain fields in entry, got %v", entry)
+	}
+
+	state, err := sugarzero.Verify(bytes.NewReader(sink.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected verify error: %v", err)
+	}
+	if state.Entries != 3 {
+		t.Fatalf("expected 3 entries, got %d", state.Entries)
+	}
+	if state.Head != chain.Head() {
+		t.Fatalf("expected head %s, got %s", chain.Head(), state.Head)
+	}
+}
+
+func TestVerifyDetectsTamperingAndRemoval(t *testing.T) {
+	sink, _ := writeAuditTrail(t)
+	lines := strings.SplitAfter(sink.String(), "\n")
+
+	tampered := strings.Replace(sink.String(), "bob", "eve", 1)
+	if _, err := sugarzero.Verify(strings.NewReader(tampered)); !errors.Is(err, sugarzero.ErrChainBroken) {
+		t.Fatalf("expected ErrChainBroken for modified entry, got %v", err)
+	}
+
+	removed := lines[0] + lines[2]
+	if _, err := sugarzero.Verify(strings.NewReader(removed)); !errors.Is(err, sugarzero.ErrChainBroken) {
+		t.Fatalf("expected ErrChainBroken for removed entry, got %v", err)
+	}
+
+	headless := lines[1] + lines[2]
+	if _, err := sugarzero.Verify(strings.NewReader(headless)); !errors.Is(err, sugarzero.ErrChainBroken) {
+		t.Fatalf("expected ErrChainBroken for truncated start, got %v", err)
+	}
+}
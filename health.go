@@ -0,0 +1,138 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// SinkStatus describes the state of one writer in the logging pipeline.
+type SinkStatus struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Queued    int    `json:"queued"`
+	Capacity  int    `json:"capacity,omitempty"`
+	Dropped   uint64 `json:"dropped"`
+	LastError string `json:"last_error,omitempty"`
+	// Breaker is the state of the sink's CircuitBreaker, if it has one.
+	Breaker string `json:"breaker,omitempty"`
+}
+
+// HealthReporter is implemented by writers that can report their own status.
+// Writers passed to NewWithOptions that implement it are included in Health.
+type HealthReporter interface {
+	Health() SinkStatus
+}
+
+// HealthStatus describes the logging pipeline as a whole.
+type HealthStatus struct {
+	// Healthy is true when every reporting sink is healthy.
+	Healthy        bool              `json:"healthy"`
+	Level          string            `json:"level"`
+	CategoryLevels map[string]string `json:"category_levels,omitempty"`
+	Sinks          []SinkStatus      `json:"sinks"`
+}
+
+// Health reports the current level and the status of every configured writer
+// implementing HealthReporter, such as AsyncWriter, NetworkWriter, and
+// CircuitBreaker.
+func (l *ZeroLogger) Health() HealthStatus {
+	status := HealthStatus{
+		Healthy: true,
+		Level:   l.GetLogLevel(),
+		Sinks:   []SinkStatus{},
+	}
+	if len(l.categoryLevels) > 0 {
+		status.CategoryLevels = make(map[string]string, len(l.categoryLevels))
+		for category, level := range l.categoryLevels {
+			status.CategoryLevels[category] = level.String()
+		}
+	}
+	for _, w := range l.sinks {
+		reporter, ok := healthReporter(w)
+		if !ok {
+			continue
+		}
+		sink := reporter.Health()
+		status.Healthy = status.Healthy && sink.Healthy
+		status.Sinks = append(status.Sinks, sink)
+	}
+	return status
+}
+
+// Health implements HealthReporter. The writer is unhealthy while it cannot
+// reach the collector.
+func (w *NetworkWriter) Health() SinkStatus {
+	stats := w.Stats()
+	return SinkStatus{
+		Name:      w.network + "://" + w.address,
+		Healthy:   stats.Connected || stats.LastError == nil,
+		Queued:    stats.Queued + stats.Spooled,
+		Capacity:  cap(w.queue),
+		Dropped:   stats.Dropped,
+		LastError: errorString(stats.LastError),
+	}
+}
+
+// Health implements HealthReporter. The writer is unhealthy while its queue
+// is full.
+func (w *AsyncWriter) Health() SinkStatus {
+	stats := w.Stats()
+	return SinkStatus{
+		Name:      fmt.Sprintf("async(%T)", w.next),
+		Healthy:   stats.Queued < stats.Capacity,
+		Queued:    stats.Queued,
+		Capacity:  stats.Capacity,
+		Dropped:   stats.Dropped(),
+		LastError: errorString(stats.LastError),
+	}
+}
+
+// HealthHandler serves the logging pipeline status of the logger in ctx as
+// JSON, with status 503 when a sink is unhealthy. Mount it next to the level
+// handler, e.g. mux.Handle("/log-health", sugarzero.HealthHandler(ctx)).
+func HealthHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		status := healthFromContext(ctx)
+		code := http.StatusOK
+		if !status.Healthy {
+			code = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		_ = json.NewEncoder(w).Encode(status)
+	})
+}
+
+// healthFromContext reports on the logger in ctx. Loggers other than
+// ZeroLogger only report their level.
+func healthFromContext(ctx context.Context) HealthStatus {
+	status := HealthStatus{Healthy: true, Sinks: []SinkStatus{}}
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			status = zl.Health()
+			return
+		}
+		status.Level = logger.GetLogLevel()
+	})
+	return status
+}
+
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+var (
+	_ HealthReporter = (*NetworkWriter)(nil)
+	_ HealthReporter = (*AsyncWriter)(nil)
+)
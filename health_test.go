@@ -0,0 +1,76 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// fakeSink is a writer reporting a fixed health status.
+type fakeSink struct {
+	bytes.Buffer
+	status sugarzero.SinkStatus
+}
+
+func (f *fakeSink) Health() sugarzero.SinkStatus {
+	return f.status
+}
+
+func TestHealthHandlerReportsSinks(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	sink := &fakeSink{status: sugarzero.SinkStatus{Name: "collector", Healthy: true}}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "warn",
+		sugarzero.WithWriters(sink, &bytes.Buffer{}),
+		sugarzero.WithAsync(8, sugarzero.DefaultDegradationPolicy()),
+		sugarzero.WithCategoryLevel(sugarzero.CategorySecurity, "info"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	handler := sugarzero.HealthHandler(ctx)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-health", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	var status sugarzero.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if !status.Healthy || status.Level != "warn" || status.CategoryLevels["security"] != "info" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if len(status.Sinks) != 2 || status.Sinks[0].Capacity != 8 || status.Sinks[1].Name != "collector" {
+		t.Fatalf("expected async and collector sinks, got %+v", status.Sinks)
+	}
+
+	sink.status = sugarzero.SinkStatus{Name: "collector", LastError: "connection refused"}
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-health", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for unhealthy sink, got %d", rec.Code)
+	}
+}
+
+func TestNetworkWriterHealthReflectsConnectivity(t *testing.T) {
+	w, err := sugarzero.NewNetworkWriter("tcp://127.0.0.1:1", 4)
+	if err != nil {
+		t.Fatalf("NewNetworkWriter failed: %v", err)
+	}
+	defer w.Close()
+
+	if health := w.Health(); !health.Healthy || health.Name != "tcp://127.0.0.1:1" {
+		t.Fatalf("expected untried writer to be healthy, got %+v", health)
+	}
+}
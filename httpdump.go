@@ -0,0 +1,131 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultDumpBodyLimit is the maximum number of body bytes captured by
+// DumpRequest and DumpResponse.
+const DefaultDumpBodyLimit = 4 << 10
+
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders lists canonical header names whose values are never logged.
+var sensitiveHeaders = map[string]struct{}{
+	"Authorization":       {},
+	"Proxy-Authorization": {},
+	"Cookie":              {},
+	"Set-Cookie":          {},
+	"X-Api-Key":           {},
+}
+
+// DumpRequest logs the method, URL, headers, and a size-capped body of req at
+// debug level. Credentials in Authorization and Cookie headers and URL
+// passwords are redacted. The request body is restored so req can still be sent or handled afterwards.
+// Nothing is read when debug level is disabled.
+func DumpRequest(ctx context.Context, req *http.Request) {
+	if req == nil {
+		return
+	}
+
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		if !levelEnabled(resolved, logger, zerolog.DebugLevel) {
+			return
+		}
+		body, restored := captureBody(req.Body, DefaultDumpBodyLimit)
+		req.Body = restored
+
+		resolved = withUnscopedFields(resolved,
+			"http_method", req.Method,
+			"http_url", req.URL.Redacted(),
+			"http_headers", redactHeaders(req.Header),
+			"http_body", body,
+		)
+		logger.Debug(resolved, "http request")
+	})
+}
+
+// DumpResponse logs the status, headers, and a size-capped body of resp at
+// debug level. Set-Cookie headers are redacted and the body is restored so
+// resp can still be read afterwards. Nothing is read when debug level is
+// disabled.
+func DumpResponse(ctx context.Context, resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		if !levelEnabled(resolved, logger, zerolog.DebugLevel) {
+			return
+		}
+		body, restored := captureBody(resp.Body, DefaultDumpBodyLimit)
+		resp.Body = restored
+
+		resolved = withUnscopedFields(resolved,
+			"http_status", resp.StatusCode,
+			"http_headers", redactHeaders(resp.Header),
+			"http_body", body,
+		)
+		logger.Debug(resolved, "http response")
+	})
+}
+
+func redactHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		if _, ok := sensitiveHeaders[http.CanonicalHeaderKey(name)]; ok {
+			redacted[name] = redactedValue
+			continue
+		}
+		redacted[name] = strings.Join(values, ", ")
+	}
+	return redacted
+}
+
+// captureBody reads up to limit bytes from body and returns them as a string
+// together with a reader that replays the full, unconsumed body.
+func captureBody(body io.ReadCloser, limit int) (string, io.ReadCloser) {
+	if body == nil || body == http.NoBody {
+		return "", body
+	}
+
+	buf := make([]byte, limit+1)
+	n, err := io.ReadFull(body, buf)
+	buf = buf[:n]
+
+	var rest io.Reader = body
+	if err != nil {
+		// The whole body fit in the buffer (or the read failed): nothing left to replay.
+		rest = eofReader{err: err}
+	}
+	restored := readCloser{Reader: io.MultiReader(bytes.NewReader(buf), rest), Closer: body}
+
+	if n > limit {
+		return string(buf[:limit]) + "...(truncated)", restored
+	}
+	return string(buf), restored
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// eofReader replays a read error, mapping the expected short-read errors to io.EOF.
+type eofReader struct{ err error }
+
+func (r eofReader) Read([]byte) (int, error) {
+	if r.err == io.EOF || r.err == io.ErrUnexpectedEOF {
+		return 0, io.EOF
+	}
+	return 0, r.err
+}
@@ -0,0 +1,75 @@
+package sugarzero
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// traceOrderReported is set once a misordered middleware chain was reported.
+var traceOrderReported atomic.Bool
+
+// HTTPMiddleware chains tracing middleware, such as
+// otelhttp.NewMiddleware(name), with sugarzero middleware in the order that
+// keeps trace fields on every entry: tracing runs first, so its span is in
+// the request context before SummaryMiddleware or WatchdogMiddleware capture
+// it, and TraceHeadersMiddleware then fills in trace_id and span_id from the
+// request headers for requests the tracing middleware does not record, or
+// when tracing is nil. middlewares run in the given order, the first
+// outermost.
+//
+// gRPC servers need no equivalent: otelgrpc installs as a stats handler,
+// which runs before every interceptor.
+// Example:
+//
+//	handler = sugarzero.HTTPMiddleware(otelhttp.NewMiddleware("api"),
+//		sugarzero.SummaryMiddleware(sugarzero.SummaryOnly),
+//		sugarzero.WatchdogMiddleware(30*time.Second),
+//	)(handler)
+func HTTPMiddleware(tracing func(http.Handler) http.Handler, middlewares ...func(http.Handler) http.Handler) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		for i := len(middlewares) - 1; i >= 0; i-- {
+			if middlewares[i] != nil {
+				next = middlewares[i](next)
+			}
+		}
+		next = TraceHeadersMiddleware()(next)
+		if tracing != nil {
+			next = tracing(next)
+		}
+		return next
+	}
+}
+
+// TraceOrderCheckMiddleware reports, once per process, a request that carries
+// trace headers but has neither a span nor trace IDs in its context when it
+// reaches this middleware. That happens when sugarzero middleware is
+// installed outside the tracing middleware, so its entries lack trace_id.
+// Install it where the sugarzero middleware is, or use HTTPMiddleware.
+func TraceOrderCheckMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !traceOrderReported.Load() && missingTraceContext(r) && traceOrderReported.CompareAndSwap(false, true) {
+				diagnose(DiagnosticTraceOrder, zerolog.WarnLevel,
+					"request has trace headers but no trace context; install sugarzero middleware inside the tracing middleware",
+					"path", r.URL.Path)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// missingTraceContext reports whether r carries trace headers that did not
+// make it into its context.
+func missingTraceContext(r *http.Request) bool {
+	if hasTraceContext(r.Context()) {
+		return false
+	}
+	for _, parse := range DefaultTraceHeaderParsers {
+		if _, _, ok := parse(r.Header); ok {
+			return true
+		}
+	}
+	return false
+}
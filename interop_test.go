@@ -0,0 +1,87 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// tracingMiddleware stands in for otelhttp: it continues the incoming W3C
+// trace in a recording server span.
+func tracingMiddleware(t *testing.T) func(http.Handler) http.Handler {
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	tracer := tp.Tracer("test-server")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+			ctx, span := tracer.Start(ctx, r.URL.Path)
+			defer span.End()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func serveTraced(ctx context.Context, handler func(http.Handler) http.Handler) {
+	h := handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sugarzero.Info(r.Context(), "handled")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil).WithContext(ctx)
+	req.Header.Set("traceparent", testTraceparent)
+	h.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+func TestHTTPMiddlewareKeepsTraceFields(t *testing.T) {
+	for name, tracing := range map[string]func(http.Handler) http.Handler{
+		"otel":    tracingMiddleware(t),
+		"headers": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx, buf := setupTest(t, "info")
+
+			serveTraced(ctx, sugarzero.HTTPMiddleware(tracing, sugarzero.SummaryMiddleware(sugarzero.SummaryAlongside)))
+
+			for i := range 2 {
+				if entry := readLogEntry(t, buf, i); entry["trace_id"] != "4bf92f3577b34da6a3ce929d0e0e4736" {
+					t.Fatalf("expected the incoming trace on entry %d, got %v", i, entry)
+				}
+			}
+		})
+	}
+}
+
+func TestTraceOrderCheckMiddleware(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+
+	check := sugarzero.TraceOrderCheckMiddleware()
+	tracing := tracingMiddleware(t)
+
+	// Correct order: the check runs inside the tracing middleware.
+	serveTraced(ctx, func(next http.Handler) http.Handler { return tracing(check(next)) })
+	if diagnostics.Len() != 0 {
+		t.Fatalf("expected no diagnostic for a correct chain, got %s", diagnostics.String())
+	}
+
+	// Wrong order, reported once.
+	serveTraced(ctx, func(next http.Handler) http.Handler { return check(tracing(next)) })
+	serveTraced(ctx, func(next http.Handler) http.Handler { return check(tracing(next)) })
+	entry := readLogEntry(t, &diagnostics)
+	if entry[sugarzero.DiagnosticKindFieldName] != sugarzero.DiagnosticTraceOrder || entry["path"] != "/orders" {
+		t.Fatalf("unexpected diagnostic %v", entry)
+	}
+	if lines := bytes.Count(diagnostics.Bytes(), []byte("\n")); lines != 1 {
+		t.Fatalf("expected one diagnostic, got %d", lines)
+	}
+}
@@ -0,0 +1,94 @@
+package sugarzero
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// WithIsolatedWriters gives each configured writer and sink its own queue and
+// goroutine instead of writing to them one after another, so a slow or
+// failing writer never delays or breaks delivery to the others. A queueSize
+// <= 0 uses DefaultAsyncQueueSize. The policy always drops rather than blocks
+// when a writer's queue is full. Sync drains every queue before syncing the
+// writers.
+// Example: WithWriters(os.Stdout, networkWriter), WithIsolatedWriters(1024, DefaultDegradationPolicy())
+func WithIsolatedWriters(queueSize int, policy DegradationPolicy) Option {
+	return func(o *options) {
+		o.isolation = &isolation{queueSize: queueSize, policy: policy}
+	}
+}
+
+type isolation struct {
+	queueSize int
+	policy    DegradationPolicy
+}
+
+// IsolatedWriter fans entries out to several writers, each behind its own
+// AsyncWriter, so the writers are written concurrently and independently.
+// Write errors are kept per writer and reported by Stats rather than
+// returned to the caller.
+type IsolatedWriter struct {
+	writers []*AsyncWriter
+}
+
+// NewIsolatedWriter starts a queue per writer. A queueSize <= 0 uses
+// DefaultAsyncQueueSize; policy.NeverBlock is always set.
+func NewIsolatedWriter(queueSize int, policy DegradationPolicy, writers ...io.Writer) *IsolatedWriter {
+	policy.NeverBlock = true
+	w := &IsolatedWriter{writers: make([]*AsyncWriter, 0, len(writers))}
+	for _, next := range writers {
+		w.writers = append(w.writers, NewAsyncWriter(next, queueSize, policy))
+	}
+	return w
+}
+
+// Write queues an unleveled entry for every writer.
+func (w *IsolatedWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel queues a copy of p for every writer. It only fails once the
+// writer is closed.
+func (w *IsolatedWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	for _, aw := range w.writers {
+		if _, err := aw.WriteLevel(level, p); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Stats returns the queue and delivery counters of each writer, in the order
+// the writers were given.
+func (w *IsolatedWriter) Stats() []AsyncWriterStats {
+	stats := make([]AsyncWriterStats, len(w.writers))
+	for i, aw := range w.writers {
+		stats[i] = aw.Stats()
+	}
+	return stats
+}
+
+// Flush blocks until every writer has written the entries queued so far.
+func (w *IsolatedWriter) Flush() error {
+	for _, aw := range w.writers {
+		if err := aw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops every queue after writing its entries and closes the writers
+// that implement io.Closer.
+func (w *IsolatedWriter) Close() error {
+	var errs []error
+	for i, aw := range w.writers {
+		if err := aw.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("sugarzero: failed to close writer %d: %w", i, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,92 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestIsolatedWritersKeepHealthyWriterFlowing(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	stuck := &gatedWriter{release: make(chan struct{})}
+	t.Cleanup(func() {
+		close(stuck.release)
+	})
+	var healthy syncBuffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(stuck, failingWriter{}, &healthy),
+		sugarzero.WithIsolatedWriters(64, sugarzero.DegradationPolicy{}))
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			sugarzero.Error(ctx, "payment failed")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("logging blocked on the stuck writer")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for bytes.Count(healthy.Bytes(), []byte("payment failed")) < 20 {
+		if time.Now().After(deadline) {
+			t.Fatalf("healthy writer got %q", healthy.Bytes())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestIsolatedWriterStats(t *testing.T) {
+	stuck := &gatedWriter{release: make(chan struct{})}
+	var healthy syncBuffer
+	w := sugarzero.NewIsolatedWriter(2, sugarzero.DegradationPolicy{}, stuck, failingWriter{}, &healthy)
+
+	for i := 0; i < 5; i++ {
+		if _, err := w.WriteLevel(zerolog.WarnLevel, []byte("entry\n")); err != nil {
+			t.Fatalf("WriteLevel failed: %v", err)
+		}
+		// Let the healthy writer keep up so only the stuck one overflows
+		for w.Stats()[2].Written < uint64(i+1) {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	close(stuck.release)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	stats := w.Stats()
+	if len(stats) != 3 {
+		t.Fatalf("expected stats for 3 writers, got %d", len(stats))
+	}
+	if stats[0].DroppedWarn == 0 {
+		t.Fatalf("expected the stuck writer to drop warn entries, got %+v", stats[0])
+	}
+	if stats[1].LastError == nil || stats[1].Written != 0 {
+		t.Fatalf("expected the failing writer to record its error, got %+v", stats[1])
+	}
+	if stats[2].Written != 5 || stats[2].Dropped() != 0 {
+		t.Fatalf("expected the healthy writer to get every entry, got %+v", stats[2])
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if _, err := w.Write([]byte("late\n")); err == nil {
+		t.Fatal("expected write after Close to fail")
+	}
+}
@@ -0,0 +1,157 @@
+package sugarzero
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultJournaldSocket is the native protocol socket of systemd-journald.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// journalPriorities maps sugarzero level names to syslog priorities.
+var journalPriorities = map[string]string{
+	"trace": "7",
+	"debug": "7",
+	"info":  "6",
+	"warn":  "4",
+	"error": "3",
+	"fatal": "2",
+	"panic": "0",
+}
+
+// journalReservedFields are the journal fields set by JournaldWriter itself.
+var journalReservedFields = map[string]struct{}{
+	"MESSAGE":           {},
+	"PRIORITY":          {},
+	"SYSLOG_IDENTIFIER": {},
+}
+
+// JournaldWriter sends entries to systemd-journald using its native protocol.
+// The message becomes MESSAGE, the level becomes PRIORITY, and every other
+// field is sent as an uppercase journal field (user_id becomes USER_ID), so
+// entries can be filtered with journalctl USER_ID=42. Fields that would map to
+// MESSAGE, PRIORITY, or SYSLOG_IDENTIFIER get a FIELD_ prefix, so they cannot
+// override the values set by the writer.
+type JournaldWriter struct {
+	mu         sync.Mutex
+	conn       *net.UnixConn
+	identifier string
+}
+
+// NewJournaldWriter connects to the journald socket at socketPath (empty uses
+// DefaultJournaldSocket) and tags entries with SYSLOG_IDENTIFIER=identifier.
+func NewJournaldWriter(socketPath, identifier string) (*JournaldWriter, error) {
+	if socketPath == "" {
+		socketPath = DefaultJournaldSocket
+	}
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: socketPath, Net: "unixgram"})
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: connect to journald: %w", err)
+	}
+	return &JournaldWriter{conn: conn, identifier: identifier}, nil
+}
+
+// Write converts a JSON entry to journal fields and sends it as one datagram.
+func (j *JournaldWriter) Write(p []byte) (int, error) {
+	var entry map[string]any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if err := dec.Decode(&entry); err != nil {
+		return 0, fmt.Errorf("sugarzero: journald expects a JSON entry: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if message, ok := entry["message"]; ok {
+		appendJournalField(&buf, "MESSAGE", journalValue(message))
+		delete(entry, "message")
+	}
+	if level, ok := entry["level"].(string); ok {
+		if priority, ok := journalPriorities[strings.ToLower(level)]; ok {
+			appendJournalField(&buf, "PRIORITY", priority)
+		}
+		delete(entry, "level")
+	}
+	if j.identifier != "" {
+		appendJournalField(&buf, "SYSLOG_IDENTIFIER", j.identifier)
+	}
+
+	keys := make([]string, 0, len(entry))
+	for key := range entry {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := journalFieldName(key)
+		if name == "" {
+			continue
+		}
+		if _, reserved := journalReservedFields[name]; reserved {
+			name = "FIELD_" + name
+		}
+		appendJournalField(&buf, name, journalValue(entry[key]))
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err := j.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the connection to journald.
+func (j *JournaldWriter) Close() error {
+	return j.conn.Close()
+}
+
+// appendJournalField encodes a field using the simple KEY=value form, or the
+// length-prefixed binary form when value contains a newline.
+func appendJournalField(buf *bytes.Buffer, name, value string) {
+	buf.WriteString(name)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalFieldName converts key to a valid journal field name: uppercase ASCII
+// letters, digits, and underscores, not starting with an underscore or digit.
+func journalFieldName(key string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(key) {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	name := strings.TrimLeft(b.String(), "_0123456789")
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name
+}
+
+func journalValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,64 @@
+package sugarzero_test
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestJournaldWriterMapsFields(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "journal.sock")
+	server, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: socket, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = server.Close()
+	})
+
+	writer, err := sugarzero.NewJournaldWriter(socket, "billing")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		_ = writer.Close()
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.New(context.Background(), "info", writer)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx, "user_id", 42, "request-id", "r-1", "order_id", int64(9007199254740993),
+		"priority", "high", "syslog_identifier", "spoofed")
+	sugarzero.Warn(ctx, "line one\nline two")
+
+	_ = server.SetReadDeadline(time.Now().Add(2 * time.Second))
+	buf := make([]byte, 64<<10)
+	n, err := server.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected read error: %v", err)
+	}
+	datagram := string(buf[:n])
+
+	for _, want := range []string{"\nPRIORITY=4\n", "\nSYSLOG_IDENTIFIER=billing\n", "USER_ID=42\n", "REQUEST_ID=r-1\n",
+		"ORDER_ID=9007199254740993\n", "FIELD_PRIORITY=high\n", "FIELD_SYSLOG_IDENTIFIER=spoofed\n"} {
+		if !strings.Contains(datagram, want) {
+			t.Fatalf("expected datagram to contain %q, got %q", want, datagram)
+		}
+	}
+	if strings.Contains(datagram, "\nPRIORITY=high") || strings.Contains(datagram, "\nSYSLOG_IDENTIFIER=spoofed") {
+		t.Fatalf("expected reserved fields to be kept, got %q", datagram)
+	}
+	if !strings.Contains(datagram, "MESSAGE\n") || !strings.Contains(datagram, "line one\nline two") {
+		t.Fatalf("expected multi-line message in binary form, got %q", datagram)
+	}
+}
@@ -0,0 +1,151 @@
+package sugarzero
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxKeyLength is the key length, in bytes, above which keys are
+// truncated when KeySanitization.MaxLength is zero.
+const DefaultMaxKeyLength = 128
+
+// KeySanitization defines how field keys are cleaned before encoding, so
+// keys built from untrusted input, such as header names, cannot break
+// downstream JSON parsers or dashboards. Invalid UTF-8, control characters,
+// and invisible formatting characters (zero-width spaces, bidi overrides)
+// are always removed.
+type KeySanitization struct {
+	// MaxLength truncates longer keys, in bytes, at a character boundary.
+	// Zero uses DefaultMaxKeyLength; a negative value disables truncation.
+	MaxLength int
+	// ASCII replaces every non-ASCII character with Replacement.
+	ASCII bool
+	// Replacement is written for invalid or replaced characters, and as the
+	// key when nothing else is left. Defaults to "_".
+	Replacement string
+	// OnSanitize, when non-nil, is called with every key that was changed and
+	// its replacement, so hostile input can be surfaced in tests or metrics.
+	OnSanitize func(original, sanitized string)
+}
+
+// WithKeySanitization applies policy to the keys of every field attached
+// with WithFields, before reserved keys, redaction, and coercion are
+// applied.
+// Example: NewWithOptions(ctx, "info", WithKeySanitization(KeySanitization{MaxLength: 64}))
+func WithKeySanitization(policy KeySanitization) Option {
+	return func(o *options) {
+		o.keySanitization = &policy
+	}
+}
+
+// Sanitize returns key cleaned according to the policy. Fullwidth forms of
+// ASCII characters, often used to spoof keys, are folded to ASCII.
+func (p KeySanitization) Sanitize(key string) string {
+	if p.clean(key) {
+		return key
+	}
+	replacement := p.Replacement
+	if replacement == "" {
+		replacement = "_"
+	}
+
+	var b strings.Builder
+	b.Grow(len(key))
+	for i := 0; i < len(key); {
+		r, size := utf8.DecodeRuneInString(key[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			b.WriteString(replacement)
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		case r >= 0xFF01 && r <= 0xFF5E:
+			// Fullwidth ASCII variants, e.g. "ｌｅｖｅｌ"
+			r -= 0xFEE0
+		}
+		if p.ASCII && r >= utf8.RuneSelf {
+			b.WriteString(replacement)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	sanitized := truncateKey(b.String(), p.maxLength())
+	if sanitized == "" {
+		sanitized = replacement
+	}
+	return sanitized
+}
+
+func (p KeySanitization) maxLength() int {
+	if p.MaxLength == 0 {
+		return DefaultMaxKeyLength
+	}
+	return p.MaxLength
+}
+
+// clean reports whether key needs no change, so common keys are not copied.
+func (p KeySanitization) clean(key string) bool {
+	if key == "" || (p.maxLength() > 0 && len(key) > p.maxLength()) {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < 0x20 || c == 0x7f || c >= utf8.RuneSelf {
+			// Non-ASCII keys take the slow path
+			return false
+		}
+	}
+	return true
+}
+
+// truncateKey cuts key to at most max bytes without splitting a character.
+func truncateKey(key string, max int) string {
+	if max < 0 || len(key) <= max {
+		return key
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(key[cut]) {
+		cut--
+	}
+	return key[:cut]
+}
+
+// sanitizeKeys returns flat with its keys sanitized according to policy.
+// flat is returned unchanged when there is nothing to do, and copied
+// otherwise.
+func sanitizeKeys(policy *KeySanitization, flat []any) []any {
+	if policy == nil {
+		return flat
+	}
+	var out []any
+	for i := 0; i+1 < len(flat); i += 2 {
+		key, ok := flat[i].(string)
+		if !ok {
+			if out != nil {
+				out = append(out, flat[i], flat[i+1])
+			}
+			continue
+		}
+		sanitized := policy.Sanitize(key)
+		if sanitized == key {
+			if out != nil {
+				out = append(out, flat[i], flat[i+1])
+			}
+			continue
+		}
+
+		if out == nil {
+			out = make([]any, i, len(flat))
+			copy(out, flat[:i])
+		}
+		if policy.OnSanitize != nil {
+			policy.OnSanitize(key, sanitized)
+		}
+		out = append(out, sanitized, flat[i+1])
+	}
+	if out == nil {
+		return flat
+	}
+	return out
+}
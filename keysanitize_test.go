@@ -0,0 +1,117 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestKeySanitization(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	sanitized := map[string]string{}
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKeySanitization(sugarzero.KeySanitization{
+			MaxLength: 16,
+			OnSanitize: func(original, key string) {
+				sanitized[original] = key
+			},
+		}),
+		sugarzero.WithReservedKeyPolicy(sugarzero.ReservedKeysRename, nil),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx,
+		"x-forwarded\x00-for\n", "10.0.0.1",
+		"ｕｓｅｒ", "u-1",
+		"\xffagent", "curl",
+		"le\u200bvel", "spoofed",
+		"an-overly-long-header-name", "v",
+		"\x1b[31m", "ansi",
+		"request_id", "r-1",
+	)
+	sugarzero.Info(ctx, "request")
+
+	if !json.Valid(bytes.TrimSpace(buf.Bytes())) {
+		t.Fatalf("expected valid JSON, got %q", buf.String())
+	}
+	entry := readLogEntry(t, &buf)
+	for key, want := range map[string]any{
+		"x-forwarded-for":  "10.0.0.1",
+		"user":             "u-1",
+		"_agent":           "curl",
+		"fields.level":     "spoofed",
+		"an-overly-long-h": "v",
+		"[31m":             "ansi",
+		"request_id":       "r-1",
+	} {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, entry)
+		}
+	}
+	if entry["level"] != "INFO" {
+		t.Fatalf("expected a sanitized key to be caught by the reserved key policy, got %v", entry)
+	}
+	if len(sanitized) != 6 || sanitized["ｕｓｅｒ"] != "user" {
+		t.Fatalf("expected OnSanitize for every changed key, got %v", sanitized)
+	}
+}
+
+func TestKeySanitizationASCII(t *testing.T) {
+	policy := sugarzero.KeySanitization{ASCII: true, Replacement: "?", MaxLength: -1}
+
+	if got := policy.Sanitize("naïve_ключ"); got != "na?ve_????" {
+		t.Fatalf("expected non-ASCII characters to be replaced, got %q", got)
+	}
+	if got := policy.Sanitize("\u200b\x00"); got != "?" {
+		t.Fatalf("expected an emptied key to become the replacement, got %q", got)
+	}
+	if long := strings.Repeat("k", 500); policy.Sanitize(long) != long {
+		t.Fatal("expected a negative MaxLength to disable truncation")
+	}
+	if got := (sugarzero.KeySanitization{}).Sanitize("日本語のキー"); got != "日本語のキー" {
+		t.Fatalf("expected non-ASCII keys to be kept by default, got %q", got)
+	}
+	if got := (sugarzero.KeySanitization{MaxLength: 4}).Sanitize("日本"); got != "日" {
+		t.Fatalf("expected truncation at a character boundary, got %q", got)
+	}
+}
+
+func FuzzKeySanitization(f *testing.F) {
+	for _, seed := range []string{"user_id", "", "\x00\xff", "ｌｅｖｅｌ", "a\u202eb", strings.Repeat("é", 100)} {
+		f.Add(seed, false)
+	}
+	f.Fuzz(func(t *testing.T, key string, ascii bool) {
+		policy := sugarzero.KeySanitization{MaxLength: 32, ASCII: ascii}
+		got := policy.Sanitize(key)
+
+		if got == "" || len(got) > 32 || !utf8.ValidString(got) {
+			t.Fatalf("Sanitize(%q) = %q: expected a non-empty valid key of at most 32 bytes", key, got)
+		}
+		for _, r := range got {
+			if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || (ascii && r >= utf8.RuneSelf) {
+				t.Fatalf("Sanitize(%q) = %q: unexpected character %U", key, got, r)
+			}
+		}
+		if again := policy.Sanitize(got); again != got {
+			t.Fatalf("expected Sanitize to be idempotent: %q -> %q -> %q", key, got, again)
+		}
+		encoded, err := json.Marshal(map[string]string{got: "v"})
+		if err != nil || !json.Valid(encoded) {
+			t.Fatalf("expected %q to encode as a JSON key: %v", got, err)
+		}
+	})
+}
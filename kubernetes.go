@@ -0,0 +1,140 @@
+package sugarzero
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultDownwardAPIDir is the mount path of the downward API volume in the
+// Kubernetes documentation examples.
+const DefaultDownwardAPIDir = "/etc/podinfo"
+
+// serviceAccountNamespaceFile holds the pod namespace in pods that mount a
+// service account token.
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// maxDownwardAPILine is the longest line read from a downward API file. The
+// annotations of a pod may total 256KiB, which quoting can double.
+const maxDownwardAPILine = 1 << 20
+
+// kubernetesEnv maps field names to the environment variables checked for
+// them, in order. The variables are set through the downward API:
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+var kubernetesEnv = []struct {
+	field string
+	vars  []string
+}{
+	{field: "k8s.pod.name", vars: []string{"POD_NAME"}},
+	{field: "k8s.namespace.name", vars: []string{"POD_NAMESPACE", "NAMESPACE"}},
+	{field: "k8s.node.name", vars: []string{"NODE_NAME"}},
+}
+
+// WithKubernetesMetadata writes the pod name, namespace, and node name on
+// every entry as the k8s.pod.name, k8s.namespace.name, and k8s.node.name
+// fields, using the OpenTelemetry names. They are read from the POD_NAME,
+// POD_NAMESPACE (or NAMESPACE), and NODE_NAME environment variables set
+// through the downward API. Inside Kubernetes, the pod name falls back to
+// HOSTNAME, which the kubelet sets to it; the namespace falls back to the
+// service account namespace file. Missing values are omitted, so the option
+// is harmless outside Kubernetes.
+// Example: NewWithOptions(ctx, "info", WithKubernetesMetadata())
+func WithKubernetesMetadata() Option {
+	return func(o *options) {
+		for _, env := range kubernetesEnv {
+			value := firstEnv(env.vars...)
+			if value == "" && env.field == "k8s.pod.name" && inKubernetes() {
+				value = os.Getenv("HOSTNAME")
+			}
+			if value != "" {
+				o.fields = append(o.fields, env.field, value)
+			} else if env.field == "k8s.namespace.name" {
+				if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+					o.fields = append(o.fields, env.field, strings.TrimSpace(string(data)))
+				}
+			}
+		}
+	}
+}
+
+// WithKubernetesLabels writes the pod labels from the "labels" file of the
+// downward API volume mounted at dir on every entry, as k8s.pod.label.<key>
+// fields. Annotations are only written for the given keys, as
+// k8s.pod.annotation.<key> fields, since pods often carry large ones such as
+// kubectl.kubernetes.io/last-applied-configuration. Missing files are
+// ignored; unreadable ones fail logger construction.
+// Example: NewWithOptions(ctx, "info", WithKubernetesLabels(DefaultDownwardAPIDir, "deployment.kubernetes.io/revision"))
+func WithKubernetesLabels(dir string, annotations ...string) Option {
+	return func(o *options) {
+		labels, err := readDownwardAPIFile(filepath.Join(dir, "labels"), "k8s.pod.label.", nil)
+		if err == nil && len(annotations) > 0 {
+			var fields []any
+			fields, err = readDownwardAPIFile(filepath.Join(dir, "annotations"), "k8s.pod.annotation.", annotations)
+			labels = append(labels, fields...)
+		}
+		if err != nil {
+			o.wrapWriter(func(io.Writer) (io.Writer, error) {
+				return nil, err
+			})
+			return
+		}
+		o.fields = append(o.fields, labels...)
+	}
+}
+
+// readDownwardAPIFile parses the key="value" lines of a downward API file
+// into key-value pairs with prefix prepended to the keys, keeping only keys
+// when it is not nil. A missing file has no pairs.
+func readDownwardAPIFile(path, prefix string, keys []string) ([]any, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: failed to read downward API file: %w", err)
+	}
+	var fields []any
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(nil, maxDownwardAPILine)
+	for scanner.Scan() {
+		key, quoted, ok := strings.Cut(scanner.Text(), "=")
+		if !ok || key == "" || (keys != nil && !slices.Contains(keys, key)) {
+			continue
+		}
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			value = quoted
+		}
+		fields = append(fields, prefix+key, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("sugarzero: failed to parse %s: %w", path, err)
+	}
+	return fields, nil
+}
+
+// inKubernetes reports whether the process runs in a Kubernetes pod, where
+// the kubelet sets KUBERNETES_SERVICE_HOST.
+func inKubernetes() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithKubernetesMetadata(t *testing.T) {
+	t.Setenv("POD_NAME", "api-7d9f8-x2x4k")
+	t.Setenv("POD_NAMESPACE", "payments")
+	t.Setenv("NODE_NAME", "node-3")
+
+	dir := t.TempDir()
+	labels := "app=\"api\"\npod-template-hash=\"7d9f8\"\n"
+	annotations := "kubernetes.io/config.source=\"api\"\nnote=\"line one\\nline two\"\n" +
+		"kubectl.kubernetes.io/last-applied-configuration=\"" + strings.Repeat("x", 100_000) + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "labels"), []byte(labels), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "annotations"), []byte(annotations), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKubernetesMetadata(),
+		sugarzero.WithKubernetesLabels(dir, "kubernetes.io/config.source", "note"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "started")
+
+	entry := readLogEntry(t, &buf)
+	if _, ok := entry["k8s.pod.annotation.kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		t.Fatal("expected annotations outside the allowlist to be omitted")
+	}
+	want := map[string]string{
+		"k8s.pod.name":                                   "api-7d9f8-x2x4k",
+		"k8s.namespace.name":                             "payments",
+		"k8s.node.name":                                  "node-3",
+		"k8s.pod.label.app":                              "api",
+		"k8s.pod.label.pod-template-hash":                "7d9f8",
+		"k8s.pod.annotation.kubernetes.io/config.source": "api",
+		"k8s.pod.annotation.note":                        "line one\nline two",
+	}
+	for key, value := range want {
+		if entry[key] != value {
+			t.Fatalf("expected %s=%q, got %v", key, value, entry[key])
+		}
+	}
+}
+
+func TestWithKubernetesLabelsMissingVolume(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKubernetesLabels(filepath.Join(t.TempDir(), "missing")),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "started")
+
+	if entry := readLogEntry(t, &buf); len(entry) != 4 {
+		t.Fatalf("expected only the standard fields, got %v", entry)
+	}
+}
+
+func TestWithKubernetesMetadataHostnameFallback(t *testing.T) {
+	t.Setenv("POD_NAME", "")
+	t.Setenv("HOSTNAME", "api-7d9f8-x2x4k")
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	for _, inCluster := range []bool{false, true} {
+		host := ""
+		if inCluster {
+			host = "10.96.0.1"
+		}
+		t.Setenv("KUBERNETES_SERVICE_HOST", host)
+
+		sugarzero.Reset()
+		var buf bytes.Buffer
+		ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+			sugarzero.WithWriters(&buf),
+			sugarzero.WithKubernetesMetadata(),
+		)
+		if err != nil {
+			t.Fatalf("Failed to create logger: %v", err)
+		}
+		sugarzero.Info(ctx, "started")
+
+		_, ok := readLogEntry(t, &buf)["k8s.pod.name"]
+		if ok != inCluster {
+			t.Fatalf("expected HOSTNAME as pod name only inside Kubernetes, got %v with KUBERNETES_SERVICE_HOST=%q", ok, host)
+		}
+	}
+}
+
+func TestWithKubernetesLabelsUnreadableFile(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	dir := t.TempDir()
+	line := "app=\"" + strings.Repeat("x", 2<<20) + "\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "labels"), []byte(line), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithKubernetesLabels(dir),
+	)
+	if err == nil {
+		t.Fatal("expected an error for a labels file that cannot be parsed")
+	}
+}
@@ -0,0 +1,36 @@
+package sugarzero
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// validLevels lists the level names accepted by New and SetLogLevel, from
+// most to least verbose.
+var validLevels = []string{"trace", "debug", "info", "warn", "error", "fatal", "panic"}
+
+// ValidLevels returns the level names accepted by New and SetLogLevel, from
+// most to least verbose, e.g. to list them in an admin API.
+func ValidLevels() []string {
+	return slices.Clone(validLevels)
+}
+
+// IsValidLevel reports whether level is accepted by SetLogLevel. Names are
+// case-insensitive. The empty string, which New treats as info, is not a
+// level name.
+func IsValidLevel(level string) bool {
+	return slices.Contains(validLevels, strings.ToLower(level))
+}
+
+func parseLevel(level string) (zerolog.Level, error) {
+	if level == "" {
+		return zerolog.InfoLevel, nil
+	}
+	if !IsValidLevel(level) {
+		return zerolog.InfoLevel, fmt.Errorf("invalid log level %q: must be one of %s", level, strings.Join(validLevels, ", "))
+	}
+	return zerolog.ParseLevel(strings.ToLower(level))
+}
@@ -0,0 +1,31 @@
+package sugarzero
+
+import "context"
+
+// Logger is the logging contract used by the package-level functions. The
+// zerolog-backed ZeroLogger implements it; other implementations (mocks,
+// alternative backends) can be injected with WithLogger.
+type Logger interface {
+	Debug(ctx context.Context, args ...any)
+	Debugf(ctx context.Context, format string, args ...any)
+	Debugln(ctx context.Context, args ...any)
+	Info(ctx context.Context, args ...any)
+	Infof(ctx context.Context, format string, args ...any)
+	Infoln(ctx context.Context, args ...any)
+	Warn(ctx context.Context, args ...any)
+	Warnf(ctx context.Context, format string, args ...any)
+	Warnln(ctx context.Context, args ...any)
+	Error(ctx context.Context, args ...any)
+	Errorf(ctx context.Context, format string, args ...any)
+	Errorln(ctx context.Context, args ...any)
+	Fatal(ctx context.Context, args ...any)
+	Fatalf(ctx context.Context, format string, args ...any)
+	Fatalln(ctx context.Context, args ...any)
+
+	// SetLogLevel changes the minimum level, returning an error for unknown levels.
+	SetLogLevel(level string) error
+	// GetLogLevel returns the current minimum level name.
+	GetLogLevel() string
+}
+
+var _ Logger = (*ZeroLogger)(nil)
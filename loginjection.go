@@ -0,0 +1,128 @@
+package sugarzero
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// LogInjectionFieldName is the key of the flag added to entries whose
+// message or fields contained CR or LF under WithLogInjectionProtection.
+const LogInjectionFieldName = "log_injection"
+
+// crlfEscaper writes CR and LF as the visible escapes MessagesStrict uses.
+var crlfEscaper = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// WithLogInjectionProtection escapes CR and LF as `\r` and `\n` in messages,
+// string and error field values, and the error attached with WithError, so
+// user input can never start a forged entry, and flags every entry that
+// contained them with LogInjectionFieldName set to true. Use it for anything
+// that logs user input; flagged entries can be alerted on with
+// OnEvent(level, MatchField(LogInjectionFieldName, true), ...). Events from
+// Raw get escaped fields but no flag, as their message is written directly.
+// Example: NewWithOptions(ctx, "info", WithLogInjectionProtection())
+func WithLogInjectionProtection() Option {
+	return func(o *options) {
+		o.injectionProtection = true
+	}
+}
+
+func hasCRLF(s string) bool {
+	return strings.ContainsAny(s, "\r\n")
+}
+
+// crlfValue returns the text of v, and whether v is a value whose text is
+// escaped: strings and errors.
+func crlfValue(v any) (string, bool) {
+	switch value := v.(type) {
+	case string:
+		return value, true
+	case error:
+		if !isNilPointer(value) {
+			return value.Error(), true
+		}
+	}
+	return "", false
+}
+
+// escapeFieldValues returns flat with CR and LF escaped in its string and
+// error values, errors becoming strings when escaped. flat is returned
+// unchanged when there is nothing to do, and copied otherwise.
+func escapeFieldValues(flat []any) []any {
+	var out []any
+	for i := 0; i+1 < len(flat); i += 2 {
+		text, ok := crlfValue(flat[i+1])
+		if !ok || !hasCRLF(text) {
+			if out != nil {
+				out = append(out, flat[i], flat[i+1])
+			}
+			continue
+		}
+		if out == nil {
+			out = make([]any, i, len(flat))
+			copy(out, flat[:i])
+		}
+		out = append(out, flat[i], crlfEscaper.Replace(text))
+	}
+	if out == nil {
+		return flat
+	}
+	return out
+}
+
+func fieldsHaveCRLF(flat []any) bool {
+	for i := 1; i < len(flat); i += 2 {
+		if text, ok := crlfValue(flat[i]); ok && hasCRLF(text) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCRLF reports whether the context's fields contain CR or LF, scanning
+// them once per context.
+func (f *contextFields) hasCRLF() bool {
+	switch f.crlf.Load() {
+	case crlfAbsent:
+		return false
+	case crlfPresent:
+		return true
+	}
+	found := fieldsHaveCRLF(f.flat)
+	if found {
+		f.crlf.Store(crlfPresent)
+	} else {
+		f.crlf.Store(crlfAbsent)
+	}
+	return found
+}
+
+// States of contextFields.crlf; zero means not scanned yet.
+const (
+	crlfAbsent uint32 = iota + 1
+	crlfPresent
+)
+
+// injected reports whether user input of the entry logged with ctx, other
+// than its message, contains CR or LF.
+func (l *ZeroLogger) injected(ctx context.Context) bool {
+	if fields := contextFieldsFromContext(ctx); fields != nil && fields.hasCRLF() {
+		return true
+	}
+	if err := errorFromContext(ctx); err != nil && !isNilPointer(err) && hasCRLF(err.Error()) {
+		return true
+	}
+	return l.goroutineFields && fieldsHaveCRLF(GoroutineFields())
+}
+
+// appendError adds err to event, escaped under WithLogInjectionProtection.
+func (l *ZeroLogger) appendError(event *zerolog.Event, err error) {
+	if l.injectionProtection && !isNilPointer(err) {
+		if text := err.Error(); hasCRLF(text) {
+			event.Str(zerolog.ErrorFieldName, crlfEscaper.Replace(text))
+			return
+		}
+	}
+	event.Err(err)
+}
@@ -0,0 +1,77 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func newInjectionLogger(t *testing.T, opts ...sugarzero.Option) (context.Context, *bytes.Buffer) {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		append([]sugarzero.Option{sugarzero.WithWriters(&buf), sugarzero.WithLogInjectionProtection()}, opts...)...)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return ctx, &buf
+}
+
+func TestLogInjectionProtection(t *testing.T) {
+	ctx, buf := newInjectionLogger(t)
+
+	forged := "bob\n" + `{"level":"INFO","message":"login ok","user":"admin"}`
+	sugarzero.Infof(ctx, "login failed for %s", forged)
+	sugarzero.Info(sugarzero.WithFields(ctx, "user_agent", "curl\r\nX-Injected: 1", "attempt", 3), "request")
+	sugarzero.Error(sugarzero.WithError(ctx, errors.New("bad header\nvalue")), "rejected")
+	sugarzero.Info(sugarzero.WithFields(ctx, "user", "alice"), "login ok")
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 4 {
+		t.Fatalf("expected 4 lines, got %d: %s", lines, buf.String())
+	}
+	entry := readLogEntry(t, buf, 0)
+	if entry["message"] != `login failed for bob\n{"level":"INFO","message":"login ok","user":"admin"}` || entry[sugarzero.LogInjectionFieldName] != true {
+		t.Fatalf("expected an escaped and flagged message, got %v", entry)
+	}
+	entry = readLogEntry(t, buf, 1)
+	if entry["user_agent"] != `curl\r\nX-Injected: 1` || entry["attempt"] != float64(3) || entry[sugarzero.LogInjectionFieldName] != true {
+		t.Fatalf("expected an escaped and flagged field, got %v", entry)
+	}
+	entry = readLogEntry(t, buf, 2)
+	if entry["error"] != `bad header\nvalue` || entry[sugarzero.LogInjectionFieldName] != true {
+		t.Fatalf("expected an escaped and flagged error, got %v", entry)
+	}
+	if _, ok := readLogEntry(t, buf, 3)[sugarzero.LogInjectionFieldName]; ok {
+		t.Fatal("expected clean entries not to be flagged")
+	}
+}
+
+func TestLogInjectionProtectionWithStrictMessages(t *testing.T) {
+	ctx, buf := newInjectionLogger(t, sugarzero.WithMessageSafety(sugarzero.MessagesStrict))
+
+	var flagged []string
+	stop, err := sugarzero.OnEvent(ctx, "info", sugarzero.MatchField(sugarzero.LogInjectionFieldName, true), func(e sugarzero.LogEvent) {
+		flagged = append(flagged, e.Message)
+	})
+	if err != nil {
+		t.Fatalf("OnEvent failed: %v", err)
+	}
+	defer stop()
+
+	sugarzero.Warn(ctx, "line\r\nbreak")
+	entry := readLogEntry(t, buf, 0)
+	if entry["message"] != `line\r\nbreak` || entry[sugarzero.LogInjectionFieldName] != true {
+		t.Fatalf("expected the entry to be flagged although MessagesStrict escaped it, got %v", entry)
+	}
+	if len(flagged) != 1 {
+		t.Fatalf("expected flagged entries to be observable with OnEvent, got %v", flagged)
+	}
+}
@@ -0,0 +1,60 @@
+package sugarzero
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// LogRefFieldName holds the correlation id shared by an error entry and
+	// the error response sent to the client.
+	LogRefFieldName = "log_ref"
+	// LogRefHeader carries the log_ref of an error response.
+	LogRefHeader = "X-Log-Ref"
+)
+
+// NewLogRef returns a random 16-character correlation id.
+func NewLogRef() string {
+	var ref [8]byte
+	_, _ = rand.Read(ref[:])
+	return hex.EncodeToString(ref[:])
+}
+
+// ErrorRef logs err at error level with a new log_ref field and returns the
+// ref, so it can be put in the error returned to the client. For gRPC:
+//
+//	ref := sugarzero.ErrorRef(ctx, err, "lookup failed")
+//	return nil, status.Errorf(codes.Internal, "internal error (log_ref %s)", ref)
+//
+// gRPC-gateway passes the status message on in the HTTP error body.
+func ErrorRef(ctx context.Context, err error, args ...any) string {
+	ref := NewLogRef()
+	withLogger(WithError(withUnscopedFields(ctx, LogRefFieldName, ref), err), func(logger Logger, resolved context.Context) {
+		logger.Error(resolved, args...)
+	})
+	return ref
+}
+
+// HTTPError logs err at error level with a new log_ref and replies with
+// status, the ref in the X-Log-Ref header, and a JSON body such as
+// {"error":"Internal Server Error","log_ref":"9f86d081884c7d65"}. The body
+// names only the status, never err, so internal details are not leaked.
+// Example: sugarzero.HTTPError(w, r, err, http.StatusInternalServerError)
+func HTTPError(w http.ResponseWriter, r *http.Request, err error, status int) {
+	ref := NewLogRef()
+	ctx := withUnscopedFields(r.Context(), LogRefFieldName, ref, "status", status)
+	withLogger(WithError(ctx, err), func(logger Logger, resolved context.Context) {
+		logger.Error(resolved, "request failed")
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set(LogRefHeader, ref)
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"error":         http.StatusText(status),
+		LogRefFieldName: ref,
+	})
+}
@@ -0,0 +1,57 @@
+package sugarzero_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestHTTPErrorCorrelatesLogAndResponse(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	req := httptest.NewRequest(http.MethodGet, "/orders/42", nil).WithContext(ctx)
+	rec := httptest.NewRecorder()
+	sugarzero.HTTPError(rec, req, errors.New("pq: connection refused"), http.StatusServiceUnavailable)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503, got %d", rec.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid body %q: %v", rec.Body.String(), err)
+	}
+	if strings.Contains(rec.Body.String(), "pq:") || body["error"] != "Service Unavailable" {
+		t.Fatalf("unexpected body: %v", body)
+	}
+	ref := body["log_ref"]
+	if len(ref) != 16 || rec.Header().Get(sugarzero.LogRefHeader) != ref {
+		t.Fatalf("expected matching 16-character ref in body and header, got %q and %q", ref, rec.Header().Get(sugarzero.LogRefHeader))
+	}
+
+	entry := readLogEntry(t, buf)
+	if entry["log_ref"] != ref || entry["error"] != "pq: connection refused" || entry["status"] != float64(503) {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+}
+
+func TestErrorRef(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	ref := sugarzero.ErrorRef(ctx, errors.New("lookup failed"), "get order")
+
+	entry := readLogEntry(t, buf)
+	if entry["log_ref"] != ref || entry["message"] != "get order" || entry["level"] != "ERROR" {
+		t.Fatalf("unexpected entry for ref %q: %v", ref, entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "logref_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+	if other := sugarzero.NewLogRef(); other == ref {
+		t.Fatalf("expected unique refs, got %q twice", ref)
+	}
+}
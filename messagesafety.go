@@ -0,0 +1,107 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+// MessageSafety selects how messages with invalid UTF-8 or control
+// characters are rewritten before encoding.
+type MessageSafety int
+
+const (
+	// MessagesAsIs relies on the JSON encoder, which escapes control
+	// characters but lets consumers decode raw NULs and newlines back into
+	// the message. This is the default.
+	MessagesAsIs MessageSafety = iota
+	// MessagesNormalize replaces invalid UTF-8 with U+FFFD and escapes NUL
+	// and other control characters as visible text, e.g. `\x00`, keeping
+	// tabs and newlines.
+	MessagesNormalize
+	// MessagesStrict also escapes newlines, carriage returns, and the Unicode
+	// line and paragraph separators, e.g. `\n`, so a message can never span
+	// lines and forge entries in line-oriented output such as the console
+	// format or downstream tools that decode the JSON.
+	MessagesStrict
+)
+
+// WithMessageSafety rewrites every message logged through the Logger methods
+// according to mode. Events from Raw are written as given.
+// Example: NewWithOptions(ctx, "info", WithMessageSafety(MessagesStrict))
+func WithMessageSafety(mode MessageSafety) Option {
+	return func(o *options) {
+		o.messageSafety = mode
+	}
+}
+
+// Sanitize returns msg rewritten according to the mode. msg is returned
+// unchanged when it is already safe.
+func (m MessageSafety) Sanitize(msg string) string {
+	if m == MessagesAsIs || m.safe(msg) {
+		return msg
+	}
+
+	var b strings.Builder
+	b.Grow(len(msg) + 8)
+	for i := 0; i < len(msg); {
+		r, size := utf8.DecodeRuneInString(msg[i:])
+		i += size
+		switch {
+		case r == utf8.RuneError && size <= 1:
+			b.WriteRune(utf8.RuneError)
+		case r == '\t' || ((r == '\n' || r == '\r') && m != MessagesStrict):
+			b.WriteRune(r)
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		case r >= 0x80 && r < 0xa0:
+			// C1 controls, e.g. NEL, which some tools treat as a newline
+			fmt.Fprintf(&b, `\u%04x`, r)
+		case (r == '\u2028' || r == '\u2029') && m == MessagesStrict:
+			fmt.Fprintf(&b, `\u%04x`, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// safe reports whether msg needs no rewriting, scanning bytes so printable
+// ASCII messages are not decoded rune by rune.
+func (m MessageSafety) safe(msg string) bool {
+	for i := 0; i < len(msg); i++ {
+		c := msg[i]
+		switch {
+		case c >= 0x20 && c < 0x7f, c == '\t':
+		case c == '\n' || c == '\r':
+			if m == MessagesStrict {
+				return false
+			}
+		default:
+			// Control characters, and non-ASCII text which may hold invalid
+			// UTF-8, C1 controls, or line separators
+			return false
+		}
+	}
+	return true
+}
+
+// message returns msg made safe according to the logger's MessageSafety and
+// log injection protection, flagging event if the entry contained CR or LF.
+func (l *ZeroLogger) message(ctx context.Context, event *zerolog.Event, msg string) string {
+	if !l.injectionProtection {
+		return l.messageSafety.Sanitize(msg)
+	}
+	// Check before sanitizing, as MessagesStrict escapes line breaks too
+	if hasCRLF(msg) || l.injected(ctx) {
+		event.Bool(LogInjectionFieldName, true)
+	}
+	return crlfEscaper.Replace(l.messageSafety.Sanitize(msg))
+}
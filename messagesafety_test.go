@@ -0,0 +1,83 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestMessageSafetyStrict(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithMessageSafety(sugarzero.MessagesStrict),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	forged := "login failed for bob\r\n" + `{"level":"INFO","message":"login ok for admin"}`
+	sugarzero.Warnf(ctx, "%s", forged)
+	sugarzero.Info(ctx, "nul\x00byte and \xffbad utf8")
+	sugarzero.Info(ctx, "plain", "args")
+
+	if lines := strings.Count(buf.String(), "\n"); lines != 3 {
+		t.Fatalf("expected 3 lines, got %d: %s", lines, buf.String())
+	}
+	if got := readLogEntry(t, &buf, 0)["message"]; got != `login failed for bob\r\n{"level":"INFO","message":"login ok for admin"}` {
+		t.Fatalf("expected newlines to be escaped, got %q", got)
+	}
+	if got := readLogEntry(t, &buf, 1)["message"]; got != `nul\x00byte and `+"\ufffd"+`bad utf8` {
+		t.Fatalf("expected NUL and invalid UTF-8 to be replaced, got %q", got)
+	}
+	if got := readLogEntry(t, &buf, 2)["message"]; got != "plainargs" {
+		t.Fatalf("expected safe messages to be unchanged, got %q", got)
+	}
+}
+
+func TestMessageSafetyModes(t *testing.T) {
+	msg := "line 1\nline 2\tend\x07\u0085\u2028"
+
+	if got := sugarzero.MessagesAsIs.Sanitize(msg); got != msg {
+		t.Fatalf("expected MessagesAsIs to keep the message, got %q", got)
+	}
+	if got := sugarzero.MessagesNormalize.Sanitize(msg); got != "line 1\nline 2\tend\\x07\\u0085\u2028" {
+		t.Fatalf("expected MessagesNormalize to keep newlines and tabs, got %q", got)
+	}
+	if got := sugarzero.MessagesStrict.Sanitize(msg); got != `line 1\nline 2`+"\t"+`end\x07\u0085\u2028` {
+		t.Fatalf("expected MessagesStrict to escape line breaks, got %q", got)
+	}
+	if got := sugarzero.MessagesStrict.Sanitize("日本語 ok"); got != "日本語 ok" {
+		t.Fatalf("expected valid non-ASCII text to be kept, got %q", got)
+	}
+}
+
+func FuzzMessageSafetyStrict(f *testing.F) {
+	for _, seed := range []string{"hello", "a\nb\r\n", "\x00\xff\xfe", "\u2028\u0085", "日本語"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, msg string) {
+		got := sugarzero.MessagesStrict.Sanitize(msg)
+		if !utf8.ValidString(got) {
+			t.Fatalf("Sanitize(%q) = %q: invalid UTF-8", msg, got)
+		}
+		for _, r := range got {
+			if (unicode.IsControl(r) && r != '\t') || r == '\u2028' || r == '\u2029' {
+				t.Fatalf("Sanitize(%q) = %q: unexpected character %U", msg, got, r)
+			}
+		}
+		if again := sugarzero.MessagesStrict.Sanitize(got); again != got {
+			t.Fatalf("expected Sanitize to be idempotent: %q -> %q -> %q", msg, got, again)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package sugarzero
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// logMetricsName is the counter exposed by LogMetrics.
+const logMetricsName = "sugarzero_log_entries_total"
+
+// LogMetrics counts written entries per level and remembers, for warn and
+// above, the trace of the most recent entry as an exemplar. Served over HTTP
+// it exposes the counters in the OpenMetrics text format with exemplars, so a
+// spike in the error counter links straight to a trace that caused it.
+type LogMetrics struct {
+	counts [zerolog.PanicLevel - zerolog.TraceLevel + 1]atomic.Uint64
+
+	mu        sync.Mutex
+	exemplars map[zerolog.Level]Exemplar
+}
+
+// Exemplar links a counter increment to the trace of the entry behind it.
+type Exemplar struct {
+	TraceID   string
+	SpanID    string
+	Timestamp time.Time
+}
+
+// NewLogMetrics returns empty log metrics.
+func NewLogMetrics() *LogMetrics {
+	return &LogMetrics{exemplars: make(map[zerolog.Level]Exemplar)}
+}
+
+// WithLogMetrics counts the logger's entries in m.
+// Example:
+//
+//	metrics := sugarzero.NewLogMetrics()
+//	ctx, _ := sugarzero.NewWithOptions(ctx, "info", sugarzero.WithLogMetrics(metrics))
+//	mux.Handle("/metrics/logs", metrics)
+func WithLogMetrics(m *LogMetrics) Option {
+	return func(o *options) {
+		o.wrapWriter(func(next io.Writer) (io.Writer, error) {
+			return &metricsWriter{next: next, metrics: m}, nil
+		})
+	}
+}
+
+// Count returns the number of entries written at level.
+func (m *LogMetrics) Count(level string) uint64 {
+	lvl, err := parseLevel(level)
+	if err != nil || !countedLevel(lvl) {
+		return 0
+	}
+	return m.counts[lvl-zerolog.TraceLevel].Load()
+}
+
+// Exemplar returns the exemplar recorded for level, if any.
+func (m *LogMetrics) Exemplar(level string) (Exemplar, bool) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return Exemplar{}, false
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	exemplar, ok := m.exemplars[lvl]
+	return exemplar, ok
+}
+
+// ServeHTTP writes the counters in the OpenMetrics text format.
+func (m *LogMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/openmetrics-text; version=1.0.0; charset=utf-8")
+	_, _ = w.Write(m.appendOpenMetrics(nil))
+}
+
+func (m *LogMetrics) appendOpenMetrics(dst []byte) []byte {
+	dst = fmt.Appendf(dst, "# TYPE %s counter\n", trimTotal(logMetricsName))
+	dst = fmt.Appendf(dst, "# HELP %s Log entries written, by level.\n", trimTotal(logMetricsName))
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for lvl := zerolog.TraceLevel; lvl <= zerolog.PanicLevel; lvl++ {
+		dst = fmt.Appendf(dst, "%s{level=%q} %d", logMetricsName, lvl.String(), m.counts[lvl-zerolog.TraceLevel].Load())
+		if exemplar, ok := m.exemplars[lvl]; ok {
+			dst = fmt.Appendf(dst, " # {trace_id=%q,span_id=%q} 1 %.3f",
+				exemplar.TraceID, exemplar.SpanID, float64(exemplar.Timestamp.UnixMilli())/1000)
+		}
+		dst = append(dst, '\n')
+	}
+	return append(dst, "# EOF\n"...)
+}
+
+func (m *LogMetrics) record(level zerolog.Level, p []byte) {
+	if !countedLevel(level) {
+		return
+	}
+	m.counts[level-zerolog.TraceLevel].Add(1)
+
+	if level < zerolog.WarnLevel || !bytes.Contains(p, []byte(`"trace_id"`)) {
+		return
+	}
+	traceID := extractStringField(p, "trace_id")
+	if traceID == "" {
+		return
+	}
+	exemplar := Exemplar{
+		TraceID:   traceID,
+		SpanID:    extractStringField(p, "span_id"),
+		Timestamp: time.Now(),
+	}
+	m.mu.Lock()
+	m.exemplars[level] = exemplar
+	m.mu.Unlock()
+}
+
+func countedLevel(level zerolog.Level) bool {
+	return level >= zerolog.TraceLevel && level <= zerolog.PanicLevel
+}
+
+// trimTotal returns the metric family name, which OpenMetrics writes without
+// the _total suffix of its samples.
+func trimTotal(name string) string {
+	return name[:len(name)-len("_total")]
+}
+
+type metricsWriter struct {
+	next    io.Writer
+	metrics *LogMetrics
+}
+
+func (w *metricsWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *metricsWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.metrics.record(level, p)
+	return writeLevel(w.next, level, p)
+}
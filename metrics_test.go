@@ -0,0 +1,62 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLogMetricsRecordsTraceExemplars(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	metrics := sugarzero.NewLogMetrics()
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithLogMetrics(metrics),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider()
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+	})
+	spanCtx, span := provider.Tracer("test").Start(ctx, "charge")
+	defer span.End()
+
+	sugarzero.Info(ctx, "started")
+	sugarzero.Error(ctx, "no trace")
+	sugarzero.Error(spanCtx, "charge failed")
+
+	if metrics.Count("info") != 1 || metrics.Count("error") != 2 {
+		t.Fatalf("unexpected counts info=%d error=%d", metrics.Count("info"), metrics.Count("error"))
+	}
+	exemplar, ok := metrics.Exemplar("error")
+	if !ok || exemplar.TraceID != span.SpanContext().TraceID().String() {
+		t.Fatalf("expected exemplar for the span's trace, got %+v", exemplar)
+	}
+	if _, ok := metrics.Exemplar("info"); ok {
+		t.Fatal("expected no exemplar below warn")
+	}
+
+	rec := httptest.NewRecorder()
+	metrics.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/logs", nil))
+	body := rec.Body.String()
+	wantLine := `sugarzero_log_entries_total{level="error"} 2 # {trace_id="` + exemplar.TraceID + `"`
+	if !strings.Contains(body, wantLine) {
+		t.Fatalf("expected exemplar line %q in:\n%s", wantLine, body)
+	}
+	if !strings.HasSuffix(body, "# EOF\n") {
+		t.Fatalf("expected OpenMetrics EOF marker, got:\n%s", body)
+	}
+}
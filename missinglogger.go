@@ -0,0 +1,125 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// MissingLoggerWarningInterval is the minimum time between two warnings in
+// MissingLoggerWarnRateLimited mode.
+const MissingLoggerWarningInterval = time.Minute
+
+// MissingLoggerWarning selects how often the global logger warns about
+// contexts that do not carry a logger.
+type MissingLoggerWarning int
+
+const (
+	// MissingLoggerWarnAlways warns on every call through such a context.
+	MissingLoggerWarnAlways MissingLoggerWarning = iota
+	// MissingLoggerWarnOnce warns once per process.
+	MissingLoggerWarnOnce
+	// MissingLoggerWarnPerCallSite warns once for each calling line.
+	MissingLoggerWarnPerCallSite
+	// MissingLoggerWarnRateLimited warns at most once per
+	// MissingLoggerWarningInterval.
+	MissingLoggerWarnRateLimited
+	// MissingLoggerWarnNever disables the warning.
+	MissingLoggerWarnNever
+)
+
+// WithMissingLoggerWarning sets how often the logger warns when it is used as
+// the fallback for a context without a logger. The default is
+// MissingLoggerWarnAlways. The warnings are diagnostics; see
+// SetDiagnosticsWriter.
+// Example: NewWithOptions(ctx, "info", WithMissingLoggerWarning(MissingLoggerWarnPerCallSite))
+func WithMissingLoggerWarning(mode MissingLoggerWarning) Option {
+	return func(o *options) {
+		o.missingLoggerWarning = mode
+	}
+}
+
+// missingLoggerWarnings tracks which fallback warnings were already emitted.
+type missingLoggerWarnings struct {
+	mode  MissingLoggerWarning
+	once  atomic.Bool
+	last  atomic.Int64
+	sites sync.Map // uintptr -> struct{}
+}
+
+// allow reports whether a warning for a call from pc must be emitted.
+func (w *missingLoggerWarnings) allow(pc uintptr) bool {
+	switch w.mode {
+	case MissingLoggerWarnOnce:
+		return w.once.CompareAndSwap(false, true)
+	case MissingLoggerWarnPerCallSite:
+		_, seen := w.sites.LoadOrStore(pc, struct{}{})
+		return !seen
+	case MissingLoggerWarnRateLimited:
+		now := time.Now().UnixNano()
+		last := w.last.Load()
+		if last != 0 && now-last < int64(MissingLoggerWarningInterval) {
+			return false
+		}
+		return w.last.CompareAndSwap(last, now)
+	case MissingLoggerWarnNever:
+		return false
+	default:
+		return true
+	}
+}
+
+// logMissingLoggerWarning reports that a package-level function was called
+// with a context lacking a logger, naming the caller skip frames above it.
+func (l *ZeroLogger) logMissingLoggerWarning(skip int) {
+	if l.missingLogger.mode == MissingLoggerWarnNever {
+		return
+	}
+	pc, file, line, _ := runtime.Caller(skip + 1)
+	if !l.missingLogger.allow(pc) {
+		return
+	}
+	diagnose(DiagnosticFallbackLogger, zerolog.WarnLevel, "context does not contain a logger, using fallback logger",
+		zerolog.CallerFieldName, file+":"+strconv.Itoa(line))
+}
+
+// ErrMissingLogger reports a context that does not carry a logger.
+var ErrMissingLogger = errors.New("sugarzero: context does not contain a logger")
+
+// WithStrictContext makes package-level functions such as Info or Sync panic
+// with an error wrapping ErrMissingLogger when the context does not carry a
+// logger, instead of warning and falling back to the global logger. Enable it
+// in development and tests to catch contexts that lost their logger along the
+// way.
+func WithStrictContext() Option {
+	return func(o *options) {
+		o.strictContext = true
+	}
+}
+
+// CheckContext returns an error wrapping ErrMissingLogger if ctx does not
+// carry a logger, e.g. to assert context plumbing in tests.
+// Example: if err := sugarzero.CheckContext(ctx); err != nil { t.Fatal(err) }
+func CheckContext(ctx context.Context) error {
+	if loggerFromContextValue(ctx) == nil {
+		return ErrMissingLogger
+	}
+	return nil
+}
+
+// missingLoggerError wraps ErrMissingLogger with the caller skip frames above
+// missingLoggerError.
+func missingLoggerError(skip int) error {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ErrMissingLogger
+	}
+	return fmt.Errorf("%w (called from %s:%d)", ErrMissingLogger, file, line)
+}
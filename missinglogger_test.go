@@ -0,0 +1,103 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func countMissingLoggerWarnings(t *testing.T, mode sugarzero.MissingLoggerWarning) (int, string) {
+	t.Helper()
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithMissingLoggerWarning(mode),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
+
+	for range 3 {
+		sugarzero.Info(context.Background(), "worker tick")
+	}
+	sugarzero.Info(context.Background(), "worker done")
+
+	output := strings.TrimSpace(diagnostics.String())
+	if output == "" {
+		return 0, ""
+	}
+	return len(strings.Split(output, "\n")), output
+}
+
+func TestWithMissingLoggerWarningModes(t *testing.T) {
+	tests := []struct {
+		name string
+		mode sugarzero.MissingLoggerWarning
+		want int
+	}{
+		{name: "always", mode: sugarzero.MissingLoggerWarnAlways, want: 4},
+		{name: "once", mode: sugarzero.MissingLoggerWarnOnce, want: 1},
+		{name: "per call site", mode: sugarzero.MissingLoggerWarnPerCallSite, want: 2},
+		{name: "rate limited", mode: sugarzero.MissingLoggerWarnRateLimited, want: 1},
+		{name: "never", mode: sugarzero.MissingLoggerWarnNever, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, output := countMissingLoggerWarnings(t, tt.mode)
+			if got != tt.want {
+				t.Fatalf("expected %d warnings, got %d:\n%s", tt.want, got, output)
+			}
+			if got > 0 && !strings.Contains(output, "missinglogger_test.go:") {
+				t.Fatalf("expected warnings to name the calling line, got %s", output)
+			}
+		})
+	}
+}
+
+func TestWithStrictContextPanicsOnMissingLogger(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithStrictContext(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "plumbed correctly")
+	if err := sugarzero.CheckContext(ctx); err != nil {
+		t.Fatalf("expected context with logger to pass, got %v", err)
+	}
+	if err := sugarzero.CheckContext(context.Background()); !errors.Is(err, sugarzero.ErrMissingLogger) {
+		t.Fatalf("expected ErrMissingLogger, got %v", err)
+	}
+
+	defer func() {
+		recovered := recover()
+		err, ok := recovered.(error)
+		if !ok || !errors.Is(err, sugarzero.ErrMissingLogger) {
+			t.Fatalf("expected panic with ErrMissingLogger, got %v", recovered)
+		}
+		if !strings.Contains(err.Error(), "missinglogger_test.go:") {
+			t.Fatalf("expected panic to name the calling line, got %v", err)
+		}
+	}()
+	sugarzero.Info(context.Background(), "lost the logger")
+}
@@ -0,0 +1,201 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// nopLogger discards every entry.
+type nopLogger struct{}
+
+// Nop returns a Logger that discards everything, for tests and code paths
+// that require a logger but should stay silent.
+func Nop() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Debug(context.Context, ...any)          {}
+func (nopLogger) Debugf(context.Context, string, ...any) {}
+func (nopLogger) Debugln(context.Context, ...any)        {}
+func (nopLogger) Info(context.Context, ...any)           {}
+func (nopLogger) Infof(context.Context, string, ...any)  {}
+func (nopLogger) Infoln(context.Context, ...any)         {}
+func (nopLogger) Warn(context.Context, ...any)           {}
+func (nopLogger) Warnf(context.Context, string, ...any)  {}
+func (nopLogger) Warnln(context.Context, ...any)         {}
+func (nopLogger) Error(context.Context, ...any)          {}
+func (nopLogger) Errorf(context.Context, string, ...any) {}
+func (nopLogger) Errorln(context.Context, ...any)        {}
+func (nopLogger) Fatal(context.Context, ...any)          {}
+func (nopLogger) Fatalf(context.Context, string, ...any) {}
+func (nopLogger) Fatalln(context.Context, ...any)        {}
+
+func (nopLogger) SetLogLevel(level string) error {
+	_, err := parseLevel(level)
+	return err
+}
+
+func (nopLogger) GetLogLevel() string {
+	return "disabled"
+}
+
+// MockEntry is a log call captured by MockLogger.
+type MockEntry struct {
+	Level   string
+	Message string
+	// Fields holds the fields attached to the context at the time of the call.
+	Fields map[string]any
+	// Err is the error attached with WithError, if any.
+	Err error
+}
+
+// MockLogger records every log call so tests can assert on what was logged
+// without parsing JSON output. It is safe for concurrent use.
+type MockLogger struct {
+	mu      sync.Mutex
+	level   string
+	entries []MockEntry
+}
+
+// NewMock returns an empty MockLogger.
+// Example:
+//
+//	mock := sugarzero.NewMock()
+//	ctx := sugarzero.WithLogger(ctx, mock)
+//	svc.Charge(ctx, order)
+//	if !mock.Contains("error", "charge failed") { t.Fatal("missing error log") }
+func NewMock() *MockLogger {
+	return &MockLogger{level: "debug"}
+}
+
+// Entries returns a copy of the recorded entries in call order.
+func (m *MockLogger) Entries() []MockEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockEntry(nil), m.entries...)
+}
+
+// Contains reports whether an entry with the given level and message was recorded.
+func (m *MockLogger) Contains(level, message string) bool {
+	return len(m.Find(func(e MockEntry) bool {
+		return e.Level == level && e.Message == message
+	})) > 0
+}
+
+// Find returns the recorded entries matching match.
+func (m *MockLogger) Find(match func(MockEntry) bool) []MockEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var found []MockEntry
+	for _, entry := range m.entries {
+		if match(entry) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// Reset discards the recorded entries.
+func (m *MockLogger) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = nil
+}
+
+func (m *MockLogger) record(ctx context.Context, level, message string) {
+	entry := MockEntry{
+		Level:   level,
+		Message: message,
+		Fields:  FieldsFromContext(ctx),
+		Err:     errorFromContext(ctx),
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+}
+
+func (m *MockLogger) Debug(ctx context.Context, args ...any) {
+	m.record(ctx, "debug", sprintArgs(args))
+}
+
+func (m *MockLogger) Debugf(ctx context.Context, format string, args ...any) {
+	m.record(ctx, "debug", fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Debugln(ctx context.Context, args ...any) {
+	m.record(ctx, "debug", sprintArgs(args))
+}
+
+func (m *MockLogger) Info(ctx context.Context, args ...any) {
+	m.record(ctx, "info", sprintArgs(args))
+}
+
+func (m *MockLogger) Infof(ctx context.Context, format string, args ...any) {
+	m.record(ctx, "info", fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Infoln(ctx context.Context, args ...any) {
+	m.record(ctx, "info", sprintArgs(args))
+}
+
+func (m *MockLogger) Warn(ctx context.Context, args ...any) {
+	m.record(ctx, "warn", sprintArgs(args))
+}
+
+func (m *MockLogger) Warnf(ctx context.Context, format string, args ...any) {
+	m.record(ctx, "warn", fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Warnln(ctx context.Context, args ...any) {
+	m.record(ctx, "warn", sprintArgs(args))
+}
+
+func (m *MockLogger) Error(ctx context.Context, args ...any) {
+	m.record(ctx, "error", sprintArgs(args))
+}
+
+func (m *MockLogger) Errorf(ctx context.Context, format string, args ...any) {
+	m.record(ctx, "error", fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Errorln(ctx context.Context, args ...any) {
+	m.record(ctx, "error", sprintArgs(args))
+}
+
+func (m *MockLogger) Fatal(ctx context.Context, args ...any) {
+	m.record(ctx, "fatal", sprintArgs(args))
+}
+
+func (m *MockLogger) Fatalf(ctx context.Context, format string, args ...any) {
+	m.record(ctx, "fatal", fmt.Sprintf(format, args...))
+}
+
+func (m *MockLogger) Fatalln(ctx context.Context, args ...any) {
+	m.record(ctx, "fatal", sprintArgs(args))
+}
+
+func (m *MockLogger) SetLogLevel(level string) error {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.level = lvl.String()
+	return nil
+}
+
+func (m *MockLogger) GetLogLevel() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.level
+}
+
+// sprintArgs formats args the same way ZeroLogger does for non-format calls.
+func sprintArgs(args []any) string {
+	if len(args) == 1 {
+		return fmt.Sprintf("%v", args[0])
+	}
+	return fmt.Sprint(args...)
+}
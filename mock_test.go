@@ -0,0 +1,49 @@
+package sugarzero_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestMockLoggerRecordsEntries(t *testing.T) {
+	mock := sugarzero.NewMock()
+	ctx := sugarzero.WithLogger(context.Background(), mock)
+	ctx = sugarzero.WithField(ctx, "order_id", "o-1")
+
+	chargeErr := errors.New("card declined")
+	sugarzero.Errorf(sugarzero.WithError(ctx, chargeErr), "charge failed for %s", "o-1")
+	sugarzero.Info(ctx, "retry scheduled")
+
+	if !mock.Contains("error", "charge failed for o-1") {
+		t.Fatalf("expected error entry, got %+v", mock.Entries())
+	}
+
+	failures := mock.Find(func(e sugarzero.MockEntry) bool {
+		return e.Level == "error" && e.Fields["order_id"] == "o-1"
+	})
+	if len(failures) != 1 || !errors.Is(failures[0].Err, chargeErr) {
+		t.Fatalf("expected one error entry carrying the error, got %+v", failures)
+	}
+
+	mock.Reset()
+	if len(mock.Entries()) != 0 {
+		t.Fatal("expected Reset to clear entries")
+	}
+}
+
+func TestNopLoggerDiscardsEverything(t *testing.T) {
+	_, testWriter := setupTest(t, "debug")
+
+	ctx := sugarzero.WithLogger(context.Background(), sugarzero.Nop())
+	sugarzero.Error(ctx, "dropped")
+
+	if testWriter.Len() != 0 {
+		t.Fatalf("expected no output, got %s", testWriter.String())
+	}
+	if err := sugarzero.SetLogLevel(ctx, "loud"); err == nil {
+		t.Fatal("expected invalid level to be rejected")
+	}
+}
@@ -0,0 +1,54 @@
+package sugarzero
+
+import "context"
+
+// MessageIDFieldName is the key under which the ID functions write the
+// message ID.
+const MessageIDFieldName = "msg_id"
+
+// DebugID logs message at debug level with a stable message ID and optional
+// key-value fields, so alerts and runbooks can match on id instead of text.
+// Example: sugarzero.InfoID(ctx, "ORD-1001", "order received", "order_id", id)
+func DebugID(ctx context.Context, id, message string, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Debug(withMessageID(resolved, id, keyvals), message)
+	})
+}
+
+// InfoID is DebugID at info level.
+func InfoID(ctx context.Context, id, message string, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Info(withMessageID(resolved, id, keyvals), message)
+	})
+}
+
+// WarnID is DebugID at warn level.
+func WarnID(ctx context.Context, id, message string, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Warn(withMessageID(resolved, id, keyvals), message)
+	})
+}
+
+// ErrorID is DebugID at error level.
+func ErrorID(ctx context.Context, id, message string, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Error(withMessageID(resolved, id, keyvals), message)
+	})
+}
+
+// FatalID is DebugID at fatal level.
+func FatalID(ctx context.Context, id, message string, keyvals ...any) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		logger.Fatal(withMessageID(resolved, id, keyvals), message)
+	})
+}
+
+func withMessageID(ctx context.Context, id string, keyvals []any) context.Context {
+	if id != "" {
+		ctx = withUnscopedFields(ctx, MessageIDFieldName, id)
+	}
+	if len(keyvals) == 0 {
+		return ctx
+	}
+	return WithFields(ctx, keyvals...)
+}
@@ -0,0 +1,39 @@
+package sugarzero_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestInfoIDWritesMessageID(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	sugarzero.InfoID(ctx, "ORD-1001", "order received", "order_id", "o-1")
+
+	entry := readLogEntry(t, testWriter)
+	if entry["msg_id"] != "ORD-1001" {
+		t.Fatalf("expected msg_id ORD-1001, got %v", entry["msg_id"])
+	}
+	if entry["message"] != "order received" || entry["order_id"] != "o-1" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "msgid_test.go") {
+		t.Fatalf("expected caller position in msgid_test.go, got %q", position)
+	}
+}
+
+func TestErrorIDRecordedByMock(t *testing.T) {
+	mock := sugarzero.NewMock()
+	ctx := sugarzero.WithLogger(t.Context(), mock)
+
+	sugarzero.ErrorID(ctx, "PAY-2002", "charge failed")
+
+	found := mock.Find(func(e sugarzero.MockEntry) bool {
+		return e.Fields["msg_id"] == "PAY-2002"
+	})
+	if len(found) != 1 || found[0].Level != "error" {
+		t.Fatalf("expected one error entry with msg_id, got %+v", mock.Entries())
+	}
+}
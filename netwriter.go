@@ -0,0 +1,423 @@
+package sugarzero
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// DefaultNetworkQueueSize is the number of entries buffered by a
+	// NetworkWriter while the remote end is slow or unreachable.
+	DefaultNetworkQueueSize = 1024
+	// DefaultSpoolBytes bounds the on-disk spool of a NetworkWriter created
+	// with NewSpooledNetworkWriter.
+	DefaultSpoolBytes = 256 << 20
+
+	networkDialTimeout  = 5 * time.Second
+	networkWriteTimeout = 5 * time.Second
+	networkMinBackoff   = 100 * time.Millisecond
+	networkMaxBackoff   = 10 * time.Second
+)
+
+// IdempotencyKeyFieldName is the field NewSpooledNetworkWriter adds to every
+// entry so that backends can drop entries replayed after a crash.
+const IdempotencyKeyFieldName = "idempotency_key"
+
+// ErrWriterClosed is returned when writing to a closed writer.
+var ErrWriterClosed = errors.New("sugarzero: writer closed")
+
+// NetworkWriter ships newline-delimited JSON entries to a collector such as
+// Logstash or Vector over TCP, UDP, or a Unix domain socket. Writes never
+// block: entries are queued in a bounded buffer and sent by a background
+// goroutine that reconnects with exponential backoff. Entries arriving while
+// the queue is full are dropped and counted, unless the writer spools to disk
+// (see NewSpooledNetworkWriter).
+type NetworkWriter struct {
+	network string
+	address string
+	// send delivers one entry over conn; it defaults to writing the raw bytes.
+	send func(conn net.Conn, entry []byte) error
+
+	mu     sync.RWMutex
+	queue  chan []byte
+	closed bool
+
+	closing chan struct{}
+	done    chan struct{}
+
+	// spool holds entries that did not fit in queue; nil disables spooling.
+	spool *diskSpool
+	// spooled wakes the sender when an entry is spooled.
+	spooled chan struct{}
+	// epoch and seq form the idempotency keys of a spooled writer.
+	epoch string
+	seq   atomic.Uint64
+
+	sent       atomic.Uint64
+	dropped    atomic.Uint64
+	reconnects atomic.Uint64
+	connected  atomic.Bool
+	lastErr    atomic.Value // errorValue
+	drops      dropNotice
+}
+
+// NetworkWriterStats reports the delivery counters of a NetworkWriter.
+type NetworkWriterStats struct {
+	// Connected reports whether the last delivery attempt succeeded.
+	Connected bool
+	Queued    int
+	// Spooled is the number of entries waiting in the on-disk spool.
+	Spooled    int
+	Sent       uint64
+	Dropped    uint64
+	Reconnects uint64
+	LastError  error
+}
+
+type errorValue struct{ err error }
+
+// NewNetworkWriter creates a writer for target, given as "tcp://host:port",
+// "udp://host:port", or "unix:///path/to.sock" for sidecar collectors
+// listening on a Unix domain socket. A queueSize <= 0 uses
+// DefaultNetworkQueueSize.
+func NewNetworkWriter(target string, queueSize int) (*NetworkWriter, error) {
+	network, address, err := parseNetworkTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	return newNetworkWriter(network, address, queueSize, writeRaw, nil), nil
+}
+
+// NewSpooledNetworkWriter is NewNetworkWriter with a write-ahead log in dir
+// that absorbs entries while the collector is unreachable, instead of
+// dropping them once the queue is full. Spooled entries are sent in order
+// after reconnecting, and entries still spooled at Close are kept on disk and
+// replayed by the next writer opened on dir. Entries are dropped only when
+// the spool reaches maxBytes; a maxBytes <= 0 uses DefaultSpoolBytes.
+//
+// Each writer needs a directory of its own. Delivery from the spool is at
+// least once: after a crash, the entry being sent may be delivered again. To
+// let backends discard such duplicates, every JSON entry gets a unique
+// idempotency_key field when written, which it keeps when replayed.
+//
+// Spooled entries survive a crash of the process as soon as they are
+// written. The spool is fsynced at most 100ms after every change and on
+// Close, so a crash of the machine loses at most the entries spooled in the
+// last 100ms, and replays at most the ones delivered in it.
+func NewSpooledNetworkWriter(target string, queueSize int, dir string, maxBytes int64) (*NetworkWriter, error) {
+	network, address, err := parseNetworkTarget(target)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultSpoolBytes
+	}
+	spool, err := openDiskSpool(dir, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+	var epoch [8]byte
+	if _, err := rand.Read(epoch[:]); err != nil {
+		_ = spool.close()
+		return nil, fmt.Errorf("sugarzero: failed to generate spool epoch: %w", err)
+	}
+	w := newNetworkWriter(network, address, queueSize, writeRaw, spool)
+	w.epoch = hex.EncodeToString(epoch[:])
+	return w, nil
+}
+
+func newNetworkWriter(network, address string, queueSize int, send func(net.Conn, []byte) error, spool *diskSpool) *NetworkWriter {
+	if queueSize <= 0 {
+		queueSize = DefaultNetworkQueueSize
+	}
+	w := &NetworkWriter{
+		network: network,
+		address: address,
+		send:    send,
+		queue:   make(chan []byte, queueSize),
+		closing: make(chan struct{}),
+		done:    make(chan struct{}),
+		spool:   spool,
+	}
+	if spool != nil {
+		w.spooled = make(chan struct{}, 1)
+	}
+	go w.run()
+	return w
+}
+
+// Write queues a copy of p for delivery. If the queue is full, p is spooled
+// to disk when spooling is enabled and dropped otherwise.
+func (w *NetworkWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	var entry []byte
+	if w.spool != nil {
+		entry = w.withIdempotencyKey(p)
+	} else {
+		entry = make([]byte, len(p))
+		copy(entry, p)
+	}
+
+	// Once entries are spooled, later ones follow them to keep the order
+	if w.spool == nil || w.spool.len() == 0 {
+		select {
+		case w.queue <- entry:
+			return len(p), nil
+		default:
+		}
+	}
+	if w.spool == nil {
+		w.drops.report(w.network+"://"+w.address, w.dropped.Add(1))
+		return len(p), nil
+	}
+	if err := w.spool.push(entry); err != nil {
+		w.drops.report(w.network+"://"+w.address, w.dropped.Add(1))
+		if !errors.Is(err, errQueueFull) {
+			reportWriteError(err)
+		}
+		return len(p), nil
+	}
+	select {
+	case w.spooled <- struct{}{}:
+	default:
+	}
+	return len(p), nil
+}
+
+// withIdempotencyKey returns a copy of the JSON entry p with a unique
+// idempotency key appended. Entries that are not JSON objects are copied as is.
+func (w *NetworkWriter) withIdempotencyKey(p []byte) []byte {
+	body := bytes.TrimRight(p, "\n")
+	if len(body) < 2 || body[0] != '{' || body[len(body)-1] != '}' {
+		return append([]byte(nil), p...)
+	}
+
+	entry := make([]byte, 0, len(p)+len(IdempotencyKeyFieldName)+len(w.epoch)+28)
+	entry = append(entry, body[:len(body)-1]...)
+	if len(body) > 2 {
+		entry = append(entry, ',')
+	}
+	entry = append(entry, `"`+IdempotencyKeyFieldName+`":"`...)
+	entry = append(entry, w.epoch...)
+	entry = append(entry, '-')
+	entry = strconv.AppendUint(entry, w.seq.Add(1), 10)
+	entry = append(entry, `"}`...)
+	return append(entry, p[len(body):]...)
+}
+
+// Stats returns a snapshot of the writer's delivery counters.
+func (w *NetworkWriter) Stats() NetworkWriterStats {
+	stats := NetworkWriterStats{
+		Connected:  w.connected.Load(),
+		Queued:     len(w.queue),
+		Spooled:    w.spoolLen(),
+		Sent:       w.sent.Load(),
+		Dropped:    w.dropped.Load(),
+		Reconnects: w.reconnects.Load(),
+	}
+	if v, ok := w.lastErr.Load().(errorValue); ok {
+		stats.LastError = v.err
+	}
+	return stats
+}
+
+// Close stops accepting entries, delivers the queued ones, and closes the
+// connection. Delivery stops at the first failed dial or send: without
+// spooling the remaining entries are dropped and counted, and with spooling
+// they are kept in the spool.
+func (w *NetworkWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.closing)
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	if w.spool != nil {
+		return w.spool.close()
+	}
+	return nil
+}
+
+func (w *NetworkWriter) run() {
+	defer close(w.done)
+
+	var conn net.Conn
+	defer func() {
+		if conn != nil {
+			_ = conn.Close()
+		}
+	}()
+
+	backoff := networkMinBackoff
+	for {
+		entry, spooled, ok := w.next()
+		if !ok {
+			return
+		}
+		if w.deliver(&conn, &backoff, entry) {
+			if spooled {
+				if err := w.spool.discard(); err != nil {
+					w.recordError(err)
+				}
+			}
+			continue
+		}
+		switch {
+		case w.spool == nil:
+			// deliver only gives up once the writer is closing; the rest of
+			// the queue is dropped instead of redialing for every entry
+			dropped := uint64(1)
+			for range w.queue {
+				dropped++
+			}
+			w.dropped.Add(dropped)
+			return
+		case spooled:
+			// The entry stays in the spool, and the queue is empty: entries
+			// written while the spool is not empty are spooled too
+			return
+		default:
+			w.spoolPending(entry)
+			return
+		}
+	}
+}
+
+// next returns the next entry to send: queued entries first, since they are
+// older than any spooled ones, then spooled entries, which stay in the spool
+// until delivered. It reports false once the queue is closed.
+func (w *NetworkWriter) next() (entry []byte, spooled, ok bool) {
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			return entry, false, ok
+		default:
+		}
+		if w.spool != nil {
+			entry, ok, err := w.spool.peek()
+			if err != nil {
+				w.recordError(err)
+			}
+			if ok {
+				return entry, true, true
+			}
+		}
+		select {
+		case entry, ok := <-w.queue:
+			return entry, false, ok
+		case <-w.spooled:
+		}
+	}
+}
+
+// deliver sends entry, reconnecting with backoff until it succeeds. It
+// reports false if the writer started closing before entry was sent.
+func (w *NetworkWriter) deliver(conn *net.Conn, backoff *time.Duration, entry []byte) bool {
+	for {
+		if *conn == nil {
+			c, err := net.DialTimeout(w.network, w.address, networkDialTimeout)
+			if err != nil {
+				w.recordError(err)
+				if !w.wait(*backoff) {
+					return false
+				}
+				*backoff = min(*backoff*2, networkMaxBackoff)
+				continue
+			}
+			*conn = c
+		}
+
+		_ = (*conn).SetDeadline(time.Now().Add(networkWriteTimeout))
+		if err := w.send(*conn, entry); err != nil {
+			w.recordError(err)
+			_ = (*conn).Close()
+			*conn = nil
+			w.reconnects.Add(1)
+			if !w.wait(*backoff) {
+				return false
+			}
+			*backoff = min(*backoff*2, networkMaxBackoff)
+			continue
+		}
+		*backoff = networkMinBackoff
+		w.connected.Store(true)
+		w.sent.Add(1)
+		return true
+	}
+}
+
+// spoolPending moves entry and the rest of the closed queue to the front of
+// the spool, so they are replayed before the entries spooled after them.
+func (w *NetworkWriter) spoolPending(entry []byte) {
+	pending := [][]byte{entry}
+	for queued := range w.queue {
+		pending = append(pending, queued)
+	}
+	if err := w.spool.unshift(pending); err != nil {
+		w.dropped.Add(uint64(len(pending)))
+		reportWriteError(err)
+	}
+}
+
+func (w *NetworkWriter) spoolLen() int {
+	if w.spool == nil {
+		return 0
+	}
+	return w.spool.len()
+}
+
+// wait sleeps for d and reports whether the writer is still running. Once the
+// writer is closing, queued entries are not retried.
+func (w *NetworkWriter) wait(d time.Duration) bool {
+	return waitOrClose(w.closing, d)
+}
+
+func writeRaw(conn net.Conn, entry []byte) error {
+	_, err := conn.Write(entry)
+	return err
+}
+
+func (w *NetworkWriter) recordError(err error) {
+	w.connected.Store(false)
+	w.lastErr.Store(errorValue{err: err})
+}
+
+func parseNetworkTarget(target string) (network, address string, err error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return "", "", fmt.Errorf("sugarzero: invalid network target %q: %w", target, err)
+	}
+	switch u.Scheme {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6":
+		if u.Host == "" {
+			return "", "", fmt.Errorf("sugarzero: network target %q is missing host:port", target)
+		}
+		return u.Scheme, u.Host, nil
+	case "unix", "unixgram":
+		if u.Path == "" {
+			return "", "", fmt.Errorf("sugarzero: network target %q is missing socket path", target)
+		}
+		return u.Scheme, u.Path, nil
+	default:
+		return "", "", fmt.Errorf("sugarzero: unsupported network scheme %q", u.Scheme)
+	}
+}
@@ -0,0 +1,214 @@
+package sugarzero_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func acceptLines(t *testing.T, listener net.Listener) <-chan string {
+	t.Helper()
+
+	lines := make(chan string, 16)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+	return lines
+}
+
+func receiveLine(t *testing.T, lines <-chan string) string {
+	t.Helper()
+
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for network entry")
+		return ""
+	}
+}
+
+func TestNetworkWriterShipsEntriesOverTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	lines := acceptLines(t, listener)
+
+	writer, err := sugarzero.NewNetworkWriter("tcp://"+listener.Addr().String(), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		_ = writer.Close()
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.New(context.Background(), "info", writer)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "shipped over tcp")
+
+	if line := receiveLine(t, lines); !strings.Contains(line, "shipped over tcp") {
+		t.Fatalf("unexpected line: %s", line)
+	}
+	if stats := writer.Stats(); stats.Sent != 1 || stats.Dropped != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+func TestNetworkWriterDropsWhenQueueFull(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	addr := listener.Addr().String()
+	_ = listener.Close()
+
+	writer, err := sugarzero.NewNetworkWriter("tcp://"+addr, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := writer.Write([]byte("{}\n")); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	stats := writer.Stats()
+	if stats.Sent != 0 || stats.Dropped != 5 {
+		t.Fatalf("expected all entries to be dropped, got %+v", stats)
+	}
+	if stats.LastError == nil {
+		t.Fatal("expected dial error to be recorded")
+	}
+	if _, err := writer.Write([]byte("{}\n")); err != sugarzero.ErrWriterClosed {
+		t.Fatalf("expected ErrWriterClosed, got %v", err)
+	}
+}
+
+func TestNewNetworkWriterRejectsUnknownScheme(t *testing.T) {
+	if _, err := sugarzero.NewNetworkWriter("http://localhost:9000", 0); err == nil {
+		t.Fatal("expected error for unsupported scheme")
+	}
+}
+
+func TestNetworkWriterShipsEntriesOverUnixSocket(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "collector.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	lines := acceptLines(t, listener)
+
+	writer, err := sugarzero.NewNetworkWriter("unix://"+socket, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = writer.Close()
+	})
+
+	if _, err := writer.Write([]byte("{\"message\":\"via sidecar\"}\n")); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+
+	if line := receiveLine(t, lines); !strings.Contains(line, "via sidecar") {
+		t.Fatalf("unexpected line: %s", line)
+	}
+}
+
+func TestSpooledNetworkWriterReplaysAfterOutage(t *testing.T) {
+	// Reserve an address, then leave it unserved to simulate an outage
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unexpected listen error: %v", err)
+	}
+	address := listener.Addr().String()
+	_ = listener.Close()
+
+	dir := t.TempDir()
+	writer, err := sugarzero.NewSpooledNetworkWriter("tcp://"+address, 1, dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range 5 {
+		if _, err := writer.Write([]byte(fmt.Sprintf(`{"n":%d}`+"\n", i))); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+	if stats := writer.Stats(); stats.Dropped != 0 || stats.Spooled == 0 {
+		t.Fatalf("expected entries to be spooled instead of dropped, got %+v", stats)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	listener, err = net.Listen("tcp", address)
+	if err != nil {
+		t.Skipf("address %s was taken before the collector came back: %v", address, err)
+	}
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	lines := acceptLines(t, listener)
+
+	writer, err = sugarzero.NewSpooledNetworkWriter("tcp://"+address, 1, dir, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = writer.Close()
+	})
+
+	keys := make(map[string]bool)
+	for i := range 5 {
+		var entry map[string]any
+		line := receiveLine(t, lines)
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", line, err)
+		}
+		if entry["n"] != float64(i) {
+			t.Fatalf("expected entry %d in order, got %s", i, line)
+		}
+		key, _ := entry[sugarzero.IdempotencyKeyFieldName].(string)
+		if key == "" || keys[key] {
+			t.Fatalf("expected a unique idempotency key, got %s", line)
+		}
+		keys[key] = true
+	}
+}
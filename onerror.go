@@ -0,0 +1,61 @@
+package sugarzero
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// LogOnError calls fn and, if it returns an error, logs the error with the
+// function name and duration_ms fields before returning fn's results
+// unchanged. The entry is logged at error level unless a registered error
+// level matches, as with WithError.
+// Example: user, err := sugarzero.LogOnError(ctx, func() (*User, error) { return repo.Load(ctx, id) })
+func LogOnError[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	start := time.Now()
+	value, err := fn()
+	if err != nil {
+		withLogger(returnedErrorContext(ctx, fn, err, start), func(logger Logger, resolved context.Context) {
+			logger.Error(resolved, "call failed")
+		})
+	}
+	return value, err
+}
+
+// Wrap returns a function that calls fn and logs the error it returns, as
+// LogOnError does, using the context it is called with.
+// Example: sync := sugarzero.Wrap(store.Sync); err := sync(ctx)
+func Wrap(fn func(context.Context) error) func(context.Context) error {
+	return func(ctx context.Context) error {
+		start := time.Now()
+		err := fn(ctx)
+		if err != nil {
+			withLogger(returnedErrorContext(ctx, fn, err, start), func(logger Logger, resolved context.Context) {
+				logger.Error(resolved, "call failed")
+			})
+		}
+		return err
+	}
+}
+
+// returnedErrorContext adds err, the name of fn, and the time since start to
+// ctx.
+func returnedErrorContext(ctx context.Context, fn any, err error, start time.Time) context.Context {
+	ctx = withUnscopedFields(ctx,
+		"function", funcName(fn),
+		"duration_ms", float64(time.Since(start).Microseconds())/1000,
+	)
+	return WithError(ctx, err)
+}
+
+// funcName returns the qualified name of the function fn, without the "-fm"
+// suffix of method values.
+func funcName(fn any) string {
+	f := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if f == nil {
+		return "unknown"
+	}
+	return strings.TrimSuffix(f.Name(), "-fm")
+}
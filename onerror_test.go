@@ -0,0 +1,69 @@
+package sugarzero_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type userStore struct{}
+
+func (userStore) Sync(context.Context) error {
+	return errors.New("replica unreachable")
+}
+
+func TestLogOnError(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	value, err := sugarzero.LogOnError(ctx, func() (int, error) {
+		return 42, nil
+	})
+	if value != 42 || err != nil || buf.Len() != 0 {
+		t.Fatalf("expected pass-through without logging, got %d, %v, %q", value, err, buf.String())
+	}
+
+	want := errors.New("not found")
+	value, err = sugarzero.LogOnError(ctx, func() (int, error) {
+		return 0, want
+	})
+	if !errors.Is(err, want) || value != 0 {
+		t.Fatalf("expected error to pass through, got %d, %v", value, err)
+	}
+
+	entry := readLogEntry(t, buf)
+	if entry["level"] != "ERROR" || entry["error"] != "not found" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+	if _, ok := entry["duration_ms"].(float64); !ok {
+		t.Fatalf("expected duration_ms, got %v", entry)
+	}
+	if function, _ := entry["function"].(string); !strings.Contains(function, "TestLogOnError") {
+		t.Fatalf("expected calling function name, got %q", function)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "onerror_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+}
+
+func TestWrap(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	sync := sugarzero.Wrap(userStore{}.Sync)
+	if err := sync(ctx); err == nil {
+		t.Fatal("expected error")
+	}
+
+	entry := readLogEntry(t, buf)
+	if entry["error"] != "replica unreachable" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+	if function, _ := entry["function"].(string); !strings.HasSuffix(function, "userStore.Sync") {
+		t.Fatalf("expected method name, got %q", function)
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "onerror_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+}
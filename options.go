@@ -0,0 +1,143 @@
+package sugarzero
+
+import (
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Option configures a logger created by NewWithOptions.
+type Option func(*options)
+
+type options struct {
+	writers []io.Writer
+	// outputs are converted to writers when the writer is built.
+	outputs []Output
+	// wrappers decorate the combined writer in the order they were added.
+	wrappers []func(io.Writer) (io.Writer, error)
+	coercion *Coercion
+	reserved reservedKeys
+	// keySanitization cleans field keys; nil keeps them as given.
+	keySanitization *KeySanitization
+	messageSafety   MessageSafety
+	// injectionProtection escapes CR and LF in user input and flags entries.
+	injectionProtection bool
+	// categoryLevels maps categories to unparsed level names.
+	categoryLevels map[string]string
+	// buffered are writers holding queued entries that Sync must drain first.
+	buffered []io.Writer
+	// batching buffers byte-stream writers; nil disables it.
+	batching *batchConfig
+	// isolation queues each writer separately instead of writing them in turn.
+	isolation *isolation
+	// breaker guards each writer with its own CircuitBreaker when set.
+	breaker  *BreakerConfig
+	sampling *SamplingConfig
+	governor *GovernorConfig
+	// name is set for loggers created through the registry.
+	name string
+	// fields are key-value pairs written on every entry.
+	fields       []any
+	blobs        *blobOffload
+	redaction    *keyRedaction
+	piiDetectors []PIIDetector
+
+	missingLoggerWarning MissingLoggerWarning
+	strictContext        bool
+	noCaller             bool
+	goroutineFields      bool
+	customLevels         []CustomLevel
+	severity             SeverityScheme
+	formatValidation     bool
+	tailSize             int
+	recentErrors         int
+	crashStore           BlobStore
+	crashEntries         int
+	schemaVersions       map[string]string
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(o)
+		}
+	}
+	return o
+}
+
+// WithWriters appends writers that receive every log entry. When no writers
+// are configured, os.Stdout is used.
+func WithWriters(writers ...io.Writer) Option {
+	return func(o *options) {
+		o.writers = append(o.writers, writers...)
+	}
+}
+
+// WithStaticFields appends key-value pairs written on every entry, such as
+// the service name.
+// Example: WithStaticFields("service", "billing", "region", "eu-west-1")
+func WithStaticFields(keyvals ...any) Option {
+	return func(o *options) {
+		o.fields = append(o.fields, keyvals...)
+	}
+}
+
+// WithoutCaller omits the caller position from entries, saving the cost of
+// resolving it.
+func WithoutCaller() Option {
+	return func(o *options) {
+		o.noCaller = true
+	}
+}
+
+func (o *options) wrapWriter(wrap func(io.Writer) (io.Writer, error)) {
+	o.wrappers = append(o.wrappers, wrap)
+}
+
+func (o *options) buildWriter() (io.Writer, error) {
+	for _, out := range o.outputs {
+		w, err := newOutputWriter(out)
+		if err != nil {
+			return nil, err
+		}
+		o.writers = append(o.writers, w)
+	}
+	o.outputs = nil
+	if o.batching != nil {
+		for i, w := range o.writers {
+			o.writers[i] = o.batchStreams(w)
+		}
+	}
+	if o.breaker != nil {
+		for i, w := range o.writers {
+			o.writers[i] = NewCircuitBreaker(w, *o.breaker)
+		}
+	}
+	writer := selectWriter(o.writers...)
+	var isolated *IsolatedWriter
+	if o.isolation != nil && len(o.writers) > 1 {
+		isolated = NewIsolatedWriter(o.isolation.queueSize, o.isolation.policy, o.writers...)
+		writer = isolated
+	}
+	for _, wrap := range o.wrappers {
+		wrapped, err := wrap(writer)
+		if err != nil {
+			return nil, err
+		}
+		writer = wrapped
+	}
+	if isolated != nil {
+		// Drained after the wrappers' buffers, which feed it
+		o.buffered = append(o.buffered, isolated)
+	}
+	return writer, nil
+}
+
+// writeLevel writes p to w, preserving the level for writers that route by it.
+func writeLevel(w io.Writer, level zerolog.Level, p []byte) (int, error) {
+	if lw, ok := w.(zerolog.LevelWriter); ok {
+		return lw.WriteLevel(level, p)
+	}
+	return w.Write(p)
+}
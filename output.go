@@ -0,0 +1,115 @@
+package sugarzero
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Output is a writer with its own level and sampling, applied independently
+// of the logger level and WithAdaptiveSampling, so each destination can ship
+// a different share of the entries.
+type Output struct {
+	Writer io.Writer
+	// Level is the minimum level written to Writer; "" writes every level.
+	// Entries without a level are always written.
+	Level string
+	// SampleRates maps level names to the fraction of entries written, in
+	// [0, 1], e.g. {"info": 0.01} for one info entry in a hundred. Levels
+	// without a rate are written in full.
+	SampleRates map[string]float64
+}
+
+// WithOutputs appends outputs that receive the entries matching their own
+// level and sample rates, alongside any writers added with WithWriters.
+// Example:
+//
+//	WithOutputs(
+//		Output{Writer: os.Stdout},
+//		Output{Writer: SinkWriter(loki), SampleRates: map[string]float64{"debug": 0, "info": 0.01}},
+//	)
+func WithOutputs(outputs ...Output) Option {
+	return func(o *options) {
+		o.outputs = append(o.outputs, outputs...)
+	}
+}
+
+// outputWriter filters and samples the entries of one Output.
+type outputWriter struct {
+	next  io.Writer
+	level zerolog.Level
+	rates map[zerolog.Level]float64
+	// seen counts entries per level with a sample rate.
+	seen map[zerolog.Level]*atomic.Uint64
+}
+
+func newOutputWriter(out Output) (*outputWriter, error) {
+	if out.Writer == nil {
+		return nil, errors.New("sugarzero: output writer must not be nil")
+	}
+	w := &outputWriter{
+		next:  out.Writer,
+		level: zerolog.TraceLevel,
+		rates: make(map[zerolog.Level]float64, len(out.SampleRates)),
+		seen:  make(map[zerolog.Level]*atomic.Uint64, len(out.SampleRates)),
+	}
+	if out.Level != "" {
+		level, err := parseLevel(out.Level)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: output level: %w", err)
+		}
+		w.level = level
+	}
+	for name, rate := range out.SampleRates {
+		level, err := parseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: output sample rate: %w", err)
+		}
+		if rate < 0 || rate > 1 {
+			return nil, fmt.Errorf("sugarzero: output sample rate for %s must be in [0, 1], got %v", name, rate)
+		}
+		w.rates[level] = rate
+		w.seen[level] = new(atomic.Uint64)
+	}
+	return w, nil
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *outputWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level != zerolog.NoLevel && (level < w.level || !w.sample(level)) {
+		return len(p), nil
+	}
+	return writeLevel(w.next, level, p)
+}
+
+// sample reports whether the next entry at level is kept. Entries are kept
+// evenly rather than at random: a rate of 0.01 keeps the 1st, 101st, 201st,
+// and so on.
+func (w *outputWriter) sample(level zerolog.Level) bool {
+	rate, ok := w.rates[level]
+	if !ok || rate >= 1 {
+		return true
+	}
+	n := float64(w.seen[level].Add(1))
+	return math.Ceil(n*rate) != math.Ceil((n-1)*rate)
+}
+
+// Sync syncs the underlying writer.
+func (w *outputWriter) Sync() error {
+	return syncWriter(w.next)
+}
+
+// Close closes the underlying writer if it implements io.Closer.
+func (w *outputWriter) Close() error {
+	if closer, ok := w.next.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
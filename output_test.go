@@ -0,0 +1,68 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestOutputsSampleAndFilterPerWriter(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var local, loki, alerts bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+		sugarzero.WithWriters(&local),
+		sugarzero.WithOutputs(
+			sugarzero.Output{Writer: &loki, SampleRates: map[string]float64{"debug": 0, "info": 0.1}},
+			sugarzero.Output{Writer: &alerts, Level: "error"},
+		))
+	if err != nil {
+		t.Fatalf("NewWithOptions failed: %v", err)
+	}
+
+	for i := 0; i < 20; i++ {
+		sugarzero.Debug(ctx, "cache miss")
+		sugarzero.Info(ctx, "request served")
+	}
+	sugarzero.Warn(ctx, "slow query")
+	sugarzero.Error(ctx, "payment failed")
+
+	count := func(buf *bytes.Buffer, msg string) int {
+		return strings.Count(buf.String(), msg)
+	}
+	if count(&local, "cache miss") != 20 || count(&local, "request served") != 20 {
+		t.Fatalf("expected every entry in the plain writer, got %q", local.String())
+	}
+	if count(&loki, "cache miss") != 0 || count(&loki, "request served") != 2 ||
+		count(&loki, "slow query") != 1 || count(&loki, "payment failed") != 1 {
+		t.Fatalf("unexpected sampled output %q", loki.String())
+	}
+	if count(&alerts, "payment failed") != 1 || count(&alerts, "slow query") != 0 || count(&alerts, "request served") != 0 {
+		t.Fatalf("unexpected error output %q", alerts.String())
+	}
+}
+
+func TestOutputsRejectInvalidConfig(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	cases := []sugarzero.Output{
+		{},
+		{Writer: &bytes.Buffer{}, Level: "loud"},
+		{Writer: &bytes.Buffer{}, SampleRates: map[string]float64{"info": 2}},
+		{Writer: &bytes.Buffer{}, SampleRates: map[string]float64{"chatty": 0.5}},
+	}
+	for _, out := range cases {
+		if _, err := sugarzero.NewWithOptions(context.Background(), "info", sugarzero.WithOutputs(out)); err == nil {
+			t.Errorf("expected %+v to be rejected", out)
+		}
+	}
+}
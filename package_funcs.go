@@ -1,98 +1,197 @@
 package sugarzero
 
-import "context"
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+)
 
 func Debug(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Debug(resolved, args...)
 	})
 }
 
 func Debugf(ctx context.Context, format string, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Debugf(resolved, format, args...)
 	})
 }
 
 func Debugln(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Debugln(resolved, args...)
 	})
 }
 
 func Info(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Info(resolved, args...)
 	})
 }
 
 func Infof(ctx context.Context, format string, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Infof(resolved, format, args...)
 	})
 }
 
 func Infoln(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Infoln(resolved, args...)
 	})
 }
 
 func Warn(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Warn(resolved, args...)
 	})
 }
 
 func Warnf(ctx context.Context, format string, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Warnf(resolved, format, args...)
 	})
 }
 
 func Warnln(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Warnln(resolved, args...)
 	})
 }
 
 func Error(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Error(resolved, args...)
 	})
 }
 
 func Errorf(ctx context.Context, format string, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Errorf(resolved, format, args...)
 	})
 }
 
 func Errorln(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Errorln(resolved, args...)
 	})
 }
 
 func Fatal(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Fatal(resolved, args...)
 	})
 }
 
 func Fatalf(ctx context.Context, format string, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	vetPrintf(format, args...)
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Fatalf(resolved, format, args...)
 	})
 }
 
 func Fatalln(ctx context.Context, args ...any) {
-	withLogger(ctx, func(logger *ZeroLogger, resolved context.Context) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
 		logger.Fatalln(resolved, args...)
 	})
 }
 
-func withLogger(ctx context.Context, fn func(*ZeroLogger, context.Context)) {
+// Raw returns a zerolog event enriched with the context's fields and trace.
+// It returns nil (a no-op event) when the context carries a non-zerolog Logger.
+// Example: sugarzero.Raw(ctx, zerolog.InfoLevel).Hex("digest", sum).Msg("stored")
+func Raw(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	var event *zerolog.Event
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		// Only the zerolog backend can hand out native events
+		if zl, ok := logger.(*ZeroLogger); ok {
+			event = zl.Raw(resolved, level)
+		}
+	})
+	return event
+}
+
+// Sync flushes and fsyncs the writers of the logger in ctx, e.g. before
+// shutdown. It is a no-op for non-zerolog Loggers.
+func Sync(ctx context.Context) error {
+	var err error
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			err = zl.Sync()
+		}
+	})
+	return err
+}
+
+// OnEvent subscribes callback to entries of the logger in ctx at or above
+// level that match match. See ZeroLogger.OnEvent.
+func OnEvent(ctx context.Context, level string, match EventMatcher, callback func(LogEvent)) (func(), error) {
+	var (
+		unsubscribe func()
+		err         = errors.New("sugarzero: OnEvent requires the zerolog backend")
+	)
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			unsubscribe, err = zl.OnEvent(level, match, callback)
+		}
+	})
+	return unsubscribe, err
+}
+
+// logAt logs message at level through the Logger interface. Levels below debug
+// are logged at debug and levels above error at error, never exiting.
+func logAt(ctx context.Context, level zerolog.Level, message string) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		switch {
+		case level <= zerolog.DebugLevel:
+			logger.Debug(resolved, message)
+		case level == zerolog.InfoLevel:
+			logger.Info(resolved, message)
+		case level == zerolog.WarnLevel:
+			logger.Warn(resolved, message)
+		default:
+			logger.Error(resolved, message)
+		}
+	})
+}
+
+// logAtDepth is logAt for helpers logging on behalf of their caller: with a
+// ZeroLogger, the entry is positioned depth frames above the caller of
+// logAtDepth.
+func logAtDepth(ctx context.Context, level zerolog.Level, depth int, message string) {
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		zl, ok := logger.(*ZeroLogger)
+		if !ok {
+			logAt(resolved, level, message)
+			return
+		}
+		// Skips this function, withLogger, and logAtDepth
+		zl.LogDepth(resolved, min(max(level, zerolog.DebugLevel), zerolog.ErrorLevel), 3+depth, message)
+	})
+}
+
+// levelEnabled reports whether logger writes entries at level in ctx,
+// honouring the category levels of a ZeroLogger. Loggers with an unknown
+// level are assumed to write them.
+func levelEnabled(ctx context.Context, logger Logger, level zerolog.Level) bool {
+	if level < zerolog.GlobalLevel() {
+		return false
+	}
+	if zl, ok := logger.(*ZeroLogger); ok {
+		if min, ok := zl.categoryLevels[categoryFromContext(ctx)]; ok {
+			return level >= min
+		}
+	}
+	min, err := parseLevel(logger.GetLogLevel())
+	return err != nil || level >= min
+}
+
+func withLogger(ctx context.Context, fn func(Logger, context.Context)) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -101,7 +200,11 @@ func withLogger(ctx context.Context, fn func(*ZeroLogger, context.Context)) {
 		return
 	}
 	if globalLogger != nil {
-		globalLogger.logMissingLoggerWarning()
+		// Skip withLogger and the package-level function that called it
+		if globalLogger.strictContext {
+			panic(missingLoggerError(2))
+		}
+		globalLogger.logMissingLoggerWarning(2)
 		// Pass the global logger in the context to allow further context-based logging.
 		// So this Warning is only logged once.
 		ctx = context.WithValue(ctx, loggerKey, globalLogger)
@@ -109,6 +212,31 @@ func withLogger(ctx context.Context, fn func(*ZeroLogger, context.Context)) {
 	}
 }
 
+// WithLogger injects logger into the context so package-level functions such
+// as Info and Errorf log through it. Any Logger implementation can be used,
+// which allows mocks and alternative backends to be injected.
+func WithLogger(ctx context.Context, logger Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
+// FromContext returns the Logger carried by the context, falling back to the
+// global logger created by New. It returns nil when neither exists.
+func FromContext(ctx context.Context) Logger {
+	if logger := loggerFromContextValue(ctx); logger != nil {
+		return logger
+	}
+	if globalLogger != nil {
+		return globalLogger
+	}
+	return nil
+}
+
 func SetLogLevel(ctx context.Context, level string) error {
 	if logger := loggerFromContextValue(ctx); logger != nil {
 		return logger.SetLogLevel(level)
@@ -0,0 +1,343 @@
+// Package pagerduty turns sugarzero log entries into PagerDuty Events API v2
+// events, so critical entries page the on-call engineer directly.
+package pagerduty
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+// DefaultEventsURL is the PagerDuty Events API v2 endpoint.
+const DefaultEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
+const (
+	// DefaultThrottle is the minimum interval between trigger events with
+	// the same dedup key.
+	DefaultThrottle = time.Minute
+	// DefaultQueueSize is the number of events waiting for delivery above
+	// which new ones are dropped.
+	DefaultQueueSize = 256
+
+	postTimeout = 5 * time.Second
+	// maxSummary is the longest summary PagerDuty accepts.
+	maxSummary = 1024
+	// pruneSize is the number of throttled keys above which expired keys are
+	// dropped.
+	pruneSize = 1024
+)
+
+var errQueueFull = errors.New("pagerduty: queue is full, event dropped")
+
+// Sink is a sugarzero.Sink that triggers a PagerDuty incident for every
+// matching entry, and resolves it when a matching recovery entry is logged.
+//
+// Events are deduplicated by DedupKey, so repeats of the same failure update
+// one incident instead of opening new ones, and trigger events with the same
+// dedup key are sent at most once per Throttle. Events are posted by a
+// background goroutine, so logging never waits for PagerDuty; Flush, called
+// by sugarzero.Sync, waits for the queued events. Fatal and panic entries are
+// posted synchronously, so they page before the process exits.
+// Example:
+//
+//	sink := &pagerduty.Sink{
+//		RoutingKey: os.Getenv("PAGERDUTY_ROUTING_KEY"),
+//		Recover:    sugarzero.MustParseFilter("recovered == true"),
+//	}
+//	ctx, err := sugarzero.NewWithOptions(ctx, "info", sugarzero.WithSinks(sink))
+//	...
+//	sugarzero.Error(sugarzero.WithDedupKey(ctx, "db-down"), "database unreachable")
+//	sugarzero.Info(sugarzero.WithField(sugarzero.WithDedupKey(ctx, "db-down"), "recovered", true), "database reachable")
+type Sink struct {
+	// RoutingKey is the integration key of the PagerDuty service.
+	RoutingKey string
+	// URL is the events endpoint; "" uses DefaultEventsURL.
+	URL string
+	// Trigger selects the entries that trigger incidents; nil selects error
+	// level and above.
+	Trigger *sugarzero.Filter
+	// Recover selects the entries that resolve the incident with their dedup
+	// key; nil resolves only through Resolve.
+	Recover *sugarzero.Filter
+	// Source names the affected system; "" uses the hostname.
+	Source string
+	// Component and Group are passed through to PagerDuty, e.g. "billing-api"
+	// and "prod-eu".
+	Component string
+	Group     string
+	// Throttle is the minimum interval between trigger events with the same
+	// dedup key; zero uses DefaultThrottle. A resolve event lifts it.
+	Throttle time.Duration
+	// QueueSize is the number of events waiting for delivery above which new
+	// ones are dropped; zero uses DefaultQueueSize.
+	QueueSize int
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+
+	mu   sync.Mutex
+	sent map[string]time.Time
+
+	start sync.Once
+	queue chan queued
+}
+
+// queued is an event waiting for delivery, or a Flush waiting for the events
+// queued before it when flushed is set.
+type queued struct {
+	event   Event
+	flushed chan struct{}
+}
+
+// Event is a PagerDuty Events API v2 event.
+type Event struct {
+	RoutingKey  string   `json:"routing_key"`
+	EventAction string   `json:"event_action"`
+	DedupKey    string   `json:"dedup_key,omitempty"`
+	Payload     *Payload `json:"payload,omitempty"`
+}
+
+// Payload describes a triggered incident.
+type Payload struct {
+	Summary       string         `json:"summary"`
+	Source        string         `json:"source"`
+	Severity      string         `json:"severity"`
+	Timestamp     string         `json:"timestamp,omitempty"`
+	Component     string         `json:"component,omitempty"`
+	Group         string         `json:"group,omitempty"`
+	CustomDetails map[string]any `json:"custom_details,omitempty"`
+}
+
+// WriteEntry queues a trigger or resolve event for entry, if it matches and
+// its dedup key is not throttled. It returns an error when the queue is full.
+func (s *Sink) WriteEntry(level zerolog.Level, entry []byte) error {
+	if s.Recover != nil && s.Recover.Match(level, entry) {
+		parsed, err := sugarzero.ParseEntry(entry)
+		if err != nil {
+			return err
+		}
+		key := DedupKey(parsed)
+		s.unthrottle(key)
+		return s.enqueue(Event{RoutingKey: s.RoutingKey, EventAction: "resolve", DedupKey: key})
+	}
+
+	if s.Trigger != nil {
+		if !s.Trigger.Match(level, entry) {
+			return nil
+		}
+	} else if level < zerolog.ErrorLevel || level > zerolog.PanicLevel {
+		return nil
+	}
+	parsed, err := sugarzero.ParseEntry(entry)
+	if err != nil {
+		return err
+	}
+	event := s.triggerEvent(level, parsed)
+	if !s.allow(event.DedupKey, time.Now()) {
+		return nil
+	}
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+		defer cancel()
+		return s.send(ctx, event)
+	}
+	return s.enqueue(event)
+}
+
+// Flush waits until the events queued so far are delivered or have failed.
+// Failed deliveries are reported through zerolog.ErrorHandler when they
+// happen.
+func (s *Sink) Flush() error {
+	s.start.Do(s.startQueue)
+	flushed := make(chan struct{})
+	s.queue <- queued{flushed: flushed}
+	<-flushed
+	return nil
+}
+
+// Resolve resolves the incident with dedupKey, e.g. from a health check
+// that sees the dependency recover.
+func (s *Sink) Resolve(ctx context.Context, dedupKey string) error {
+	if dedupKey == "" {
+		return errors.New("pagerduty: resolve needs a dedup key")
+	}
+	s.unthrottle(dedupKey)
+	return s.send(ctx, Event{RoutingKey: s.RoutingKey, EventAction: "resolve", DedupKey: dedupKey})
+}
+
+// DedupKey returns the dedup key of entry: its dedup_key field (see
+// sugarzero.WithDedupKey), else its trace_id, else its message.
+func DedupKey(entry sugarzero.Entry) string {
+	for _, field := range []string{sugarzero.DedupKeyFieldName, "trace_id"} {
+		if key, _ := entry.Fields[field].(string); key != "" {
+			return key
+		}
+	}
+	return entry.Message
+}
+
+// Severity maps an entry level to a PagerDuty severity. The custom levels
+// critical, alert, and emergency (see sugarzero.SyslogLevels) are critical.
+func Severity(level zerolog.Level, name string) string {
+	switch strings.ToLower(name) {
+	case "critical", "alert", "emergency":
+		return "critical"
+	}
+	switch {
+	case level >= zerolog.FatalLevel && level <= zerolog.PanicLevel:
+		return "critical"
+	case level == zerolog.ErrorLevel:
+		return "error"
+	case level == zerolog.WarnLevel:
+		return "warning"
+	}
+	return "info"
+}
+
+// allow reports whether a trigger event for key may be sent at now, and
+// records it.
+func (s *Sink) allow(key string, now time.Time) bool {
+	throttle := s.Throttle
+	if throttle <= 0 {
+		throttle = DefaultThrottle
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if last, ok := s.sent[key]; ok && now.Sub(last) < throttle {
+		return false
+	}
+	if s.sent == nil {
+		s.sent = make(map[string]time.Time)
+	}
+	if len(s.sent) >= pruneSize {
+		maps.DeleteFunc(s.sent, func(_ string, last time.Time) bool {
+			return now.Sub(last) >= throttle
+		})
+	}
+	s.sent[key] = now
+	return true
+}
+
+// unthrottle lets the next trigger event for key through, as its incident is
+// resolved.
+func (s *Sink) unthrottle(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sent, key)
+}
+
+// enqueue hands event to the delivery goroutine.
+func (s *Sink) enqueue(event Event) error {
+	s.start.Do(s.startQueue)
+	select {
+	case s.queue <- queued{event: event}:
+		return nil
+	default:
+		return errQueueFull
+	}
+}
+
+// startQueue creates the queue and starts the delivery goroutine, on first
+// use so that the zero Sink works.
+func (s *Sink) startQueue() {
+	size := s.QueueSize
+	if size <= 0 {
+		size = DefaultQueueSize
+	}
+	s.queue = make(chan queued, size)
+	go s.run()
+}
+
+// run delivers queued events in order, reporting failures through
+// zerolog.ErrorHandler or, without one, on stderr like zerolog does.
+func (s *Sink) run() {
+	for item := range s.queue {
+		if item.flushed != nil {
+			close(item.flushed)
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), postTimeout)
+		err := s.send(ctx, item.event)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if zerolog.ErrorHandler != nil {
+			zerolog.ErrorHandler(err)
+		} else {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+		}
+	}
+}
+
+func (s *Sink) triggerEvent(level zerolog.Level, entry sugarzero.Entry) Event {
+	source := s.Source
+	if source == "" {
+		source, _ = os.Hostname()
+	}
+	summary := entry.Message
+	if len(summary) > maxSummary {
+		summary = strings.ToValidUTF8(summary[:maxSummary], "")
+	}
+	var timestamp string
+	if !entry.Time.IsZero() {
+		timestamp = entry.Time.Format(time.RFC3339Nano)
+	}
+	return Event{
+		RoutingKey:  s.RoutingKey,
+		EventAction: "trigger",
+		DedupKey:    DedupKey(entry),
+		Payload: &Payload{
+			Summary:       summary,
+			Source:        source,
+			Severity:      Severity(level, entry.Level),
+			Timestamp:     timestamp,
+			Component:     s.Component,
+			Group:         s.Group,
+			CustomDetails: entry.Fields,
+		},
+	}
+}
+
+func (s *Sink) send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("pagerduty: failed to encode event: %w", err)
+	}
+	url := s.URL
+	if url == "" {
+		url = DefaultEventsURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("pagerduty: invalid request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("pagerduty: %s failed: %w", event.EventAction, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("pagerduty: %s rejected: %s: %s", event.EventAction, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,223 @@
+package pagerduty_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/pagerduty"
+	"github.com/rs/zerolog"
+)
+
+// eventServer records the events posted to it.
+type eventServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	events []pagerduty.Event
+}
+
+func newEventServer(t *testing.T, handle func(w http.ResponseWriter)) *eventServer {
+	t.Helper()
+	s := &eventServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event pagerduty.Event
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid event: %v", err)
+		}
+		s.mu.Lock()
+		s.events = append(s.events, event)
+		s.mu.Unlock()
+		if handle != nil {
+			handle(w)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *eventServer) snapshot() []pagerduty.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]pagerduty.Event(nil), s.events...)
+}
+
+func TestSinkTriggersAndResolves(t *testing.T) {
+	server := newEventServer(t, nil)
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithSinks(&pagerduty.Sink{
+			RoutingKey: "routing-key",
+			URL:        server.URL,
+			Recover:    sugarzero.MustParseFilter("recovered == true"),
+			Source:     "billing-1",
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	db := sugarzero.WithDedupKey(ctx, "db-down")
+	sugarzero.Warn(ctx, "slow query")
+	sugarzero.Error(sugarzero.WithField(db, "shard", 3), "database unreachable")
+	sugarzero.Info(sugarzero.WithField(db, "recovered", true), "database reachable")
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+
+	events := server.snapshot()
+	if len(events) != 2 {
+		t.Fatalf("expected trigger and resolve, got %+v", events)
+	}
+	trigger := events[0]
+	if trigger.EventAction != "trigger" || trigger.DedupKey != "db-down" || trigger.RoutingKey != "routing-key" {
+		t.Fatalf("unexpected trigger: %+v", trigger)
+	}
+	if trigger.Payload.Severity != "error" || trigger.Payload.Summary != "database unreachable" ||
+		trigger.Payload.Source != "billing-1" || trigger.Payload.CustomDetails["shard"] != float64(3) {
+		t.Fatalf("unexpected payload: %+v", trigger.Payload)
+	}
+	if events[1].EventAction != "resolve" || events[1].DedupKey != "db-down" || events[1].Payload != nil {
+		t.Fatalf("unexpected resolve: %+v", events[1])
+	}
+}
+
+func TestDedupKeyAndSeverity(t *testing.T) {
+	entry := sugarzero.Entry{Message: "boom", Fields: map[string]any{"trace_id": "abc"}}
+	if got := pagerduty.DedupKey(entry); got != "abc" {
+		t.Fatalf("expected trace id as dedup key, got %q", got)
+	}
+	tests := []struct {
+		level zerolog.Level
+		name  string
+		want  string
+	}{
+		{zerolog.FatalLevel, "fatal", "critical"},
+		{zerolog.ErrorLevel, "CRITICAL", "critical"},
+		{zerolog.ErrorLevel, "error", "error"},
+		{zerolog.WarnLevel, "warn", "warning"},
+		{zerolog.InfoLevel, "notice", "info"},
+	}
+	for _, tt := range tests {
+		if got := pagerduty.Severity(tt.level, tt.name); got != tt.want {
+			t.Fatalf("Severity(%v, %q) = %q, want %q", tt.level, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSinkReportsRejectedEvents(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"status":"invalid event"}`, http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	sink := &pagerduty.Sink{URL: server.URL}
+	if err := sink.WriteEntry(zerolog.FatalLevel, []byte(`{"level":"fatal","message":"boom"}`)); err == nil {
+		t.Fatal("expected an error for a rejected fatal event")
+	}
+	if err := sink.Resolve(context.Background(), ""); err == nil {
+		t.Fatal("expected an error for an empty dedup key")
+	}
+
+	var mu sync.Mutex
+	var reported []error
+	previous := zerolog.ErrorHandler
+	zerolog.ErrorHandler = func(err error) {
+		mu.Lock()
+		reported = append(reported, err)
+		mu.Unlock()
+	}
+	t.Cleanup(func() {
+		zerolog.ErrorHandler = previous
+	})
+	if err := sink.WriteEntry(zerolog.ErrorLevel, []byte(`{"level":"error","message":"queued"}`)); err != nil {
+		t.Fatalf("expected the event to be queued, got %v", err)
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reported) != 1 {
+		t.Fatalf("expected the rejected queued event to be reported, got %v", reported)
+	}
+}
+
+func TestSinkThrottlesByDedupKey(t *testing.T) {
+	server := newEventServer(t, nil)
+	sink := &pagerduty.Sink{URL: server.URL, Throttle: time.Hour, Recover: sugarzero.MustParseFilter("recovered == true")}
+
+	for _, tt := range []struct {
+		level zerolog.Level
+		entry string
+	}{
+		{zerolog.ErrorLevel, `{"level":"error","message":"db down","dedup_key":"db"}`},
+		{zerolog.ErrorLevel, `{"level":"error","message":"db still down","dedup_key":"db"}`},
+		{zerolog.ErrorLevel, `{"level":"error","message":"cache down","dedup_key":"cache"}`},
+		{zerolog.InfoLevel, `{"level":"info","message":"db up","dedup_key":"db","recovered":true}`},
+		{zerolog.ErrorLevel, `{"level":"error","message":"db down again","dedup_key":"db"}`},
+	} {
+		if err := sink.WriteEntry(tt.level, []byte(tt.entry)); err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	}
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	var got []string
+	for _, event := range server.snapshot() {
+		got = append(got, event.EventAction+":"+event.DedupKey)
+	}
+	want := []string{"trigger:db", "trigger:cache", "resolve:db", "trigger:db"}
+	if len(got) != len(want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("expected events %v, got %v", want, got)
+		}
+	}
+}
+
+func TestSinkDeliversAsynchronously(t *testing.T) {
+	release := make(chan struct{})
+	server := newEventServer(t, func(w http.ResponseWriter) {
+		<-release
+		w.WriteHeader(http.StatusAccepted)
+	})
+	sink := &pagerduty.Sink{URL: server.URL, QueueSize: 1}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- sink.WriteEntry(zerolog.ErrorLevel, []byte(`{"level":"error","message":"slow"}`))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("WriteEntry failed: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected WriteEntry not to wait for PagerDuty")
+	}
+
+	close(release)
+	if err := sink.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	if events := server.snapshot(); len(events) != 1 || events[0].Payload.Summary != "slow" {
+		t.Fatalf("expected the event after Flush, got %+v", events)
+	}
+}
@@ -0,0 +1,65 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/rs/zerolog"
+)
+
+// RecoverAndLog recovers a panic in the calling goroutine and logs it at
+// error level with structured panic_type, panic_value, and stack fields. It
+// must be deferred directly:
+//
+//	defer sugarzero.RecoverAndLog(ctx)
+//
+// The panic is not re-raised.
+func RecoverAndLog(ctx context.Context) {
+	if r := recover(); r != nil {
+		// Skips RecoverAndLog and the runtime's panic frame, positioning the
+		// entry where the panic was raised
+		logPanic(ctx, r, 2)
+	}
+}
+
+// LogPanic logs a value obtained from recover at error level with the fields
+// returned by PanicFields and the current stack (see FeatureStack), and writes a crash bundle if
+// the logger was created with WithCrashBundles.
+func LogPanic(ctx context.Context, value any) {
+	logPanic(ctx, value, 1)
+}
+
+// logPanic implements LogPanic, positioning the entry skip frames above its
+// caller.
+func logPanic(ctx context.Context, value any, skip int) {
+	ctx = withUnscopedFields(ctx, PanicFields(value)...)
+	if FeatureEnabled(FeatureStack) {
+		ctx = withUnscopedFields(ctx, "stack", string(debug.Stack()))
+	}
+	withLogger(ctx, func(logger Logger, resolved context.Context) {
+		// Skips this function, withLogger, and logPanic
+		logAtDepth(resolved, zerolog.ErrorLevel, 3+skip, "panic recovered")
+		if zl, ok := logger.(*ZeroLogger); ok && zl.events != nil && zl.events.crash != nil {
+			zl.events.crash.write("panic")
+		}
+	})
+}
+
+// PanicFields describes a panic value as panic_type and panic_value fields.
+// Errors are logged by their message, strings as-is, fmt.Stringers by their
+// String method, and any other value as nested JSON, so structured panic
+// payloads stay queryable instead of being flattened with %v.
+func PanicFields(value any) []any {
+	fields := []any{"panic_type", fmt.Sprintf("%T", value)}
+	switch v := value.(type) {
+	case error:
+		return append(fields, "panic_value", v.Error())
+	case string:
+		return append(fields, "panic_value", v)
+	case fmt.Stringer:
+		return append(fields, "panic_value", v.String())
+	default:
+		return append(fields, JSON("panic_value", v))
+	}
+}
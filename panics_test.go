@@ -0,0 +1,103 @@
+package sugarzero_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type orderConflict struct {
+	OrderID string `json:"order_id"`
+	Version int    `json:"version"`
+}
+
+func panicWith(ctx context.Context, value any) {
+	defer sugarzero.RecoverAndLog(ctx)
+	panic(value)
+}
+
+func TestRecoverAndLogFormatsPanicValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     any
+		panicType string
+		check     func(any) bool
+	}{
+		{
+			name:      "error",
+			value:     errors.New("connection reset"),
+			panicType: "*errors.errorString",
+			check:     func(v any) bool { return v == "connection reset" },
+		},
+		{
+			name:      "string",
+			value:     "unreachable state",
+			panicType: "string",
+			check:     func(v any) bool { return v == "unreachable state" },
+		},
+		{
+			name:      "struct",
+			value:     orderConflict{OrderID: "o-1", Version: 3},
+			panicType: "sugarzero_test.orderConflict",
+			check: func(v any) bool {
+				m, ok := v.(map[string]any)
+				return ok && m["order_id"] == "o-1" && m["version"] == float64(3)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, testWriter := setupTest(t, "info")
+
+			panicWith(ctx, tt.value)
+
+			entry := readLogEntry(t, testWriter)
+			if entry["level"] != "ERROR" || entry["message"] != "panic recovered" {
+				t.Fatalf("unexpected entry %v", entry)
+			}
+			if entry["panic_type"] != tt.panicType {
+				t.Fatalf("expected panic_type %q, got %v", tt.panicType, entry["panic_type"])
+			}
+			if !tt.check(entry["panic_value"]) {
+				t.Fatalf("unexpected panic_value %#v", entry["panic_value"])
+			}
+			if stack, _ := entry["stack"].(string); !strings.Contains(stack, "panicWith") {
+				t.Fatalf("expected stack to include the panicking function, got %q", stack)
+			}
+		})
+	}
+}
+
+func TestRecoverAndLogPositionsAtPanic(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	var line int
+	func() {
+		defer sugarzero.RecoverAndLog(ctx)
+		_, _, line, _ = runtime.Caller(0)
+		panic("boom")
+	}()
+
+	entry := readLogEntry(t, testWriter)
+	if want := fmt.Sprintf("panics_test.go:%d", line+1); !strings.HasSuffix(entry["position"].(string), want) {
+		t.Fatalf("expected position %s, got %v", want, entry["position"])
+	}
+}
+
+func TestLogPanicPositionsAtCaller(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	_, _, line, _ := runtime.Caller(0)
+	sugarzero.LogPanic(ctx, "boom")
+
+	entry := readLogEntry(t, testWriter)
+	if want := fmt.Sprintf("panics_test.go:%d", line+1); !strings.HasSuffix(entry["position"].(string), want) {
+		t.Fatalf("expected position %s, got %v", want, entry["position"])
+	}
+}
@@ -0,0 +1,222 @@
+package sugarzero
+
+import (
+	"bytes"
+	"regexp"
+
+	"github.com/rs/zerolog"
+)
+
+// PIIRedactedFieldName is set to true on entries in which WithPIIDetection
+// masked at least one value.
+const PIIRedactedFieldName = "pii_redacted"
+
+// PIIDetector finds one kind of personal data in string values.
+type PIIDetector struct {
+	// Name identifies the detector, e.g. "email".
+	Name string
+	// Pattern matches candidate values.
+	Pattern *regexp.Regexp
+	// Validate, if set, filters out false positives among the matches.
+	Validate func(match []byte) bool
+}
+
+// PIIEmail detects email addresses.
+var PIIEmail = PIIDetector{
+	Name:    "email",
+	Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+}
+
+// PIICreditCard detects payment card numbers of 13 to 19 digits, optionally
+// grouped with spaces or dashes, that pass the Luhn check.
+var PIICreditCard = PIIDetector{
+	Name:     "credit_card",
+	Pattern:  regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	Validate: luhnValid,
+}
+
+// PIISSN detects US social security numbers written as 123-45-6789.
+var PIISSN = PIIDetector{
+	Name:     "ssn",
+	Pattern:  regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`),
+	Validate: ssnValid,
+}
+
+// DefaultPIIDetectors returns the email, credit card, and SSN detectors.
+func DefaultPIIDetectors() []PIIDetector {
+	return []PIIDetector{PIIEmail, PIICreditCard, PIISSN}
+}
+
+// WithPIIDetection scans the string and number values of every entry,
+// including the message, and replaces matches with "[REDACTED]". Entries with
+// masked values are tagged with pii_redacted=true. Field names are not
+// scanned. Without detectors, DefaultPIIDetectors is used. Entries are
+// scrubbed before they reach the writers, the tail buffer, RecentErrors,
+// crash bundles, and OnEvent subscribers.
+//
+// This is a safety net for values that slip past key-based redaction, not a
+// substitute for it: heuristics miss unusual formats.
+func WithPIIDetection(detectors ...PIIDetector) Option {
+	if len(detectors) == 0 {
+		detectors = DefaultPIIDetectors()
+	}
+	return func(o *options) {
+		o.piiDetectors = detectors
+	}
+}
+
+// piiScrubber masks the values matched by its detectors in encoded entries.
+type piiScrubber struct {
+	detectors []PIIDetector
+}
+
+// scrub returns the entry with PII in string and number values masked and the
+// pii_redacted tag added, or p unchanged when nothing matched. Masked numbers
+// become strings.
+func (s *piiScrubber) scrub(p []byte) []byte {
+	var (
+		out []byte
+		key []byte
+	)
+	last := 0
+	for i := 0; i < len(p); {
+		switch c := p[i]; {
+		case c == '"':
+			begin, end, ok := nextJSONString(p, i)
+			if !ok {
+				i = len(p)
+				continue
+			}
+			i = end + 1
+			if isJSONKey(p, end+1) {
+				key = p[begin:end]
+				continue
+			}
+			masked := maskPII(s.detectors, p[begin:end])
+			if masked == nil {
+				continue
+			}
+			out = append(out, p[last:begin]...)
+			out = append(out, masked...)
+			last = end
+		case c == '-' || '0' <= c && c <= '9':
+			// Digits outside of strings only occur in numbers
+			end := i + 1
+			for end < len(p) && isJSONNumberByte(p[end]) {
+				end++
+			}
+			// Unix timestamps can pass the Luhn check
+			if string(key) == zerolog.TimestampFieldName {
+				i = end
+				continue
+			}
+			if masked := maskPII(s.detectors, p[i:end]); masked != nil {
+				out = append(out, p[last:i]...)
+				out = append(out, '"')
+				out = append(out, masked...)
+				out = append(out, '"')
+				last = end
+			}
+			i = end
+		default:
+			i++
+		}
+	}
+	if out == nil {
+		return p
+	}
+	out = append(out, p[last:]...)
+
+	closing := bytes.LastIndexByte(out, '}')
+	if closing < 0 {
+		return out
+	}
+	tagged := make([]byte, 0, len(out)+len(PIIRedactedFieldName)+9)
+	tagged = append(tagged, out[:closing]...)
+	tagged = append(tagged, `,"`+PIIRedactedFieldName+`":true`...)
+	return append(tagged, out[closing:]...)
+}
+
+func isJSONNumberByte(c byte) bool {
+	return '0' <= c && c <= '9' || c == '.' || c == 'e' || c == 'E' || c == '+' || c == '-'
+}
+
+// maskPII returns value with every match of detectors replaced, or nil if
+// nothing matched.
+func maskPII(detectors []PIIDetector, value []byte) []byte {
+	changed := false
+	for _, detector := range detectors {
+		value = detector.Pattern.ReplaceAllFunc(value, func(match []byte) []byte {
+			if detector.Validate != nil && !detector.Validate(match) {
+				return match
+			}
+			changed = true
+			return []byte(redactedValue)
+		})
+	}
+	if !changed {
+		return nil
+	}
+	return value
+}
+
+// nextJSONString returns the bounds of the contents of the next JSON string
+// literal in p at or after start.
+func nextJSONString(p []byte, start int) (begin, end int, ok bool) {
+	quote := bytes.IndexByte(p[start:], '"')
+	if quote < 0 {
+		return 0, 0, false
+	}
+	begin = start + quote + 1
+	for i := begin; i < len(p); i++ {
+		switch p[i] {
+		case '\\':
+			i++
+		case '"':
+			return begin, i, true
+		}
+	}
+	return 0, 0, false
+}
+
+// isJSONKey reports whether the string literal ending before offset is an
+// object key.
+func isJSONKey(p []byte, offset int) bool {
+	for ; offset < len(p); offset++ {
+		switch p[offset] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func luhnValid(match []byte) bool {
+	sum, digits := 0, 0
+	for i := len(match) - 1; i >= 0; i-- {
+		c := match[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+func ssnValid(match []byte) bool {
+	area := string(match[:3])
+	return area != "000" && area != "666" && match[0] != '9' &&
+		string(match[4:6]) != "00" && string(match[7:]) != "0000"
+}
@@ -0,0 +1,107 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithPIIDetectionMasksValues(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithPIIDetection(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx,
+		"note", "card 4111 1111 1111 1111, ssn 123-45-6789",
+		"order", "1234567890123",
+		"alice@example.com", "key is not scanned",
+	)
+	sugarzero.Info(ctx, "contact alice@example.com")
+
+	entry := readLogEntry(t, &buf)
+	if entry["message"] != "contact [REDACTED]" {
+		t.Fatalf("expected masked message, got %v", entry["message"])
+	}
+	if entry["note"] != "card [REDACTED], ssn [REDACTED]" {
+		t.Fatalf("expected masked note, got %v", entry["note"])
+	}
+	if entry["order"] != "1234567890123" {
+		t.Fatalf("expected non-Luhn number to be kept, got %v", entry["order"])
+	}
+	if _, ok := entry["alice@example.com"]; !ok {
+		t.Fatalf("expected field names to be left alone, got %v", entry)
+	}
+	if entry[sugarzero.PIIRedactedFieldName] != true {
+		t.Fatalf("expected %s=true, got %v", sugarzero.PIIRedactedFieldName, entry[sugarzero.PIIRedactedFieldName])
+	}
+}
+
+func TestWithPIIDetectionLeavesCleanEntriesUntouched(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithPIIDetection(sugarzero.PIIEmail),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "ssn", "123-45-6789"), "nothing to see")
+
+	if strings.Contains(buf.String(), sugarzero.PIIRedactedFieldName) {
+		t.Fatalf("expected no pii tag, got %s", buf.String())
+	}
+	entry := readLogEntry(t, &buf)
+	if entry["ssn"] != "123-45-6789" {
+		t.Fatalf("expected only configured detectors to run, got %v", entry["ssn"])
+	}
+}
+
+func TestWithPIIDetectionScrubsBeforeEventConsumers(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithRecentErrors(4),
+		sugarzero.WithPIIDetection(),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Error(sugarzero.WithField(ctx, "card", 4111111111111111), "login failed for alice@example.com")
+
+	entry := readLogEntry(t, &buf)
+	if entry["message"] != "login failed for [REDACTED]" || entry["card"] != "[REDACTED]" {
+		t.Fatalf("expected masked message and number, got %v", entry)
+	}
+	recent := sugarzero.RecentErrors(ctx)
+	if len(recent) != 1 {
+		t.Fatalf("expected 1 recent error, got %v", recent)
+	}
+	if recent[0].Message != "login failed for [REDACTED]" || recent[0].Fields["card"] != "[REDACTED]" {
+		t.Fatalf("expected RecentErrors to keep the scrubbed entry, got %+v", recent[0])
+	}
+}
@@ -0,0 +1,49 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/pprof"
+)
+
+// ProfileLabels returns the context fields named by keys as pprof labels, so
+// CPU and goroutine profiles can be sliced by the same dimensions as logs.
+// Keys without a field are skipped; non-string values are formatted with %v.
+func ProfileLabels(ctx context.Context, keys ...string) pprof.LabelSet {
+	fields := FieldsFromContext(ctx)
+	labels := make([]string, 0, 2*len(keys))
+	for _, key := range keys {
+		value, ok := fields[key]
+		if !ok {
+			continue
+		}
+		if s, ok := value.(string); ok {
+			labels = append(labels, key, s)
+			continue
+		}
+		labels = append(labels, key, fmt.Sprint(value))
+	}
+	return pprof.Labels(labels...)
+}
+
+// DoWithProfileLabels runs fn with the context fields named by keys set as
+// pprof labels on the current goroutine, restoring the previous labels when
+// fn returns. Goroutines started by fn inherit the labels.
+// Example: sugarzero.DoWithProfileLabels(ctx, []string{"request_id", "endpoint"}, handle)
+func DoWithProfileLabels(ctx context.Context, keys []string, fn func(context.Context)) {
+	pprof.Do(ctx, ProfileLabels(ctx, keys...), fn)
+}
+
+// ProfileLabelsMiddleware returns HTTP middleware that labels the handling
+// goroutine with the context fields named by keys. It must run after the
+// middleware that attaches those fields to the request context.
+func ProfileLabelsMiddleware(keys ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			DoWithProfileLabels(r.Context(), keys, func(ctx context.Context) {
+				next.ServeHTTP(w, r.WithContext(ctx))
+			})
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package sugarzero_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestDoWithProfileLabelsUsesContextFields(t *testing.T) {
+	ctx := sugarzero.WithFields(context.Background(), "request_id", "req-1", "status", 200, "user", "u-1")
+
+	sugarzero.DoWithProfileLabels(ctx, []string{"request_id", "status", "missing"}, func(ctx context.Context) {
+		if got, ok := pprof.Label(ctx, "request_id"); !ok || got != "req-1" {
+			t.Fatalf("expected request_id label, got %q", got)
+		}
+		if got, ok := pprof.Label(ctx, "status"); !ok || got != "200" {
+			t.Fatalf("expected status label, got %q", got)
+		}
+		if _, ok := pprof.Label(ctx, "user"); ok {
+			t.Fatal("expected unlisted fields not to become labels")
+		}
+	})
+}
+
+func TestProfileLabelsMiddleware(t *testing.T) {
+	var endpoint string
+	handler := sugarzero.ProfileLabelsMiddleware("endpoint")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		endpoint, _ = pprof.Label(r.Context(), "endpoint")
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req = req.WithContext(sugarzero.WithField(req.Context(), "endpoint", "GET /orders"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if endpoint != "GET /orders" {
+		t.Fatalf("expected endpoint label, got %q", endpoint)
+	}
+}
@@ -0,0 +1,92 @@
+package sugarzero
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelSplitWriter sends entries below a threshold level to one writer and
+// entries at or above it to another.
+type LevelSplitWriter struct {
+	low       io.Writer
+	high      io.Writer
+	threshold zerolog.Level
+}
+
+// NewLevelSplitWriter writes entries below level to low and entries at or
+// above level to high.
+// Example: NewLevelSplitWriter(os.Stdout, os.Stderr, "warn")
+func NewLevelSplitWriter(low, high io.Writer, level string) (*LevelSplitWriter, error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelSplitWriter{low: low, high: high, threshold: lvl}, nil
+}
+
+// Write is used for entries without level information and goes to the low writer.
+func (w *LevelSplitWriter) Write(p []byte) (int, error) {
+	return w.low.Write(p)
+}
+
+// WriteLevel routes p according to level.
+func (w *LevelSplitWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level >= w.threshold && level != zerolog.NoLevel {
+		return w.high.Write(p)
+	}
+	return w.low.Write(p)
+}
+
+// WithTwelveFactorOutput writes debug and info entries to stdout and warn and
+// above to stderr as JSON, the split most container orchestrators expect.
+func WithTwelveFactorOutput() Option {
+	return func(o *options) {
+		o.writers = append(o.writers, &LevelSplitWriter{
+			low:       os.Stdout,
+			high:      os.Stderr,
+			threshold: zerolog.WarnLevel,
+		})
+	}
+}
+
+// WithConsoleFormat writes entries as colorless, human-readable lines instead
+// of JSON, for local development. Entry levels are kept, so it combines with
+// level routing such as WithTwelveFactorOutput.
+func WithConsoleFormat() Option {
+	return func(o *options) {
+		o.wrapWriter(func(next io.Writer) (io.Writer, error) {
+			return &consoleWriter{
+				format: zerolog.ConsoleWriter{NoColor: true, TimeFormat: time.RFC3339},
+				next:   next,
+			}, nil
+		})
+	}
+}
+
+// consoleWriter formats entries with a zerolog.ConsoleWriter, which drops the
+// level, and writes each line to next with the level of its entry.
+type consoleWriter struct {
+	format zerolog.ConsoleWriter
+	next   io.Writer
+}
+
+func (w *consoleWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *consoleWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var line bytes.Buffer
+	format := w.format
+	format.Out = &line
+	if _, err := format.Write(p); err != nil {
+		return 0, err
+	}
+	if _, err := writeLevel(w.next, level, line.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
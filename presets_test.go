@@ -0,0 +1,100 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestLevelSplitWriterRoutesByLevel(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var stdout, stderr, mirror bytes.Buffer
+	split, err := sugarzero.NewLevelSplitWriter(&stdout, &stderr, "warn")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, err := sugarzero.New(context.Background(), "debug", split, &mirror)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Debug(ctx, "debug entry")
+	sugarzero.Info(ctx, "info entry")
+	sugarzero.Warn(ctx, "warn entry")
+	sugarzero.Error(ctx, "error entry")
+
+	if out := stdout.String(); !strings.Contains(out, "debug entry") || !strings.Contains(out, "info entry") || strings.Contains(out, "warn entry") {
+		t.Fatalf("unexpected stdout content: %s", out)
+	}
+	if out := stderr.String(); !strings.Contains(out, "warn entry") || !strings.Contains(out, "error entry") || strings.Contains(out, "info entry") {
+		t.Fatalf("unexpected stderr content: %s", out)
+	}
+	if lines := strings.Count(mirror.String(), "\n"); lines != 4 {
+		t.Fatalf("expected plain writer to receive all 4 entries, got %d", lines)
+	}
+}
+
+func TestNewLevelSplitWriterRejectsInvalidLevel(t *testing.T) {
+	if _, err := sugarzero.NewLevelSplitWriter(&bytes.Buffer{}, &bytes.Buffer{}, "loud"); err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+}
+
+func TestLevelSplitWriterThroughWrappers(t *testing.T) {
+	for name, wrapper := range map[string]func(split io.Writer) sugarzero.Option{
+		"console": func(io.Writer) sugarzero.Option {
+			return sugarzero.WithConsoleFormat()
+		},
+		"batch": func(io.Writer) sugarzero.Option {
+			return sugarzero.WithBatching(0, 0, 0)
+		},
+		"tenant": func(split io.Writer) sugarzero.Option {
+			return sugarzero.WithTenantRouting("tenant_id", func(string) (io.Writer, error) {
+				return split, nil
+			})
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			sugarzero.Reset()
+			t.Cleanup(func() {
+				sugarzero.Reset()
+			})
+
+			var stdout, stderr bytes.Buffer
+			split, err := sugarzero.NewLevelSplitWriter(&stdout, &stderr, "warn")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+				sugarzero.WithWriters(split),
+				wrapper(split),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+
+			ctx = sugarzero.WithField(ctx, "tenant_id", "acme")
+			sugarzero.Info(ctx, "info entry")
+			sugarzero.Error(ctx, "error entry")
+			if err := sugarzero.Sync(ctx); err != nil {
+				t.Fatalf("Sync failed: %v", err)
+			}
+
+			if out := stdout.String(); !strings.Contains(out, "info entry") || strings.Contains(out, "error entry") {
+				t.Fatalf("unexpected stdout content: %s", out)
+			}
+			if out := stderr.String(); !strings.Contains(out, "error entry") || strings.Contains(out, "info entry") {
+				t.Fatalf("unexpected stderr content: %s", out)
+			}
+		})
+	}
+}
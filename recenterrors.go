@@ -0,0 +1,83 @@
+package sugarzero
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// WithRecentErrors keeps the last size warn and error entries in memory,
+// including fatal and panic, for RecentErrors and RecentErrorsHandler.
+// Example: NewWithOptions(ctx, "info", WithRecentErrors(50))
+func WithRecentErrors(size int) Option {
+	return func(o *options) {
+		o.recentErrors = size
+	}
+}
+
+// RecentErrors returns the buffered warn and error entries, newest first. It
+// returns nil unless the logger was created with WithRecentErrors.
+func (l *ZeroLogger) RecentErrors() []Entry {
+	if l.events == nil || l.events.recentErrors == nil {
+		return nil
+	}
+	buffered := l.events.recentErrors.snapshot()
+	entries := make([]Entry, 0, len(buffered))
+	for _, raw := range slices.Backward(buffered) {
+		if entry, err := ParseEntry(raw.raw); err == nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// RecentErrors returns the buffered warn and error entries of the logger in
+// ctx, newest first.
+func RecentErrors(ctx context.Context) []Entry {
+	var entries []Entry
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			entries = zl.RecentErrors()
+		}
+	})
+	return entries
+}
+
+// RecentErrorsHandler serves the recent warn and error entries of the logger
+// in ctx as a JSON array, newest first. The limit query parameter caps the
+// number of entries.
+// Example: mux.Handle("/debug/errors", sugarzero.RecentErrorsHandler(ctx))
+func RecentErrorsHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		entries := RecentErrors(ctx)
+		if entries == nil {
+			entries = []Entry{}
+		}
+		if value := r.URL.Query().Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				http.Error(w, "invalid limit", http.StatusBadRequest)
+				return
+			}
+			entries = entries[:min(limit, len(entries))]
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(entries)
+	})
+}
+
+// recordsRecentError reports whether entries at level belong in the recent
+// errors buffer.
+func recordsRecentError(level zerolog.Level) bool {
+	return level >= zerolog.WarnLevel && level <= zerolog.PanicLevel
+}
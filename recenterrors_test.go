@@ -0,0 +1,68 @@
+package sugarzero_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestRecentErrors(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithRecentErrors(2),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Warn(ctx, "evicted")
+	sugarzero.Info(ctx, "not an error")
+	sugarzero.Warn(ctx, "disk 80% full")
+	sugarzero.Error(sugarzero.WithField(ctx, "order_id", "o-1"), "charge failed")
+
+	entries := sugarzero.RecentErrors(ctx)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 entries, got %v", entries)
+	}
+	if entries[0].Message != "charge failed" || entries[0].Level != "error" || entries[0].Fields["order_id"] != "o-1" {
+		t.Fatalf("expected newest error first, got %+v", entries[0])
+	}
+	if entries[1].Message != "disk 80% full" || entries[1].Time.IsZero() {
+		t.Fatalf("unexpected second entry: %+v", entries[1])
+	}
+
+	rec := httptest.NewRecorder()
+	sugarzero.RecentErrorsHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors?limit=1", nil))
+	var served []sugarzero.Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil {
+		t.Fatalf("invalid response %q: %v", rec.Body.String(), err)
+	}
+	if len(served) != 1 || served[0].Message != "charge failed" {
+		t.Fatalf("unexpected response: %s", rec.Body.String())
+	}
+}
+
+func TestRecentErrorsDisabled(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+	sugarzero.Error(ctx, "boom")
+
+	if entries := sugarzero.RecentErrors(ctx); entries != nil {
+		t.Fatalf("expected no entries, got %v", entries)
+	}
+
+	rec := httptest.NewRecorder()
+	sugarzero.RecentErrorsHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors", nil))
+	if body := rec.Body.String(); body != "[]\n" {
+		t.Fatalf("expected empty array, got %q", body)
+	}
+}
@@ -0,0 +1,111 @@
+package sugarzero
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// WithKeyRedaction replaces the values of fields whose key equals one of keys,
+// or starts with one of prefixes, with "[REDACTED]". Keys are compared as
+// emitted, including any WithScope prefix, and case-sensitively. The keys of
+// objects nested in values logged with JSON are redacted the same way.
+//
+// Unlike WithPIIDetection, no value is inspected and no regular expression is
+// involved: the check is a map lookup plus a prefix scan, done once per
+// context when its fields are first encoded. Use it on hot paths, or where a
+// regex engine is not acceptable.
+// Example: WithKeyRedaction([]string{"password", "ssn"}, []string{"secret_"})
+func WithKeyRedaction(keys, prefixes []string) Option {
+	return func(o *options) {
+		if len(keys) == 0 && len(prefixes) == 0 {
+			o.redaction = nil
+			return
+		}
+		exact := make(map[string]struct{}, len(keys))
+		for _, key := range keys {
+			exact[key] = struct{}{}
+		}
+		o.redaction = &keyRedaction{exact: exact, prefixes: append([]string(nil), prefixes...)}
+	}
+}
+
+type keyRedaction struct {
+	exact    map[string]struct{}
+	prefixes []string
+}
+
+func (r *keyRedaction) matches(key string) bool {
+	if _, ok := r.exact[key]; ok {
+		return true
+	}
+	for _, prefix := range r.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON returns data with the values of redacted keys masked in every
+// object, at any depth, or data itself when no key matched. The order of keys
+// is kept.
+func (r *keyRedaction) redactJSON(data []byte) []byte {
+	var out []byte
+	last := 0
+	for start := 0; start < len(data); {
+		begin, end, ok := nextJSONString(data, start)
+		if !ok {
+			break
+		}
+		start = end + 1
+		if !isJSONKey(data, end+1) {
+			continue
+		}
+		var key string
+		if err := json.Unmarshal(data[begin-1:end+1], &key); err != nil || !r.matches(key) {
+			continue
+		}
+		valueStart := end + 1 + bytes.IndexByte(data[end+1:], ':') + 1
+		valueEnd := len(data)
+		dec := json.NewDecoder(bytes.NewReader(data[valueStart:]))
+		var value json.RawMessage
+		if err := dec.Decode(&value); err == nil {
+			valueEnd = valueStart + int(dec.InputOffset())
+		}
+		out = append(out, data[last:valueStart]...)
+		out = append(out, `"`+redactedValue+`"`...)
+		last, start = valueEnd, valueEnd
+	}
+	if out == nil {
+		return data
+	}
+	return append(out, data[last:]...)
+}
+
+// redactFields returns flat with the values of redacted keys masked, and JSON
+// and Diff values rendered with their nested keys redacted. flat itself is never
+// modified.
+func (l *ZeroLogger) redactFields(flat []any) []any {
+	var redacted []any
+	for i := 0; i+1 < len(flat); i += 2 {
+		value := flat[i+1]
+		if key, ok := flat[i].(string); ok && l.redaction != nil && l.redaction.matches(key) {
+			value = redactedValue
+		} else if v, ok := value.(jsonValue); ok {
+			value = v.render(l.redaction)
+		} else if v, ok := value.(diffValue); ok {
+			value = v.render(l.redaction)
+		} else {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]any(nil), flat...)
+		}
+		redacted[i+1] = value
+	}
+	if redacted == nil {
+		return flat
+	}
+	return redacted
+}
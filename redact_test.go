@@ -0,0 +1,87 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithKeyRedactionMasksExactAndPrefixKeys(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithKeyRedaction([]string{"password"}, []string{"secret_"}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx,
+		"password", "hunter2",
+		"secret_token", "abc",
+		"password_hint", "pet name",
+		"user", "alice",
+	)
+	sugarzero.Info(ctx, "login")
+
+	entry := readLogEntry(t, &buf)
+	expected := map[string]any{
+		"password":      "[REDACTED]",
+		"secret_token":  "[REDACTED]",
+		"password_hint": "pet name",
+		"user":          "alice",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+}
+
+func setupRedactionBenchmark(b *testing.B, opts ...sugarzero.Option) context.Context {
+	b.Helper()
+
+	sugarzero.Reset()
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		append([]sugarzero.Option{sugarzero.WithWriters(io.Discard)}, opts...)...)
+	if err != nil {
+		b.Fatalf("Failed to create logger: %v", err)
+	}
+
+	b.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	b.ReportAllocs()
+
+	return ctx
+}
+
+// The redaction benchmarks attach fresh fields on every iteration, as a
+// request handler would, so the per-context redaction cost is included.
+
+func BenchmarkKeyRedaction(b *testing.B) {
+	ctx := setupRedactionBenchmark(b,
+		sugarzero.WithKeyRedaction([]string{"password", "ssn"}, []string{"secret_"}))
+
+	for b.Loop() {
+		reqCtx := sugarzero.WithFields(ctx, "user", "alice@example.com", "password", "hunter2", "secret_key", "abc")
+		sugarzero.Info(reqCtx, "login attempt")
+	}
+}
+
+func BenchmarkPIIDetection(b *testing.B) {
+	ctx := setupRedactionBenchmark(b, sugarzero.WithPIIDetection())
+
+	for b.Loop() {
+		reqCtx := sugarzero.WithFields(ctx, "user", "alice@example.com", "password", "hunter2", "secret_key", "abc")
+		sugarzero.Info(reqCtx, "login attempt")
+	}
+}
@@ -0,0 +1,149 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// LoggerNameFieldName is the key under which named loggers write their name.
+const LoggerNameFieldName = "logger"
+
+// NamedConfig configures one named logger.
+type NamedConfig struct {
+	Level   string
+	Options []Option
+}
+
+var registry = struct {
+	sync.RWMutex
+	loggers map[string]*ZeroLogger
+}{}
+
+// Register creates a logger with its own level and options and stores it
+// under name, replacing any logger previously registered with that name.
+// Entries carry the name in the "logger" field.
+// Example:
+//
+//	sugarzero.Register("access", "info", sugarzero.WithWriters(accessFile))
+//	sugarzero.Info(sugarzero.WithNamed(ctx, "access"), "GET /orders 200")
+func Register(name, level string, opts ...Option) (*ZeroLogger, error) {
+	logger, err := newNamedLogger(name, level, opts)
+	if err != nil {
+		return nil, err
+	}
+	zerologGlobals.Do(setZerologGlobals)
+
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.loggers == nil {
+		registry.loggers = make(map[string]*ZeroLogger)
+	}
+	registry.loggers[name] = logger
+	return logger, nil
+}
+
+// ConfigureNamed registers every logger in configs. Either all loggers are
+// registered or, if any configuration is invalid, none is.
+func ConfigureNamed(configs map[string]NamedConfig) error {
+	names := make([]string, 0, len(configs))
+	for name := range configs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	built := make(map[string]*ZeroLogger, len(configs))
+	for _, name := range names {
+		logger, err := newNamedLogger(name, configs[name].Level, configs[name].Options)
+		if err != nil {
+			return err
+		}
+		built[name] = logger
+	}
+	zerologGlobals.Do(setZerologGlobals)
+
+	registry.Lock()
+	defer registry.Unlock()
+	if registry.loggers == nil {
+		registry.loggers = make(map[string]*ZeroLogger)
+	}
+	for name, logger := range built {
+		registry.loggers[name] = logger
+	}
+	return nil
+}
+
+// Named returns the logger registered under name. If there is none, it falls
+// back to the global logger, and to Nop if New has not been called.
+func Named(name string) Logger {
+	registry.RLock()
+	logger, ok := registry.loggers[name]
+	registry.RUnlock()
+	if ok {
+		return logger
+	}
+	if globalLogger != nil {
+		return globalLogger
+	}
+	return Nop()
+}
+
+// LookupNamed returns the logger registered under name and whether it exists.
+func LookupNamed(name string) (*ZeroLogger, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	logger, ok := registry.loggers[name]
+	return logger, ok
+}
+
+// Unregister removes the logger registered under name, if any. Contexts
+// already carrying it keep logging through it.
+func Unregister(name string) {
+	registry.Lock()
+	defer registry.Unlock()
+	delete(registry.loggers, name)
+}
+
+// NamedLoggers returns the names of the registered loggers, sorted.
+func NamedLoggers() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+	names := make([]string, 0, len(registry.loggers))
+	for name := range registry.loggers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithNamed injects the logger returned by Named(name) into ctx, keeping the
+// fields already attached to ctx.
+func WithNamed(ctx context.Context, name string) context.Context {
+	return WithLogger(ctx, Named(name))
+}
+
+func newNamedLogger(name, level string, opts []Option) (*ZeroLogger, error) {
+	if name == "" {
+		return nil, errors.New("sugarzero: logger name must not be empty")
+	}
+	named := append(append([]Option(nil), opts...), withName(name))
+	logger, err := newZeroLogger(level, named...)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: logger %q: %w", name, err)
+	}
+	return logger, nil
+}
+
+func withName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+func resetRegistry() {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.loggers = nil
+}
@@ -0,0 +1,83 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestNamedLoggersHaveIndependentOutputsAndLevels(t *testing.T) {
+	ctx, appOutput := setupTest(t, "info")
+
+	var access, audit bytes.Buffer
+	err := sugarzero.ConfigureNamed(map[string]sugarzero.NamedConfig{
+		"access": {Level: "info", Options: []sugarzero.Option{sugarzero.WithWriters(&access)}},
+		"audit":  {Level: "debug", Options: []sugarzero.Option{sugarzero.WithWriters(&audit)}},
+	})
+	if err != nil {
+		t.Fatalf("ConfigureNamed failed: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithNamed(ctx, "access"), "GET /orders 200")
+	sugarzero.Debug(sugarzero.WithNamed(sugarzero.WithField(ctx, "actor", "admin"), "audit"), "role granted")
+
+	entry := readLogEntry(t, &access)
+	if entry["message"] != "GET /orders 200" || entry["logger"] != "access" {
+		t.Fatalf("unexpected access entry %v", entry)
+	}
+	entry = readLogEntry(t, &audit)
+	if entry["message"] != "role granted" || entry["actor"] != "admin" || entry["logger"] != "audit" {
+		t.Fatalf("unexpected audit entry %v", entry)
+	}
+	if appOutput.Len() != 0 {
+		t.Fatalf("expected named entries to bypass the default logger, got %s", appOutput.String())
+	}
+
+	if err := sugarzero.SetLogLevel(sugarzero.WithNamed(ctx, "access"), "error"); err != nil {
+		t.Fatalf("SetLogLevel failed: %v", err)
+	}
+	if sugarzero.GetLogLevel(ctx) != "info" {
+		t.Fatal("expected changing a named level to leave the default logger alone")
+	}
+
+	sugarzero.Info(sugarzero.WithNamed(ctx, "unknown"), "falls back")
+	if appOutput.Len() == 0 {
+		t.Fatal("expected unknown names to fall back to the default logger")
+	}
+	if names := sugarzero.NamedLoggers(); len(names) != 2 || names[0] != "access" || names[1] != "audit" {
+		t.Fatalf("unexpected registry names %v", names)
+	}
+}
+
+func TestConfigureNamedIsAllOrNothing(t *testing.T) {
+	setupTest(t, "info")
+
+	err := sugarzero.ConfigureNamed(map[string]sugarzero.NamedConfig{
+		"access": {Level: "info"},
+		"audit":  {Level: "loud"},
+	})
+	if err == nil {
+		t.Fatal("expected error for invalid level")
+	}
+	if _, ok := sugarzero.LookupNamed("access"); ok {
+		t.Fatal("expected no logger to be registered after a failed ConfigureNamed")
+	}
+}
+
+func TestUnregisterRemovesNamedLogger(t *testing.T) {
+	setupTest(t, "info")
+
+	if _, err := sugarzero.Register("access", "info"); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	sugarzero.Unregister("access")
+	sugarzero.Unregister("unknown")
+
+	if _, ok := sugarzero.LookupNamed("access"); ok {
+		t.Fatal("expected access to be unregistered")
+	}
+	if names := sugarzero.NamedLoggers(); len(names) != 0 {
+		t.Fatalf("expected an empty registry, got %v", names)
+	}
+}
@@ -0,0 +1,220 @@
+package sugarzero
+
+import (
+	"slices"
+
+	"github.com/rs/zerolog"
+)
+
+// ReservedKeyPolicy decides what happens to a field whose key collides with a
+// key written by the logger itself, such as level or message.
+type ReservedKeyPolicy int
+
+const (
+	// ReservedKeysAllow writes colliding fields as-is, producing duplicate keys.
+	// This is the default.
+	ReservedKeysAllow ReservedKeyPolicy = iota
+	// ReservedKeysRename moves colliding fields under a "fields." prefix,
+	// e.g. a user "level" field is written as "fields.level".
+	ReservedKeysRename
+	// ReservedKeysDrop discards colliding fields.
+	ReservedKeysDrop
+)
+
+// ReservedFieldPrefix is prepended to colliding keys under ReservedKeysRename.
+const ReservedFieldPrefix = "fields."
+
+// ReservedKeys returns the keys the logger writes itself: the timestamp, level,
+// message, caller position, error, and trace correlation keys.
+func ReservedKeys() []string {
+	return []string{
+		zerolog.TimestampFieldName,
+		zerolog.LevelFieldName,
+		zerolog.MessageFieldName,
+		zerolog.CallerFieldName,
+		zerolog.ErrorFieldName,
+		"trace_id",
+		"span_id",
+	}
+}
+
+// WithReservedKeyPolicy protects reserved keys from being overridden by fields
+// attached with WithFields or WithStaticFields, or added to Raw events.
+// onConflict, when non-nil, is called with the key of every renamed or dropped
+// field so collisions can be surfaced in tests or metrics instead of silently
+// corrupting ingestion.
+// Example: NewWithOptions(ctx, "info", WithReservedKeyPolicy(ReservedKeysRename, nil))
+func WithReservedKeyPolicy(policy ReservedKeyPolicy, onConflict func(key string)) Option {
+	return func(o *options) {
+		o.reserved = reservedKeys{policy: policy, onConflict: onConflict}
+	}
+}
+
+type reservedKeys struct {
+	policy     ReservedKeyPolicy
+	onConflict func(key string)
+}
+
+// apply returns flat with colliding keys renamed or dropped. flat is returned
+// unchanged when there is nothing to do, and copied otherwise.
+func (r reservedKeys) apply(flat []any) []any {
+	if r.policy == ReservedKeysAllow {
+		return flat
+	}
+
+	reserved := ReservedKeys()
+	var out []any
+	for i := 0; i+1 < len(flat); i += 2 {
+		key, _ := flat[i].(string)
+		if !isReservedKey(reserved, key) {
+			if out != nil {
+				out = append(out, flat[i], flat[i+1])
+			}
+			continue
+		}
+
+		if out == nil {
+			out = make([]any, i, len(flat))
+			copy(out, flat[:i])
+		}
+		if r.onConflict != nil {
+			r.onConflict(key)
+		}
+		if r.policy == ReservedKeysRename {
+			out = append(out, ReservedFieldPrefix+key, flat[i+1])
+		}
+	}
+	if out == nil {
+		return flat
+	}
+	return out
+}
+
+// filterEntry returns the encoded entry p with the duplicates of reserved keys
+// renamed or dropped, catching keys added to the event itself, such as on Raw
+// events, which never pass through apply. Of each duplicated key the logger's
+// own occurrence is kept: the last one for the timestamp, caller position, and
+// message, which zerolog writes after every field, and the first one for the
+// others, which are written before the fields added by the caller. p is
+// returned unchanged when there is nothing to do, and copied otherwise.
+func (r reservedKeys) filterEntry(p []byte) []byte {
+	if r.policy == ReservedKeysAllow {
+		return p
+	}
+
+	members := topLevelMembers(p)
+	var conflicts []int
+	for _, key := range ReservedKeys() {
+		var found []int
+		for i, member := range members {
+			if member.key == key {
+				found = append(found, i)
+			}
+		}
+		if len(found) < 2 {
+			continue
+		}
+		switch key {
+		case zerolog.TimestampFieldName, zerolog.CallerFieldName, zerolog.MessageFieldName:
+			found = found[:len(found)-1]
+		default:
+			found = found[1:]
+		}
+		conflicts = append(conflicts, found...)
+	}
+	if len(conflicts) == 0 {
+		return p
+	}
+	slices.Sort(conflicts)
+
+	out := make([]byte, 0, len(p)+len(conflicts)*len(ReservedFieldPrefix))
+	last := 0
+	for _, i := range conflicts {
+		member := members[i]
+		if r.onConflict != nil {
+			r.onConflict(member.key)
+		}
+		if r.policy == ReservedKeysRename {
+			out = append(out, p[last:member.keyBegin]...)
+			out = append(out, ReservedFieldPrefix...)
+			last = member.keyBegin
+			continue
+		}
+		// Drop the member with the comma separating it from its neighbour
+		begin, end := member.start, member.end
+		if p[begin] == '{' {
+			begin++
+			if p[end] == ',' {
+				end++
+			}
+		}
+		out = append(out, p[last:begin]...)
+		last = end
+	}
+	return append(out, p[last:]...)
+}
+
+// entryMember is a top-level member of an encoded entry.
+type entryMember struct {
+	key string
+	// start is the offset of the comma or brace before the member,
+	// keyBegin the offset of the key's contents, and end the offset of the
+	// comma or brace after the value.
+	start, keyBegin, end int
+}
+
+// topLevelMembers returns the members of the JSON object p, without
+// descending into nested objects and arrays.
+func topLevelMembers(p []byte) []entryMember {
+	var (
+		members []entryMember
+		depth   int
+		start   int
+		open    bool
+	)
+	for i := 0; i < len(p); {
+		switch p[i] {
+		case '"':
+			begin, end, ok := nextJSONString(p, i)
+			if !ok {
+				return members
+			}
+			if depth == 1 && isJSONKey(p, end+1) {
+				members = append(members, entryMember{key: string(p[begin:end]), start: start, keyBegin: begin})
+				open = true
+			}
+			i = end + 1
+			continue
+		case '{', '[':
+			if depth == 0 {
+				start = i
+			}
+			depth++
+		case '}', ']':
+			if depth == 1 && open {
+				members[len(members)-1].end = i
+				open = false
+			}
+			depth--
+		case ',':
+			if depth == 1 {
+				if open {
+					members[len(members)-1].end = i
+					open = false
+				}
+				start = i
+			}
+		}
+		i++
+	}
+	return members
+}
+
+func isReservedKey(reserved []string, key string) bool {
+	for _, candidate := range reserved {
+		if key == candidate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestReservedKeyPolicyRename(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var conflicts []string
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithReservedKeyPolicy(sugarzero.ReservedKeysRename, func(key string) {
+			conflicts = append(conflicts, key)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx, "level", "gold", "user_id", "u-1", "message", "hijacked")
+	sugarzero.Info(ctx, "checkout")
+
+	entry := readLogEntry(t, &buf)
+	if entry["level"] != "INFO" || entry["message"] != "checkout" {
+		t.Fatalf("expected reserved keys to be untouched, got %v", entry)
+	}
+	if entry["fields.level"] != "gold" || entry["fields.message"] != "hijacked" {
+		t.Fatalf("expected colliding fields to be renamed, got %v", entry)
+	}
+	if entry["user_id"] != "u-1" {
+		t.Fatalf("expected regular fields to be kept, got %v", entry)
+	}
+	if len(conflicts) != 2 || conflicts[0] != "level" || conflicts[1] != "message" {
+		t.Fatalf("expected conflicts for level and message, got %v", conflicts)
+	}
+}
+
+func TestReservedKeyPolicyDrop(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithReservedKeyPolicy(sugarzero.ReservedKeysDrop, nil),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithFields(ctx, "trace_id", "forged", "order_id", "o-1"), "placed")
+
+	entry := readLogEntry(t, &buf)
+	if _, ok := entry["trace_id"]; ok {
+		t.Fatalf("expected trace_id to be dropped, got %v", entry)
+	}
+	if entry["order_id"] != "o-1" {
+		t.Fatalf("expected order_id to be kept, got %v", entry)
+	}
+}
+
+func TestReservedKeyPolicyCoversStaticFieldsAndRawEvents(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var conflicts []string
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithStaticFields("level", "static", "service", "billing"),
+		sugarzero.WithReservedKeyPolicy(sugarzero.ReservedKeysRename, func(key string) {
+			conflicts = append(conflicts, key)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Raw(ctx, zerolog.WarnLevel).
+		Str("level", "raw").
+		Str("time", "forged").
+		Dict("request", zerolog.Dict().Str("message", "nested")).
+		Str("message", "hijacked").
+		Msg("quota exceeded")
+
+	out := buf.String()
+	entry := readLogEntry(t, &buf)
+	if entry["level"] != "WARN" || entry["message"] != "quota exceeded" || entry["time"] == "forged" {
+		t.Fatalf("expected reserved keys to be untouched, got %v", entry)
+	}
+	if entry["fields.level"] != "raw" || entry["fields.time"] != "forged" || entry["fields.message"] != "hijacked" {
+		t.Fatalf("expected colliding Raw fields to be renamed, got %v", entry)
+	}
+	if request, _ := entry["request"].(map[string]any); request["message"] != "nested" {
+		t.Fatalf("expected nested keys to be kept, got %v", entry)
+	}
+	if entry["service"] != "billing" || !strings.Contains(out, `"fields.level":"static"`) {
+		t.Fatalf("expected the static level field to be renamed, got %s", out)
+	}
+	if len(conflicts) != 4 {
+		t.Fatalf("expected the static and Raw conflicts to be reported, got %v", conflicts)
+	}
+}
+
+func TestReservedKeyPolicyDropsRawDuplicates(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithReservedKeyPolicy(sugarzero.ReservedKeysDrop, nil),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Raw(ctx, zerolog.InfoLevel).Str("level", "raw").Str("order_id", "o-1").Str("message", "hijacked").Msg("placed")
+
+	out := buf.String()
+	if strings.Count(out, `"level"`) != 1 || strings.Count(out, `"message"`) != 1 {
+		t.Fatalf("expected duplicated keys to be dropped, got %s", out)
+	}
+	entry := readLogEntry(t, &buf)
+	if entry["level"] != "INFO" || entry["message"] != "placed" || entry["order_id"] != "o-1" {
+		t.Fatalf("unexpected entry %v", entry)
+	}
+}
@@ -0,0 +1,22 @@
+package sugarzero
+
+import (
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// WithOTelResource writes every attribute of res, such as service.name,
+// service.version, and deployment.environment, on every entry, so log
+// metadata matches the metadata of the traces the service exports. Keys keep
+// their OpenTelemetry names. A nil resource adds nothing.
+// Example: NewWithOptions(ctx, "info", WithOTelResource(res))
+func WithOTelResource(res *resource.Resource) Option {
+	return func(o *options) {
+		if res == nil {
+			return
+		}
+		for iter := res.Iter(); iter.Next(); {
+			attr := iter.Attribute()
+			o.fields = append(o.fields, string(attr.Key), attr.Value.AsInterface())
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestWithOTelResourceAddsResourceAttributes(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", "checkout"),
+		attribute.String("service.version", "1.4.2"),
+		attribute.String("deployment.environment", "staging"),
+		attribute.Int("service.instance.shard", 3),
+	)
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithOTelResource(res),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "order_id", "o-1"), "order placed")
+
+	entry := readLogEntry(t, &buf)
+	expected := map[string]any{
+		"service.name":           "checkout",
+		"service.version":        "1.4.2",
+		"deployment.environment": "staging",
+		"service.instance.shard": float64(3),
+		"order_id":               "o-1",
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+}
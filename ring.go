@@ -0,0 +1,86 @@
+package sugarzero
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// ringWatcherBuffer is the number of live entries queued per watcher, such
+// as a TailHandler client; entries for watchers that fall further behind are
+// dropped.
+const ringWatcherBuffer = 256
+
+type ringEntry struct {
+	level zerolog.Level
+	raw   []byte
+}
+
+// entryRing is a ring of the most recent entries that also fans new entries
+// out to watchers.
+type entryRing struct {
+	mu       sync.Mutex
+	entries  []ringEntry
+	next     int
+	full     bool
+	watchers map[chan ringEntry]struct{}
+}
+
+func newEntryRing(size int) *entryRing {
+	return &entryRing{
+		entries:  make([]ringEntry, size),
+		watchers: make(map[chan ringEntry]struct{}),
+	}
+}
+
+func (r *entryRing) add(level zerolog.Level, p []byte) {
+	entry := ringEntry{level: level, raw: bytes.TrimRight(bytes.Clone(p), "\n")}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries[r.next] = entry
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	for ch := range r.watchers {
+		select {
+		case ch <- entry:
+		default:
+		}
+	}
+}
+
+// snapshot returns the buffered entries, oldest first.
+func (r *entryRing) snapshot() []ringEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked()
+}
+
+func (r *entryRing) snapshotLocked() []ringEntry {
+	if !r.full {
+		return append([]ringEntry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]ringEntry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// watch returns the buffered entries and a channel receiving every later
+// entry until cancel is called.
+func (r *entryRing) watch() ([]ringEntry, <-chan ringEntry, func()) {
+	ch := make(chan ringEntry, ringWatcherBuffer)
+	r.mu.Lock()
+	backlog := r.snapshotLocked()
+	r.watchers[ch] = struct{}{}
+	r.mu.Unlock()
+
+	var once sync.Once
+	return backlog, ch, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.watchers, ch)
+			r.mu.Unlock()
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package sugarzero
+
+import (
+	"context"
+	"os"
+	"runtime"
+	"time"
+)
+
+// DefaultRuntimeStatsInterval is used by StartRuntimeStatsLogger when no
+// interval is provided.
+const DefaultRuntimeStatsInterval = time.Minute
+
+// StartRuntimeStatsLogger logs heap, GC, goroutine, and file descriptor
+// statistics at info level every interval until ctx is canceled. An
+// interval <= 0 uses DefaultRuntimeStatsInterval. File descriptors are only
+// reported on systems exposing /proc/self/fd. Nothing is collected while
+// FeatureRuntimeStats is disabled.
+// Example:
+//
+//	ctx, cancel := context.WithCancel(ctx)
+//	defer cancel()
+//	sugarzero.StartRuntimeStatsLogger(ctx, 30*time.Second)
+func StartRuntimeStatsLogger(ctx context.Context, interval time.Duration) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if interval <= 0 {
+		interval = DefaultRuntimeStatsInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if FeatureEnabled(FeatureRuntimeStats) {
+					logRuntimeStats(ctx)
+				}
+			}
+		}
+	}()
+}
+
+func logRuntimeStats(ctx context.Context) {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	fields := []any{
+		"heap_alloc_bytes", mem.HeapAlloc,
+		"heap_inuse_bytes", mem.HeapInuse,
+		"heap_objects", mem.HeapObjects,
+		"sys_bytes", mem.Sys,
+		"gc_count", mem.NumGC,
+		"gc_pause_total_ms", float64(mem.PauseTotalNs) / float64(time.Millisecond),
+		"goroutines", runtime.NumGoroutine(),
+	}
+	if mem.NumGC > 0 {
+		lastPause := mem.PauseNs[(mem.NumGC+255)%256]
+		fields = append(fields, "gc_pause_last_ms", float64(lastPause)/float64(time.Millisecond))
+	}
+	if fds, ok := openFileDescriptors(); ok {
+		fields = append(fields, "open_fds", fds)
+	}
+
+	Info(withUnscopedFields(ctx, fields...), "runtime stats")
+}
+
+// openFileDescriptors counts the process's open file descriptors where the
+// platform exposes them.
+func openFileDescriptors() (int, bool) {
+	entries, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return 0, false
+	}
+	return len(entries), true
+}
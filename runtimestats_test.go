@@ -0,0 +1,40 @@
+package sugarzero_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestRuntimeStatsLoggerEmitsUntilCanceled(t *testing.T) {
+	mock := sugarzero.NewMock()
+	ctx, cancel := context.WithCancel(sugarzero.WithLogger(context.Background(), mock))
+	defer cancel()
+
+	sugarzero.StartRuntimeStatsLogger(ctx, 5*time.Millisecond)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !mock.Contains("info", "runtime stats") && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	entries := mock.Entries()
+	if len(entries) == 0 {
+		t.Fatal("expected runtime stats entries")
+	}
+	for _, key := range []string{"heap_alloc_bytes", "gc_count", "goroutines"} {
+		if _, ok := entries[0].Fields[key]; !ok {
+			t.Fatalf("expected %s field, got %v", key, entries[0].Fields)
+		}
+	}
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	mock.Reset()
+	time.Sleep(20 * time.Millisecond)
+	if n := len(mock.Entries()); n != 0 {
+		t.Fatalf("expected no entries after cancel, got %d", n)
+	}
+}
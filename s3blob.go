@@ -0,0 +1,193 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Config locates the bucket an S3BlobStore writes to and the credentials it
+// signs requests with.
+type S3Config struct {
+	Bucket string
+	Region string
+	// Prefix is prepended to blob keys, e.g. "logs/blobs/".
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is set for temporary credentials.
+	SessionToken string
+	// Endpoint replaces the AWS endpoint with an S3-compatible one, such as
+	// MinIO, addressed with path-style URLs, e.g. "http://localhost:9000".
+	Endpoint string
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+}
+
+// S3BlobStore is a BlobStore writing blobs as objects in an S3 bucket with
+// requests signed with AWS Signature Version 4, without depending on the AWS
+// SDK. Put returns the s3:// URL of the object.
+// Example: store, err := NewS3BlobStore(S3Config{Bucket: "logs", Region: "eu-west-1", AccessKeyID: id, SecretAccessKey: secret})
+type S3BlobStore struct {
+	cfg S3Config
+}
+
+// NewS3BlobStore returns an S3BlobStore for cfg.
+func NewS3BlobStore(cfg S3Config) (*S3BlobStore, error) {
+	switch {
+	case cfg.Bucket == "":
+		return nil, errors.New("sugarzero: S3 bucket must not be empty")
+	case cfg.Region == "":
+		return nil, errors.New("sugarzero: S3 region must not be empty")
+	case cfg.AccessKeyID == "" || cfg.SecretAccessKey == "":
+		return nil, errors.New("sugarzero: S3 credentials must not be empty")
+	}
+	return &S3BlobStore{cfg: cfg}, nil
+}
+
+// Put writes data to the object Prefix+key.
+func (s *S3BlobStore) Put(ctx context.Context, key string, data []byte) (string, error) {
+	object := s.cfg.Prefix + key
+	target := &url.URL{Scheme: "https", Host: s.cfg.Bucket + ".s3." + s.cfg.Region + ".amazonaws.com", Path: "/" + object}
+	if s.cfg.Endpoint != "" {
+		endpoint, err := url.Parse(s.cfg.Endpoint)
+		if err != nil {
+			return "", fmt.Errorf("sugarzero: invalid S3 endpoint: %w", err)
+		}
+		target = endpoint.JoinPath(s.cfg.Bucket, object)
+	}
+	target.RawPath = awsEscapePath(target.Path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return "", fmt.Errorf("sugarzero: failed to create S3 request: %w", err)
+	}
+	if s.cfg.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.cfg.SessionToken)
+	}
+	signV4(req, data, s.cfg.AccessKeyID, s.cfg.SecretAccessKey, s.cfg.Region, "s3", time.Now())
+
+	if err := sendBlobRequest(s.cfg.Client, req); err != nil {
+		return "", fmt.Errorf("sugarzero: failed to put S3 object %q: %w", object, err)
+	}
+	return s.Locate(key), nil
+}
+
+// Locate returns the s3:// URL Put returns for key.
+func (s *S3BlobStore) Locate(key string) string {
+	return "s3://" + s.cfg.Bucket + "/" + s.cfg.Prefix + key
+}
+
+// signV4 signs req and its payload with AWS Signature Version 4, covering
+// the host and every header already set.
+func signV4(req *http.Request, payload []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := slices.Sorted(maps.Keys(headers))
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	query := req.URL.Query()
+	params := make([]string, 0, len(query))
+	for name, values := range query {
+		for _, value := range values {
+			params = append(params, awsEscape(name)+"="+awsEscape(value))
+		}
+	}
+	slices.Sort(params)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.Join(params, "&"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := []byte("AWS4" + secretAccessKey)
+	for _, part := range []string{date, region, service, "aws4_request"} {
+		signingKey = hmacSHA256(signingKey, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKeyID+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+// awsEscape percent-encodes every byte of s except the unreserved characters
+// A-Z, a-z, 0-9, '-', '.', '_', and '~', as AWS signatures require.
+func awsEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// awsEscapePath escapes each segment of path with awsEscape.
+func awsEscapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sendBlobRequest sends req with client, or http.DefaultClient, and returns
+// an error for responses other than 2xx, including the start of their body.
+func sendBlobRequest(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,77 @@
+package sugarzero_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestS3BlobStorePutsSignedObjects(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	store, err := sugarzero.NewS3BlobStore(sugarzero.S3Config{
+		Bucket:          "logs",
+		Region:          "eu-west-1",
+		Prefix:          "blobs/",
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Endpoint:        server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewS3BlobStore failed: %v", err)
+	}
+
+	ref, err := store.Put(context.Background(), "abc", []byte("payload"))
+	if err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if ref != "s3://logs/blobs/abc" {
+		t.Fatalf("unexpected reference %q", ref)
+	}
+	if got.Method != http.MethodPut || got.URL.Path != "/logs/blobs/abc" || string(body) != "payload" {
+		t.Fatalf("unexpected request %s %s %q", got.Method, got.URL.Path, body)
+	}
+	sum := sha256.Sum256([]byte("payload"))
+	if got.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(sum[:]) || got.Header.Get("X-Amz-Security-Token") != "session" {
+		t.Fatalf("unexpected headers %v", got.Header)
+	}
+	auth := got.Header.Get("Authorization")
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") ||
+		!strings.Contains(auth, "/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=") {
+		t.Fatalf("unexpected Authorization %q", auth)
+	}
+}
+
+func TestS3BlobStoreReportsErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "<Code>AccessDenied</Code>", http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	store, err := sugarzero.NewS3BlobStore(sugarzero.S3Config{
+		Bucket: "logs", Region: "eu-west-1", AccessKeyID: "id", SecretAccessKey: "secret", Endpoint: server.URL,
+	})
+	if err != nil {
+		t.Fatalf("NewS3BlobStore failed: %v", err)
+	}
+	if _, err := store.Put(context.Background(), "abc", []byte("payload")); err == nil || !strings.Contains(err.Error(), "AccessDenied") {
+		t.Fatalf("expected the S3 error, got %v", err)
+	}
+	if _, err := sugarzero.NewS3BlobStore(sugarzero.S3Config{Bucket: "logs", Region: "eu-west-1"}); err == nil {
+		t.Fatal("expected an error for missing credentials")
+	}
+}
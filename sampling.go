@@ -0,0 +1,178 @@
+package sugarzero
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultSamplingWindow is the window over which adaptive sampling measures
+// volume when no window is configured.
+const DefaultSamplingWindow = time.Second
+
+// SamplingConfig configures adaptive sampling.
+type SamplingConfig struct {
+	// EventsPerSecond is the volume budget. When the previous window exceeded
+	// it, each message key keeps one in every N entries, with N chosen so the
+	// total fits the budget again.
+	EventsPerSecond int `json:"events_per_second" yaml:"events_per_second"`
+	// Window is how often rates are recomputed, e.g. "1s" in configuration
+	// files. Defaults to DefaultSamplingWindow.
+	Window Duration `json:"window" yaml:"window"`
+	// KeepLevel is the level from which entries are never sampled.
+	// Defaults to "warn".
+	KeepLevel string `json:"keep_level" yaml:"keep_level"`
+}
+
+// WithAdaptiveSampling enables volume-driven sampling. Entries are grouped by
+// level and message key (the dedup key set with WithDedupKey, else the format
+// string or the message of single-string calls), and every key keeps its
+// first entry per window so rare messages are never lost. When a window ends,
+// a "sampled N similar events" summary is logged for every key that was
+// thinned out. Summaries are emitted on the first log call of the following
+// window. Critical entries (WithSync), entries of the CategorySecurity
+// category, and Raw events are never sampled.
+func WithAdaptiveSampling(cfg SamplingConfig) Option {
+	return func(o *options) {
+		o.sampling = &cfg
+	}
+}
+
+type samplingKey struct {
+	level zerolog.Level
+	key   string
+}
+
+type adaptiveSampler struct {
+	budget    int
+	window    time.Duration
+	keepLevel zerolog.Level
+	now       func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	total       int
+	rate        int
+	seen        map[samplingKey]int
+	suppressed  map[samplingKey]int
+}
+
+// sampledSummary reports entries dropped for one key during a window.
+type sampledSummary struct {
+	samplingKey
+	count int
+}
+
+func newAdaptiveSampler(cfg *SamplingConfig) (*adaptiveSampler, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if cfg.EventsPerSecond <= 0 {
+		return nil, errors.New("sugarzero: sampling budget must be positive")
+	}
+	window := time.Duration(cfg.Window)
+	if window <= 0 {
+		window = DefaultSamplingWindow
+	}
+	keepLevel := zerolog.WarnLevel
+	if cfg.KeepLevel != "" {
+		lvl, err := parseLevel(cfg.KeepLevel)
+		if err != nil {
+			return nil, fmt.Errorf("sugarzero: sampling keep level: %w", err)
+		}
+		keepLevel = lvl
+	}
+	return &adaptiveSampler{
+		budget:      max(1, int(float64(cfg.EventsPerSecond)*window.Seconds())),
+		window:      window,
+		keepLevel:   keepLevel,
+		now:         time.Now,
+		windowStart: time.Now(),
+		rate:        1,
+		seen:        make(map[samplingKey]int),
+		suppressed:  make(map[samplingKey]int),
+	}, nil
+}
+
+// allow reports whether the entry should be written, along with the
+// summaries of the window that just ended, if any.
+func (s *adaptiveSampler) allow(level zerolog.Level, key string) (bool, []sampledSummary) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var summaries []sampledSummary
+	if now := s.now(); now.Sub(s.windowStart) >= s.window {
+		summaries = s.roll(now)
+	}
+
+	s.total++
+	if level >= s.keepLevel && level != zerolog.NoLevel {
+		return true, summaries
+	}
+
+	k := samplingKey{level: level, key: key}
+	seen := s.seen[k]
+	s.seen[k] = seen + 1
+	if seen%s.rate == 0 {
+		return true, summaries
+	}
+	s.suppressed[k]++
+	return false, summaries
+}
+
+// roll starts a new window, deriving its rate from the volume of the last one.
+func (s *adaptiveSampler) roll(now time.Time) []sampledSummary {
+	var summaries []sampledSummary
+	for k, count := range s.suppressed {
+		summaries = append(summaries, sampledSummary{samplingKey: k, count: count})
+	}
+
+	// Only whole idle windows reset the rate; a single busy window keeps it
+	if now.Sub(s.windowStart) >= 2*s.window {
+		s.rate = 1
+	} else {
+		s.rate = max(1, (s.total+s.budget-1)/s.budget)
+	}
+	s.windowStart = now
+	s.total = 0
+	clear(s.seen)
+	clear(s.suppressed)
+	return summaries
+}
+
+// sample applies the sampler to an entry, logging any pending summaries.
+func (l *ZeroLogger) sample(ctx context.Context, level zerolog.Level, key string) bool {
+	if isSync(ctx) || categoryFromContext(ctx) == CategorySecurity {
+		return true
+	}
+	if dedup := DedupKeyFromContext(ctx); dedup != "" {
+		key = dedup
+	}
+	allowed, summaries := l.sampler.allow(level, key)
+	if len(summaries) > 0 {
+		l.mu.RLock()
+		logger := l.logger
+		l.mu.RUnlock()
+		for _, summary := range summaries {
+			logger.WithLevel(summary.level).
+				Str("sample_key", summary.key).
+				Int("sampled", summary.count).
+				Msgf("sampled %d similar events", summary.count)
+		}
+	}
+	return allowed
+}
+
+// sampleKey derives the message key of a non-format log call.
+func sampleKey(args []any) string {
+	if len(args) > 0 {
+		if msg, ok := args[0].(string); ok {
+			return msg
+		}
+	}
+	return ""
+}
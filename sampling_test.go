@@ -0,0 +1,104 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestAdaptiveSamplingThinsSpikesAndSummarizes(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	window := 50 * time.Millisecond
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{EventsPerSecond: 200, Window: sugarzero.Duration(window)}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// The first window is unsampled and sets the rate for the next one
+	for i := 0; i < 100; i++ {
+		sugarzero.Infof(ctx, "cache miss %d", i)
+	}
+	time.Sleep(window)
+
+	buf.Reset()
+	for i := 0; i < 100; i++ {
+		sugarzero.Infof(ctx, "cache miss %d", i)
+	}
+	sugarzero.Info(ctx, "rare event")
+	sugarzero.Error(ctx, "always kept")
+
+	out := buf.String()
+	misses := strings.Count(out, "cache miss")
+	if misses == 0 || misses >= 100 {
+		t.Fatalf("expected cache misses to be sampled, got %d", misses)
+	}
+	if !strings.Contains(out, "rare event") || !strings.Contains(out, "always kept") {
+		t.Fatalf("expected rare and error entries to be kept, got %s", out)
+	}
+
+	time.Sleep(window)
+	buf.Reset()
+	sugarzero.Info(ctx, "next window")
+	if !strings.Contains(buf.String(), "similar events") || !strings.Contains(buf.String(), `"sample_key":"cache miss %d"`) {
+		t.Fatalf("expected a sampling summary, got %s", buf.String())
+	}
+}
+
+func TestAdaptiveSamplingKeepsSecurityEntries(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	window := 50 * time.Millisecond
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{EventsPerSecond: 200, Window: sugarzero.Duration(window)}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	security := sugarzero.WithCategory(ctx, sugarzero.CategorySecurity)
+
+	// The first window is unsampled and sets the rate for the next one
+	for i := 0; i < 100; i++ {
+		sugarzero.Warnf(security, "login failed %d", i)
+	}
+	time.Sleep(window)
+
+	buf.Reset()
+	for i := 0; i < 100; i++ {
+		sugarzero.Warnf(security, "login failed %d", i)
+	}
+	if failures := strings.Count(buf.String(), "login failed"); failures != 100 {
+		t.Fatalf("expected every security entry to be kept, got %d", failures)
+	}
+}
+
+func TestAdaptiveSamplingRejectsInvalidBudget(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{}),
+	)
+	if err == nil {
+		t.Fatal("expected error for zero sampling budget")
+	}
+}
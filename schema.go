@@ -0,0 +1,266 @@
+package sugarzero
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// SchemaViolation describes an emitted entry that does not match the schema
+// configured with WithSchema.
+type SchemaViolation struct {
+	// Entry is the raw encoded entry.
+	Entry []byte
+	// Problems lists every mismatch, e.g. `missing required field "service"`.
+	Problems []string
+}
+
+// jsonSchema is the subset of JSON Schema understood by WithSchema.
+type jsonSchema struct {
+	Type                 schemaTypes            `json:"type"`
+	Required             []string               `json:"required"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	AdditionalProperties *bool                  `json:"additionalProperties"`
+	Items                *jsonSchema            `json:"items"`
+	Enum                 []any                  `json:"enum"`
+}
+
+// schemaKeywords are the keywords understood by jsonSchema; annotations,
+// which never affect validation, are accepted too.
+var schemaKeywords = map[string]bool{
+	"type": true, "required": true, "properties": true, "additionalProperties": true, "items": true, "enum": true,
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true, "default": true, "examples": true,
+}
+
+// schemaTypes accepts both "type": "string" and "type": ["string", "null"].
+type schemaTypes []string
+
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// WithSchema validates every emitted entry against a JSON Schema and reports
+// mismatches to onViolation. Entries are always written, valid or not. This is
+// meant for development and CI, where catching schema drift early matters more
+// than the cost of decoding each entry.
+//
+// The supported keywords are type, required, properties,
+// additionalProperties (boolean form), items, and enum, besides annotations
+// such as title and description. Schemas using any other keyword, such as
+// pattern, format, or oneOf, fail logger construction rather than silently
+// passing entries they would reject.
+func WithSchema(schema []byte, onViolation func(SchemaViolation)) Option {
+	return func(o *options) {
+		o.wrapWriter(func(next io.Writer) (io.Writer, error) {
+			parsed, err := compileSchema(schema)
+			if err != nil {
+				return nil, fmt.Errorf("sugarzero: invalid log schema: %w", err)
+			}
+			return &schemaWriter{next: next, schema: parsed, onViolation: onViolation}, nil
+		})
+	}
+}
+
+// compileSchema parses data, rejecting keywords jsonSchema does not support.
+func compileSchema(data []byte) (*jsonSchema, error) {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if err := checkSchemaKeywords("", raw); err != nil {
+		return nil, err
+	}
+	var parsed jsonSchema
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// checkSchemaKeywords reports the first unsupported keyword in the schema at
+// path, or in the schemas nested in its properties and items.
+func checkSchemaKeywords(path string, schema any) error {
+	object, ok := schema.(map[string]any)
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !schemaKeywords[key] {
+			return fmt.Errorf("%s: unsupported keyword %q", displayPath(path), key)
+		}
+	}
+	if properties, ok := object["properties"].(map[string]any); ok {
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := checkSchemaKeywords(joinPath(path, name), properties[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return checkSchemaKeywords(path+"[]", object["items"])
+}
+
+type schemaWriter struct {
+	next        io.Writer
+	schema      *jsonSchema
+	onViolation func(SchemaViolation)
+}
+
+func (w *schemaWriter) Write(p []byte) (int, error) {
+	w.check(p)
+	return w.next.Write(p)
+}
+
+func (w *schemaWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.check(p)
+	return writeLevel(w.next, level, p)
+}
+
+func (w *schemaWriter) check(p []byte) {
+	if w.onViolation == nil {
+		return
+	}
+	if problems := w.validate(p); len(problems) > 0 {
+		entry := append([]byte(nil), bytes.TrimRight(p, "\n")...)
+		w.onViolation(SchemaViolation{Entry: entry, Problems: problems})
+	}
+}
+
+func (w *schemaWriter) validate(p []byte) []string {
+	decoder := json.NewDecoder(bytes.NewReader(p))
+	decoder.UseNumber()
+	var entry any
+	if err := decoder.Decode(&entry); err != nil {
+		return []string{fmt.Sprintf("entry is not valid JSON: %v", err)}
+	}
+	var problems []string
+	w.schema.validate("", entry, &problems)
+	return problems
+}
+
+func (s *jsonSchema) validate(path string, value any, problems *[]string) {
+	if len(s.Type) > 0 && !s.matchesType(value) {
+		*problems = append(*problems, fmt.Sprintf("%s: expected type %s, got %s", displayPath(path), strings.Join(s.Type, " or "), jsonTypeOf(value)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !s.matchesEnum(value) {
+		*problems = append(*problems, fmt.Sprintf("%s: value %v is not one of %v", displayPath(path), value, s.Enum))
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for _, key := range s.Required {
+			if _, ok := v[key]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s: missing required field %q", displayPath(path), key))
+			}
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			child, ok := s.Properties[key]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					*problems = append(*problems, fmt.Sprintf("%s: unexpected field %q", displayPath(path), key))
+				}
+				continue
+			}
+			child.validate(joinPath(path, key), v[key], problems)
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	}
+}
+
+func (s *jsonSchema) matchesType(value any) bool {
+	actual := jsonTypeOf(value)
+	for _, want := range s.Type {
+		if want == actual || (want == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *jsonSchema) matchesEnum(value any) bool {
+	for _, allowed := range s.Enum {
+		if number, ok := value.(json.Number); ok {
+			if f, err := number.Float64(); err == nil && reflect.DeepEqual(f, allowed) {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(value, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := v.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "entry"
+	}
+	return path
+}
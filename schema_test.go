@@ -0,0 +1,103 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+const orderSchema = `{
+	"type": "object",
+	"required": ["level", "message", "service"],
+	"properties": {
+		"service": {"type": "string"},
+		"status":  {"type": "integer", "enum": [200, 404, 500]}
+	}
+}`
+
+func TestWithSchemaReportsViolations(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var violations []sugarzero.SchemaViolation
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithSchema([]byte(orderSchema), func(v sugarzero.SchemaViolation) {
+			violations = append(violations, v)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithFields(ctx, "service", "orders", "status", 200), "valid entry")
+	if len(violations) != 0 {
+		t.Fatalf("expected no violations, got %+v", violations)
+	}
+
+	sugarzero.Info(sugarzero.WithFields(ctx, "status", "ok"), "invalid entry")
+	if len(violations) != 1 {
+		t.Fatalf("expected one violation, got %+v", violations)
+	}
+
+	problems := strings.Join(violations[0].Problems, "\n")
+	if !strings.Contains(problems, `missing required field "service"`) {
+		t.Fatalf("expected missing field problem, got %s", problems)
+	}
+	if !strings.Contains(problems, "status: expected type integer, got string") {
+		t.Fatalf("expected type problem, got %s", problems)
+	}
+	if !strings.Contains(buf.String(), "invalid entry") {
+		t.Fatal("expected invalid entries to still be written")
+	}
+}
+
+func TestWithSchemaRejectsInvalidSchema(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithSchema([]byte(`{"type": 5}`), nil),
+	)
+	if err == nil {
+		t.Fatal("expected error for invalid schema")
+	}
+}
+
+func TestWithSchemaRejectsUnsupportedKeywords(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	for schema, keyword := range map[string]string{
+		`{"properties": {"service": {"type": "string", "pattern": "^api-"}}}`: `service: unsupported keyword "pattern"`,
+		`{"properties": {"ids": {"items": {"format": "uuid"}}}}`:              `ids[]: unsupported keyword "format"`,
+		`{"oneOf": [{"required": ["a"]}, {"required": ["b"]}]}`:               `entry: unsupported keyword "oneOf"`,
+	} {
+		_, err := sugarzero.NewWithOptions(context.Background(), "info",
+			sugarzero.WithWriters(&bytes.Buffer{}),
+			sugarzero.WithSchema([]byte(schema), nil),
+		)
+		if err == nil || !strings.Contains(err.Error(), keyword) {
+			t.Fatalf("expected %s for %s, got %v", keyword, schema, err)
+		}
+	}
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithSchema([]byte(`{"$schema": "https://json-schema.org/draft/2020-12/schema", "title": "log entry", "type": "object"}`), nil),
+	)
+	if err != nil {
+		t.Fatalf("expected annotations to be accepted, got %v", err)
+	}
+}
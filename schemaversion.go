@@ -0,0 +1,109 @@
+package sugarzero
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// SchemaVersionFieldName holds the version of the field layout of an entry.
+const SchemaVersionFieldName = "schema_version"
+
+// SchemaVersion is a MAJOR.MINOR version of a log field layout. Bump the
+// minor version for additive changes such as new fields, and the major
+// version when the meaning or type of an existing field changes.
+type SchemaVersion struct {
+	Major int
+	Minor int
+}
+
+// ParseSchemaVersion parses "MAJOR.MINOR" or "MAJOR", e.g. "2.1" or "3".
+func ParseSchemaVersion(s string) (SchemaVersion, error) {
+	majorText, minorText, hasMinor := strings.Cut(s, ".")
+	major, err := strconv.Atoi(majorText)
+	if err != nil || major < 0 {
+		return SchemaVersion{}, fmt.Errorf("sugarzero: invalid schema version %q: must be MAJOR or MAJOR.MINOR", s)
+	}
+	var minor int
+	if hasMinor {
+		minor, err = strconv.Atoi(minorText)
+		if err != nil || minor < 0 {
+			return SchemaVersion{}, fmt.Errorf("sugarzero: invalid schema version %q: must be MAJOR or MAJOR.MINOR", s)
+		}
+	}
+	return SchemaVersion{Major: major, Minor: minor}, nil
+}
+
+// String returns the version as "MAJOR.MINOR".
+func (v SchemaVersion) String() string {
+	return strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor)
+}
+
+// BumpMinor returns the version after an additive change.
+func (v SchemaVersion) BumpMinor() SchemaVersion {
+	return SchemaVersion{Major: v.Major, Minor: v.Minor + 1}
+}
+
+// BumpMajor returns the version after a breaking change.
+func (v SchemaVersion) BumpMajor() SchemaVersion {
+	return SchemaVersion{Major: v.Major + 1}
+}
+
+// CanRead reports whether a parser written for v can read entries written
+// at version entry: the major versions match and entry adds at most fields
+// the parser ignores.
+func (v SchemaVersion) CanRead(entry SchemaVersion) bool {
+	return v.Major == entry.Major
+}
+
+// WithSchemaVersion writes version in the schema_version field of every
+// entry, so downstream parsers can branch on it when field meanings change.
+// Example: NewWithOptions(ctx, "info", WithSchemaVersion("2.1"))
+func WithSchemaVersion(version string) Option {
+	return WithCategorySchemaVersion("", version)
+}
+
+// WithCategorySchemaVersion sets the schema_version of entries of category,
+// overriding WithSchemaVersion, for categories such as "audit" whose layout
+// evolves separately.
+// Example: NewWithOptions(ctx, "info", WithCategorySchemaVersion("audit", "3"))
+func WithCategorySchemaVersion(category, version string) Option {
+	return func(o *options) {
+		if o.schemaVersions == nil {
+			o.schemaVersions = make(map[string]string)
+		}
+		o.schemaVersions[category] = version
+	}
+}
+
+func (o *options) parseSchemaVersions() (map[string]string, error) {
+	if len(o.schemaVersions) == 0 {
+		return nil, nil
+	}
+	versions := make(map[string]string, len(o.schemaVersions))
+	for category, version := range o.schemaVersions {
+		parsed, err := ParseSchemaVersion(version)
+		if err != nil {
+			if category != "" {
+				return nil, fmt.Errorf("sugarzero: category %q: %w", category, err)
+			}
+			return nil, err
+		}
+		versions[category] = parsed.String()
+	}
+	return versions, nil
+}
+
+// appendSchemaVersion adds the schema version for the category in ctx.
+func (l *ZeroLogger) appendSchemaVersion(event *zerolog.Event, ctx context.Context) {
+	version, ok := l.schemaVersions[categoryFromContext(ctx)]
+	if !ok {
+		version, ok = l.schemaVersions[""]
+	}
+	if ok {
+		event.Str(SchemaVersionFieldName, version)
+	}
+}
@@ -0,0 +1,71 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestSchemaVersionFields(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithSchemaVersion("2.1"),
+		sugarzero.WithCategorySchemaVersion("audit", "3"),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "request served")
+	sugarzero.Info(sugarzero.WithCategory(ctx, "audit"), "role granted")
+	sugarzero.Info(sugarzero.WithCategory(ctx, "billing"), "invoice sent")
+
+	for i, want := range []string{"2.1", "3.0", "2.1"} {
+		if entry := readLogEntry(t, &buf, i); entry["schema_version"] != want {
+			t.Fatalf("entry %d: expected schema_version %q, got %v", i, want, entry)
+		}
+	}
+}
+
+func TestSchemaVersionInvalid(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	_, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithCategorySchemaVersion("audit", "v3"),
+	)
+	if err == nil {
+		t.Fatal("expected an error for an invalid version")
+	}
+}
+
+func TestSchemaVersionHelpers(t *testing.T) {
+	v, err := sugarzero.ParseSchemaVersion("2.1")
+	if err != nil {
+		t.Fatalf("ParseSchemaVersion failed: %v", err)
+	}
+	if got := v.BumpMinor().String(); got != "2.2" {
+		t.Fatalf("expected 2.2, got %s", got)
+	}
+	if got := v.BumpMajor().String(); got != "3.0" {
+		t.Fatalf("expected 3.0, got %s", got)
+	}
+	if !v.CanRead(v.BumpMinor()) || v.CanRead(v.BumpMajor()) {
+		t.Fatal("expected parsers to read minor but not major bumps")
+	}
+	for _, invalid := range []string{"", "v2", "2.x", "-1", "1.-2"} {
+		if _, err := sugarzero.ParseSchemaVersion(invalid); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package sugarzero
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// ScopeSeparator joins scopes and field keys.
+const ScopeSeparator = "."
+
+var scopeKey any = ctxKey{name: "scope"}
+
+// WithScope namespaces the fields added with the returned context, so that
+// components cannot collide on common keys: within WithScope(ctx, "payment"),
+// WithField(ctx, "amount", 10) is emitted as "payment.amount". Nested scopes
+// are joined ("payment.refund.amount"), and an empty scope clears the
+// namespace. Fields added before the scope keep their keys.
+func WithScope(ctx context.Context, scope string) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if scope != "" {
+		scope = scopedKey(scopeFromContext(ctx), scope)
+	}
+	return context.WithValue(ctx, scopeKey, scope)
+}
+
+// WithPackageScope is WithScope with the name of the calling function's
+// package, e.g. "payment" for code in example.com/shop/payment.
+func WithPackageScope(ctx context.Context) context.Context {
+	return WithScope(ctx, callerPackage(2))
+}
+
+// ScopeFromContext returns the scope set with WithScope, or "".
+func ScopeFromContext(ctx context.Context) string {
+	return scopeFromContext(ctx)
+}
+
+func scopeFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	scope, _ := ctx.Value(scopeKey).(string)
+	return scope
+}
+
+func scopedKey(scope, key string) string {
+	if scope == "" {
+		return key
+	}
+	return scope + ScopeSeparator + key
+}
+
+// callerPackage returns the last element of the package path of the function
+// skip frames above callerPackage.
+func callerPackage(skip int) string {
+	pc, _, _, ok := runtime.Caller(skip)
+	if !ok {
+		return ""
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return ""
+	}
+	// Names look like "example.com/shop/payment.(*Service).Charge"
+	name := fn.Name()
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		name = name[slash+1:]
+	}
+	pkg, _, _ := strings.Cut(name, ".")
+	return pkg
+}
+
+// withUnscopedFields is WithFields for keys owned by sugarzero, such as
+// "category", which writers and readers expect at the top level.
+func withUnscopedFields(ctx context.Context, keyvals ...any) context.Context {
+	scope := scopeFromContext(ctx)
+	if scope == "" {
+		return WithFields(ctx, keyvals...)
+	}
+	ctx = WithFields(context.WithValue(ctx, scopeKey, ""), keyvals...)
+	return context.WithValue(ctx, scopeKey, scope)
+}
@@ -0,0 +1,100 @@
+package sugarzero_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithScopePrefixesFieldKeys(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	ctx = sugarzero.WithField(ctx, "request_id", "req-1")
+	payment := sugarzero.WithCategory(sugarzero.WithScope(ctx, "payment"), sugarzero.CategoryBusiness)
+	payment = sugarzero.WithFields(payment, "amount", 10, sugarzero.JSON("card", map[string]string{"brand": "visa"}))
+	refund := sugarzero.WithField(sugarzero.WithScope(payment, "refund"), "amount", 4)
+	shipping := sugarzero.WithField(sugarzero.WithScope(refund, ""), "shipping_amount", 5)
+
+	sugarzero.Info(shipping, "scoped")
+
+	entry := readLogEntry(t, testWriter)
+	expected := map[string]any{
+		"request_id":            "req-1",
+		"payment.amount":        float64(10),
+		"payment.refund.amount": float64(4),
+		"shipping_amount":       float64(5),
+		"category":              sugarzero.CategoryBusiness,
+	}
+	for key, want := range expected {
+		if entry[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, entry[key])
+		}
+	}
+	if card, _ := entry["payment.card"].(map[string]any); card["brand"] != "visa" {
+		t.Fatalf("expected scoped JSON field, got %v", entry["payment.card"])
+	}
+	if got := sugarzero.ScopeFromContext(refund); got != "payment.refund" {
+		t.Fatalf("expected nested scope, got %q", got)
+	}
+}
+
+func TestWithScopeKeepsLibraryKeysUnscoped(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+	payment := sugarzero.WithScope(ctx, "payment")
+
+	sugarzero.LogPanic(payment, "boom")
+	_, _ = sugarzero.LogOnError(payment, func() (int, error) {
+		return 0, errors.New("declined")
+	})
+	recorder := httptest.NewRecorder()
+	sugarzero.HTTPError(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(payment), errors.New("declined"), http.StatusBadGateway)
+
+	lines := strings.Split(strings.TrimSpace(testWriter.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 entries, got %d: %s", len(lines), testWriter)
+	}
+	for i, keys := range [][]string{{"panic_type", "panic_value"}, {"function", "duration_ms"}, {"status", sugarzero.LogRefFieldName}} {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &entry); err != nil {
+			t.Fatalf("invalid JSON %q: %v", lines[i], err)
+		}
+		for _, key := range keys {
+			if _, ok := entry[key]; !ok {
+				t.Fatalf("expected %s at the top level, got %v", key, entry)
+			}
+		}
+	}
+}
+
+func TestWithoutFieldsResolvesScopedKeys(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	ctx = sugarzero.WithCategory(sugarzero.WithField(ctx, "request_id", "req-1"), sugarzero.CategoryBusiness)
+	payment := sugarzero.WithFields(sugarzero.WithScope(ctx, "payment"), "amount", 10, "card", "visa")
+	payment = sugarzero.WithoutFields(payment, "amount", "category")
+
+	sugarzero.Info(payment, "charged")
+
+	entry := readLogEntry(t, testWriter)
+	if _, ok := entry["payment.amount"]; ok {
+		t.Fatalf("expected the scoped amount to be removed, got %v", entry)
+	}
+	if _, ok := entry["category"]; ok {
+		t.Fatalf("expected the unscoped category to be removed, got %v", entry)
+	}
+	if entry["payment.card"] != "visa" || entry["request_id"] != "req-1" {
+		t.Fatalf("expected the other fields to stay, got %v", entry)
+	}
+}
+
+func TestWithPackageScopeUsesCallerPackage(t *testing.T) {
+	ctx := sugarzero.WithPackageScope(t.Context())
+	if got := sugarzero.ScopeFromContext(ctx); got != "sugarzero_test" {
+		t.Fatalf("expected caller package scope, got %q", got)
+	}
+}
@@ -0,0 +1,73 @@
+package sugarzero
+
+import "github.com/rs/zerolog"
+
+// SeverityNumberFieldName holds the OpenTelemetry severity number written by
+// SeverityOTel.
+const SeverityNumberFieldName = "severity_number"
+
+// SeverityScheme selects the numeric severity written next to the level.
+type SeverityScheme int
+
+const (
+	// SeverityNone writes no numeric severity.
+	SeverityNone SeverityScheme = iota
+	// SeverityRFC5424 writes syslog severities to the severity field, where
+	// lower is more severe: debug and trace are 7, info 6, warn 4, error 3,
+	// fatal 2, and panic 1.
+	SeverityRFC5424
+	// SeverityOTel writes OpenTelemetry severity numbers to the
+	// severity_number field, where higher is more severe: trace 1, debug 5,
+	// info 9, warn 13, error 17, fatal 21, and panic 24.
+	SeverityOTel
+)
+
+// WithSeverityNumber adds a numeric severity to every entry, so backends can
+// filter with thresholds such as "severity <= 3" instead of matching level
+// names. Entries at custom levels always carry their own severity; with
+// SeverityOTel they also get the number of the level they map to.
+// Example: NewWithOptions(ctx, "info", WithSeverityNumber(SeverityOTel))
+func WithSeverityNumber(scheme SeverityScheme) Option {
+	return func(o *options) {
+		o.severity = scheme
+	}
+}
+
+// appendSeverity adds the numeric severity of level to event.
+func (s SeverityScheme) appendSeverity(event *zerolog.Event, level zerolog.Level, custom *CustomLevel) {
+	switch s {
+	case SeverityRFC5424:
+		if custom == nil {
+			if n, ok := rfc5424Severity[level]; ok {
+				event.Int(SeverityFieldName, n)
+			}
+		}
+	case SeverityOTel:
+		if custom != nil {
+			level = custom.Level
+		}
+		if n, ok := otelSeverity[level]; ok {
+			event.Int(SeverityNumberFieldName, n)
+		}
+	}
+}
+
+var rfc5424Severity = map[zerolog.Level]int{
+	zerolog.TraceLevel: 7,
+	zerolog.DebugLevel: 7,
+	zerolog.InfoLevel:  6,
+	zerolog.WarnLevel:  4,
+	zerolog.ErrorLevel: 3,
+	zerolog.FatalLevel: 2,
+	zerolog.PanicLevel: 1,
+}
+
+var otelSeverity = map[zerolog.Level]int{
+	zerolog.TraceLevel: 1,
+	zerolog.DebugLevel: 5,
+	zerolog.InfoLevel:  9,
+	zerolog.WarnLevel:  13,
+	zerolog.ErrorLevel: 17,
+	zerolog.FatalLevel: 21,
+	zerolog.PanicLevel: 24,
+}
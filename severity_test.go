@@ -0,0 +1,67 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWithSeverityNumber(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme sugarzero.SeverityScheme
+		field  string
+		want   []float64
+	}{
+		{name: "rfc5424", scheme: sugarzero.SeverityRFC5424, field: "severity", want: []float64{7, 6, 4, 3, 5}},
+		{name: "otel", scheme: sugarzero.SeverityOTel, field: "severity_number", want: []float64{5, 9, 13, 17, 9}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sugarzero.Reset()
+			t.Cleanup(func() {
+				sugarzero.Reset()
+			})
+
+			var buf bytes.Buffer
+			ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+				sugarzero.WithWriters(&buf),
+				sugarzero.WithSeverityNumber(tt.scheme),
+				sugarzero.WithCustomLevels(sugarzero.SyslogLevels()...),
+			)
+			if err != nil {
+				t.Fatalf("Failed to create logger: %v", err)
+			}
+
+			sugarzero.Debug(ctx, "debug")
+			sugarzero.Info(ctx, "info")
+			sugarzero.Warn(ctx, "warn")
+			sugarzero.Error(ctx, "error")
+			sugarzero.Log(ctx, "notice", "notice")
+
+			for i, want := range tt.want {
+				entry := readLogEntry(t, &buf, i)
+				if entry[tt.field] != want {
+					t.Fatalf("entry %d: expected %s=%v, got %v", i, tt.field, want, entry)
+				}
+			}
+		})
+	}
+}
+
+func TestSeverityNumberDisabledByDefault(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	sugarzero.Info(ctx, "plain")
+
+	entry := readLogEntry(t, buf)
+	if _, ok := entry["severity"]; ok {
+		t.Fatalf("unexpected severity field: %v", entry)
+	}
+	if _, ok := entry["severity_number"]; ok {
+		t.Fatalf("unexpected severity_number field: %v", entry)
+	}
+}
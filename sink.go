@@ -0,0 +1,96 @@
+package sugarzero
+
+import (
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// Sink receives encoded log entries together with their level, so it can
+// filter or route by level without parsing the JSON.
+//
+// entry points into the logger's encoding buffer and is only valid for the
+// duration of the call; sinks that keep it must copy it. This lets sinks
+// such as ring buffers or shared-memory transports consume entries without
+// an intermediate copy.
+type Sink interface {
+	WriteEntry(level zerolog.Level, entry []byte) error
+}
+
+// SinkFunc adapts a function to the Sink interface.
+type SinkFunc func(level zerolog.Level, entry []byte) error
+
+// WriteEntry calls f(level, entry).
+func (f SinkFunc) WriteEntry(level zerolog.Level, entry []byte) error {
+	return f(level, entry)
+}
+
+// WithSinks appends sinks that receive every log entry, alongside any writers
+// added with WithWriters. Sinks implementing Sync, Flush, or HealthReporter
+// take part in Sync and Health like writers do.
+func WithSinks(sinks ...Sink) Option {
+	return func(o *options) {
+		for _, sink := range sinks {
+			if sink != nil {
+				o.writers = append(o.writers, SinkWriter(sink))
+			}
+		}
+	}
+}
+
+// LevelSink returns a Sink that forwards entries at min level or above to
+// next and discards the rest. Entries without a level are always forwarded.
+// Example: WithSinks(LevelSink(zerolog.ErrorLevel, alerts))
+func LevelSink(min zerolog.Level, next Sink) Sink {
+	return SinkFunc(func(level zerolog.Level, entry []byte) error {
+		if level != zerolog.NoLevel && level < min {
+			return nil
+		}
+		return next.WriteEntry(level, entry)
+	})
+}
+
+// SinkWriter adapts sink to a zerolog.LevelWriter, for use with WithWriters
+// or writer wrappers such as NewAsyncWriter.
+func SinkWriter(sink Sink) zerolog.LevelWriter {
+	return &sinkWriter{sink: sink}
+}
+
+type sinkWriter struct {
+	sink Sink
+}
+
+func (w *sinkWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w *sinkWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if err := w.sink.WriteEntry(level, p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Sync commits entries buffered by the sink, if it supports it.
+func (w *sinkWriter) Sync() error {
+	switch s := w.sink.(type) {
+	case interface{ Sync() error }:
+		return s.Sync()
+	case interface{ Flush() error }:
+		return s.Flush()
+	}
+	return nil
+}
+
+// healthReporter returns the HealthReporter behind w, if any.
+func healthReporter(w io.Writer) (HealthReporter, bool) {
+	if ow, ok := w.(*outputWriter); ok {
+		return healthReporter(ow.next)
+	}
+	if sw, ok := w.(*sinkWriter); ok {
+		reporter, ok := sw.sink.(HealthReporter)
+		return reporter, ok
+	}
+	reporter, ok := w.(HealthReporter)
+	return reporter, ok
+}
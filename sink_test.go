@@ -0,0 +1,90 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type recordingSink struct {
+	levels  []zerolog.Level
+	entries []string
+	synced  int
+}
+
+func (s *recordingSink) WriteEntry(level zerolog.Level, entry []byte) error {
+	s.levels = append(s.levels, level)
+	s.entries = append(s.entries, string(entry))
+	return nil
+}
+
+func (s *recordingSink) Sync() error {
+	s.synced++
+	return nil
+}
+
+func (s *recordingSink) Health() sugarzero.SinkStatus {
+	return sugarzero.SinkStatus{Name: "recording", Healthy: true}
+}
+
+func TestWithSinksReceivesLevelAndEntry(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	all := &recordingSink{}
+	errorsOnly := &recordingSink{}
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&buf),
+		sugarzero.WithSinks(all, sugarzero.LevelSink(zerolog.ErrorLevel, errorsOnly)),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(ctx, "routine")
+	sugarzero.Error(ctx, "broken")
+
+	if len(all.levels) != 2 || all.levels[0] != zerolog.InfoLevel || all.levels[1] != zerolog.ErrorLevel {
+		t.Fatalf("expected info and error levels, got %v", all.levels)
+	}
+	if len(errorsOnly.entries) != 1 {
+		t.Fatalf("expected only the error entry, got %v", errorsOnly.entries)
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(errorsOnly.entries[0]), &entry); err != nil {
+		t.Fatalf("failed to decode entry: %v", err)
+	}
+	if entry["message"] != "broken" {
+		t.Fatalf("expected error entry, got %v", entry)
+	}
+	if buf.Len() == 0 {
+		t.Fatal("expected writers to keep receiving entries")
+	}
+
+	if err := sugarzero.Sync(ctx); err != nil {
+		t.Fatalf("Sync failed: %v", err)
+	}
+	if all.synced != 1 {
+		t.Fatalf("expected Sync to reach the sink, got %d calls", all.synced)
+	}
+
+	rec := httptest.NewRecorder()
+	sugarzero.HealthHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/log-health", nil))
+	var status sugarzero.HealthStatus
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(status.Sinks) != 1 || status.Sinks[0].Name != "recording" {
+		t.Fatalf("expected sink health to be reported, got %+v", status.Sinks)
+	}
+}
@@ -0,0 +1,208 @@
+package sugarzero
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// sloBuckets is the number of buckets each rolling window is divided into.
+const sloBuckets = 60
+
+// SLOConfig configures an SLOCounter.
+type SLOConfig struct {
+	// Windows are the rolling windows ratios are reported for. Defaults to
+	// 5 minutes and 1 hour.
+	Windows []time.Duration
+	// Eligible selects the requests that count towards the SLO, e.g.
+	// `status > 0 && path =~ '^/api/'`; nil counts the entries with a
+	// numeric status, duration_s, or duration_ms field, so other entries
+	// written to the counter are not taken for successful requests.
+	Eligible *Filter
+	// Failure selects the failed requests, e.g.
+	// `status >= 500 || duration_s > 0.3`; nil fails status 500 and above.
+	Failure *Filter
+}
+
+// SLOWindow reports the requests seen over a rolling window.
+type SLOWindow struct {
+	Window   time.Duration `json:"window"`
+	Total    uint64        `json:"total"`
+	Failures uint64        `json:"failures"`
+	// Ratio is the share of successful requests, 1 without requests.
+	Ratio float64 `json:"ratio"`
+}
+
+// SLOCounter classifies access log entries as successes or failures and
+// keeps rolling-window success ratios, so a service can compute its
+// availability SLO from its own logs. It is an io.Writer for the lines of
+// AccessLogMiddleware, in any format, and a Sink for JSON entries; lines it
+// cannot parse are ignored. Served over HTTP it exposes the windows as
+// Prometheus gauges.
+// Example:
+//
+//	slo, err := sugarzero.NewSLOCounter(sugarzero.SLOConfig{})
+//	handler = sugarzero.AccessLogMiddleware(io.MultiWriter(accessLog, slo), sugarzero.AccessLogCombined)(handler)
+//	mux.Handle("/metrics/slo", slo)
+type SLOCounter struct {
+	eligible *Filter
+	failure  *Filter
+
+	mu      sync.Mutex
+	parser  lineParser
+	partial []byte
+	windows []*sloWindow
+}
+
+type sloWindow struct {
+	size    time.Duration
+	width   time.Duration
+	buckets [sloBuckets]sloBucket
+}
+
+type sloBucket struct {
+	epoch    int64
+	total    uint64
+	failures uint64
+}
+
+var (
+	defaultSLOEligible = MustParseFilter("status > 0 || duration_s >= 0 || duration_ms >= 0")
+	defaultSLOFailure  = MustParseFilter("status >= 500")
+)
+
+// NewSLOCounter validates cfg and returns an empty counter.
+func NewSLOCounter(cfg SLOConfig) (*SLOCounter, error) {
+	windows := cfg.Windows
+	if len(windows) == 0 {
+		windows = []time.Duration{5 * time.Minute, time.Hour}
+	}
+	c := &SLOCounter{eligible: cfg.Eligible, failure: cfg.Failure}
+	if c.eligible == nil {
+		c.eligible = defaultSLOEligible
+	}
+	if c.failure == nil {
+		c.failure = defaultSLOFailure
+	}
+	for _, size := range windows {
+		if size < sloBuckets*time.Millisecond {
+			return nil, fmt.Errorf("sugarzero: SLO window %s is too short", size)
+		}
+		c.windows = append(c.windows, &sloWindow{size: size, width: size / sloBuckets})
+	}
+	return c, nil
+}
+
+// Write classifies each complete access log line in p.
+func (c *SLOCounter) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data := p
+	if len(c.partial) > 0 {
+		data = append(c.partial, p...)
+		c.partial = nil
+	}
+	for {
+		line, rest, ok := bytes.Cut(data, []byte("\n"))
+		if !ok {
+			if len(data) > 0 {
+				c.partial = append([]byte(nil), data...)
+			}
+			return len(p), nil
+		}
+		if entry, ok, err := c.parser.parse(line); ok && err == nil {
+			c.recordLocked(entry, time.Now())
+		}
+		data = rest
+	}
+}
+
+// WriteEntry classifies a JSON entry.
+func (c *SLOCounter) WriteEntry(_ zerolog.Level, entry []byte) error {
+	parsed, err := ParseEntry(entry)
+	if err != nil {
+		return nil
+	}
+	c.Record(parsed)
+	return nil
+}
+
+// Record classifies a decoded entry.
+func (c *SLOCounter) Record(entry Entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.recordLocked(entry, time.Now())
+}
+
+func (c *SLOCounter) recordLocked(entry Entry, now time.Time) {
+	if !c.eligible.MatchEntry(entry) {
+		return
+	}
+	failed := c.failure.MatchEntry(entry)
+	for _, w := range c.windows {
+		epoch := now.UnixNano() / int64(w.width)
+		bucket := &w.buckets[epoch%sloBuckets]
+		if bucket.epoch != epoch {
+			*bucket = sloBucket{epoch: epoch}
+		}
+		bucket.total++
+		if failed {
+			bucket.failures++
+		}
+	}
+}
+
+// Windows reports every rolling window, shortest first as configured.
+func (c *SLOCounter) Windows() []SLOWindow {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	report := make([]SLOWindow, 0, len(c.windows))
+	for _, w := range c.windows {
+		current := now.UnixNano() / int64(w.width)
+		window := SLOWindow{Window: w.size, Ratio: 1}
+		for _, bucket := range w.buckets {
+			if bucket.epoch > current-sloBuckets && bucket.epoch <= current {
+				window.Total += bucket.total
+				window.Failures += bucket.failures
+			}
+		}
+		if window.Total > 0 {
+			window.Ratio = float64(window.Total-window.Failures) / float64(window.Total)
+		}
+		report = append(report, window)
+	}
+	return report
+}
+
+// ServeHTTP writes the windows in the Prometheus text format.
+func (c *SLOCounter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var buf bytes.Buffer
+	windows := c.Windows()
+	metrics := []struct {
+		name, help string
+		value      func(SLOWindow) any
+	}{
+		{"sugarzero_slo_requests", "Requests counted towards the SLO in the window.", func(s SLOWindow) any { return s.Total }},
+		{"sugarzero_slo_failures", "Failed requests in the window.", func(s SLOWindow) any { return s.Failures }},
+		{"sugarzero_slo_success_ratio", "Share of successful requests in the window.", func(s SLOWindow) any { return s.Ratio }},
+	}
+	for _, metric := range metrics {
+		fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name)
+		for _, window := range windows {
+			fmt.Fprintf(&buf, "%s{window=%q} %v\n", metric.name, window.Window.String(), metric.value(window))
+		}
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write(buf.Bytes())
+}
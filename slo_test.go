@@ -0,0 +1,74 @@
+package sugarzero_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
+)
+
+func TestSLOCounterFromAccessLog(t *testing.T) {
+	slo, err := sugarzero.NewSLOCounter(sugarzero.SLOConfig{
+		Eligible: sugarzero.MustParseFilter("path =~ '^/api/'"),
+	})
+	if err != nil {
+		t.Fatalf("NewSLOCounter failed: %v", err)
+	}
+
+	var accessLog strings.Builder
+	handler := sugarzero.AccessLogMiddleware(io.MultiWriter(&accessLog, slo), sugarzero.AccessLogW3C)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("fail") != "" {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+	for _, target := range []string{"/api/orders", "/api/orders?fail=1", "/api/users", "/api/users", "/healthz?fail=1"} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	windows := slo.Windows()
+	if len(windows) != 2 || windows[0].Window != 5*time.Minute {
+		t.Fatalf("expected the default windows, got %+v", windows)
+	}
+	for _, window := range windows {
+		if window.Total != 4 || window.Failures != 1 || window.Ratio != 0.75 {
+			t.Fatalf("unexpected window %+v", window)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	slo.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics/slo", nil))
+	if !strings.Contains(rec.Body.String(), `sugarzero_slo_success_ratio{window="5m0s"} 0.75`) {
+		t.Fatalf("unexpected metrics:\n%s", rec.Body.String())
+	}
+}
+
+func TestSLOCounterCustomFailure(t *testing.T) {
+	slo, err := sugarzero.NewSLOCounter(sugarzero.SLOConfig{
+		Windows: []time.Duration{time.Minute},
+		Failure: sugarzero.MustParseFilter("status >= 500 || latency_ms > 300"),
+	})
+	if err != nil {
+		t.Fatalf("NewSLOCounter failed: %v", err)
+	}
+	_ = slo.WriteEntry(zerolog.InfoLevel, []byte(`{"level":"info","message":"served","status":200,"latency_ms":20}`))
+	_ = slo.WriteEntry(zerolog.InfoLevel, []byte(`{"level":"info","message":"served","status":200,"latency_ms":900}`))
+	// Lines may arrive split across writes.
+	_, _ = slo.Write([]byte(`{"level":"info","message":"served",`))
+	_, _ = slo.Write([]byte(`"status":502}` + "\nnot a log line\n"))
+	// Entries without a status or duration are not requests.
+	_ = slo.WriteEntry(zerolog.InfoLevel, []byte(`{"level":"info","message":"cache warmed"}`))
+
+	if window := slo.Windows()[0]; window.Total != 3 || window.Failures != 2 {
+		t.Fatalf("unexpected window %+v", window)
+	}
+
+	if _, err := sugarzero.NewSLOCounter(sugarzero.SLOConfig{Windows: []time.Duration{time.Millisecond}}); err == nil {
+		t.Fatal("expected an error for a too short window")
+	}
+}
@@ -0,0 +1,74 @@
+package sugarzero
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+)
+
+// Snapshot is a point-in-time view of a logger's state for dashboards and
+// /debug/vars consumers.
+type Snapshot struct {
+	HealthStatus
+	// Sampling is nil when adaptive sampling is disabled.
+	Sampling *SamplingSnapshot `json:"sampling,omitempty"`
+}
+
+// SamplingSnapshot reports the adaptive sampler's budget and current rate.
+type SamplingSnapshot struct {
+	EventsPerSecond int `json:"events_per_second"`
+	// Rate is N in "keep one in N entries" for the current window.
+	Rate int `json:"rate"`
+	// Suppressed counts entries dropped so far in the current window.
+	Suppressed int `json:"suppressed"`
+}
+
+// Snapshot returns the logger's levels, sink status, and sampling state.
+func (l *ZeroLogger) Snapshot() Snapshot {
+	snapshot := Snapshot{HealthStatus: l.Health()}
+	if l.sampler != nil {
+		sampling := l.sampler.snapshot()
+		snapshot.Sampling = &sampling
+	}
+	return snapshot
+}
+
+// PublishExpvar publishes the Snapshot of the logger in ctx under name in the
+// expvar registry, so it is served on /debug/vars. The snapshot is taken on
+// every scrape. It returns an error if name is already published.
+// Example: sugarzero.PublishExpvar(ctx, "logger")
+func PublishExpvar(ctx context.Context, name string) error {
+	if expvar.Get(name) != nil {
+		return fmt.Errorf("sugarzero: expvar %q already published", name)
+	}
+	expvar.Publish(name, expvar.Func(func() any {
+		return snapshotFromContext(ctx)
+	}))
+	return nil
+}
+
+func snapshotFromContext(ctx context.Context) Snapshot {
+	snapshot := Snapshot{HealthStatus: healthFromContext(ctx)}
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok && zl.sampler != nil {
+			sampling := zl.sampler.snapshot()
+			snapshot.Sampling = &sampling
+		}
+	})
+	return snapshot
+}
+
+func (s *adaptiveSampler) snapshot() SamplingSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suppressed := 0
+	for _, count := range s.suppressed {
+		suppressed += count
+	}
+	return SamplingSnapshot{
+		EventsPerSecond: int(float64(s.budget) / s.window.Seconds()),
+		Rate:            s.rate,
+		Suppressed:      suppressed,
+	}
+}
@@ -0,0 +1,48 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestPublishExpvarServesSnapshot(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&bytes.Buffer{}),
+		sugarzero.WithAdaptiveSampling(sugarzero.SamplingConfig{EventsPerSecond: 500}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	// expvar names cannot be unpublished, so keep them unique across -count runs
+	name := fmt.Sprintf("sugarzero_test_logger_%d", time.Now().UnixNano())
+	if err := sugarzero.PublishExpvar(ctx, name); err != nil {
+		t.Fatalf("PublishExpvar failed: %v", err)
+	}
+	if err := sugarzero.PublishExpvar(ctx, name); err == nil {
+		t.Fatal("expected error when publishing the same name twice")
+	}
+
+	var snapshot sugarzero.Snapshot
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &snapshot); err != nil {
+		t.Fatalf("invalid expvar JSON: %v", err)
+	}
+	if snapshot.Level != "info" || !snapshot.Healthy {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+	if snapshot.Sampling == nil || snapshot.Sampling.EventsPerSecond != 500 || snapshot.Sampling.Rate != 1 {
+		t.Fatalf("unexpected sampling snapshot %+v", snapshot.Sampling)
+	}
+}
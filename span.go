@@ -0,0 +1,74 @@
+package sugarzero
+
+import (
+	"context"
+	rtrace "runtime/trace"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// LogSpanFieldName holds the name of a span started with Span.
+	LogSpanFieldName = "log_span"
+	// LogSpanIDFieldName holds the id of the innermost Span of an entry.
+	LogSpanIDFieldName = "log_span_id"
+	// LogParentSpanIDFieldName holds the id of the enclosing Span of a span's
+	// start and end entries.
+	LogParentSpanIDFieldName = "log_parent_span_id"
+)
+
+var logSpanKey any = ctxKey{name: "logSpan"}
+
+// Span times an operation in the logs, for latency breakdowns without an
+// OpenTelemetry backend. It logs a "span started" debug entry and returns a
+// context whose entries carry the span's log_span_id, and a function that
+// logs a "span finished" info entry with the duration_ms of the span. Start
+// and end entries carry the span name in log_span and, for nested spans, the
+// enclosing span in log_parent_span_id. Calling end more than once logs once.
+//
+// While a runtime trace is being recorded, e.g. with "go test -trace" or
+// net/http/pprof's /debug/pprof/trace, Span also starts a runtime/trace task
+// of the same name annotated with the log_span_id, so "go tool trace" views
+// line up with the logged timings.
+// Example:
+//
+//	ctx, end := sugarzero.Span(ctx, "load cart")
+//	defer end()
+func Span(ctx context.Context, name string) (context.Context, func()) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	id := NewLogRef()
+	parent, _ := ctx.Value(logSpanKey).(string)
+
+	ctx = withUnscopedFields(WithoutFields(ctx, LogSpanIDFieldName), LogSpanIDFieldName, id)
+	ctx = context.WithValue(ctx, logSpanKey, id)
+	var task *rtrace.Task
+	if rtrace.IsEnabled() {
+		ctx, task = rtrace.NewTask(ctx, name)
+		rtrace.Log(ctx, LogSpanIDFieldName, id)
+	}
+
+	marked := withUnscopedFields(ctx, LogSpanFieldName, name)
+	if parent != "" {
+		marked = withUnscopedFields(marked, LogParentSpanIDFieldName, parent)
+	}
+	withLogger(marked, func(logger Logger, resolved context.Context) {
+		logger.Debug(resolved, "span started")
+	})
+
+	start := time.Now()
+	var ended atomic.Bool
+	return ctx, func() {
+		if !ended.CompareAndSwap(false, true) {
+			return
+		}
+		if task != nil {
+			task.End()
+		}
+		finished := withUnscopedFields(marked, "duration_ms", float64(time.Since(start).Microseconds())/1000)
+		withLogger(finished, func(logger Logger, resolved context.Context) {
+			logger.Info(resolved, "span finished")
+		})
+	}
+}
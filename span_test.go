@@ -0,0 +1,67 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"runtime/trace"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestSpanLogsStartAndEnd(t *testing.T) {
+	ctx, buf := setupTest(t, "debug")
+
+	requestCtx, endRequest := sugarzero.Span(ctx, "handle request")
+	cartCtx, endCart := sugarzero.Span(requestCtx, "load cart")
+	sugarzero.Info(cartCtx, "cache miss")
+	endCart()
+	endCart()
+	endRequest()
+
+	requestStart := readLogEntry(t, buf, 0)
+	cartStart := readLogEntry(t, buf, 1)
+	inner := readLogEntry(t, buf, 2)
+	cartEnd := readLogEntry(t, buf, 3)
+	requestEnd := readLogEntry(t, buf, 4)
+	if strings.Count(buf.String(), "\n") != 5 {
+		t.Fatalf("expected end to log once, got:\n%s", buf.String())
+	}
+
+	requestID, _ := requestStart["log_span_id"].(string)
+	cartID, _ := cartStart["log_span_id"].(string)
+	if requestID == "" || cartID == "" || requestID == cartID {
+		t.Fatalf("expected distinct span ids, got %q and %q", requestID, cartID)
+	}
+	if requestStart["message"] != "span started" || requestStart["log_span"] != "handle request" || requestStart["level"] != "DEBUG" {
+		t.Fatalf("unexpected start entry: %v", requestStart)
+	}
+	if cartStart["log_parent_span_id"] != requestID || inner["log_span_id"] != cartID || inner["log_span"] != nil {
+		t.Fatalf("unexpected nesting: %v, %v", cartStart, inner)
+	}
+	if cartEnd["message"] != "span finished" || cartEnd["log_span_id"] != cartID || cartEnd["duration_ms"] == nil {
+		t.Fatalf("unexpected end entry: %v", cartEnd)
+	}
+	if requestEnd["log_span_id"] != requestID || requestEnd["log_parent_span_id"] != nil {
+		t.Fatalf("unexpected end entry: %v", requestEnd)
+	}
+	if position, _ := cartEnd["position"].(string); !strings.Contains(position, "span_test.go") {
+		t.Fatalf("expected call site in test file, got %q", position)
+	}
+}
+
+func TestSpanAnnotatesRuntimeTrace(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+
+	var recorded bytes.Buffer
+	if err := trace.Start(&recorded); err != nil {
+		t.Skipf("runtime trace unavailable: %v", err)
+	}
+	_, end := sugarzero.Span(ctx, "checkout-task")
+	end()
+	trace.Stop()
+
+	if !bytes.Contains(recorded.Bytes(), []byte("checkout-task")) {
+		t.Fatal("expected the span name in the runtime trace")
+	}
+}
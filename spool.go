@@ -0,0 +1,339 @@
+package sugarzero
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// spoolFileName is the name of the write-ahead log inside a spool directory.
+const spoolFileName = "spool.wal"
+
+// spoolHeaderSize is the size of the header holding the read offset.
+const spoolHeaderSize = 8
+
+// spoolSyncInterval bounds how long appended records and read offset updates
+// stay in the page cache before they are fsynced.
+const spoolSyncInterval = 100 * time.Millisecond
+
+var errQueueFull = errors.New("sugarzero: queue is full")
+
+// diskSpool is a bounded FIFO of entries stored in a single file. The file
+// starts with the offset of the next unread record, followed by records of a
+// 4-byte big-endian length and the entry bytes. The read offset is persisted
+// after every discard, so a restarted process resumes where delivery stopped.
+//
+// Writes reach the file immediately, so they survive a crash of the process.
+// They are fsynced in batches, at most spoolSyncInterval after being made and
+// on close, so a crash of the machine loses at most the entries spooled in
+// that interval and replays at most the entries discarded in it.
+type diskSpool struct {
+	mu       sync.Mutex
+	dir      string
+	file     *os.File
+	maxBytes int64
+	// read is the offset of the next unread record; size is the file size.
+	read    int64
+	size    int64
+	entries int
+
+	// dirty reports writes not fsynced yet; syncTimer fsyncs them once
+	// spoolSyncInterval has passed since lastSync.
+	dirty     bool
+	lastSync  time.Time
+	syncTimer *time.Timer
+	closed    bool
+}
+
+func openDiskSpool(dir string, maxBytes int64) (*diskSpool, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("sugarzero: failed to create spool directory: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, spoolFileName), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: failed to open spool: %w", err)
+	}
+	s := &diskSpool{dir: dir, file: file, maxBytes: maxBytes}
+	if err := s.load(); err != nil {
+		_ = file.Close()
+		return nil, fmt.Errorf("sugarzero: failed to load spool: %w", err)
+	}
+	return s, nil
+}
+
+// load reads the header of an existing spool and counts its unread records.
+// A truncated trailing record, left by a crash mid-append, is discarded.
+func (s *diskSpool) load() error {
+	info, err := s.file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() < spoolHeaderSize {
+		return s.reset()
+	}
+
+	var header [spoolHeaderSize]byte
+	if _, err := s.file.ReadAt(header[:], 0); err != nil {
+		return err
+	}
+	s.read = int64(binary.BigEndian.Uint64(header[:]))
+	s.size = info.Size()
+	if s.read < spoolHeaderSize || s.read > s.size {
+		return s.reset()
+	}
+
+	for offset := s.read; offset < s.size; {
+		length, err := s.recordLength(offset)
+		if err != nil || offset+4+length > s.size {
+			s.size = offset
+			return s.file.Truncate(offset)
+		}
+		offset += 4 + length
+		s.entries++
+	}
+	return nil
+}
+
+func (s *diskSpool) recordLength(offset int64) (int64, error) {
+	var prefix [4]byte
+	if _, err := s.file.ReadAt(prefix[:], offset); err != nil {
+		return 0, err
+	}
+	return int64(binary.BigEndian.Uint32(prefix[:])), nil
+}
+
+// len returns the number of unread entries.
+func (s *diskSpool) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.entries
+}
+
+// push appends entry, compacting the file first if the read records leave
+// room for it. It returns errQueueFull when the spool cannot hold entry.
+func (s *diskSpool) push(entry []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record := int64(4 + len(entry))
+	if s.size+record > s.maxBytes && s.read > spoolHeaderSize {
+		if err := s.rewrite(nil); err != nil {
+			return err
+		}
+	}
+	if s.size+record > s.maxBytes {
+		return errQueueFull
+	}
+	if err := s.writeRecord(s.size, entry); err != nil {
+		return err
+	}
+	s.size += record
+	s.entries++
+	return s.markDirty()
+}
+
+// unshift inserts entries, in order, before the unread records.
+func (s *diskSpool) unshift(entries [][]byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rewrite(entries)
+}
+
+// peek returns the oldest entry without removing it, so that a crash before
+// discard replays it.
+func (s *diskSpool) peek() ([]byte, bool, error) {
+	entries, err := s.peekN(1)
+	if err != nil || len(entries) == 0 {
+		return nil, false, err
+	}
+	return entries[0], true, nil
+}
+
+// peekN returns up to n of the oldest entries without removing them.
+func (s *diskSpool) peekN(n int) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([][]byte, 0, min(n, s.entries))
+	offset := s.read
+	for len(entries) < n && len(entries) < s.entries {
+		length, err := s.recordLength(offset)
+		if err != nil {
+			return nil, err
+		}
+		entry := make([]byte, length)
+		if _, err := s.file.ReadAt(entry, offset+4); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+		offset += 4 + length
+	}
+	return entries, nil
+}
+
+// discard removes the oldest entry.
+func (s *diskSpool) discard() error {
+	return s.discardN(1)
+}
+
+// discardN removes the n oldest entries.
+func (s *diskSpool) discardN(n int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n = min(n, s.entries)
+	if n == 0 {
+		return nil
+	}
+	if n == s.entries {
+		return s.reset()
+	}
+	offset := s.read
+	for range n {
+		length, err := s.recordLength(offset)
+		if err != nil {
+			return err
+		}
+		offset += 4 + length
+	}
+	s.entries -= n
+	s.read = offset
+	if err := s.writeHeader(); err != nil {
+		return err
+	}
+	return s.markDirty()
+}
+
+// close fsyncs pending writes and closes the file.
+func (s *diskSpool) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	if s.syncTimer != nil {
+		s.syncTimer.Stop()
+		s.syncTimer = nil
+	}
+	err := s.sync()
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// markDirty records a write, fsyncing it now if the last fsync is at least
+// spoolSyncInterval ago, and otherwise when the interval is over. s.mu must
+// be held.
+func (s *diskSpool) markDirty() error {
+	s.dirty = true
+	wait := spoolSyncInterval - time.Since(s.lastSync)
+	if wait <= 0 {
+		return s.sync()
+	}
+	if s.syncTimer == nil {
+		s.syncTimer = time.AfterFunc(wait, s.syncPending)
+	}
+	return nil
+}
+
+// syncPending fsyncs the writes left dirty by markDirty.
+func (s *diskSpool) syncPending() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.syncTimer = nil
+	if s.closed {
+		return
+	}
+	if err := s.sync(); err != nil {
+		reportWriteError(fmt.Errorf("sugarzero: failed to sync spool: %w", err))
+	}
+}
+
+// sync fsyncs the file if it has dirty writes. s.mu must be held.
+func (s *diskSpool) sync() error {
+	if !s.dirty {
+		return nil
+	}
+	s.dirty = false
+	s.lastSync = time.Now()
+	return s.file.Sync()
+}
+
+// rewrite compacts the file, placing head before the unread records. The new
+// spool is written to a temporary file and renamed over the old one, so a
+// crash leaves either the old or the new spool intact, never a mix that
+// would replay already delivered entries.
+func (s *diskSpool) rewrite(head [][]byte) error {
+	unread := make([]byte, s.size-s.read)
+	if _, err := s.file.ReadAt(unread, s.read); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	size := int64(spoolHeaderSize + len(unread))
+	for _, entry := range head {
+		size += int64(4 + len(entry))
+	}
+	data := make([]byte, spoolHeaderSize, size)
+	binary.BigEndian.PutUint64(data, spoolHeaderSize)
+	for _, entry := range head {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(entry)))
+		data = append(data, entry...)
+	}
+	data = append(data, unread...)
+
+	path := filepath.Join(s.dir, spoolFileName)
+	tmp, err := os.CreateTemp(s.dir, spoolFileName+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+
+	_ = s.file.Close()
+	s.file = tmp
+	s.read = spoolHeaderSize
+	s.size = size
+	s.entries += len(head)
+	// The new file holds every earlier write and was fsynced above
+	s.dirty = false
+	return nil
+}
+
+func (s *diskSpool) reset() error {
+	s.read, s.size, s.entries = spoolHeaderSize, spoolHeaderSize, 0
+	if err := s.file.Truncate(spoolHeaderSize); err != nil {
+		return err
+	}
+	if err := s.writeHeader(); err != nil {
+		return err
+	}
+	return s.markDirty()
+}
+
+func (s *diskSpool) writeRecord(offset int64, entry []byte) error {
+	record := make([]byte, 4+len(entry))
+	binary.BigEndian.PutUint32(record, uint32(len(entry)))
+	copy(record[4:], entry)
+	_, err := s.file.WriteAt(record, offset)
+	return err
+}
+
+func (s *diskSpool) writeHeader() error {
+	var header [spoolHeaderSize]byte
+	binary.BigEndian.PutUint64(header[:], uint64(s.read))
+	_, err := s.file.WriteAt(header[:], 0)
+	return err
+}
@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/trace"
@@ -20,18 +23,24 @@ type traceInfo struct {
 	spanID  string
 }
 
-const (
-	// callerSkipFramePublic is the skip frame count for public log methods (Debug, Info, etc.)
-	callerSkipFramePublic = 5
-	// callerSkipFrameInternal is the skip frame count when logging internal warnings
-	callerSkipFrameInternal = 3
-)
+// callerSkipFramePublic is the skip frame count for public log methods (Debug, Info, etc.)
+const callerSkipFramePublic = 5
+
+// callerSkipFrameDepth skips writeArgs and LogDepth, so depth 0 positions
+// entries at the caller of LogDepth.
+const callerSkipFrameDepth = 2
 
 var (
-	loggerKey        = ctxKey{name: "logger"}
-	fieldsKey        = ctxKey{name: "fields"}
-	traceKey         = ctxKey{name: "trace"}
+	// Context keys are stored as interfaces so lookups do not allocate.
+	loggerKey any = ctxKey{name: "logger"}
+	fieldsKey any = ctxKey{name: "fields"}
+	traceKey  any = ctxKey{name: "trace"}
+	errorKey  any = ctxKey{name: "error"}
+	// categoryKey holds the event category set by WithCategory.
+	categoryKey any = ctxKey{name: "category"}
+
 	configureZerolog sync.Once
+	zerologGlobals   sync.Once
 	globalLogger     *ZeroLogger
 )
 
@@ -40,6 +49,49 @@ type ZeroLogger struct {
 	mu     sync.RWMutex
 	logger zerolog.Logger
 	level  zerolog.Level
+	// generation is incremented whenever logger is replaced, invalidating
+	// the per-context loggers cached by contextFields.
+	generation uint64
+	// coercion canonicalizes field values before encoding; nil keeps zerolog's defaults.
+	coercion *Coercion
+	// reserved decides what happens to user fields named like built-in keys.
+	reserved reservedKeys
+	// keySanitization cleans field keys from untrusted input; nil disables it.
+	keySanitization *KeySanitization
+	// messageSafety rewrites unsafe characters in messages.
+	messageSafety MessageSafety
+	// injectionProtection escapes CR and LF in messages and field values and
+	// flags the entries that contained them.
+	injectionProtection bool
+	// redaction masks the values of sensitive keys; nil disables it.
+	redaction *keyRedaction
+	// blobs moves oversized field values to a BlobStore; nil keeps them inline.
+	blobs *blobOffload
+	// categoryLevels overrides the minimum level for categorized entries.
+	categoryLevels map[string]zerolog.Level
+	// sinks are the configured writers, buffering ones first, as seen by
+	// Sync and Health.
+	sinks []io.Writer
+	// sampler thins out high-volume entries; nil disables sampling.
+	sampler *adaptiveSampler
+	// governor sheds entries when logging exceeds its CPU budget; nil
+	// disables it.
+	governor *governor
+	// events dispatches written entries to OnEvent subscribers.
+	events *eventBus
+	// missingLogger rate-limits the fallback warning of the global logger.
+	missingLogger *missingLoggerWarnings
+	// strictContext makes fallback to the global logger panic.
+	strictContext bool
+	customLevels  map[string]*CustomLevel
+	severity      SeverityScheme
+	// formatValidation reports bad format verbs as diagnostics.
+	formatValidation bool
+	// goroutineFields adds the fields stashed with PushFields.
+	goroutineFields bool
+	// schemaVersions maps categories to schema versions; "" holds the
+	// default.
+	schemaVersions map[string]string
 }
 
 // Reset resets the global logger state. This is intended for testing purposes only.
@@ -47,12 +99,26 @@ type ZeroLogger struct {
 func Reset() {
 	globalLogger = nil
 	configureZerolog = sync.Once{}
+	zerologGlobals = sync.Once{}
+	resetErrorLevels()
+	resetRegistry()
+	resetDiagnostics()
+	resetFeatures()
+	resetGoroutineStash()
+	resetGoroutineDumps()
 }
 
 // New creates a zerolog-backed Logger, stores it as the global default, and
 // injects it into the returned context. When writers is empty, os.Stdout is used.
 // ! Notice: This function should be called only once during application initialization.
 func New(ctx context.Context, level string, writers ...io.Writer) (context.Context, error) {
+	return NewWithOptions(ctx, level, WithWriters(writers...))
+}
+
+// NewWithOptions is like New but accepts functional options to configure the
+// logger's outputs and behavior.
+// ! Notice: This function should be called only once during application initialization.
+func NewWithOptions(ctx context.Context, level string, opts ...Option) (context.Context, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -61,35 +127,14 @@ func New(ctx context.Context, level string, writers ...io.Writer) (context.Conte
 		return context.WithValue(ctx, loggerKey, globalLogger), nil
 	}
 
-	lvl, err := parseLevel(level)
+	logger, err := newZeroLogger(level, opts...)
 	if err != nil {
 		return ctx, err
 	}
 
-	writer := selectWriter(writers...)
-
 	configureZerolog.Do(func() {
-		// Configure zerolog to use "position" as caller field name and uppercase level
-		zerolog.CallerFieldName = "position"
-		zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
-			return fmt.Sprintf("%s:%d", file, line)
-		}
-		zerolog.LevelFieldMarshalFunc = func(l zerolog.Level) string {
-			return strings.ToUpper(l.String())
-		}
-
-		// Create logger with native Caller() for position
-		base := zerolog.New(writer).
-			Level(lvl).
-			With().
-			Timestamp().
-			Caller().
-			Logger()
-
-		globalLogger = &ZeroLogger{
-			logger: base,
-			level:  lvl,
-		}
+		zerologGlobals.Do(setZerologGlobals)
+		globalLogger = logger
 	})
 
 	if globalLogger == nil {
@@ -99,8 +144,143 @@ func New(ctx context.Context, level string, writers ...io.Writer) (context.Conte
 	return context.WithValue(ctx, loggerKey, globalLogger), nil
 }
 
+// newZeroLogger builds a ZeroLogger from level and opts without touching any
+// global state.
+func newZeroLogger(level string, opts ...Option) (*ZeroLogger, error) {
+	cfg := newOptions(opts...)
+	customLevels, err := cfg.parseCustomLevels()
+	if err != nil {
+		return nil, err
+	}
+	lvl, err := (&ZeroLogger{customLevels: customLevels}).parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := cfg.buildWriter()
+	if err != nil {
+		return nil, err
+	}
+	categoryLevels, err := cfg.parseCategoryLevels()
+	if err != nil {
+		return nil, err
+	}
+	schemaVersions, err := cfg.parseSchemaVersions()
+	if err != nil {
+		return nil, err
+	}
+	sampler, err := newAdaptiveSampler(cfg.sampling)
+	if err != nil {
+		return nil, err
+	}
+	governor, err := newGovernor(cfg.governor)
+	if err != nil {
+		return nil, err
+	}
+
+	events := &eventBus{next: writer, reserved: cfg.reserved}
+	if len(cfg.piiDetectors) > 0 {
+		events.pii = &piiScrubber{detectors: cfg.piiDetectors}
+	}
+	if cfg.tailSize > 0 {
+		events.tail = newEntryRing(cfg.tailSize)
+	}
+	if cfg.recentErrors > 0 {
+		events.recentErrors = newEntryRing(cfg.recentErrors)
+	}
+	if cfg.crashStore != nil {
+		events.crash = &crashReporter{store: cfg.crashStore, entries: newEntryRing(cfg.crashEntries)}
+	}
+
+	base := zerolog.New(events).
+		Level(lvl).
+		With().
+		Timestamp()
+	if cfg.name != "" {
+		base = base.Str(LoggerNameFieldName, cfg.name)
+	}
+	if len(cfg.fields) > 0 {
+		base = base.Fields(cfg.reserved.apply(cfg.fields))
+	}
+
+	zl := base.Logger()
+	if !cfg.noCaller {
+		// Position is added by a hook so FeatureCaller can switch it off
+		zl = zl.Hook(callerHook{})
+	}
+
+	logger := &ZeroLogger{
+		logger:              zl,
+		level:               lvl,
+		coercion:            cfg.coercion,
+		reserved:            cfg.reserved,
+		keySanitization:     cfg.keySanitization,
+		messageSafety:       cfg.messageSafety,
+		injectionProtection: cfg.injectionProtection,
+		redaction:           cfg.redaction,
+		blobs:               cfg.blobs.withRedaction(cfg.redaction, cfg.piiDetectors),
+		categoryLevels:      categoryLevels,
+		sinks:               cfg.sinks(),
+		sampler:             sampler,
+		governor:            governor,
+		events:              events,
+		missingLogger:       &missingLoggerWarnings{mode: cfg.missingLoggerWarning},
+		strictContext:       cfg.strictContext,
+		customLevels:        customLevels,
+		severity:            cfg.severity,
+		formatValidation:    cfg.formatValidation,
+		goroutineFields:     cfg.goroutineFields,
+		schemaVersions:      schemaVersions,
+	}
+	if events.crash != nil {
+		events.crash.logger = logger
+	}
+	return logger, nil
+}
+
+// setZerologGlobals configures zerolog to use "position" as caller field name
+// and uppercase levels.
+func setZerologGlobals() {
+	zerolog.CallerFieldName = "position"
+	zerolog.CallerMarshalFunc = func(pc uintptr, file string, line int) string {
+		return file + ":" + strconv.Itoa(line)
+	}
+	zerolog.LevelFieldMarshalFunc = func(l zerolog.Level) string {
+		return strings.ToUpper(l.String())
+	}
+}
+
+// NewFromZerolog wraps an existing zerolog.Logger so applications that already
+// configure zerolog (sampling, hooks, outputs) can use sugarzero's context,
+// fields, and tracing layer on top of it. The wrapper is injected into the
+// returned context and becomes the global default if none exists yet.
+// Unlike New, zerolog's global settings (field names, level format) are left
+// untouched, and caller positions are only emitted if zl was built with Caller().
+func NewFromZerolog(ctx context.Context, zl zerolog.Logger) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	logger := &ZeroLogger{
+		logger: zl,
+		level:  zl.GetLevel(),
+	}
+	if globalLogger == nil {
+		globalLogger = logger
+	}
+
+	return context.WithValue(ctx, loggerKey, logger)
+}
+
 // WithFields merges the provided fields into the context so they are emitted
-// on the next log call. Fields should be provided as alternating key-value pairs.
+// on the next log call. Fields should be provided as alternating key-value pairs,
+// optionally mixed with Field values built by helpers such as JSON. Inside a
+// scope set with WithScope, keys are prefixed with the scope.
+//
+// Values are encoded once per context, on the first log call through it, and
+// the encoding is reused by every later call. Pointers, maps, slices, and
+// Stringers mutated after that call keep being logged with their old value;
+// pass a copy, or add the field again with WithFields, to log the new one.
 // Example: WithFields(ctx, "user_id", 123, "action", "login")
 func WithFields(ctx context.Context, keyvals ...any) context.Context {
 	if ctx == nil {
@@ -110,36 +290,46 @@ func WithFields(ctx context.Context, keyvals ...any) context.Context {
 		return ctx
 	}
 
-	// keyvals must be in pairs
-	if len(keyvals)%2 != 0 {
-		// Ignore the last odd value
-		keyvals = keyvals[:len(keyvals)-1]
-	}
-
+	scope := scopeFromContext(ctx)
 	flat := make([]any, 0, len(keyvals))
-	for i := 0; i < len(keyvals); i += 2 {
-		key, ok := keyvals[i].(string)
-		if !ok || key == "" {
-			// Skip non-string keys
+	for i := 0; i < len(keyvals); {
+		// Field values carry their own key
+		if field, ok := keyvals[i].(Field); ok {
+			if field.Key != "" {
+				flat = append(flat, scopedKey(scope, field.Key), field.Value)
+			}
+			i++
 			continue
 		}
-		flat = append(flat, key, keyvals[i+1])
+
+		// keyvals must be in pairs, ignore the last odd value
+		if i+1 >= len(keyvals) {
+			break
+		}
+
+		// Skip non-string keys
+		key, ok := keyvals[i].(string)
+		if ok && key != "" {
+			flat = append(flat, scopedKey(scope, key), keyvals[i+1])
+		}
+		i += 2
 	}
 
 	if len(flat) == 0 {
 		return ctx
 	}
 
-	if existing, ok := ctx.Value(fieldsKey).([]any); ok && len(existing) > 0 {
+	if existing := flattenedFieldsFromContext(ctx); len(existing) > 0 {
 		merged := make([]any, 0, len(existing)+len(flat))
 		merged = append(merged, existing...)
 		flat = append(merged, flat...)
 	}
 
-	return context.WithValue(ctx, fieldsKey, flat)
+	return context.WithValue(ctx, fieldsKey, &contextFields{flat: flat})
 }
 
 // WithField is a convenience wrapper to add a single field to the context.
+// Like WithFields, the value is encoded once per context.
 func WithField(ctx context.Context, key string, value any) context.Context {
 	if key == "" {
 		return ctx
@@ -147,6 +337,35 @@ func WithField(ctx context.Context, key string, value any) context.Context {
 	return WithFields(ctx, key, value)
 }
 
+// WithoutFields returns a context in which the fields with the given keys,
+// added earlier with WithFields, are no longer emitted. Like WithFields, keys
+// are resolved in the current scope: within WithScope(ctx, "payment"),
+// "amount" removes "payment.amount". Keys are also matched as written, so
+// fully qualified keys and unscoped ones such as "category" can be removed
+// too. The parent context is unchanged, so the fields still apply wherever
+// it is used.
+// Example: sugarzero.WithoutFields(ctx, "request_body") before handing ctx to a subsystem.
+func WithoutFields(ctx context.Context, keys ...string) context.Context {
+	existing := flattenedFieldsFromContext(ctx)
+	if len(existing) == 0 || len(keys) == 0 {
+		return ctx
+	}
+
+	scope := scopeFromContext(ctx)
+	flat := make([]any, 0, len(existing))
+	for i := 0; i+1 < len(existing); i += 2 {
+		key, _ := existing[i].(string)
+		if !slices.ContainsFunc(keys, func(k string) bool { return key == k || key == scopedKey(scope, k) }) {
+			flat = append(flat, existing[i], existing[i+1])
+		}
+	}
+	if len(flat) == len(existing) {
+		return ctx
+	}
+
+	return context.WithValue(ctx, fieldsKey, &contextFields{flat: flat})
+}
+
 // WithTracing extracts the current OpenTelemetry span information from the context
 // and stores it so the logger can emit it automatically.
 func WithTracing(ctx context.Context) context.Context {
@@ -256,8 +475,27 @@ func (l *ZeroLogger) Fatalln(ctx context.Context, args ...any) {
 	l.writeArgs(ctx, zerolog.FatalLevel, callerSkipFramePublic, args...)
 }
 
+// Raw returns a zerolog event at level with the context's fields, error, and
+// trace identifiers already applied, for callers that need zerolog's typed
+// appenders. The caller must finish the event with Msg, Msgf, or Send. A nil
+// event is returned when level is disabled; zerolog treats it as a no-op.
+// Raw events bypass the governor, sampling, and message safety, as their
+// message is only known when they are finished.
+func (l *ZeroLogger) Raw(ctx context.Context, level zerolog.Level) *zerolog.Event {
+	// The event is finished by the caller, so no frames need to be skipped.
+	return l.newEvent(ctx, level, 0)
+}
+
+// LogDepth logs msg at level like Info and the other level methods, through
+// the governor, sampling, and message safety, with the caller position taken
+// depth frames above the caller of LogDepth. It is meant for adapters of
+// other logging APIs. Fatal and panic entries are only written.
+func (l *ZeroLogger) LogDepth(ctx context.Context, level zerolog.Level, depth int, msg string) {
+	l.writeArgs(ctx, level, callerSkipFrameDepth+depth, msg)
+}
+
 func (l *ZeroLogger) SetLogLevel(level string) error {
-	lvl, err := parseLevel(level)
+	lvl, err := l.parseLevel(level)
 	if err != nil {
 		return err
 	}
@@ -267,6 +505,7 @@ func (l *ZeroLogger) SetLogLevel(level string) error {
 
 	l.level = lvl
 	l.logger = l.logger.Level(lvl)
+	l.generation++
 
 	return nil
 }
@@ -279,83 +518,152 @@ func (l *ZeroLogger) GetLogLevel() string {
 }
 
 func (l *ZeroLogger) writeArgs(ctx context.Context, level zerolog.Level, skipFrame int, args ...any) {
-	l.mu.RLock()
-	logger := l.logger
-	l.mu.RUnlock()
-
-	ctx = ensureTracing(ctx)
-
-	event := logger.WithLevel(level).CallerSkipFrame(skipFrame)
+	if g := l.governor; g != nil {
+		start := time.Now()
+		if g.drop(ctx, level, start) {
+			return
+		}
+		defer g.track(start)
+	}
+	event := l.newEvent(ctx, level, skipFrame)
 	if event == nil {
 		return
 	}
-
-	if trace := traceFromContext(ctx); trace != nil {
-		event.Str("trace_id", trace.traceID)
-		event.Str("span_id", trace.spanID)
-	}
-
-	if fields := flattenedFieldsFromContext(ctx); len(fields) > 0 {
-		event.Fields(fields)
+	if l.sampler != nil && !l.sample(ctx, level, sampleKey(args)) {
+		event.Discard()
+		return
 	}
 
 	if len(args) == 0 {
-		event.Msg("")
+		event.Msg(l.message(ctx, event, ""))
+		l.syncIfRequested(ctx)
 		return
 	}
 
+	// Plain string messages need no formatting
+	if len(args) == 1 {
+		if msg, ok := args[0].(string); ok {
+			event.Msg(l.message(ctx, event, msg))
+			l.syncIfRequested(ctx)
+			return
+		}
+	}
+
+	buf := getBuffer()
 	if len(args) == 1 {
-		event.Msgf("%v", args[0])
+		*buf = fmt.Appendf(*buf, "%v", args[0])
 	} else {
-		event.Msg(fmt.Sprint(args...))
+		*buf = fmt.Append(*buf, args...)
 	}
+	event.Msg(l.message(ctx, event, string(*buf)))
+	putBuffer(buf)
+	l.syncIfRequested(ctx)
 }
 
 func (l *ZeroLogger) writef(ctx context.Context, level zerolog.Level, skipFrame int, format string, args ...any) {
-	l.mu.RLock()
-	logger := l.logger
-	l.mu.RUnlock()
-
-	ctx = ensureTracing(ctx)
-
-	event := logger.WithLevel(level).CallerSkipFrame(skipFrame)
+	if g := l.governor; g != nil {
+		start := time.Now()
+		if g.drop(ctx, level, start) {
+			return
+		}
+		defer g.track(start)
+	}
+	event := l.newEvent(ctx, level, skipFrame)
 	if event == nil {
 		return
 	}
-
-	if trace := traceFromContext(ctx); trace != nil {
-		event.Str("trace_id", trace.traceID)
-		event.Str("span_id", trace.spanID)
+	if l.sampler != nil && !l.sample(ctx, level, format) {
+		event.Discard()
+		return
 	}
 
-	if fields := flattenedFieldsFromContext(ctx); len(fields) > 0 {
-		event.Fields(fields)
+	buf := getBuffer()
+	*buf = fmt.Appendf(*buf, format, args...)
+	if l.formatValidation {
+		checkFormat(format, *buf, skipFrame)
 	}
-
-	event.Msgf(format, args...)
+	event.Msg(l.message(ctx, event, string(*buf)))
+	putBuffer(buf)
+	l.syncIfRequested(ctx)
 }
 
-func (l *ZeroLogger) logMissingLoggerWarning() {
+// newEvent creates an event at the given level and enriches it with the trace,
+// error, and fields carried by ctx. It returns nil when the level is disabled.
+func (l *ZeroLogger) newEvent(ctx context.Context, level zerolog.Level, skipFrame int) *zerolog.Event {
+	if summary := SummaryFromContext(ctx); summary != nil && summary.suppresses(level) {
+		return nil
+	}
+
 	l.mu.RLock()
 	logger := l.logger
+	generation := l.generation
 	l.mu.RUnlock()
 
-	event := logger.WithLevel(zerolog.WarnLevel).CallerSkipFrame(callerSkipFrameInternal)
+	err := errorFromContext(ctx)
+	if err != nil {
+		if mapped, ok := classifyError(err); ok {
+			level = mapped
+		}
+	}
+
+	minLevel := logger.GetLevel()
+	var (
+		categoryLevel zerolog.Level
+		categorized   bool
+	)
+	if len(l.categoryLevels) > 0 {
+		if categoryLevel, categorized = l.categoryLevels[categoryFromContext(ctx)]; categorized {
+			minLevel = categoryLevel
+		}
+	}
+	// Disabled calls return before the context fields are encoded
+	if level < minLevel || level < zerolog.GlobalLevel() {
+		return nil
+	}
+
+	// Context fields are encoded once per context and reused by every event
+	if fields := contextFieldsFromContext(ctx); fields != nil {
+		logger = fields.logger(l, generation, logger)
+	}
+	if categorized {
+		logger = logger.Level(categoryLevel)
+	}
+
+	var event *zerolog.Event
+	custom := l.customLevel(ctx)
+	if custom != nil {
+		event = customLevelEvent(logger, l.events, custom, skipFrame)
+	} else {
+		event = logger.WithLevel(level).CallerSkipFrame(skipFrame)
+	}
 	if event == nil {
-		return
+		return nil
+	}
+	if l.severity != SeverityNone {
+		l.severity.appendSeverity(event, level, custom)
 	}
-	event.Msg("context does not contain a logger, using fallback logger")
-}
 
-func parseLevel(level string) (zerolog.Level, error) {
-	if level == "" {
-		return zerolog.InfoLevel, nil
+	if len(l.schemaVersions) > 0 {
+		l.appendSchemaVersion(event, ctx)
+	}
+	if l.goroutineFields {
+		if stashed := GoroutineFields(); len(stashed) > 0 {
+			event.Fields(l.prepareFields(stashed))
+		}
+	}
+	appendTrace(event, ctx)
+	if features[FeatureFunction].Load() {
+		event.Str(FunctionFieldName, callerFunction())
+	}
+	if flags := featureFlagsFromContext(ctx); flags != nil {
+		flags.appendTo(event)
 	}
-	lvl, err := zerolog.ParseLevel(strings.ToLower(level))
+
 	if err != nil {
-		return zerolog.InfoLevel, fmt.Errorf("invalid log level %q: %w", level, err)
+		l.appendError(event, err)
 	}
-	return lvl, nil
+
+	return event
 }
 
 func selectWriter(writers ...io.Writer) io.Writer {
@@ -365,31 +673,58 @@ func selectWriter(writers ...io.Writer) io.Writer {
 	if len(writers) == 1 {
 		return writers[0]
 	}
-	return io.MultiWriter(writers...)
+	// MultiLevelWriter preserves level information for writers that route by level
+	return zerolog.MultiLevelWriter(writers...)
 }
 
 func flattenedFieldsFromContext(ctx context.Context) []any {
+	if fields := contextFieldsFromContext(ctx); fields != nil {
+		return fields.flat
+	}
+	return nil
+}
+
+func contextFieldsFromContext(ctx context.Context) *contextFields {
 	if ctx == nil {
 		return nil
 	}
-	if fields, ok := ctx.Value(fieldsKey).([]any); ok && len(fields) > 0 {
+	if fields, ok := ctx.Value(fieldsKey).(*contextFields); ok && fields != nil && len(fields.flat) > 0 {
 		return fields
 	}
 	return nil
 }
 
-func loggerFromContextValue(ctx context.Context) *ZeroLogger {
+func loggerFromContextValue(ctx context.Context) Logger {
 	if ctx == nil {
 		return nil
 	}
-	if ctxLogger, ok := ctx.Value(loggerKey).(*ZeroLogger); ok && ctxLogger != nil {
+	if ctxLogger, ok := ctx.Value(loggerKey).(Logger); ok && ctxLogger != nil {
 		return ctxLogger
 	}
 	return nil
 }
 
-func ensureTracing(ctx context.Context) context.Context {
-	return WithTracing(ctx)
+// appendTrace adds trace_id and span_id to event. Unlike WithTracing it reads
+// the active span directly, so the hot path does not allocate a new context.
+func appendTrace(event *zerolog.Event, ctx context.Context) {
+	if ctx == nil {
+		return
+	}
+
+	span := trace.SpanFromContext(ctx)
+	if span != nil && span.IsRecording() {
+		if spanCtx := span.SpanContext(); spanCtx.IsValid() {
+			traceID, spanID := spanCtx.TraceID(), spanCtx.SpanID()
+			event.Hex("trace_id", traceID[:])
+			event.Hex("span_id", spanID[:])
+			return
+		}
+	}
+
+	if trace := traceFromContext(ctx); trace != nil {
+		event.Str("trace_id", trace.traceID)
+		event.Str("span_id", trace.spanID)
+	}
 }
 
 func traceFromContext(ctx context.Context) *traceInfo {
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"strings"
 	"testing"
 
 	"github.com/bigboss2063/sugarzero"
+	"github.com/rs/zerolog"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 )
 
@@ -115,10 +118,12 @@ func TestLoggerWithContext(t *testing.T) {
 
 func TestPackageLoggerWarnsWhenContextMissingLogger(t *testing.T) {
 	_, testWriter := setupTest(t, "debug")
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
 
 	sugarzero.Info(context.Background(), "no logger in context")
 
-	entry := readLogEntry(t, testWriter, 0)
+	entry := readLogEntry(t, &diagnostics)
 
 	if strings.ToUpper(entry["level"].(string)) != "WARN" {
 		t.Fatalf("expected WARN level, got %s", entry["level"])
@@ -128,6 +133,14 @@ func TestPackageLoggerWarnsWhenContextMissingLogger(t *testing.T) {
 		t.Fatalf("unexpected warning message: %s", entry["message"])
 	}
 
+	if entry[sugarzero.DiagnosticFieldName] != true || entry[sugarzero.DiagnosticKindFieldName] != sugarzero.DiagnosticFallbackLogger {
+		t.Fatalf("expected a fallback logger diagnostic, got %v", entry)
+	}
+
+	if entry = readLogEntry(t, testWriter); entry["message"] != "no logger in context" {
+		t.Fatalf("expected only the application entry in the output, got %v", entry)
+	}
+
 	if got := sugarzero.GetLogLevel(context.Background()); got != "debug" {
 		t.Fatalf("logger level changed unexpectedly: %s", got)
 	}
@@ -246,6 +259,34 @@ func TestLogLevelChange(t *testing.T) {
 	}
 }
 
+func TestLogLevelChangeAppliesToContextWithFields(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	ctx = sugarzero.WithFields(ctx, "request_id", "req-1")
+	sugarzero.Info(ctx, "before level change")
+
+	entry := readLogEntry(t, testWriter)
+	if entry["request_id"] != "req-1" {
+		t.Fatalf("expected request_id=req-1, got %v", entry["request_id"])
+	}
+
+	sugarzero.SetLogLevel(ctx, "error")
+
+	testWriter.Reset()
+	sugarzero.Info(ctx, "after level change")
+	if strings.TrimSpace(testWriter.String()) != "" {
+		t.Fatalf("Info message should not appear at error level, got %s", testWriter.String())
+	}
+
+	sugarzero.SetLogLevel(ctx, "debug")
+	sugarzero.Debug(ctx, "debug enabled")
+
+	entry = readLogEntry(t, testWriter)
+	if entry["request_id"] != "req-1" || entry["message"] != "debug enabled" {
+		t.Fatalf("unexpected entry after level change: %v", entry)
+	}
+}
+
 func TestAllLogLevels(t *testing.T) {
 	ctx, testWriter := setupTest(t, "debug")
 
@@ -352,13 +393,17 @@ func TestLogFunctionsWithLn(t *testing.T) {
 
 func TestNilContext(t *testing.T) {
 	_, testWriter := setupTest(t, "debug")
+	var diagnostics bytes.Buffer
+	sugarzero.SetDiagnosticsWriter(&diagnostics)
 
 	sugarzero.Info(nil, "nil context message")
 
-	// 至少应包含警告和 Info
-	_ = readLogEntry(t, testWriter, 1)
+	entry := readLogEntry(t, testWriter)
+	if entry["message"] != "nil context message" {
+		t.Fatalf("expected the entry to be logged, got %v", entry)
+	}
 
-	entry := readLogEntry(t, testWriter, 0)
+	entry = readLogEntry(t, &diagnostics)
 
 	if strings.ToUpper(entry["level"].(string)) != "WARN" {
 		t.Fatalf("expected WARN level for missing logger, got %s", entry["level"])
@@ -379,6 +424,33 @@ func TestInvalidLogLevel(t *testing.T) {
 	}
 }
 
+func TestLevelValidationHelpers(t *testing.T) {
+	levels := sugarzero.ValidLevels()
+	if strings.Join(levels, ",") != "trace,debug,info,warn,error,fatal,panic" {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+	levels[0] = "changed"
+	if sugarzero.ValidLevels()[0] != "trace" {
+		t.Fatal("expected ValidLevels to return a copy")
+	}
+
+	for _, level := range []string{"debug", "WARN", "Error"} {
+		if !sugarzero.IsValidLevel(level) {
+			t.Fatalf("expected %q to be valid", level)
+		}
+	}
+	for _, level := range []string{"", "verbose", "disabled", "1"} {
+		if sugarzero.IsValidLevel(level) {
+			t.Fatalf("expected %q to be invalid", level)
+		}
+	}
+
+	ctx, _ := setupTest(t, "info")
+	if err := sugarzero.SetLogLevel(ctx, "1"); err == nil {
+		t.Fatal("expected SetLogLevel to reject levels IsValidLevel rejects")
+	}
+}
+
 func TestEmptyFields(t *testing.T) {
 	ctx, testWriter := setupTest(t, "debug")
 
@@ -442,6 +514,49 @@ func TestFieldsMerging(t *testing.T) {
 	}
 }
 
+func TestFieldValuesAreEncodedOncePerContext(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+
+	tags := map[string]string{"stage": "before"}
+	fieldsCtx := sugarzero.WithField(ctx, "tags", tags)
+	sugarzero.Info(fieldsCtx, "first")
+
+	tags["stage"] = "after"
+	sugarzero.Info(fieldsCtx, "second")
+	sugarzero.Info(sugarzero.WithField(ctx, "tags", tags), "re-added")
+
+	for i, want := range []string{"before", "before", "after"} {
+		entry := readLogEntry(t, testWriter, i)
+		if got := entry["tags"].(map[string]any)["stage"]; got != want {
+			t.Fatalf("entry %d: expected stage=%s, got %v", i, want, got)
+		}
+	}
+}
+
+// countingMarshaler counts how often it is encoded.
+type countingMarshaler struct{ calls *int }
+
+func (m countingMarshaler) MarshalJSON() ([]byte, error) {
+	*m.calls++
+	return []byte(`"encoded"`), nil
+}
+
+func TestDisabledCallsDoNotEncodeContextFields(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	var calls int
+	ctx = sugarzero.WithField(ctx, "payload", countingMarshaler{calls: &calls})
+	sugarzero.Debug(ctx, "filtered out")
+	if calls != 0 || testWriter.Len() != 0 {
+		t.Fatalf("expected a disabled call to skip field encoding, got %d calls", calls)
+	}
+
+	sugarzero.Info(ctx, "written")
+	if calls != 1 {
+		t.Fatalf("expected one encoding, got %d", calls)
+	}
+}
+
 func TestMultipleWriters(t *testing.T) {
 	sugarzero.Reset()
 
@@ -536,6 +651,98 @@ func TestLoggerOmitsTraceMetadataWithoutSpan(t *testing.T) {
 	}
 }
 
+func TestRawEventKeepsContextEnrichment(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	ctx = sugarzero.WithField(ctx, "request_id", "req-raw")
+	sugarzero.Raw(ctx, zerolog.InfoLevel).
+		Hex("digest", []byte{0xde, 0xad}).
+		Dict("payload", zerolog.Dict().Int("size", 3)).
+		Msg("raw event")
+
+	entry := readLogEntry(t, testWriter)
+
+	if entry["message"] != "raw event" || entry["request_id"] != "req-raw" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+	if entry["digest"] != "dead" {
+		t.Fatalf("expected digest=dead, got %v", entry["digest"])
+	}
+	if position, _ := entry["position"].(string); !strings.Contains(position, "sugarzero_test.go") {
+		t.Fatalf("expected caller position in test file, got %v", entry["position"])
+	}
+
+	testWriter.Reset()
+	sugarzero.Raw(ctx, zerolog.DebugLevel).Str("ignored", "x").Msg("disabled")
+	if testWriter.Len() != 0 {
+		t.Fatalf("expected disabled level to produce no output, got %s", testWriter.String())
+	}
+}
+
+func TestNewFromZerologKeepsExistingConfiguration(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	hooked := 0
+	zl := zerolog.New(&buf).
+		Level(zerolog.WarnLevel).
+		Hook(zerolog.HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+			hooked++
+		})).
+		With().Str("app", "legacy").Logger()
+
+	ctx := sugarzero.NewFromZerolog(context.Background(), zl)
+	ctx = sugarzero.WithField(ctx, "request_id", "req-zl")
+
+	sugarzero.Info(ctx, "filtered by zerolog level")
+	sugarzero.Warn(ctx, "kept")
+
+	entry := readLogEntry(t, &buf)
+
+	if entry["message"] != "kept" || entry["app"] != "legacy" || entry["request_id"] != "req-zl" {
+		t.Fatalf("unexpected entry: %v", entry)
+	}
+	if hooked != 1 {
+		t.Fatalf("expected zerolog hook to run once, ran %d times", hooked)
+	}
+	if got := sugarzero.GetLogLevel(ctx); got != "warn" {
+		t.Fatalf("expected level warn, got %s", got)
+	}
+}
+
+// recordingLogger is a minimal Logger used to check that package functions
+// dispatch through the interface.
+type recordingLogger struct {
+	sugarzero.Logger
+	messages []string
+}
+
+func (r *recordingLogger) Info(ctx context.Context, args ...any) {
+	r.messages = append(r.messages, fmt.Sprint(args...))
+}
+
+func TestWithLoggerInjectsCustomImplementation(t *testing.T) {
+	_, testWriter := setupTest(t, "debug")
+
+	recorder := &recordingLogger{}
+	ctx := sugarzero.WithLogger(context.Background(), recorder)
+
+	sugarzero.Info(ctx, "routed to custom logger")
+
+	if len(recorder.messages) != 1 || recorder.messages[0] != "routed to custom logger" {
+		t.Fatalf("unexpected recorded messages: %v", recorder.messages)
+	}
+	if testWriter.Len() != 0 {
+		t.Fatalf("expected global logger to stay silent, got %s", testWriter.String())
+	}
+	if sugarzero.FromContext(ctx) != recorder {
+		t.Fatal("expected FromContext to return the injected logger")
+	}
+}
+
 func TestFieldsFromEmptyContext(t *testing.T) {
 	ctx := context.Background()
 	fields := sugarzero.FieldsFromContext(ctx)
@@ -573,3 +780,108 @@ func BenchmarkLoggerWithoutPreFormatting(b *testing.B) {
 		sugarzero.Infof(ctx, "Message number: %d", i)
 	}
 }
+
+func TestWithoutFieldsMasksFieldsDownstream(t *testing.T) {
+	ctx, testWriter := setupTest(t, "info")
+
+	parent := sugarzero.WithFields(ctx, "request_id", "req-1", "payload", "{...large...}", "user_id", 7)
+	child := sugarzero.WithoutFields(parent, "payload", "missing")
+	child = sugarzero.WithField(child, "subsystem", "billing")
+
+	sugarzero.Info(child, "child entry")
+	entry := readLogEntry(t, testWriter)
+	if _, ok := entry["payload"]; ok {
+		t.Fatalf("expected payload to be removed, got %v", entry)
+	}
+	if entry["request_id"] != "req-1" || entry["user_id"] != float64(7) || entry["subsystem"] != "billing" {
+		t.Fatalf("expected remaining fields to be kept, got %v", entry)
+	}
+
+	testWriter.Reset()
+	sugarzero.Info(parent, "parent entry")
+	entry = readLogEntry(t, testWriter)
+	if entry["payload"] != "{...large...}" {
+		t.Fatalf("expected parent context to keep payload, got %v", entry)
+	}
+
+	if fields := sugarzero.FieldsFromContext(sugarzero.WithoutFields(parent, "request_id", "payload", "user_id")); len(fields) != 0 {
+		t.Fatalf("expected no fields left, got %v", fields)
+	}
+}
+
+func setupBenchmark(b *testing.B) context.Context {
+	b.Helper()
+
+	sugarzero.Reset()
+	ctx, err := sugarzero.New(context.Background(), "info", io.Discard)
+	if err != nil {
+		b.Fatalf("Failed to create logger: %v", err)
+	}
+
+	b.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	b.ReportAllocs()
+
+	return ctx
+}
+
+func BenchmarkInfoMessage(b *testing.B) {
+	ctx := setupBenchmark(b)
+	ctx = sugarzero.WithFields(ctx, "request_id", "bench-123", "user_id", 789)
+
+	for b.Loop() {
+		sugarzero.Info(ctx, "Benchmark message")
+	}
+}
+
+func BenchmarkInfoMultipleArgs(b *testing.B) {
+	ctx := setupBenchmark(b)
+
+	for i := 0; b.Loop(); i++ {
+		sugarzero.Info(ctx, "processed ", i, " items")
+	}
+}
+
+func BenchmarkInfofFormatting(b *testing.B) {
+	ctx := setupBenchmark(b)
+
+	for i := 0; b.Loop(); i++ {
+		sugarzero.Infof(ctx, "Message number: %d", i)
+	}
+}
+
+func BenchmarkInfoWithTrace(b *testing.B) {
+	ctx := setupBenchmark(b)
+
+	tp := sdktrace.NewTracerProvider()
+	b.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	ctx, span := tp.Tracer("bench").Start(ctx, "bench-operation")
+	defer span.End()
+
+	for b.Loop() {
+		sugarzero.Info(ctx, "traced message")
+	}
+}
+
+func BenchmarkInfoWithManyFields(b *testing.B) {
+	ctx := setupBenchmark(b)
+	ctx = sugarzero.WithFields(ctx,
+		"request_id", "bench-123",
+		"user_id", 789,
+		"tenant", "acme",
+		"endpoint", "/api/orders",
+		"method", "POST",
+		"region", "eu-west-1",
+		"attempt", 3,
+		"cached", true,
+		"latency_ms", 12.5,
+		"version", "1.4.2",
+	)
+
+	for b.Loop() {
+		sugarzero.Info(ctx, "Benchmark message")
+	}
+}
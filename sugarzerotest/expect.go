@@ -0,0 +1,294 @@
+package sugarzerotest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+// recorders maps each test to the Recorder its expectations check.
+var recorders sync.Map
+
+// Recorder keeps every entry written by a logger during a test.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []sugarzero.LogEvent
+}
+
+// Record captures the entries of the logger in ctx for the rest of t, at
+// every level, and makes the Recorder the one ExpectEntry and InOrder check
+// for t. The logger must have been created by New or NewWithOptions.
+// Example:
+//
+//	ctx, _ := sugarzero.New(context.Background(), "debug", io.Discard)
+//	sugarzerotest.Record(t, ctx)
+//	sugarzerotest.ExpectEntry(t, sugarzerotest.Entry().Message("payment audited")).Exactly(1)
+func Record(t testing.TB, ctx context.Context) *Recorder {
+	t.Helper()
+	rec := &Recorder{}
+	stop, err := sugarzero.OnEvent(ctx, "trace", nil, rec.add)
+	if err != nil {
+		t.Fatalf("sugarzerotest: record entries: %v", err)
+	}
+	recorders.Store(t, rec)
+	t.Cleanup(func() {
+		stop()
+		recorders.CompareAndDelete(t, rec)
+	})
+	return rec
+}
+
+func (r *Recorder) add(event sugarzero.LogEvent) {
+	// Raw must not be retained after the callback
+	event.Raw = nil
+	r.mu.Lock()
+	r.entries = append(r.entries, event)
+	r.mu.Unlock()
+}
+
+// Entries returns the entries recorded so far, oldest first.
+func (r *Recorder) Entries() []sugarzero.LogEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]sugarzero.LogEvent(nil), r.entries...)
+}
+
+// Count returns the number of recorded entries matched by m.
+func (r *Recorder) Count(m Matcher) int {
+	n := 0
+	for _, entry := range r.Entries() {
+		if m.Match(entry) {
+			n++
+		}
+	}
+	return n
+}
+
+// dump lists the recorded entries for failure messages.
+func (r *Recorder) dump() string {
+	entries := r.Entries()
+	if len(entries) == 0 {
+		return "  (no entries)"
+	}
+	var b strings.Builder
+	for i, entry := range entries {
+		fields, _ := json.Marshal(entry.Fields)
+		fmt.Fprintf(&b, "  %d: %s\n", i, fields)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// Matcher selects entries for ExpectEntry and InOrder. String describes the
+// entries it matches in failure messages.
+type Matcher interface {
+	Match(sugarzero.LogEvent) bool
+	String() string
+}
+
+// EntryMatcher is a Matcher built fluently; every condition must hold.
+type EntryMatcher struct {
+	conditions []condition
+}
+
+type condition struct {
+	describe string
+	match    sugarzero.EventMatcher
+}
+
+// Entry returns an EntryMatcher matching every entry until conditions are
+// added.
+// Example: sugarzerotest.Entry().Level("info").Field("payment_id", "p-1")
+func Entry() *EntryMatcher {
+	return &EntryMatcher{}
+}
+
+func (m *EntryMatcher) with(describe string, match sugarzero.EventMatcher) *EntryMatcher {
+	return &EntryMatcher{conditions: append(m.conditions[:len(m.conditions):len(m.conditions)], condition{describe, match})}
+}
+
+// Level requires the entry's level, e.g. "warn".
+func (m *EntryMatcher) Level(level string) *EntryMatcher {
+	level = strings.ToLower(level)
+	return m.with(fmt.Sprintf("level=%s", level), func(e sugarzero.LogEvent) bool {
+		return strings.EqualFold(e.Level, level)
+	})
+}
+
+// Message requires the entry's message to equal msg.
+func (m *EntryMatcher) Message(msg string) *EntryMatcher {
+	return m.with(fmt.Sprintf("message=%q", msg), func(e sugarzero.LogEvent) bool {
+		return e.Message == msg
+	})
+}
+
+// MessageContains requires the entry's message to contain substr.
+func (m *EntryMatcher) MessageContains(substr string) *EntryMatcher {
+	return m.with(fmt.Sprintf("message~%q", substr), sugarzero.MatchMessage(substr))
+}
+
+// Field requires field key to equal value once decoded from JSON, so
+// numbers are compared as float64 whatever integer type value has.
+func (m *EntryMatcher) Field(key string, value any) *EntryMatcher {
+	want := normalizeValue(value)
+	return m.with(fmt.Sprintf("%s=%v", key, value), func(e sugarzero.LogEvent) bool {
+		got, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(got, want)
+	})
+}
+
+// HasField requires field key to be present.
+func (m *EntryMatcher) HasField(key string) *EntryMatcher {
+	return m.with(fmt.Sprintf("has %s", key), func(e sugarzero.LogEvent) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+// Where requires match, described as describe in failure messages.
+func (m *EntryMatcher) Where(describe string, match sugarzero.EventMatcher) *EntryMatcher {
+	return m.with(describe, match)
+}
+
+// Match reports whether every condition holds for e.
+func (m *EntryMatcher) Match(e sugarzero.LogEvent) bool {
+	for _, c := range m.conditions {
+		if !c.match(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes the conditions, e.g. `{level=info message="paid"}`.
+func (m *EntryMatcher) String() string {
+	describes := make([]string, len(m.conditions))
+	for i, c := range m.conditions {
+		describes[i] = c.describe
+	}
+	return "{" + strings.Join(describes, " ") + "}"
+}
+
+// normalizeValue round-trips value through JSON, as recorded fields are.
+func normalizeValue(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return value
+	}
+	return decoded
+}
+
+// Expectation is a count assertion on matching entries, checked when the
+// test finishes. By default at least one entry must match.
+type Expectation struct {
+	mu  sync.Mutex
+	min int
+	max int // -1 for no upper bound
+}
+
+// ExpectEntry asserts that entries matched by want are logged during t, at
+// least once unless the count is narrowed with Exactly, AtLeast, or Never.
+// It is checked against t's Recorder when t finishes, so it can be declared
+// before the code under test runs.
+// Example:
+//
+//	sugarzerotest.ExpectEntry(t, sugarzerotest.Entry().Level("info").Message("payment audited")).Exactly(1)
+func ExpectEntry(t testing.TB, want Matcher) *Expectation {
+	t.Helper()
+	rec := recorderFor(t)
+	exp := &Expectation{min: 1, max: -1}
+	t.Cleanup(func() {
+		exp.mu.Lock()
+		lo, hi := exp.min, exp.max
+		exp.mu.Unlock()
+		n := rec.Count(want)
+		if n >= lo && (hi < 0 || n <= hi) {
+			return
+		}
+		t.Errorf("sugarzerotest: expected %s entries matching %s, got %d; recorded entries:\n%s", describeCount(lo, hi), want, n, rec.dump())
+	})
+	return exp
+}
+
+// Exactly requires exactly n matching entries.
+func (e *Expectation) Exactly(n int) *Expectation {
+	return e.bounds(n, n)
+}
+
+// AtLeast requires n or more matching entries.
+func (e *Expectation) AtLeast(n int) *Expectation {
+	return e.bounds(n, -1)
+}
+
+// Never requires no matching entry.
+func (e *Expectation) Never() *Expectation {
+	return e.bounds(0, 0)
+}
+
+func (e *Expectation) bounds(lo, hi int) *Expectation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.min, e.max = lo, hi
+	return e
+}
+
+func describeCount(lo, hi int) string {
+	if lo == hi {
+		return fmt.Sprintf("exactly %d", lo)
+	}
+	return fmt.Sprintf("at least %d", lo)
+}
+
+// InOrder asserts that entries matched by each matcher are logged during t
+// in the given order, other entries possibly in between. Like ExpectEntry,
+// it is checked when t finishes.
+// Example:
+//
+//	sugarzerotest.InOrder(t,
+//		sugarzerotest.Entry().Message("payment authorized"),
+//		sugarzerotest.Entry().Message("payment captured"),
+//	)
+func InOrder(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	rec := recorderFor(t)
+	t.Cleanup(func() {
+		entries := rec.Entries()
+		next := 0
+		for _, entry := range entries {
+			if next < len(matchers) && matchers[next].Match(entry) {
+				next++
+			}
+		}
+		if next == len(matchers) {
+			return
+		}
+		t.Errorf("sugarzerotest: expected entries in order %s, but none matched %s after the previous ones; recorded entries:\n%s",
+			describeAll(matchers), matchers[next], rec.dump())
+	})
+}
+
+func describeAll(matchers []Matcher) string {
+	describes := make([]string, len(matchers))
+	for i, m := range matchers {
+		describes[i] = m.String()
+	}
+	return strings.Join(describes, " < ")
+}
+
+func recorderFor(t testing.TB) *Recorder {
+	t.Helper()
+	rec, ok := recorders.Load(t)
+	if !ok {
+		t.Fatalf("sugarzerotest: no entries recorded for %s; call Record first", t.Name())
+	}
+	return rec.(*Recorder)
+}
@@ -0,0 +1,112 @@
+package sugarzerotest_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/sugarzerotest"
+)
+
+// fakeT records failures and runs cleanups on demand, so failing
+// expectations can be asserted on.
+type fakeT struct {
+	testing.TB
+	cleanups []func()
+	errors   []string
+}
+
+func (f *fakeT) Helper()           {}
+func (f *fakeT) Name() string      { return "fake" }
+func (f *fakeT) Cleanup(fn func()) { f.cleanups = append(f.cleanups, fn) }
+func (f *fakeT) Errorf(format string, args ...any) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
+
+func (f *fakeT) finish() {
+	for i := len(f.cleanups) - 1; i >= 0; i-- {
+		f.cleanups[i]()
+	}
+}
+
+func newLogger(t *testing.T) context.Context {
+	t.Helper()
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	ctx, err := sugarzero.New(context.Background(), "debug", io.Discard)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return ctx
+}
+
+func pay(ctx context.Context, id string, amount int) {
+	ctx = sugarzero.WithFields(ctx, "payment_id", id, "amount", amount)
+	sugarzero.Debug(ctx, "payment authorized")
+	sugarzero.Info(ctx, "payment audited")
+	sugarzero.Info(ctx, "payment captured")
+}
+
+func TestExpectEntryPasses(t *testing.T) {
+	ctx := newLogger(t)
+	sugarzerotest.Record(t, ctx)
+
+	audit := sugarzerotest.Entry().Level("info").Message("payment audited")
+	sugarzerotest.ExpectEntry(t, audit.Field("payment_id", "p-1").Field("amount", 42)).Exactly(1)
+	sugarzerotest.ExpectEntry(t, audit).AtLeast(2)
+	sugarzerotest.ExpectEntry(t, sugarzerotest.Entry().Level("error")).Never()
+	sugarzerotest.InOrder(t,
+		sugarzerotest.Entry().Message("payment authorized").Field("payment_id", "p-1"),
+		sugarzerotest.Entry().MessageContains("captured").Field("payment_id", "p-1"),
+		sugarzerotest.Entry().Message("payment captured").Field("payment_id", "p-2"),
+	)
+
+	pay(ctx, "p-1", 42)
+	pay(ctx, "p-2", 7)
+}
+
+func TestExpectEntryReportsFailures(t *testing.T) {
+	ctx := newLogger(t)
+	ft := &fakeT{TB: t}
+	rec := sugarzerotest.Record(ft, ctx)
+
+	sugarzerotest.ExpectEntry(ft, sugarzerotest.Entry().Message("payment audited")).Exactly(1)
+	sugarzerotest.ExpectEntry(ft, sugarzerotest.Entry().HasField("refund_id"))
+	sugarzerotest.InOrder(ft,
+		sugarzerotest.Entry().Message("payment captured").Field("payment_id", "p-2"),
+		sugarzerotest.Entry().Message("payment authorized").Field("payment_id", "p-1"),
+	)
+
+	pay(ctx, "p-1", 42)
+	pay(ctx, "p-2", 42)
+	if n := len(rec.Entries()); n != 6 {
+		t.Fatalf("expected 6 recorded entries, got %d", n)
+	}
+	ft.finish()
+
+	if len(ft.errors) != 3 {
+		t.Fatalf("expected 3 failures, got %d: %q", len(ft.errors), ft.errors)
+	}
+	for _, want := range []string{
+		`expected exactly 1 entries matching {message="payment audited"}, got 2`,
+		`expected at least 1 entries matching {has refund_id}, got 0`,
+		`none matched {message="payment authorized" payment_id=p-1} after the previous ones`,
+	} {
+		if !strings.Contains(strings.Join(ft.errors, "\n"), want) {
+			t.Fatalf("expected a failure containing %q, got %q", want, ft.errors)
+		}
+	}
+	if !strings.Contains(ft.errors[0], `"payment_id":"p-1"`) {
+		t.Fatalf("expected failures to list the recorded entries, got %q", ft.errors[0])
+	}
+
+	sugarzero.Info(ctx, "after the test")
+	if n := len(rec.Entries()); n != 6 {
+		t.Fatalf("expected recording to stop with the test, got %d entries", n)
+	}
+}
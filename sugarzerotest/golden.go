@@ -0,0 +1,157 @@
+// Package sugarzerotest provides helpers for testing code that logs through
+// sugarzero.
+package sugarzerotest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty
+// value, makes AssertGolden rewrite golden files instead of comparing them.
+//
+//	SUGARZERO_UPDATE_GOLDEN=1 go test ./...
+const UpdateGoldenEnv = "SUGARZERO_UPDATE_GOLDEN"
+
+// volatileFields are replaced by placeholders before comparison because their
+// values change between runs.
+var volatileFields = []string{"time", "position", "trace_id", "span_id"}
+
+// Normalize rewrites a newline-delimited JSON log stream into a stable form:
+// volatile fields (time, position, trace_id, span_id) are replaced by
+// placeholders and whitespace is removed. Keys keep the order they were
+// written in, duplicate keys included, and numbers are kept as written. Lines
+// that are not JSON objects are kept as is.
+func Normalize(output []byte) []byte {
+	var normalized bytes.Buffer
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if line == "" {
+			continue
+		}
+		normalized.WriteString(normalizeLine(line))
+		normalized.WriteByte('\n')
+	}
+	return normalized.Bytes()
+}
+
+func normalizeLine(line string) string {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	if token, err := dec.Token(); err != nil || token != json.Delim('{') {
+		return line
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return line
+		}
+		key, _ := token.(string)
+		// Values are copied as written, so numbers and nested key order survive
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return line
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		if err := writeJSONString(&buf, key); err != nil {
+			return line
+		}
+		buf.WriteByte(':')
+		if slices.Contains(volatileFields, key) {
+			if err := writeJSONString(&buf, "<"+key+">"); err != nil {
+				return line
+			}
+			continue
+		}
+		if err := json.Compact(&buf, value); err != nil {
+			return line
+		}
+	}
+	if token, err := dec.Token(); err != nil || token != json.Delim('}') {
+		return line
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// writeJSONString writes s to buf as a JSON string, without escaping HTML.
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return err
+	}
+	// Drop the newline written by Encode
+	buf.Truncate(buf.Len() - 1)
+	return nil
+}
+
+// AssertGolden normalizes output and compares it with the golden file at path,
+// reporting a line diff on mismatch. When UpdateGoldenEnv is set, the golden
+// file is (re)written instead.
+func AssertGolden(t testing.TB, path string, output []byte) {
+	t.Helper()
+
+	got := Normalize(output)
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("sugarzerotest: create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("sugarzerotest: write golden file: %v", err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("sugarzerotest: read golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+
+	if diff := diffLines(string(want), string(got)); diff != "" {
+		t.Errorf("log output does not match %s (-want +got):\n%s", path, diff)
+	}
+}
+
+// diffLines returns a line-by-line diff of want and got, or "" if they match.
+func diffLines(want, got string) string {
+	if want == got {
+		return ""
+	}
+
+	wantLines := strings.Split(strings.TrimRight(want, "\n"), "\n")
+	gotLines := strings.Split(strings.TrimRight(got, "\n"), "\n")
+
+	var diff strings.Builder
+	for i := 0; i < max(len(wantLines), len(gotLines)); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w == g {
+			continue
+		}
+		fmt.Fprintf(&diff, "line %d:\n", i+1)
+		if i < len(wantLines) {
+			fmt.Fprintf(&diff, "- %s\n", w)
+		}
+		if i < len(gotLines) {
+			fmt.Fprintf(&diff, "+ %s\n", g)
+		}
+	}
+	return diff.String()
+}
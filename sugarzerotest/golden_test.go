@@ -0,0 +1,82 @@
+package sugarzerotest_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	"github.com/bigboss2063/sugarzero/sugarzerotest"
+)
+
+func TestAssertGoldenMatchesNormalizedOutput(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	var buf bytes.Buffer
+	ctx, err := sugarzero.New(context.Background(), "info", &buf)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithFields(ctx, "order_id", "o-1", "amount", 42)
+	sugarzero.Info(ctx, "order placed")
+	sugarzero.Warn(ctx, "payment retried")
+
+	sugarzerotest.AssertGolden(t, filepath.Join("testdata", "orders.golden"), buf.Bytes())
+}
+
+func TestNormalizeReplacesVolatileFields(t *testing.T) {
+	raw := `{"time":"2024-01-01T00:00:00Z","position":"/src/a.go:10","trace_id":"abc","message":"hi","level":"INFO"}` + "\n"
+
+	got := string(sugarzerotest.Normalize([]byte(raw)))
+	want := `{"time":"<time>","position":"<position>","trace_id":"<trace_id>","message":"hi","level":"INFO"}` + "\n"
+
+	if got != want {
+		t.Fatalf("unexpected normalized output:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestNormalizeKeepsNumbersAndDuplicateKeys(t *testing.T) {
+	raw := `{"level":"INFO", "id":9007199254740993, "ratio":1.50, "user":"a", "user":"b", "nested":{"z":1, "a":2}}` + "\n"
+
+	got := string(sugarzerotest.Normalize([]byte(raw)))
+	want := `{"level":"INFO","id":9007199254740993,"ratio":1.50,"user":"a","user":"b","nested":{"z":1,"a":2}}` + "\n"
+
+	if got != want {
+		t.Fatalf("unexpected normalized output:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+type recordingTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, strings.TrimSpace(fmt.Sprintf(format, args...)))
+}
+
+func TestAssertGoldenReportsDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "want.golden")
+	t.Setenv(sugarzerotest.UpdateGoldenEnv, "1")
+	sugarzerotest.AssertGolden(t, path, []byte(`{"message":"expected"}`+"\n"))
+	t.Setenv(sugarzerotest.UpdateGoldenEnv, "")
+
+	recorder := &recordingTB{TB: t}
+	sugarzerotest.AssertGolden(recorder, path, []byte(`{"message":"actual"}`+"\n"))
+
+	if len(recorder.errors) != 1 {
+		t.Fatalf("expected one reported mismatch, got %v", recorder.errors)
+	}
+	if !strings.Contains(recorder.errors[0], `- {"message":"expected"}`) || !strings.Contains(recorder.errors[0], `+ {"message":"actual"}`) {
+		t.Fatalf("expected diff in report, got %s", recorder.errors[0])
+	}
+}
@@ -0,0 +1,139 @@
+package sugarzero
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// SummaryMode decides what happens to regular entries of a request that
+// emits a summary.
+type SummaryMode int
+
+const (
+	// SummaryAlongside writes regular entries as usual.
+	SummaryAlongside SummaryMode = iota
+	// SummaryOnly drops regular entries below warn level, so each request
+	// produces a single canonical line plus its warnings and errors. The
+	// number of dropped entries is reported in the "suppressed_entries" field.
+	SummaryOnly
+)
+
+var summaryKey any = ctxKey{name: "summary"}
+
+// Summary accumulates fields over the lifetime of a request to be emitted as
+// one wide, canonical log entry. It is safe for concurrent use.
+type Summary struct {
+	mode       SummaryMode
+	suppressed atomic.Int64
+
+	mu     sync.Mutex
+	keys   []string
+	values map[string]any
+}
+
+// WithSummary starts a summary and stores it in the returned context, where
+// AddSummaryField can find it.
+func WithSummary(ctx context.Context, mode SummaryMode) (context.Context, *Summary) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	summary := &Summary{mode: mode, values: make(map[string]any)}
+	return context.WithValue(ctx, summaryKey, summary), summary
+}
+
+// SummaryFromContext returns the summary started with WithSummary, or nil.
+func SummaryFromContext(ctx context.Context) *Summary {
+	if ctx == nil {
+		return nil
+	}
+	summary, _ := ctx.Value(summaryKey).(*Summary)
+	return summary
+}
+
+// AddSummaryField sets key on the request summary in ctx. Setting a key again
+// replaces its value. It is a no-op when ctx carries no summary.
+// Example: sugarzero.AddSummaryField(ctx, "cart_items", len(cart.Items))
+func AddSummaryField(ctx context.Context, key string, value any) {
+	if summary := SummaryFromContext(ctx); summary != nil {
+		summary.Set(key, value)
+	}
+}
+
+// Set sets key on the summary, replacing any previous value.
+func (s *Summary) Set(key string, value any) {
+	if key == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.values[key]; !ok {
+		s.keys = append(s.keys, key)
+	}
+	s.values[key] = value
+}
+
+// Fields returns the accumulated fields as alternating key-value pairs in the
+// order they were first set.
+func (s *Summary) Fields() []any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fields := make([]any, 0, 2*len(s.keys))
+	for _, key := range s.keys {
+		fields = append(fields, key, s.values[key])
+	}
+	return fields
+}
+
+// Emit writes the summary as a single entry at level, together with the fields
+// already attached to ctx.
+func (s *Summary) Emit(ctx context.Context, level zerolog.Level, message string) {
+	if s.mode == SummaryOnly {
+		ctx = withUnscopedFields(ctx, "suppressed_entries", s.suppressed.Load())
+	}
+	// Detach the summary so SummaryOnly does not suppress its own entry
+	ctx = context.WithValue(ctx, summaryKey, (*Summary)(nil))
+	logAt(WithFields(ctx, s.Fields()...), level, message)
+}
+
+// suppresses reports whether a regular entry at level is dropped in favor of
+// the summary, counting it if so.
+func (s *Summary) suppresses(level zerolog.Level) bool {
+	if s.mode != SummaryOnly || level >= zerolog.WarnLevel {
+		return false
+	}
+	s.suppressed.Add(1)
+	return true
+}
+
+// SummaryMiddleware returns HTTP middleware that starts a summary for every
+// request and emits it when the handler returns, with method, path, status,
+// bytes, and duration_ms fields added. Requests answered with a 5xx status
+// are summarized at error level, all others at info.
+func SummaryMiddleware(mode SummaryMode) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			ctx, summary := WithSummary(r.Context(), mode)
+			recorder := &responseRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r.WithContext(ctx))
+
+			summary.Set("method", r.Method)
+			summary.Set("path", r.URL.Path)
+			summary.Set("status", recorder.statusCode())
+			summary.Set("bytes", recorder.bytes)
+			summary.Set("duration_ms", float64(time.Since(start).Microseconds())/1000)
+
+			level := zerolog.InfoLevel
+			if recorder.statusCode() >= http.StatusInternalServerError {
+				level = zerolog.ErrorLevel
+			}
+			summary.Emit(ctx, level, "request completed")
+		})
+	}
+}
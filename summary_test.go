@@ -0,0 +1,87 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func serveWithSummary(t *testing.T, mode sugarzero.SummaryMode, status int) []map[string]any {
+	t.Helper()
+	ctx, testWriter := setupTest(t, "debug")
+
+	handler := sugarzero.SummaryMiddleware(mode)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sugarzero.AddSummaryField(r.Context(), "cart_items", 3)
+		sugarzero.Info(r.Context(), "loaded cart")
+		sugarzero.AddSummaryField(r.Context(), "payment", "declined")
+		sugarzero.Warn(r.Context(), "payment declined")
+		w.WriteHeader(status)
+	}))
+	req := httptest.NewRequest(http.MethodPost, "/checkout", nil)
+	req = req.WithContext(sugarzero.WithField(ctx, "request_id", "req-1"))
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	var entries []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(testWriter.String()), "\n") {
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSummaryMiddlewareEmitsCanonicalLine(t *testing.T) {
+	entries := serveWithSummary(t, sugarzero.SummaryAlongside, http.StatusPaymentRequired)
+	if len(entries) != 3 {
+		t.Fatalf("expected two regular entries and a summary, got %d", len(entries))
+	}
+
+	summary := entries[2]
+	expected := map[string]any{
+		"message":    "request completed",
+		"level":      "INFO",
+		"request_id": "req-1",
+		"cart_items": float64(3),
+		"payment":    "declined",
+		"method":     "POST",
+		"path":       "/checkout",
+		"status":     float64(402),
+	}
+	for key, want := range expected {
+		if summary[key] != want {
+			t.Fatalf("expected %s=%v, got %v", key, want, summary[key])
+		}
+	}
+	if _, ok := summary["duration_ms"]; !ok {
+		t.Fatal("expected duration_ms in summary")
+	}
+}
+
+func TestSummaryOnlySuppressesRegularEntries(t *testing.T) {
+	entries := serveWithSummary(t, sugarzero.SummaryOnly, http.StatusInternalServerError)
+	if len(entries) != 2 {
+		t.Fatalf("expected the warning and the summary, got %d entries", len(entries))
+	}
+	if entries[0]["message"] != "payment declined" {
+		t.Fatalf("expected warnings to be kept, got %v", entries[0])
+	}
+	if entries[1]["level"] != "ERROR" || entries[1]["suppressed_entries"] != float64(1) {
+		t.Fatalf("unexpected summary %v", entries[1])
+	}
+}
+
+func TestAddSummaryFieldWithoutSummaryIsNoop(t *testing.T) {
+	ctx, testWriter := setupTest(t, "debug")
+	sugarzero.AddSummaryField(ctx, "ignored", true)
+	sugarzero.Info(ctx, "plain")
+	if bytes.Contains(testWriter.Bytes(), []byte("ignored")) {
+		t.Fatalf("expected no summary fields, got %s", testWriter.String())
+	}
+}
@@ -0,0 +1,188 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// WithTailBuffer keeps the last size entries in memory for TailHandler, which
+// replays them before streaming new entries. Entries are copied, so it costs
+// one allocation per entry; enable it where no central log store is
+// available, such as development and staging environments.
+// Example: NewWithOptions(ctx, "debug", WithTailBuffer(1000))
+func WithTailBuffer(size int) Option {
+	return func(o *options) {
+		o.tailSize = size
+	}
+}
+
+// tailFilter selects the entries streamed by TailHandler.
+type tailFilter struct {
+	level  zerolog.Level
+	query  string
+	fields map[string]string
+	expr   *Filter
+}
+
+// parseTailFilter reads the level, q, field, and filter query parameters.
+func parseTailFilter(r *http.Request) (tailFilter, error) {
+	query := r.URL.Query()
+	filter := tailFilter{level: zerolog.TraceLevel, query: query.Get("q")}
+	if level := query.Get("level"); level != "" {
+		lvl, err := parseLevel(level)
+		if err != nil {
+			return filter, err
+		}
+		filter.level = lvl
+	}
+	for _, field := range query["field"] {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return filter, fmt.Errorf("invalid field filter %q: must be key=value", field)
+		}
+		if filter.fields == nil {
+			filter.fields = make(map[string]string)
+		}
+		filter.fields[key] = value
+	}
+	if expr := query.Get("filter"); expr != "" {
+		compiled, err := ParseFilter(expr)
+		if err != nil {
+			return filter, err
+		}
+		filter.expr = compiled
+	}
+	return filter, nil
+}
+
+func (f tailFilter) matches(entry ringEntry) bool {
+	if entry.level < f.level || (entry.level == zerolog.NoLevel && f.level > zerolog.TraceLevel) {
+		return false
+	}
+	if f.query != "" && !bytes.Contains(entry.raw, []byte(f.query)) {
+		return false
+	}
+	if f.expr != nil && !f.expr.Match(entry.level, entry.raw) {
+		return false
+	}
+	if len(f.fields) == 0 {
+		return true
+	}
+	var fields map[string]any
+	if json.Unmarshal(entry.raw, &fields) != nil {
+		return false
+	}
+	for key, want := range f.fields {
+		got, ok := fields[key]
+		if !ok || fmt.Sprint(got) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// TailHandler streams the entries of the logger in ctx as server-sent
+// events, like "kubectl logs -f" for the service itself. Each event's data is
+// one JSON entry. The buffered entries are sent first, then new ones as they
+// are written. The logger must be created with WithTailBuffer.
+//
+// Query parameters filter the stream: level sets the minimum level, q
+// matches a substring of the encoded entry, and field=key=value, which may be
+// repeated, matches field values. filter takes a Filter expression.
+// follow=false ends the stream after the buffered entries.
+// Example: mux.Handle("/debug/tail", sugarzero.TailHandler(ctx))
+//
+//	curl -N 'localhost:8080/debug/tail?level=warn&field=tenant_id=acme'
+func TailHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", "GET")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		tail, err := tailFromContext(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		filter, err := parseTailFilter(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		follow, _ := strconv.ParseBool(r.URL.Query().Get("follow"))
+		if r.URL.Query().Get("follow") == "" {
+			follow = true
+		}
+
+		// The controller reaches the Flusher behind wrapping middleware
+		controller := http.NewResponseController(w)
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+
+		send := func(entry ringEntry) bool {
+			if !filter.matches(entry) {
+				return true
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", entry.raw); err != nil {
+				return false
+			}
+			return true
+		}
+		flush := func() {
+			_ = controller.Flush()
+		}
+
+		if !follow {
+			for _, entry := range tail.snapshot() {
+				if !send(entry) {
+					return
+				}
+			}
+			flush()
+			return
+		}
+
+		backlog, live, cancel := tail.watch()
+		defer cancel()
+		for _, entry := range backlog {
+			if !send(entry) {
+				return
+			}
+		}
+		flush()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case entry := <-live:
+				if !send(entry) {
+					return
+				}
+				flush()
+			}
+		}
+	})
+}
+
+func tailFromContext(ctx context.Context) (*entryRing, error) {
+	var tail *entryRing
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok && zl.events != nil {
+			tail = zl.events.tail
+		}
+	})
+	if tail == nil {
+		return nil, errors.New("sugarzero: log tail is not enabled, see WithTailBuffer")
+	}
+	return tail, nil
+}
@@ -0,0 +1,160 @@
+package sugarzero_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func newTailLogger(t *testing.T, size int) context.Context {
+	t.Helper()
+
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "debug",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithTailBuffer(size),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+	return ctx
+}
+
+func tailMessages(t *testing.T, body string) []string {
+	t.Helper()
+
+	var messages []string
+	for _, line := range strings.Split(body, "\n") {
+		data, ok := strings.CutPrefix(line, "data: ")
+		if !ok {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			t.Fatalf("invalid entry %q: %v", data, err)
+		}
+		messages = append(messages, entry["message"].(string))
+	}
+	return messages
+}
+
+func TestTailHandlerReplaysFilteredBuffer(t *testing.T) {
+	ctx := newTailLogger(t, 3)
+
+	sugarzero.Info(ctx, "evicted")
+	sugarzero.Debug(ctx, "debug")
+	sugarzero.Warn(sugarzero.WithField(ctx, "tenant_id", "acme"), "acme warn")
+	sugarzero.Error(sugarzero.WithField(ctx, "tenant_id", "other"), "other error")
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"debug", "acme warn", "other error"}},
+		{query: "level=warn", want: []string{"acme warn", "other error"}},
+		{query: "field=tenant_id=acme", want: []string{"acme warn"}},
+		{query: "q=other", want: []string{"other error"}},
+		{query: "filter=" + url.QueryEscape("level>=warn && tenant_id!='acme'"), want: []string{"other error"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/tail?follow=false&"+tt.query, nil)
+		sugarzero.TailHandler(ctx).ServeHTTP(rec, req)
+
+		if rec.Header().Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("%s: unexpected content type %q", tt.query, rec.Header().Get("Content-Type"))
+		}
+		if got := tailMessages(t, rec.Body.String()); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Fatalf("%s: expected %v, got %v", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestTailHandlerStreamsNewEntries(t *testing.T) {
+	ctx := newTailLogger(t, 10)
+	sugarzero.Info(ctx, "before")
+
+	server := httptest.NewServer(sugarzero.TailHandler(ctx))
+	defer server.Close()
+
+	reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL+"?level=info", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() string {
+		for lines.Scan() {
+			if data, ok := strings.CutPrefix(lines.Text(), "data: "); ok {
+				return tailMessages(t, "data: "+data)[0]
+			}
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return ""
+	}
+
+	if got := next(); got != "before" {
+		t.Fatalf("expected buffered entry first, got %q", got)
+	}
+	sugarzero.Debug(ctx, "filtered")
+	sugarzero.Info(ctx, "after")
+	if got := next(); got != "after" {
+		t.Fatalf("expected live entry, got %q", got)
+	}
+}
+
+func TestTailHandlerStreamsBehindMiddleware(t *testing.T) {
+	middlewares := map[string]func(http.Handler) http.Handler{
+		"access log": sugarzero.AccessLogMiddleware(io.Discard, sugarzero.AccessLogCommon),
+		"summary":    sugarzero.SummaryMiddleware(sugarzero.SummaryAlongside),
+	}
+	for name, middleware := range middlewares {
+		ctx := newTailLogger(t, 10)
+		sugarzero.Info(ctx, "before")
+
+		server := httptest.NewServer(middleware(sugarzero.TailHandler(ctx)))
+		reqCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		req, _ := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL, nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s: request failed: %v", name, err)
+		}
+
+		// Without a flush the buffered entry never reaches the client
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		if err != nil || !strings.HasPrefix(line, "data: ") {
+			t.Fatalf("%s: expected the buffered entry to be flushed, got %q: %v", name, line, err)
+		}
+		resp.Body.Close()
+		cancel()
+		server.Close()
+	}
+}
+
+func TestTailHandlerRequiresTailBuffer(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+
+	rec := httptest.NewRecorder()
+	sugarzero.TailHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tail", nil))
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
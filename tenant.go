@@ -0,0 +1,198 @@
+package sugarzero
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultTenantCapacity is the number of tenant writers kept open by
+// WithTenantRouting before the least recently used one is closed.
+const DefaultTenantCapacity = 64
+
+// TenantWriterFactory opens the writer that receives entries for tenant.
+// Writers implementing io.Closer are closed when evicted.
+type TenantWriterFactory func(tenant string) (io.Writer, error)
+
+// TenantRouter is a writer that shards entries by the value of a field, so each
+// tenant's logs land in a dedicated writer. Entries without the field go to the
+// fallback writer.
+type TenantRouter struct {
+	mu       sync.Mutex
+	field    string
+	factory  TenantWriterFactory
+	fallback io.Writer
+	capacity int
+	lru      *list.List
+	writers  map[string]*list.Element
+}
+
+// tenantWriter is an open tenant writer. Writes to it are serialized by mu,
+// outside the router's lock, and closed is set once it was evicted and closed.
+type tenantWriter struct {
+	tenant string
+	writer io.Writer
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// WithTenantRouting routes each entry to a writer obtained from factory for the
+// value of field (for example "tenant_id"). Entries without the field are
+// written to the configured writers. At most DefaultTenantCapacity tenant
+// writers are kept open at once.
+func WithTenantRouting(field string, factory TenantWriterFactory) Option {
+	return func(o *options) {
+		o.wrapWriter(func(fallback io.Writer) (io.Writer, error) {
+			return NewTenantRouter(field, factory, fallback, DefaultTenantCapacity)
+		})
+	}
+}
+
+// NewTenantRouter creates a TenantRouter keeping at most capacity tenant
+// writers open. A capacity <= 0 uses DefaultTenantCapacity.
+func NewTenantRouter(field string, factory TenantWriterFactory, fallback io.Writer, capacity int) (*TenantRouter, error) {
+	if field == "" {
+		return nil, errors.New("sugarzero: tenant field must not be empty")
+	}
+	if factory == nil {
+		return nil, errors.New("sugarzero: tenant writer factory must not be nil")
+	}
+	if capacity <= 0 {
+		capacity = DefaultTenantCapacity
+	}
+	return &TenantRouter{
+		field:    field,
+		factory:  factory,
+		fallback: fallback,
+		capacity: capacity,
+		lru:      list.New(),
+		writers:  make(map[string]*list.Element),
+	}, nil
+}
+
+// Write routes a single encoded entry to the writer of its tenant.
+func (r *TenantRouter) Write(p []byte) (int, error) {
+	return r.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel routes a single encoded entry to the writer of its tenant,
+// keeping its level for writers that route by it. The router's lock only
+// covers looking up or opening the writer, so a slow tenant writer does not
+// hold up the others.
+func (r *TenantRouter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	tenant := extractStringField(p, r.field)
+	if tenant == "" {
+		if r.fallback == nil {
+			return len(p), nil
+		}
+		return writeLevel(r.fallback, level, p)
+	}
+
+	for {
+		r.mu.Lock()
+		tw, evicted, err := r.writerFor(tenant)
+		r.mu.Unlock()
+		// A failing Close must not prevent routing to the new tenant.
+		_ = closeTenantWriters(evicted)
+		if err != nil {
+			return 0, err
+		}
+
+		tw.mu.Lock()
+		if tw.closed {
+			// Evicted between the lookup and the write; open it again
+			tw.mu.Unlock()
+			continue
+		}
+		n, err := writeLevel(tw.writer, level, p)
+		tw.mu.Unlock()
+		return n, err
+	}
+}
+
+// Close closes every open tenant writer that implements io.Closer.
+func (r *TenantRouter) Close() error {
+	r.mu.Lock()
+	var evicted []*tenantWriter
+	for r.lru.Len() > 0 {
+		evicted = append(evicted, r.evictOldest())
+	}
+	r.mu.Unlock()
+	return closeTenantWriters(evicted)
+}
+
+// writerFor returns the writer of tenant, opening it if needed, and the
+// writers evicted to make room for it, which the caller must close after
+// releasing r.mu. r.mu must be held.
+func (r *TenantRouter) writerFor(tenant string) (*tenantWriter, []*tenantWriter, error) {
+	if elem, ok := r.writers[tenant]; ok {
+		r.lru.MoveToFront(elem)
+		return elem.Value.(*tenantWriter), nil, nil
+	}
+
+	writer, err := r.factory(tenant)
+	if err != nil {
+		return nil, nil, fmt.Errorf("sugarzero: open writer for tenant %q: %w", tenant, err)
+	}
+
+	var evicted []*tenantWriter
+	for r.lru.Len() >= r.capacity {
+		evicted = append(evicted, r.evictOldest())
+	}
+	tw := &tenantWriter{tenant: tenant, writer: writer}
+	r.writers[tenant] = r.lru.PushFront(tw)
+	return tw, evicted, nil
+}
+
+// evictOldest removes the least recently used writer. r.mu must be held.
+func (r *TenantRouter) evictOldest() *tenantWriter {
+	elem := r.lru.Back()
+	r.lru.Remove(elem)
+	tw := elem.Value.(*tenantWriter)
+	delete(r.writers, tw.tenant)
+	return tw
+}
+
+// closeTenantWriters closes evicted writers once their in-flight writes are
+// done.
+func closeTenantWriters(evicted []*tenantWriter) error {
+	var errs []error
+	for _, tw := range evicted {
+		tw.mu.Lock()
+		tw.closed = true
+		if closer, ok := tw.writer.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		tw.mu.Unlock()
+	}
+	return errors.Join(errs...)
+}
+
+// extractStringField returns the value of key in the JSON object p, rendering
+// non-string values with their JSON representation.
+func extractStringField(p []byte, key string) string {
+	var entry map[string]json.RawMessage
+	if err := json.Unmarshal(p, &entry); err != nil {
+		return ""
+	}
+	raw, ok := entry[key]
+	if !ok {
+		return ""
+	}
+	var value string
+	if err := json.Unmarshal(raw, &value); err == nil {
+		return value
+	}
+	if string(raw) == "null" {
+		return ""
+	}
+	return string(raw)
+}
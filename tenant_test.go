@@ -0,0 +1,161 @@
+package sugarzero_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (b *closingBuffer) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestTenantRoutingShardsByField(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+
+	var shared bytes.Buffer
+	tenants := map[string]*closingBuffer{}
+
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(&shared),
+		sugarzero.WithTenantRouting("tenant_id", func(tenant string) (io.Writer, error) {
+			buf := &closingBuffer{}
+			tenants[tenant] = buf
+			return buf, nil
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	sugarzero.Info(sugarzero.WithField(ctx, "tenant_id", "acme"), "acme entry")
+	sugarzero.Info(sugarzero.WithField(ctx, "tenant_id", "globex"), "globex entry")
+	sugarzero.Info(ctx, "shared entry")
+
+	if got := tenants["acme"].String(); !strings.Contains(got, "acme entry") || strings.Contains(got, "globex") {
+		t.Fatalf("unexpected acme output: %s", got)
+	}
+	if got := tenants["globex"].String(); !strings.Contains(got, "globex entry") {
+		t.Fatalf("unexpected globex output: %s", got)
+	}
+	if got := shared.String(); !strings.Contains(got, "shared entry") || strings.Contains(got, "acme entry") {
+		t.Fatalf("unexpected shared output: %s", got)
+	}
+}
+
+func TestTenantRouterEvictsLeastRecentlyUsed(t *testing.T) {
+	opened := map[string]*closingBuffer{}
+	router, err := sugarzero.NewTenantRouter("tenant", func(tenant string) (io.Writer, error) {
+		buf := &closingBuffer{}
+		opened[tenant] = buf
+		return buf, nil
+	}, nil, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, tenant := range []string{"a", "b", "a", "c"} {
+		if _, err := router.Write([]byte(`{"tenant":"` + tenant + `"}` + "\n")); err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	}
+
+	if !opened["b"].closed {
+		t.Fatal("expected least recently used tenant b to be closed")
+	}
+	if opened["a"].closed || opened["c"].closed {
+		t.Fatal("expected recently used tenants to stay open")
+	}
+
+	if err := router.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+	if !opened["a"].closed || !opened["c"].closed {
+		t.Fatal("expected Close to close all tenant writers")
+	}
+}
+
+func TestTenantRouterKeepsLevels(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(sugarzero.Reset)
+
+	var low, high bytes.Buffer
+	ctx, err := sugarzero.NewWithOptions(context.Background(), "info",
+		sugarzero.WithWriters(io.Discard),
+		sugarzero.WithTenantRouting("tenant_id", func(tenant string) (io.Writer, error) {
+			return sugarzero.NewLevelSplitWriter(&low, &high, "warn")
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create logger: %v", err)
+	}
+
+	ctx = sugarzero.WithField(ctx, "tenant_id", "acme")
+	sugarzero.Info(ctx, "info entry")
+	sugarzero.Error(ctx, "error entry")
+
+	if got := low.String(); !strings.Contains(got, "info entry") || strings.Contains(got, "error entry") {
+		t.Fatalf("unexpected low output: %s", got)
+	}
+	if got := high.String(); !strings.Contains(got, "error entry") {
+		t.Fatalf("expected the tenant writer to receive the level, got %s", got)
+	}
+}
+
+// blockingWriter blocks every write until release is closed.
+type blockingWriter struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	close(w.started)
+	<-w.release
+	return len(p), nil
+}
+
+func TestTenantRouterWritesOutsideLock(t *testing.T) {
+	slow := &blockingWriter{started: make(chan struct{}), release: make(chan struct{})}
+	var fast bytes.Buffer
+	router, err := sugarzero.NewTenantRouter("tenant", func(tenant string) (io.Writer, error) {
+		if tenant == "slow" {
+			return slow, nil
+		}
+		return &fast, nil
+	}, nil, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	go func() {
+		_, _ = router.Write([]byte(`{"tenant":"slow"}` + "\n"))
+	}()
+	<-slow.started
+	defer close(slow.release)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := router.Write([]byte(`{"tenant":"fast"}` + "\n"))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected write error: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected a slow tenant writer not to block other tenants")
+	}
+}
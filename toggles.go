@@ -0,0 +1,146 @@
+package sugarzero
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Enrichers that can be switched off at runtime with EnableFeature, to shed
+// logging CPU cost under load without redeploying.
+const (
+	// FeatureCaller adds the caller position. Enabled by default.
+	FeatureCaller = "caller"
+	// FeatureFunction adds the calling function name as FunctionFieldName.
+	// Disabled by default.
+	FeatureFunction = "function"
+	// FeatureStack adds the stack to entries logged by LogPanic and
+	// RecoverAndLog. Enabled by default.
+	FeatureStack = "stack"
+	// FeatureRuntimeStats lets StartRuntimeStatsLogger collect and log
+	// runtime statistics. Enabled by default.
+	FeatureRuntimeStats = "runtime_stats"
+)
+
+// FunctionFieldName is the field holding the calling function with
+// FeatureFunction enabled.
+const FunctionFieldName = "function"
+
+var features = map[string]*atomic.Bool{
+	FeatureCaller:       new(atomic.Bool),
+	FeatureFunction:     new(atomic.Bool),
+	FeatureStack:        new(atomic.Bool),
+	FeatureRuntimeStats: new(atomic.Bool),
+}
+
+var defaultFeatures = map[string]bool{
+	FeatureCaller:       true,
+	FeatureFunction:     false,
+	FeatureStack:        true,
+	FeatureRuntimeStats: true,
+}
+
+func init() {
+	resetFeatures()
+}
+
+func resetFeatures() {
+	for name, enabled := range defaultFeatures {
+		features[name].Store(enabled)
+	}
+}
+
+// EnableFeature switches a feature on or off for every logger in the
+// process. It takes effect on the next entry.
+// Example: sugarzero.EnableFeature(sugarzero.FeatureCaller, false)
+func EnableFeature(name string, enabled bool) error {
+	feature, ok := features[name]
+	if !ok {
+		return fmt.Errorf("sugarzero: unknown feature %q: must be one of %s",
+			name, strings.Join(slices.Sorted(maps.Keys(features)), ", "))
+	}
+	feature.Store(enabled)
+	return nil
+}
+
+// FeatureEnabled reports whether a feature is on; unknown features are off.
+func FeatureEnabled(name string) bool {
+	feature, ok := features[name]
+	return ok && feature.Load()
+}
+
+// Features returns the state of every feature.
+func Features() map[string]bool {
+	states := make(map[string]bool, len(features))
+	for name, feature := range features {
+		states[name] = feature.Load()
+	}
+	return states
+}
+
+// FeaturesHandler serves the feature states as JSON on GET, and on POST
+// applies a JSON object of states, e.g. {"caller": false}, and serves the
+// result. Unknown features are rejected before any state changes.
+func FeaturesHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet, http.MethodHead:
+		case http.MethodPost:
+			var states map[string]bool
+			if err := json.NewDecoder(r.Body).Decode(&states); err != nil {
+				http.Error(w, "invalid JSON body", http.StatusBadRequest)
+				return
+			}
+			for name := range states {
+				if _, ok := features[name]; !ok {
+					http.Error(w, fmt.Sprintf("unknown feature %q", name), http.StatusBadRequest)
+					return
+				}
+			}
+			for name, enabled := range states {
+				features[name].Store(enabled)
+			}
+		default:
+			w.Header().Set("Allow", "GET, HEAD, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Features())
+	})
+}
+
+// callerHook adds the caller position while FeatureCaller is enabled. It
+// replaces zerolog's Context.Caller, which cannot be switched off.
+type callerHook struct{}
+
+func (callerHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if features[FeatureCaller].Load() {
+		// Skips Run, the hook loop in zerolog, and Event.Caller itself
+		e.Caller(3)
+	}
+}
+
+// callerFunction returns the name of the first function on the stack outside
+// sugarzero and zerolog, e.g. "example.com/shop/payment.(*Service).Charge".
+func callerFunction() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/bigboss2063/sugarzero.") &&
+			!strings.HasPrefix(frame.Function, "github.com/rs/zerolog.") {
+			return frame.Function
+		}
+		if !more {
+			return ""
+		}
+	}
+}
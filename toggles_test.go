@@ -0,0 +1,93 @@
+package sugarzero_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestEnableFeatureTogglesEnrichers(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	sugarzero.Info(ctx, "with caller")
+	entry := readLogEntry(t, buf)
+	if position, _ := entry["position"].(string); !strings.Contains(position, "toggles_test.go") {
+		t.Fatalf("expected caller position, got %v", entry)
+	}
+	if _, ok := entry[sugarzero.FunctionFieldName]; ok {
+		t.Fatalf("expected no function name by default, got %v", entry)
+	}
+
+	if err := sugarzero.EnableFeature(sugarzero.FeatureCaller, false); err != nil {
+		t.Fatalf("EnableFeature failed: %v", err)
+	}
+	if err := sugarzero.EnableFeature(sugarzero.FeatureFunction, true); err != nil {
+		t.Fatalf("EnableFeature failed: %v", err)
+	}
+	sugarzero.Info(ctx, "without caller")
+	entry = readLogEntry(t, buf, 1)
+	if _, ok := entry["position"]; ok {
+		t.Fatalf("expected caller position to be switched off, got %v", entry)
+	}
+	if fn, _ := entry[sugarzero.FunctionFieldName].(string); !strings.HasSuffix(fn, "TestEnableFeatureTogglesEnrichers") {
+		t.Fatalf("expected the test function name, got %v", entry)
+	}
+
+	sugarzero.LogPanic(ctx, "boom")
+	if _, ok := readLogEntry(t, buf, 2)["stack"]; !ok {
+		t.Fatal("expected a panic stack by default")
+	}
+	if err := sugarzero.EnableFeature(sugarzero.FeatureStack, false); err != nil {
+		t.Fatalf("EnableFeature failed: %v", err)
+	}
+	sugarzero.LogPanic(ctx, "boom")
+	if _, ok := readLogEntry(t, buf, 3)["stack"]; ok {
+		t.Fatal("expected the panic stack to be switched off")
+	}
+
+	if err := sugarzero.EnableFeature("colors", true); err == nil {
+		t.Fatal("expected unknown feature to be rejected")
+	}
+	if sugarzero.FeatureEnabled("colors") {
+		t.Fatal("expected unknown feature to be off")
+	}
+
+	sugarzero.Reset()
+	if !sugarzero.FeatureEnabled(sugarzero.FeatureCaller) || sugarzero.FeatureEnabled(sugarzero.FeatureFunction) {
+		t.Fatalf("expected Reset to restore defaults, got %v", sugarzero.Features())
+	}
+}
+
+func TestFeaturesHandler(t *testing.T) {
+	sugarzero.Reset()
+	t.Cleanup(func() {
+		sugarzero.Reset()
+	})
+	handler := sugarzero.FeaturesHandler()
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/log-features", strings.NewReader(`{"caller": false, "runtime_stats": false}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var states map[string]bool
+	if err := json.Unmarshal(rec.Body.Bytes(), &states); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if states[sugarzero.FeatureCaller] || states[sugarzero.FeatureRuntimeStats] || !states[sugarzero.FeatureStack] {
+		t.Fatalf("unexpected states %v", states)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/log-features", strings.NewReader(`{"stack": false, "colors": true}`)))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown feature, got %d", rec.Code)
+	}
+	if !sugarzero.FeatureEnabled(sugarzero.FeatureStack) {
+		t.Fatal("expected a rejected request to change nothing")
+	}
+}
@@ -0,0 +1,132 @@
+package sugarzero
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// TraceHeaderParser extracts a trace and span ID from request headers. IDs are
+// returned as lowercase hex so they correlate with OpenTelemetry IDs.
+type TraceHeaderParser func(h http.Header) (traceID, spanID string, ok bool)
+
+// DefaultTraceHeaderParsers are tried in order when no parsers are given:
+// W3C traceparent, B3, then AWS X-Ray.
+var DefaultTraceHeaderParsers = []TraceHeaderParser{
+	ParseW3CTraceHeaders,
+	ParseB3TraceHeaders,
+	ParseXRayTraceHeaders,
+}
+
+// WithTraceHeaders stores the trace and span ID found in h in ctx, so entries
+// carry trace_id and span_id even when no OpenTelemetry SDK is installed. An
+// active recording span still takes precedence when logging. parsers are
+// tried in order; none defaults to DefaultTraceHeaderParsers.
+//
+// gRPC interceptors can use it by copying the incoming metadata into an
+// http.Header with Header.Add, which canonicalizes the keys.
+func WithTraceHeaders(ctx context.Context, h http.Header, parsers ...TraceHeaderParser) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(parsers) == 0 {
+		parsers = DefaultTraceHeaderParsers
+	}
+	for _, parse := range parsers {
+		if traceID, spanID, ok := parse(h); ok {
+			return context.WithValue(ctx, traceKey, &traceInfo{traceID: traceID, spanID: spanID})
+		}
+	}
+	return ctx
+}
+
+// TraceHeadersMiddleware returns HTTP middleware that applies WithTraceHeaders
+// to every request.
+func TraceHeadersMiddleware(parsers ...TraceHeaderParser) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithTraceHeaders(r.Context(), r.Header, parsers...)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// ParseW3CTraceHeaders reads the W3C traceparent header,
+// e.g. "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func ParseW3CTraceHeaders(h http.Header) (string, string, bool) {
+	parts := strings.Split(strings.TrimSpace(h.Get("traceparent")), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" {
+		return "", "", false
+	}
+	return validTraceIDs(parts[1], parts[2])
+}
+
+// ParseB3TraceHeaders reads the B3 single header ("b3: {trace}-{span}-...")
+// or the multi headers X-B3-TraceId and X-B3-SpanId. 64-bit trace IDs are
+// left-padded to 128 bits.
+func ParseB3TraceHeaders(h http.Header) (string, string, bool) {
+	if single := strings.TrimSpace(h.Get("b3")); single != "" {
+		parts := strings.Split(single, "-")
+		if len(parts) >= 2 {
+			return validTraceIDs(padTraceID(parts[0]), parts[1])
+		}
+		return "", "", false
+	}
+	return validTraceIDs(padTraceID(strings.TrimSpace(h.Get("X-B3-TraceId"))), strings.TrimSpace(h.Get("X-B3-SpanId")))
+}
+
+// ParseXRayTraceHeaders reads the AWS X-Ray header, e.g.
+// "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1".
+// The root is converted to the 32-hex-digit form used by OpenTelemetry.
+func ParseXRayTraceHeaders(h http.Header) (string, string, bool) {
+	var root, parent string
+	for _, part := range strings.Split(h.Get("X-Amzn-Trace-Id"), ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			root = value
+		case "Parent":
+			parent = value
+		}
+	}
+	segments := strings.Split(root, "-")
+	if len(segments) != 3 || segments[0] != "1" {
+		return "", "", false
+	}
+	return validTraceIDs(segments[1]+segments[2], parent)
+}
+
+func padTraceID(id string) string {
+	if len(id) == 16 {
+		return "0000000000000000" + id
+	}
+	return id
+}
+
+// validTraceIDs lowercases the IDs and checks they are non-zero hex of the
+// lengths used by OpenTelemetry.
+func validTraceIDs(traceID, spanID string) (string, string, bool) {
+	traceID, spanID = strings.ToLower(traceID), strings.ToLower(spanID)
+	if !isNonZeroHex(traceID, 32) || !isNonZeroHex(spanID, 16) {
+		return "", "", false
+	}
+	return traceID, spanID, true
+}
+
+func isNonZeroHex(s string, length int) bool {
+	if len(s) != length {
+		return false
+	}
+	nonZero := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '0':
+		case c >= '1' && c <= '9', c >= 'a' && c <= 'f':
+			nonZero = true
+		default:
+			return false
+		}
+	}
+	return nonZero
+}
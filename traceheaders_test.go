@@ -0,0 +1,78 @@
+package sugarzero_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestTraceHeaderParsers(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		traceID string
+		spanID  string
+	}{
+		{
+			name:    "w3c",
+			headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			traceID: "4bf92f3577b34da6a3ce929d0e0e4736",
+			spanID:  "00f067aa0ba902b7",
+		},
+		{
+			name:    "b3 single",
+			headers: map[string]string{"b3": "80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+			traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+		},
+		{
+			name:    "b3 multi with 64-bit trace id",
+			headers: map[string]string{"X-B3-TraceId": "64FE8B2A57D3EFF7", "X-B3-SpanId": "e457b5a2e4d86bd1"},
+			traceID: "000000000000000064fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+		},
+		{
+			name:    "x-ray",
+			headers: map[string]string{"X-Amzn-Trace-Id": "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1"},
+			traceID: "5759e988bd862e3fe1be46a994272793",
+			spanID:  "53995c3f42cd8ad8",
+		},
+		{
+			name:    "invalid",
+			headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, testWriter := setupTest(t, "info")
+
+			var logged bool
+			handler := sugarzero.TraceHeadersMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				sugarzero.Info(r.Context(), "handled")
+				logged = true
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+			if !logged {
+				t.Fatal("handler was not called")
+			}
+
+			entry := readLogEntry(t, testWriter)
+			if tt.traceID == "" {
+				if _, ok := entry["trace_id"]; ok {
+					t.Fatalf("expected no trace_id for invalid headers, got %v", entry["trace_id"])
+				}
+				return
+			}
+			if entry["trace_id"] != tt.traceID || entry["span_id"] != tt.spanID {
+				t.Fatalf("expected %s/%s, got %v/%v", tt.traceID, tt.spanID, entry["trace_id"], entry["span_id"])
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package sugarzero
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceparentHeader is the W3C Trace Context request header.
+const TraceparentHeader = "traceparent"
+
+// WithNewTrace stores a newly generated W3C trace and span ID in ctx, so
+// entries carry trace_id and span_id before tracing is adopted. ctx is
+// returned unchanged if it already has a valid span or trace IDs.
+func WithNewTrace(ctx context.Context) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if hasTraceContext(ctx) {
+		return ctx
+	}
+	var ids [24]byte
+	_, _ = rand.Read(ids[:])
+	// All-zero IDs are invalid; the odds of generating one are negligible,
+	// but a fixed bit keeps them impossible.
+	ids[15] |= 1
+	ids[23] |= 1
+	return context.WithValue(ctx, traceKey, &traceInfo{
+		traceID: hex.EncodeToString(ids[:16]),
+		spanID:  hex.EncodeToString(ids[16:]),
+	})
+}
+
+// Traceparent returns the W3C traceparent header value for the active span
+// in ctx, or else for the trace IDs set by WithNewTrace or WithTraceHeaders,
+// and "" if ctx has neither.
+func Traceparent(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		return "00-" + spanCtx.TraceID().String() + "-" + spanCtx.SpanID().String() + "-" + spanCtx.TraceFlags().String()
+	}
+	if info := traceFromContext(ctx); info != nil {
+		return "00-" + info.traceID + "-" + info.spanID + "-01"
+	}
+	return ""
+}
+
+// TraceparentMiddleware returns HTTP middleware that gives every request a
+// trace: IDs from the request headers are used as WithTraceHeaders does, and
+// requests without them get new IDs from WithNewTrace. The traceparent is
+// echoed in the response so clients can quote it when reporting problems.
+// Requests with an active span, e.g. from otelhttp, are left alone.
+func TraceparentMiddleware(parsers ...TraceHeaderParser) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithNewTrace(WithTraceHeaders(r.Context(), r.Header, parsers...))
+			if traceparent := Traceparent(ctx); traceparent != "" {
+				w.Header().Set(TraceparentHeader, traceparent)
+			}
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TraceparentTransport returns an http.RoundTripper that adds the traceparent
+// of the request context to outgoing requests that have none, so downstream
+// services log the same trace_id. A nil base uses http.DefaultTransport.
+// Example: client := &http.Client{Transport: sugarzero.TraceparentTransport(nil)}
+func TraceparentTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return traceparentTransport{base: base}
+}
+
+type traceparentTransport struct {
+	base http.RoundTripper
+}
+
+func (t traceparentTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	if r.Header.Get(TraceparentHeader) == "" {
+		if traceparent := Traceparent(r.Context()); traceparent != "" {
+			r = r.Clone(r.Context())
+			r.Header.Set(TraceparentHeader, traceparent)
+		}
+	}
+	return t.base.RoundTrip(r)
+}
+
+func hasTraceContext(ctx context.Context) bool {
+	return trace.SpanContextFromContext(ctx).IsValid() || traceFromContext(ctx) != nil
+}
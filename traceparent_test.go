@@ -0,0 +1,72 @@
+package sugarzero_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-[0-9a-f]{16}-01$`)
+
+func TestTraceparentMiddlewareGeneratesTrace(t *testing.T) {
+	ctx, buf := setupTest(t, "info")
+
+	var outgoing string
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		outgoing = r.Header.Get(sugarzero.TraceparentHeader)
+	}))
+	defer downstream.Close()
+	client := &http.Client{Transport: sugarzero.TraceparentTransport(nil)}
+
+	handler := sugarzero.TraceparentMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sugarzero.Info(r.Context(), "handled")
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("downstream call failed: %v", err)
+			return
+		}
+		resp.Body.Close()
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	match := traceparentPattern.FindStringSubmatch(rec.Header().Get(sugarzero.TraceparentHeader))
+	if match == nil {
+		t.Fatalf("expected a generated traceparent, got %q", rec.Header().Get(sugarzero.TraceparentHeader))
+	}
+	if entry := readLogEntry(t, buf); entry["trace_id"] != match[1] {
+		t.Fatalf("expected trace_id %s, got %v", match[1], entry)
+	}
+	if outgoing != match[0] {
+		t.Fatalf("expected traceparent %q on the outgoing request, got %q", match[0], outgoing)
+	}
+}
+
+func TestTraceparentMiddlewareContinuesIncomingTrace(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+
+	handler := sugarzero.TraceparentMiddleware()(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	req.Header.Set("traceparent", testTraceparent)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(sugarzero.TraceparentHeader); got != testTraceparent {
+		t.Fatalf("expected the incoming traceparent, got %q", got)
+	}
+}
+
+func TestWithNewTraceKeepsExistingTrace(t *testing.T) {
+	ctx := sugarzero.WithNewTrace(context.Background())
+	if sugarzero.WithNewTrace(ctx) != ctx {
+		t.Fatal("expected the existing trace to be kept")
+	}
+	if sugarzero.Traceparent(context.Background()) != "" {
+		t.Fatal("expected no traceparent without a trace")
+	}
+}
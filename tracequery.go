@@ -0,0 +1,79 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// EntriesForTrace returns the entries of traceID retained by the tail buffer,
+// oldest first. It returns nil unless the logger was created with
+// WithTailBuffer.
+func (l *ZeroLogger) EntriesForTrace(traceID string) []Entry {
+	if traceID == "" || l.events == nil || l.events.tail == nil {
+		return nil
+	}
+	return entriesForTrace(l.events.tail, traceID)
+}
+
+// EntriesForTrace returns the entries of traceID retained by the tail buffer
+// of the logger in ctx, oldest first.
+func EntriesForTrace(ctx context.Context, traceID string) []Entry {
+	var entries []Entry
+	withLogger(ctx, func(logger Logger, _ context.Context) {
+		if zl, ok := logger.(*ZeroLogger); ok {
+			entries = zl.EntriesForTrace(traceID)
+		}
+	})
+	return entries
+}
+
+func entriesForTrace(tail *entryRing, traceID string) []Entry {
+	var entries []Entry
+	needle := []byte(traceID)
+	for _, buffered := range tail.snapshot() {
+		if !bytes.Contains(buffered.raw, needle) {
+			continue
+		}
+		entry, err := ParseEntry(buffered.raw)
+		if err == nil && entry.Fields["trace_id"] == traceID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// TraceEntriesHandler serves the entries of the trace named by the trace_id
+// query parameter as a JSON array, oldest first, to debug a single failed
+// request without querying the central log store. The logger must be created
+// with WithTailBuffer.
+// Example: mux.Handle("/debug/trace", sugarzero.TraceEntriesHandler(ctx))
+//
+//	curl 'localhost:8080/debug/trace?trace_id=4bf92f3577b34da6a3ce929d0e0e4736'
+func TraceEntriesHandler(ctx context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		traceID := r.URL.Query().Get("trace_id")
+		if traceID == "" {
+			http.Error(w, "missing trace_id", http.StatusBadRequest)
+			return
+		}
+		tail, err := tailFromContext(ctx)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+
+		entries := entriesForTrace(tail, traceID)
+		if entries == nil {
+			entries = []Entry{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(entries)
+	})
+}
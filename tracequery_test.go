@@ -0,0 +1,58 @@
+package sugarzero_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bigboss2063/sugarzero"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestEntriesForTrace(t *testing.T) {
+	ctx := newTailLogger(t, 10)
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() {
+		_ = tp.Shutdown(context.Background())
+	})
+	tracer := tp.Tracer("test-tracer")
+	failed, failedSpan := tracer.Start(ctx, "failed-request")
+	defer failedSpan.End()
+	other, otherSpan := tracer.Start(ctx, "other-request")
+	defer otherSpan.End()
+	failedID := failedSpan.SpanContext().TraceID().String()
+
+	sugarzero.Info(failed, "request received")
+	sugarzero.Info(other, "unrelated request")
+	sugarzero.Error(failed, "request failed")
+
+	entries := sugarzero.EntriesForTrace(ctx, failedID)
+	if len(entries) != 2 || entries[0].Message != "request received" || entries[1].Message != "request failed" {
+		t.Fatalf("unexpected entries: %+v", entries)
+	}
+
+	rec := httptest.NewRecorder()
+	sugarzero.TraceEntriesHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/trace?trace_id="+failedID, nil))
+	var served []sugarzero.Entry
+	if err := json.Unmarshal(rec.Body.Bytes(), &served); err != nil || len(served) != 2 {
+		t.Fatalf("unexpected response %q: %v", rec.Body.String(), err)
+	}
+
+	rec = httptest.NewRecorder()
+	sugarzero.TraceEntriesHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/trace", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 without trace_id, got %d", rec.Code)
+	}
+}
+
+func TestTraceEntriesHandlerWithoutTailBuffer(t *testing.T) {
+	ctx, _ := setupTest(t, "info")
+
+	rec := httptest.NewRecorder()
+	sugarzero.TraceEntriesHandler(ctx).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/trace?trace_id=abc", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
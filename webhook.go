@@ -0,0 +1,355 @@
+package sugarzero
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+const (
+	// DefaultWebhookInterval is the minimum interval between webhook posts
+	// with the same key.
+	DefaultWebhookInterval = time.Minute
+	// DefaultWebhookTitle is the title template used when none is configured.
+	DefaultWebhookTitle = "[{{.Level}}] {{.Message}}"
+	// DefaultWebhookBody is the body template used when none is configured.
+	DefaultWebhookBody = "{{range $key, $value := .Fields}}{{$key}}: {{$value}}\n{{end}}"
+
+	webhookQueueSize   = 16
+	webhookPostTimeout = 10 * time.Second
+	// webhookPruneSize is the number of throttled keys above which idle keys
+	// are dropped.
+	webhookPruneSize = 1024
+)
+
+// WebhookFormat selects the payload posted by a WebhookNotifier.
+type WebhookFormat int
+
+const (
+	// WebhookGeneric posts {"title", "body", "level", "message", "fields"}.
+	WebhookGeneric WebhookFormat = iota
+	// WebhookSlack posts a Slack incoming webhook message.
+	WebhookSlack
+	// WebhookTeams posts a Microsoft Teams message card.
+	WebhookTeams
+)
+
+// WebhookConfig configures a WebhookNotifier.
+type WebhookConfig struct {
+	// URL is the webhook endpoint.
+	URL string
+	// Format selects the payload. Defaults to WebhookGeneric.
+	Format WebhookFormat
+	// Title and Body are text/template templates executed with the LogEvent.
+	// They default to DefaultWebhookTitle and DefaultWebhookBody.
+	Title string
+	Body  string
+	// Interval is the minimum interval between posts with the same key.
+	// Entries matched in between are counted, and the last of them is posted
+	// with the count once the interval is over, or on Close. Defaults to
+	// DefaultWebhookInterval.
+	Interval time.Duration
+	// Key groups entries for throttling, e.g. by the rule that matched them;
+	// nil uses the dedup_key field (see WithDedupKey), else the message.
+	Key func(LogEvent) string
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+}
+
+// WebhookNotifier posts matched entries to a Slack, Teams, or generic
+// webhook, for small teams that want a chat message when something breaks.
+// Its Notify method is an OnEvent callback; combine it with MatchFilter to
+// select entries. Posts are throttled per key, so one noisy failure does not
+// hide others. They are sent in the background and failures are reported
+// like writer errors.
+// Example:
+//
+//	notifier, err := sugarzero.NewWebhookNotifier(sugarzero.WebhookConfig{URL: hookURL, Format: sugarzero.WebhookSlack})
+//	stop, err := logger.OnEvent("info", sugarzero.MatchFilter(sugarzero.MustParseFilter(
+//		"level>=error || category=='deploy'")), notifier.Notify)
+type WebhookNotifier struct {
+	cfg   WebhookConfig
+	title *template.Template
+	body  *template.Template
+	queue chan []byte
+	done  chan struct{}
+
+	mu     sync.Mutex
+	keys   map[string]*webhookKey
+	closed bool
+}
+
+// webhookKey is the throttling state of one key.
+type webhookKey struct {
+	last time.Time
+	// suppressed counts the entries matched since last; pending is the
+	// latest of them, posted by timer once the interval is over.
+	suppressed int
+	pending    LogEvent
+	timer      *time.Timer
+}
+
+// NewWebhookNotifier validates cfg and starts the notifier.
+func NewWebhookNotifier(cfg WebhookConfig) (*WebhookNotifier, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("sugarzero: webhook URL must not be empty")
+	}
+	if cfg.Title == "" {
+		cfg.Title = DefaultWebhookTitle
+	}
+	if cfg.Body == "" {
+		cfg.Body = DefaultWebhookBody
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultWebhookInterval
+	}
+	title, err := template.New("title").Parse(cfg.Title)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: invalid webhook title: %w", err)
+	}
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		return nil, fmt.Errorf("sugarzero: invalid webhook body: %w", err)
+	}
+
+	n := &WebhookNotifier{
+		cfg:   cfg,
+		title: title,
+		body:  body,
+		queue: make(chan []byte, webhookQueueSize),
+		done:  make(chan struct{}),
+		keys:  make(map[string]*webhookKey),
+	}
+	go n.run()
+	return n, nil
+}
+
+// Notify queues a post for event unless one with the same key was sent
+// within the interval.
+func (n *WebhookNotifier) Notify(event LogEvent) {
+	key := n.key(event)
+	event.Raw = nil
+
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return
+	}
+	now := time.Now()
+	k := n.keys[key]
+	if k == nil {
+		n.prune(now)
+		k = &webhookKey{}
+		n.keys[key] = k
+	}
+	if !k.last.IsZero() && now.Sub(k.last) < n.cfg.Interval {
+		n.suppress(key, k, event, 1)
+		n.mu.Unlock()
+		return
+	}
+	k.last = now
+	suppressed := k.suppressed
+	k.suppressed, k.pending = 0, LogEvent{}
+	if k.timer != nil {
+		k.timer.Stop()
+		k.timer = nil
+	}
+	n.mu.Unlock()
+
+	n.send(key, event, suppressed)
+}
+
+// Close posts the entries suppressed so far, sends the queued posts, and
+// stops the notifier.
+func (n *WebhookNotifier) Close() error {
+	n.mu.Lock()
+	if n.closed {
+		n.mu.Unlock()
+		return nil
+	}
+	n.closed = true
+	var pending []LogEvent
+	var counts []int
+	for _, k := range n.keys {
+		if k.timer != nil {
+			k.timer.Stop()
+			k.timer = nil
+		}
+		if k.suppressed > 0 {
+			pending = append(pending, k.pending)
+			counts = append(counts, k.suppressed-1)
+		}
+	}
+	n.mu.Unlock()
+
+	// Closed stops Notify and the timers from queueing, so these posts wait
+	// for room instead of being dropped
+	for i, event := range pending {
+		payload, err := n.payload(event, counts[i])
+		if err != nil {
+			reportWriteError(err)
+			continue
+		}
+		n.queue <- payload
+	}
+	close(n.queue)
+	<-n.done
+	return nil
+}
+
+// key returns the throttling key of event.
+func (n *WebhookNotifier) key(event LogEvent) string {
+	if n.cfg.Key != nil {
+		return n.cfg.Key(event)
+	}
+	if key, _ := event.Fields[DedupKeyFieldName].(string); key != "" {
+		return key
+	}
+	return event.Message
+}
+
+// suppress counts count entries of key ending with event, and arms the timer
+// posting them once the interval is over. n.mu must be held.
+func (n *WebhookNotifier) suppress(key string, k *webhookKey, event LogEvent, count int) {
+	k.suppressed += count
+	k.pending = event
+	if k.timer == nil {
+		k.timer = time.AfterFunc(n.cfg.Interval-time.Since(k.last), func() {
+			n.flush(key)
+		})
+	}
+}
+
+// flush posts the last suppressed entry of key with the count of the others.
+func (n *WebhookNotifier) flush(key string) {
+	n.mu.Lock()
+	k := n.keys[key]
+	if n.closed || k == nil {
+		n.mu.Unlock()
+		return
+	}
+	k.timer = nil
+	if k.suppressed == 0 {
+		n.mu.Unlock()
+		return
+	}
+	event, suppressed := k.pending, k.suppressed-1
+	k.last = time.Now()
+	k.suppressed, k.pending = 0, LogEvent{}
+	n.mu.Unlock()
+
+	n.send(key, event, suppressed)
+}
+
+// send queues a post for event, counting it as suppressed again if the queue
+// is full.
+func (n *WebhookNotifier) send(key string, event LogEvent, suppressed int) {
+	payload, err := n.payload(event, suppressed)
+	if err != nil {
+		reportWriteError(err)
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return
+	}
+	select {
+	case n.queue <- payload:
+	default:
+		if k := n.keys[key]; k != nil {
+			n.suppress(key, k, event, 1+suppressed)
+		}
+	}
+}
+
+// prune drops keys without suppressed entries whose interval is over, once
+// there are webhookPruneSize of them. n.mu must be held.
+func (n *WebhookNotifier) prune(now time.Time) {
+	if len(n.keys) < webhookPruneSize {
+		return
+	}
+	maps.DeleteFunc(n.keys, func(_ string, k *webhookKey) bool {
+		return k.suppressed == 0 && now.Sub(k.last) >= n.cfg.Interval
+	})
+}
+
+func (n *WebhookNotifier) run() {
+	defer close(n.done)
+	for payload := range n.queue {
+		if err := n.post(payload); err != nil {
+			reportWriteError(err)
+		}
+	}
+}
+
+func (n *WebhookNotifier) payload(event LogEvent, suppressed int) ([]byte, error) {
+	var title, body strings.Builder
+	if err := n.title.Execute(&title, event); err != nil {
+		return nil, fmt.Errorf("sugarzero: webhook title: %w", err)
+	}
+	if err := n.body.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("sugarzero: webhook body: %w", err)
+	}
+	text := strings.TrimSpace(body.String())
+	if suppressed > 0 {
+		text += fmt.Sprintf("\n\n(%d more notifications suppressed)", suppressed)
+	}
+
+	var payload any
+	switch n.cfg.Format {
+	case WebhookSlack:
+		payload = map[string]string{"text": "*" + title.String() + "*\n" + text}
+	case WebhookTeams:
+		payload = map[string]string{
+			"@type":    "MessageCard",
+			"@context": "https://schema.org/extensions",
+			"summary":  title.String(),
+			"title":    title.String(),
+			"text":     text,
+		}
+	default:
+		payload = map[string]any{
+			"title":   title.String(),
+			"body":    text,
+			"level":   event.Level,
+			"message": event.Message,
+			"fields":  event.Fields,
+		}
+	}
+	return json.Marshal(payload)
+}
+
+func (n *WebhookNotifier) post(payload []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookPostTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("sugarzero: invalid webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := n.cfg.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sugarzero: webhook post failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("sugarzero: webhook rejected post: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package sugarzero_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bigboss2063/sugarzero"
+)
+
+func TestWebhookNotifierSlack(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		payloads []map[string]string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		mu.Lock()
+		payloads = append(payloads, payload)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	ctx, _ := setupTest(t, "info")
+	notifier, err := sugarzero.NewWebhookNotifier(sugarzero.WebhookConfig{
+		URL:      server.URL,
+		Format:   sugarzero.WebhookSlack,
+		Body:     "deploy {{.Fields.version}}",
+		Interval: time.Hour,
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	stop, err := sugarzero.OnEvent(ctx, "info", sugarzero.MatchFilter(sugarzero.MustParseFilter("level>=error || category=='deploy'")), notifier.Notify)
+	if err != nil {
+		t.Fatalf("OnEvent failed: %v", err)
+	}
+	defer stop()
+
+	deploy := sugarzero.WithField(sugarzero.WithCategory(ctx, "deploy"), "version", "v1.2.0")
+	sugarzero.Info(ctx, "request served")
+	sugarzero.Info(deploy, "deploy started")
+	sugarzero.Info(deploy, "deploy started")
+	sugarzero.Error(ctx, "deploy failed")
+	sugarzero.Info(sugarzero.WithField(deploy, "version", "v1.2.1"), "deploy started")
+	if err := notifier.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(payloads) != 3 {
+		t.Fatalf("expected a post per key and one for the suppressed entries, got %v", payloads)
+	}
+	if payloads[0]["text"] != "*[info] deploy started*\ndeploy v1.2.0" {
+		t.Fatalf("unexpected first post %q", payloads[0]["text"])
+	}
+	if !strings.Contains(payloads[1]["text"], "deploy failed") {
+		t.Fatalf("expected another key not to be throttled, got %q", payloads[1]["text"])
+	}
+	if !strings.Contains(payloads[2]["text"], "deploy v1.2.1") || !strings.Contains(payloads[2]["text"], "(1 more notifications suppressed)") {
+		t.Fatalf("expected the last suppressed entry with the count on Close, got %q", payloads[2]["text"])
+	}
+}
+
+func TestWebhookNotifierFlushesSuppressedAfterInterval(t *testing.T) {
+	posts := make(chan map[string]any, 4)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload map[string]any
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		posts <- payload
+	}))
+	defer server.Close()
+
+	notifier, err := sugarzero.NewWebhookNotifier(sugarzero.WebhookConfig{
+		URL:      server.URL,
+		Interval: 50 * time.Millisecond,
+		Key: func(event sugarzero.LogEvent) string {
+			return event.Level
+		},
+	})
+	if err != nil {
+		t.Fatalf("NewWebhookNotifier failed: %v", err)
+	}
+	defer notifier.Close()
+
+	for _, message := range []string{"disk full", "disk still full", "disk full again"} {
+		notifier.Notify(sugarzero.LogEvent{Level: "error", Message: message})
+	}
+	for _, want := range []string{"disk full", "disk full again"} {
+		select {
+		case payload := <-posts:
+			if payload["message"] != want {
+				t.Fatalf("expected %q to be posted, got %v", want, payload)
+			}
+			if want == "disk full again" && !strings.Contains(payload["body"].(string), "(1 more notifications suppressed)") {
+				t.Fatalf("expected the suppressed count, got %v", payload)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected %q to be posted without further entries", want)
+		}
+	}
+}
+
+func TestWebhookNotifierInvalidConfig(t *testing.T) {
+	for _, cfg := range []sugarzero.WebhookConfig{
+		{},
+		{URL: "http://example.invalid", Title: "{{.Level"},
+	} {
+		if _, err := sugarzero.NewWebhookNotifier(cfg); err == nil {
+			t.Fatalf("expected error for %+v", cfg)
+		}
+	}
+}